@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"time"
 
 	"github.com/Gkemhcs/kavach-backend/internal/auth"
@@ -20,7 +21,6 @@ import (
 	secretProvider "github.com/Gkemhcs/kavach-backend/internal/provider"
 	providerdb "github.com/Gkemhcs/kavach-backend/internal/provider/gen"
 	"github.com/Gkemhcs/kavach-backend/internal/secret"
-	secretdb "github.com/Gkemhcs/kavach-backend/internal/secret/gen"
 	"github.com/Gkemhcs/kavach-backend/internal/secretgroup"
 	secretgroupdb "github.com/Gkemhcs/kavach-backend/internal/secretgroup/gen"
 
@@ -79,7 +79,20 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	providerFactory := secretProvider.NewProviderFactory(logger)
+	syncExecutor := secretProvider.NewSyncExecutor(secretProvider.SyncExecutorConfig{
+		Concurrency:      cfg.SyncProviderConcurrency,
+		BreakerThreshold: cfg.SyncBreakerThreshold,
+		BreakerCooldown:  time.Duration(cfg.SyncBreakerCooldown) * time.Second,
+	}, logger)
+	// Kavach acts as an OIDC issuer for workload identity federation when an issuer URL is configured
+	var oidcIssuer *secretProvider.OIDCIssuer
+	if cfg.OIDCIssuerURL != "" {
+		oidcIssuer, err = secretProvider.NewOIDCIssuer(cfg.OIDCIssuerURL, cfg.OIDCSigningKey)
+		if err != nil {
+			panic(err)
+		}
+	}
+	providerFactory := secretProvider.NewProviderFactory(syncExecutor, oidcIssuer, logger)
 	providerService := secretProvider.NewProviderService(providerdb.New(dbConn), providerFactory, logger, providerEncryptor)
 	providerHandler := secretProvider.NewProviderHandler(providerService, logger)
 
@@ -88,8 +101,11 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	secretService := secret.NewSecretService(secretdb.New(dbConn), secretEncryptionService, providerService, logger)
+	secretService := secret.NewSecretService(secret.NewSecretRepository(dbConn), secretEncryptionService, providerService, logger)
 	secretHandler := secret.NewSecretHandler(secretService, logger)
+	secretService.StartReuseScanScheduler(context.Background(), time.Duration(cfg.SecretReuseScanInterval)*time.Minute)
+	secretService.StartSyncWorkers(context.Background(), cfg.SyncWorkerCount)
+	secretService.StartDriftCheckScheduler(context.Background(), time.Duration(cfg.DriftCheckInterval)*time.Minute)
 	// Auth service and handler setup
 	authService := auth.NewAuthService(githubProvider, userdb.New(dbConn), jwter, logger)
 	authHandler := auth.NewAuthHandler(authService, logger)
@@ -122,7 +138,7 @@ func main() {
 	// Register all routes (auth, org, etc.)
 	s.SetupRoutes(authHandler, iamHandler, orgHandler,
 		groupHandler, environmentHandler, userGroupHandler, secretHandler, providerHandler,
-		jwter, cfg, logger, authzMiddleware)
+		oidcIssuer, jwter, cfg, logger, authzMiddleware)
 
 	// Start the server and log fatal on error
 	if err := s.Start(); err != nil {
@@ -26,6 +26,22 @@ type Config struct {
 	ModelFilePath         string
 	SecretEncryptionKey   string
 	ProviderEncryptionKey string
+	// SecretReuseScanInterval is the interval between scheduled secret reuse scans in minutes (0 disables them)
+	SecretReuseScanInterval int
+	// SyncWorkerCount is the number of background workers processing queued provider syncs (0 disables them)
+	SyncWorkerCount int
+	// DriftCheckInterval is the interval between scheduled provider drift checks in minutes (0 disables them)
+	DriftCheckInterval int
+	// SyncProviderConcurrency is the number of secrets written to each provider in parallel
+	SyncProviderConcurrency int
+	// SyncBreakerThreshold is the number of consecutive provider failures that pause writes to it
+	SyncBreakerThreshold int
+	// SyncBreakerCooldown is how long writes to a failing provider stay paused in seconds
+	SyncBreakerCooldown int
+	// OIDCIssuerURL is the public URL Kavach serves its OIDC discovery document under (empty disables workload identity federation)
+	OIDCIssuerURL string
+	// OIDCSigningKey is the PEM-encoded RSA private key workload tokens are signed with
+	OIDCSigningKey string
 	// Database connection pooling configuration
 	DBMaxOpenConns    int // Maximum number of open connections to the database
 	DBMaxIdleConns    int // Maximum number of idle connections in the pool
@@ -53,6 +69,12 @@ func Load() (*Config, error) {
 	viper.SetDefault("MODEL_FILE_PATH", "internal/authz/model.conf")
 	viper.SetDefault("ENCRYPTION_KEY", "RhK7KoKSwOuFOHxONMNaO9Z9pDgJKwZjaNhcbgZ7Qqc=")
 	viper.SetDefault("GITHUB_REDIRECT_URL", "http://localhost:8080/api/v1/auth/github/callback")
+	viper.SetDefault("SECRET_REUSE_SCAN_INTERVAL", 1440) // Daily secret reuse scan
+	viper.SetDefault("SYNC_WORKER_COUNT", 2)
+	viper.SetDefault("DRIFT_CHECK_INTERVAL", 360) // Provider drift check every 6 hours
+	viper.SetDefault("SYNC_PROVIDER_CONCURRENCY", 8)
+	viper.SetDefault("SYNC_BREAKER_THRESHOLD", 5)
+	viper.SetDefault("SYNC_BREAKER_COOLDOWN", 60)
 	// Database connection pooling defaults
 	viper.SetDefault("DB_MAX_OPEN_CONNS", 25)    // Maximum open connections
 	viper.SetDefault("DB_MAX_IDLE_CONNS", 5)     // Maximum idle connections
@@ -66,23 +88,31 @@ func Load() (*Config, error) {
 	}
 
 	config := &Config{
-		Port:                  viper.GetString("PORT"),
-		Env:                   viper.GetString("ENV"),
-		GitHubClientID:        viper.GetString("GITHUB_CLIENT_ID"),
-		GitHubClientSecret:    viper.GetString("GITHUB_CLIENT_SECRET"),
-		GitHubRedirectURL:     viper.GetString("GITHUB_REDIRECT_URL"),
-		DBUser:                viper.GetString("DB_USER"),
-		DBPort:                viper.GetString("DB_PORT"),
-		DBHost:                viper.GetString("DB_HOST"),
-		DBName:                viper.GetString("DB_NAME"),
-		DBPassword:            viper.GetString("DB_PASSWORD"),
-		JWTSecret:             viper.GetString("JWT_SECRET"),
-		JWTDuration:           viper.GetInt("JWT_DURATION"),
-		AccessTokenDuration:   viper.GetInt("ACCESS_TOKEN_DURATION"),
-		RefreshTokenDuration:  viper.GetInt("REFRESH_TOKEN_DURATION"),
-		ModelFilePath:         viper.GetString("MODEL_FILE_PATH"),
-		SecretEncryptionKey:   viper.GetString("ENCRYPTION_KEY"),
-		ProviderEncryptionKey: viper.GetString("ENCRYPTION_KEY"),
+		Port:                    viper.GetString("PORT"),
+		Env:                     viper.GetString("ENV"),
+		GitHubClientID:          viper.GetString("GITHUB_CLIENT_ID"),
+		GitHubClientSecret:      viper.GetString("GITHUB_CLIENT_SECRET"),
+		GitHubRedirectURL:       viper.GetString("GITHUB_REDIRECT_URL"),
+		DBUser:                  viper.GetString("DB_USER"),
+		DBPort:                  viper.GetString("DB_PORT"),
+		DBHost:                  viper.GetString("DB_HOST"),
+		DBName:                  viper.GetString("DB_NAME"),
+		DBPassword:              viper.GetString("DB_PASSWORD"),
+		JWTSecret:               viper.GetString("JWT_SECRET"),
+		JWTDuration:             viper.GetInt("JWT_DURATION"),
+		AccessTokenDuration:     viper.GetInt("ACCESS_TOKEN_DURATION"),
+		RefreshTokenDuration:    viper.GetInt("REFRESH_TOKEN_DURATION"),
+		ModelFilePath:           viper.GetString("MODEL_FILE_PATH"),
+		SecretEncryptionKey:     viper.GetString("ENCRYPTION_KEY"),
+		ProviderEncryptionKey:   viper.GetString("ENCRYPTION_KEY"),
+		SecretReuseScanInterval: viper.GetInt("SECRET_REUSE_SCAN_INTERVAL"),
+		SyncWorkerCount:         viper.GetInt("SYNC_WORKER_COUNT"),
+		DriftCheckInterval:      viper.GetInt("DRIFT_CHECK_INTERVAL"),
+		SyncProviderConcurrency: viper.GetInt("SYNC_PROVIDER_CONCURRENCY"),
+		SyncBreakerThreshold:    viper.GetInt("SYNC_BREAKER_THRESHOLD"),
+		SyncBreakerCooldown:     viper.GetInt("SYNC_BREAKER_COOLDOWN"),
+		OIDCIssuerURL:           viper.GetString("OIDC_ISSUER_URL"),
+		OIDCSigningKey:          viper.GetString("OIDC_SIGNING_KEY"),
 		// Database connection pooling configuration
 		DBMaxOpenConns:    viper.GetInt("DB_MAX_OPEN_CONNS"),
 		DBMaxIdleConns:    viper.GetInt("DB_MAX_IDLE_CONNS"),
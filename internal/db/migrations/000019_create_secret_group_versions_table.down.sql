@@ -0,0 +1,6 @@
+-- +goose Down
+-- Rollback migration for secret_group_versions and secret_group_secrets tables
+
+DROP INDEX IF EXISTS idx_secret_group_versions_group_created;
+DROP TABLE IF EXISTS secret_group_secrets;
+DROP TABLE IF EXISTS secret_group_versions;
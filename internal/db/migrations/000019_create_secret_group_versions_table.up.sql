@@ -0,0 +1,21 @@
+-- +goose Up
+-- Migration to create secret_group_versions and secret_group_secrets tables for
+-- versioned base secrets shared by every environment of a secret group
+
+CREATE TABLE secret_group_versions (
+    id VARCHAR(8) PRIMARY KEY DEFAULT substr(md5(random()::text), 1, 8),
+    secret_group_id UUID NOT NULL REFERENCES secret_groups(id) ON DELETE CASCADE,
+    commit_message TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE secret_group_secrets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    version_id VARCHAR(8) NOT NULL REFERENCES secret_group_versions(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    value_encrypted BYTEA NOT NULL,
+    UNIQUE (version_id, name)
+);
+
+-- Index for resolving the latest base version of a secret group
+CREATE INDEX idx_secret_group_versions_group_created ON secret_group_versions(secret_group_id, created_at DESC);
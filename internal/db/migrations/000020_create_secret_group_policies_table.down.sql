@@ -0,0 +1,4 @@
+-- +goose Down
+-- Rollback migration for secret_group_policies table
+
+DROP TABLE IF EXISTS secret_group_policies;
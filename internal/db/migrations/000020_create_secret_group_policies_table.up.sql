@@ -0,0 +1,10 @@
+-- +goose Up
+-- Migration to create secret_group_policies table for value policy schemas enforced on secret versions
+
+CREATE TABLE secret_group_policies (
+    secret_group_id UUID PRIMARY KEY REFERENCES secret_groups(id) ON DELETE CASCADE,
+    schema JSONB NOT NULL, -- Required/forbidden keys, per-key rules and per-environment overrides
+    updated_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
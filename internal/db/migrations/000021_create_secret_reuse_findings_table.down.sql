@@ -0,0 +1,5 @@
+-- +goose Down
+-- Rollback migration for secret_reuse_findings table
+
+DROP INDEX IF EXISTS idx_secret_reuse_findings_org_last_seen;
+DROP TABLE IF EXISTS secret_reuse_findings;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Migration to create secret_reuse_findings table for identical secret values reused across environments.
+-- Values are identified by a keyed fingerprint only; plaintext values are never stored.
+
+CREATE TABLE secret_reuse_findings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    fingerprint TEXT NOT NULL, -- HMAC-SHA256 of the decrypted value keyed with a server-side secret
+    scope TEXT NOT NULL CHECK (scope IN ('cross_environment', 'cross_secret_group')),
+    occurrences JSONB NOT NULL, -- Secret group, environment, version and key of every occurrence
+    suppressed BOOLEAN NOT NULL DEFAULT false,
+    suppressed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    suppressed_reason TEXT,
+    suppressed_at TIMESTAMPTZ,
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    resolved_at TIMESTAMPTZ,
+    UNIQUE (organization_id, fingerprint)
+);
+
+-- Index for listing the findings of an organization
+CREATE INDEX idx_secret_reuse_findings_org_last_seen ON secret_reuse_findings(organization_id, last_seen_at DESC);
@@ -0,0 +1,5 @@
+-- +goose Down
+-- Rollback migration for secret_key_changes and secret_key_change_cleanups tables
+
+DROP TABLE IF EXISTS secret_key_change_cleanups;
+DROP TABLE IF EXISTS secret_key_changes;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Migration to create secret_key_changes table recording key renames and moves between environments,
+-- and secret_key_change_cleanups tracking which providers have removed the old remote name.
+
+CREATE TABLE secret_key_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    version_id VARCHAR(8) NOT NULL REFERENCES secret_versions(id) ON DELETE CASCADE,
+    environment_id UUID NOT NULL REFERENCES environments(id) ON DELETE CASCADE,
+    change_type TEXT NOT NULL CHECK (change_type IN ('rename', 'move_out', 'move_in')),
+    old_name TEXT NOT NULL,
+    new_name TEXT NOT NULL,
+    related_environment_id UUID REFERENCES environments(id) ON DELETE SET NULL, -- Other side of a move
+    related_version_id VARCHAR(8) REFERENCES secret_versions(id) ON DELETE SET NULL, -- Version created on the other side of a move
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE secret_key_change_cleanups (
+    key_change_id UUID NOT NULL REFERENCES secret_key_changes(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    cleaned_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (key_change_id, provider)
+);
+
+-- Index for loading the key changes of a version
+CREATE INDEX idx_secret_key_changes_version ON secret_key_changes(version_id);
+
+-- Index for key history and pending remote removals of an environment
+CREATE INDEX idx_secret_key_changes_environment_created ON secret_key_changes(environment_id, created_at DESC);
@@ -0,0 +1,7 @@
+-- +goose Down
+-- Rollback migration for secret_shares and secret_share_events tables
+
+DROP INDEX IF EXISTS idx_secret_share_events_share;
+DROP INDEX IF EXISTS idx_secret_shares_environment;
+DROP TABLE IF EXISTS secret_share_events;
+DROP TABLE IF EXISTS secret_shares;
@@ -0,0 +1,35 @@
+-- +goose Up
+-- Migration to create secret_shares table for one-time, time-limited links to a single secret value.
+-- Only a hash of the share token is stored; the value is encrypted and cleared once the share is used up.
+
+CREATE TABLE secret_shares (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    environment_id UUID NOT NULL REFERENCES environments(id) ON DELETE CASCADE,
+    version_id VARCHAR(8) REFERENCES secret_versions(id) ON DELETE SET NULL, -- NULL for ad-hoc values
+    secret_name TEXT, -- NULL for ad-hoc values
+    token_hash TEXT NOT NULL UNIQUE, -- SHA-256 of the share token
+    value_encrypted BYTEA, -- NULL once the share is used up, expired or revoked
+    passphrase_hash TEXT, -- bcrypt hash of the optional passphrase
+    max_views INTEGER NOT NULL DEFAULT 1 CHECK (max_views > 0),
+    view_count INTEGER NOT NULL DEFAULT 0,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Every creation, retrieval, failed passphrase attempt and revocation of a share
+CREATE TABLE secret_share_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    share_id UUID NOT NULL REFERENCES secret_shares(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL CHECK (event_type IN ('created', 'retrieved', 'passphrase_failed', 'revoked')),
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL for public retrievals
+    ip_address TEXT,
+    user_agent TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Indexes for listing the shares of an environment and the events of a share
+CREATE INDEX idx_secret_shares_environment ON secret_shares(environment_id, created_at DESC);
+CREATE INDEX idx_secret_share_events_share ON secret_share_events(share_id, created_at);
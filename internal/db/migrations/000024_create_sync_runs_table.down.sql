@@ -0,0 +1,7 @@
+-- +goose Down
+-- Rollback migration for sync_runs and sync_run_results tables
+
+DROP INDEX IF EXISTS idx_sync_runs_environment_provider_started;
+DROP INDEX IF EXISTS idx_sync_runs_environment_started;
+DROP TABLE IF EXISTS sync_run_results;
+DROP TABLE IF EXISTS sync_runs;
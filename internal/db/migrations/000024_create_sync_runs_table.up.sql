@@ -0,0 +1,34 @@
+-- +goose Up
+-- Migration to create sync_runs table recording every sync of an environment to an external provider
+-- together with the outcome of each secret.
+
+CREATE TABLE sync_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    environment_id UUID NOT NULL REFERENCES environments(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    provider_credential_id UUID REFERENCES provider_credentials(id) ON DELETE SET NULL,
+    version_id VARCHAR(8) REFERENCES secret_versions(id) ON DELETE SET NULL, -- NULL when the run failed before a version was resolved
+    initiated_by UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL for runs not started by a user
+    status TEXT NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'success', 'partial', 'failed')),
+    message TEXT,
+    error TEXT,
+    synced_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    total_count INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at TIMESTAMPTZ
+);
+
+-- Outcome of each secret written or removed by a sync run
+CREATE TABLE sync_run_results (
+    run_id UUID NOT NULL REFERENCES sync_runs(id) ON DELETE CASCADE,
+    operation TEXT NOT NULL CHECK (operation IN ('upsert', 'delete')),
+    name TEXT NOT NULL,
+    success BOOLEAN NOT NULL,
+    error TEXT,
+    PRIMARY KEY (run_id, operation, name)
+);
+
+-- Indexes for listing the sync history of an environment and of a provider
+CREATE INDEX idx_sync_runs_environment_started ON sync_runs(environment_id, started_at DESC);
+CREATE INDEX idx_sync_runs_environment_provider_started ON sync_runs(environment_id, provider, started_at DESC);
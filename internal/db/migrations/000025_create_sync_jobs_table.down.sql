@@ -0,0 +1,6 @@
+-- +goose Down
+-- Rollback migration for sync_jobs table
+
+DROP INDEX IF EXISTS idx_sync_jobs_environment_created;
+DROP INDEX IF EXISTS idx_sync_jobs_claimable;
+DROP TABLE IF EXISTS sync_jobs;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Migration to create sync_jobs table, a durable queue of provider syncs processed by background workers.
+-- Workers claim jobs with a lease that they extend while syncing; jobs whose lease expires are picked up again.
+
+CREATE TABLE sync_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    environment_id UUID NOT NULL REFERENCES environments(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    version_id VARCHAR(8) REFERENCES secret_versions(id) ON DELETE SET NULL, -- NULL syncs the latest version
+    initiated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    status TEXT NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'completed', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    run_id UUID REFERENCES sync_runs(id) ON DELETE SET NULL, -- Sync run of the latest attempt
+    last_error TEXT,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT now(), -- Earliest time the job may be claimed
+    locked_by TEXT, -- Worker holding the lease
+    lease_expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at TIMESTAMPTZ
+);
+
+-- Index for claiming due jobs and reclaiming jobs with expired leases
+CREATE INDEX idx_sync_jobs_claimable ON sync_jobs(status, run_at) WHERE status IN ('queued', 'running');
+
+-- Index for listing the jobs of an environment
+CREATE INDEX idx_sync_jobs_environment_created ON sync_jobs(environment_id, created_at DESC);
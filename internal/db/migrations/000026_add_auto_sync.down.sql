@@ -0,0 +1,9 @@
+-- +goose Down
+-- Rollback migration for auto-sync settings
+
+DROP INDEX IF EXISTS idx_sync_jobs_pending_auto;
+ALTER TABLE sync_runs DROP COLUMN IF EXISTS trigger;
+ALTER TABLE sync_jobs DROP COLUMN IF EXISTS trigger;
+ALTER TABLE provider_credentials
+    DROP COLUMN IF EXISTS auto_sync_debounce_seconds,
+    DROP COLUMN IF EXISTS auto_sync;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Migration to add per-provider auto-sync settings. New versions of an environment queue a debounced sync
+-- to every provider with auto-sync enabled; jobs and runs record whether they were started manually or automatically.
+
+ALTER TABLE provider_credentials
+    ADD COLUMN auto_sync BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN auto_sync_debounce_seconds INTEGER NOT NULL DEFAULT 60 CHECK (auto_sync_debounce_seconds BETWEEN 0 AND 3600);
+
+ALTER TABLE sync_jobs
+    ADD COLUMN trigger TEXT NOT NULL DEFAULT 'manual' CHECK (trigger IN ('manual', 'auto'));
+
+ALTER TABLE sync_runs
+    ADD COLUMN trigger TEXT NOT NULL DEFAULT 'manual' CHECK (trigger IN ('manual', 'auto'));
+
+-- At most one pending auto-sync job per provider, so bursts of versions coalesce into one sync
+CREATE UNIQUE INDEX idx_sync_jobs_pending_auto ON sync_jobs(environment_id, provider)
+    WHERE status = 'queued' AND trigger = 'auto' AND attempts = 0;
@@ -0,0 +1,5 @@
+-- +goose Down
+-- Rollback migration for drift_checks table
+
+DROP INDEX IF EXISTS idx_drift_checks_environment_provider_checked;
+DROP TABLE IF EXISTS drift_checks;
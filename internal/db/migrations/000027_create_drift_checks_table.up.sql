@@ -0,0 +1,20 @@
+-- +goose Up
+-- Migration to create drift_checks table recording how the secrets held by an external provider
+-- differ from the latest version of an environment.
+
+CREATE TABLE drift_checks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    environment_id UUID NOT NULL REFERENCES environments(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    version_id VARCHAR(8) REFERENCES secret_versions(id) ON DELETE SET NULL, -- latest version the provider was compared with
+    synced_version_id VARCHAR(8) REFERENCES secret_versions(id) ON DELETE SET NULL, -- version of the last successful sync, if any
+    status TEXT NOT NULL CHECK (status IN ('in_sync', 'drifted', 'failed')),
+    findings JSONB NOT NULL DEFAULT '{}', -- missing, extra and changed keys; never contains values
+    error TEXT,
+    trigger TEXT NOT NULL DEFAULT 'manual' CHECK (trigger IN ('manual', 'scheduled')),
+    checked_by UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL for scheduled checks
+    checked_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Index for finding the latest check of every provider of an environment
+CREATE INDEX idx_drift_checks_environment_provider_checked ON drift_checks(environment_id, provider, checked_at DESC);
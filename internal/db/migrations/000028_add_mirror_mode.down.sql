@@ -0,0 +1,11 @@
+-- +goose Down
+-- Rollback migration for provider mirror mode
+
+DELETE FROM sync_run_results WHERE operation = 'prune';
+
+ALTER TABLE sync_run_results
+    DROP CONSTRAINT sync_run_results_operation_check,
+    ADD CONSTRAINT sync_run_results_operation_check CHECK (operation IN ('upsert', 'delete'));
+
+ALTER TABLE provider_credentials
+    DROP COLUMN IF EXISTS mirror_mode;
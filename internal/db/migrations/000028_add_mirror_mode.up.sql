@@ -0,0 +1,10 @@
+-- +goose Up
+-- Migration to add opt-in mirror mode to provider credentials. In mirror mode a sync also deletes remote
+-- secrets managed by Kavach that are no longer in the synced version; those deletions are recorded as prunes.
+
+ALTER TABLE provider_credentials
+    ADD COLUMN mirror_mode BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE sync_run_results
+    DROP CONSTRAINT sync_run_results_operation_check,
+    ADD CONSTRAINT sync_run_results_operation_check CHECK (operation IN ('upsert', 'delete', 'prune'));
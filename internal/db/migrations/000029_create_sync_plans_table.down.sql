@@ -0,0 +1,8 @@
+-- +goose Down
+-- Rollback migration for sync_plans table
+
+ALTER TABLE sync_jobs
+    DROP COLUMN IF EXISTS plan_id;
+
+DROP INDEX IF EXISTS idx_sync_plans_environment_created;
+DROP TABLE IF EXISTS sync_plans;
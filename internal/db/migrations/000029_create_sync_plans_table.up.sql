@@ -0,0 +1,27 @@
+-- +goose Up
+-- Migration to create sync_plans table recording what a sync of a version to an external provider
+-- would create, update, leave unchanged or delete, so the reviewed plan can later be applied as is.
+
+CREATE TABLE sync_plans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    environment_id UUID NOT NULL REFERENCES environments(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    version_id VARCHAR(8) NOT NULL REFERENCES secret_versions(id) ON DELETE CASCADE,
+    mirror_mode BOOLEAN NOT NULL DEFAULT false, -- whether the plan includes deleting managed secrets missing from the version
+    changes JSONB NOT NULL DEFAULT '[]', -- planned action for every remote name; never contains values
+    collisions JSONB NOT NULL DEFAULT '[]', -- keys that map to the same remote name; a plan with collisions cannot be applied
+    credential_updated_at TIMESTAMPTZ NOT NULL, -- provider credential version the plan was computed against
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    applied_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    applied_at TIMESTAMPTZ,
+    job_id UUID REFERENCES sync_jobs(id) ON DELETE SET NULL -- sync job applying the plan
+);
+
+-- Sync jobs applying a plan write only what the plan lists
+ALTER TABLE sync_jobs
+    ADD COLUMN plan_id UUID REFERENCES sync_plans(id) ON DELETE SET NULL;
+
+-- Index for listing the plans of an environment
+CREATE INDEX idx_sync_plans_environment_created ON sync_plans(environment_id, created_at DESC);
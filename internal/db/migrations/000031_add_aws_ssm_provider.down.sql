@@ -0,0 +1,8 @@
+-- +goose Down
+-- Rollback migration for the AWS SSM Parameter Store provider
+
+DELETE FROM provider_credentials WHERE provider = 'aws_ssm';
+
+ALTER TABLE provider_credentials
+    DROP CONSTRAINT provider_credentials_provider_check,
+    ADD CONSTRAINT provider_credentials_provider_check CHECK (provider IN ('github', 'gcp', 'azure', 'aws_secrets_manager'));
@@ -0,0 +1,8 @@
+-- +goose Down
+-- Rollback migration for the HashiCorp Vault provider
+
+DELETE FROM provider_credentials WHERE provider = 'vault';
+
+ALTER TABLE provider_credentials
+    DROP CONSTRAINT provider_credentials_provider_check,
+    ADD CONSTRAINT provider_credentials_provider_check CHECK (provider IN ('github', 'gcp', 'azure', 'aws_secrets_manager', 'aws_ssm'));
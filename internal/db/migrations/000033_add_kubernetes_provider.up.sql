@@ -0,0 +1,6 @@
+-- +goose Up
+-- Migration to allow Kubernetes provider credentials
+
+ALTER TABLE provider_credentials
+    DROP CONSTRAINT provider_credentials_provider_check,
+    ADD CONSTRAINT provider_credentials_provider_check CHECK (provider IN ('github', 'gcp', 'azure', 'aws_secrets_manager', 'aws_ssm', 'vault', 'kubernetes'));
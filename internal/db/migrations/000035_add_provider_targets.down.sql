@@ -0,0 +1,30 @@
+-- +goose Down
+-- Rollback migration for named provider targets. Only the oldest target of each provider is kept.
+
+DROP INDEX IF EXISTS idx_sync_jobs_pending_auto;
+DELETE FROM sync_jobs WHERE status = 'queued' AND trigger = 'auto' AND attempts = 0 AND target <> provider;
+CREATE UNIQUE INDEX idx_sync_jobs_pending_auto ON sync_jobs(environment_id, provider)
+    WHERE status = 'queued' AND trigger = 'auto' AND attempts = 0;
+
+DROP INDEX IF EXISTS idx_drift_checks_environment_target_checked;
+CREATE INDEX idx_drift_checks_environment_provider_checked ON drift_checks(environment_id, provider, checked_at DESC);
+
+DROP INDEX IF EXISTS idx_sync_runs_environment_target_started;
+CREATE INDEX idx_sync_runs_environment_provider_started ON sync_runs(environment_id, provider, started_at DESC);
+
+ALTER TABLE secret_key_change_cleanups RENAME COLUMN target TO provider;
+
+ALTER TABLE sync_plans DROP COLUMN IF EXISTS target;
+ALTER TABLE drift_checks DROP COLUMN IF EXISTS target;
+ALTER TABLE sync_jobs DROP COLUMN IF EXISTS target;
+ALTER TABLE sync_runs DROP COLUMN IF EXISTS target;
+
+DELETE FROM provider_credentials pc
+USING provider_credentials o
+WHERE o.environment_id = pc.environment_id AND o.provider = pc.provider
+  AND (o.created_at, o.id) < (pc.created_at, pc.id);
+
+ALTER TABLE provider_credentials
+    DROP CONSTRAINT provider_credentials_environment_id_name_key,
+    ADD CONSTRAINT provider_credentials_environment_id_provider_key UNIQUE (environment_id, provider),
+    DROP COLUMN name;
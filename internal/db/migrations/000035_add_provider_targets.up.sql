@@ -0,0 +1,40 @@
+-- +goose Up
+-- Migration to allow several named provider targets per environment, such as two GitHub repositories.
+-- Existing credentials are named after their provider, so routes and history that used the provider
+-- name keep resolving. Sync runs, jobs, plans, drift checks and key change cleanups record the target.
+
+ALTER TABLE provider_credentials ADD COLUMN name TEXT;
+UPDATE provider_credentials SET name = provider;
+ALTER TABLE provider_credentials
+    ALTER COLUMN name SET NOT NULL,
+    DROP CONSTRAINT provider_credentials_environment_id_provider_key,
+    ADD CONSTRAINT provider_credentials_environment_id_name_key UNIQUE (environment_id, name);
+
+ALTER TABLE sync_runs ADD COLUMN target TEXT;
+UPDATE sync_runs SET target = provider;
+ALTER TABLE sync_runs ALTER COLUMN target SET NOT NULL;
+
+ALTER TABLE sync_jobs ADD COLUMN target TEXT;
+UPDATE sync_jobs SET target = provider;
+ALTER TABLE sync_jobs ALTER COLUMN target SET NOT NULL;
+
+ALTER TABLE drift_checks ADD COLUMN target TEXT;
+UPDATE drift_checks SET target = provider;
+ALTER TABLE drift_checks ALTER COLUMN target SET NOT NULL;
+
+ALTER TABLE sync_plans ADD COLUMN target TEXT;
+UPDATE sync_plans SET target = provider;
+ALTER TABLE sync_plans ALTER COLUMN target SET NOT NULL;
+
+ALTER TABLE secret_key_change_cleanups RENAME COLUMN provider TO target;
+
+-- History and the pending auto-sync of an environment are now kept per target
+DROP INDEX IF EXISTS idx_sync_runs_environment_provider_started;
+CREATE INDEX idx_sync_runs_environment_target_started ON sync_runs(environment_id, target, started_at DESC);
+
+DROP INDEX IF EXISTS idx_drift_checks_environment_provider_checked;
+CREATE INDEX idx_drift_checks_environment_target_checked ON drift_checks(environment_id, target, checked_at DESC);
+
+DROP INDEX IF EXISTS idx_sync_jobs_pending_auto;
+CREATE UNIQUE INDEX idx_sync_jobs_pending_auto ON sync_jobs(environment_id, target)
+    WHERE status = 'queued' AND trigger = 'auto' AND attempts = 0;
@@ -0,0 +1,15 @@
+-- +goose Down
+-- Rollback migration for provider connections. Targets using a connection have no credentials
+-- of their own and are removed.
+
+DELETE FROM provider_credentials WHERE connection_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_provider_credentials_connection;
+
+ALTER TABLE provider_credentials
+    DROP CONSTRAINT provider_credentials_credentials_source_check,
+    ALTER COLUMN credentials SET NOT NULL,
+    DROP COLUMN connection_id;
+
+DROP TABLE IF EXISTS provider_connection_grants;
+DROP TABLE IF EXISTS provider_connections;
@@ -0,0 +1,41 @@
+-- +goose Up
+-- Migration to create organization-level provider connections. A connection holds provider credentials
+-- once for an organization; environment targets reference it and keep only their own config. Managing a
+-- connection and being allowed to use it are separate: a grant lets a secret group or an environment use it.
+
+CREATE TABLE provider_connections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    provider TEXT NOT NULL CHECK (provider IN ('github', 'gcp', 'azure', 'aws_secrets_manager', 'aws_ssm', 'vault', 'kubernetes', 'gitlab')),
+    credentials BYTEA NOT NULL, -- Encrypted credentials shared by every target using the connection
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, name)
+);
+
+-- A grant covers one secret group, including all of its environments, or one environment
+CREATE TABLE provider_connection_grants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES provider_connections(id) ON DELETE CASCADE,
+    secret_group_id UUID REFERENCES secret_groups(id) ON DELETE CASCADE,
+    environment_id UUID REFERENCES environments(id) ON DELETE CASCADE,
+    granted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((secret_group_id IS NULL) <> (environment_id IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_provider_connection_grants_secret_group ON provider_connection_grants(connection_id, secret_group_id)
+    WHERE secret_group_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_provider_connection_grants_environment ON provider_connection_grants(connection_id, environment_id)
+    WHERE environment_id IS NOT NULL;
+
+-- Targets either hold their own credentials or use a connection's. A connection cannot be
+-- deleted while targets use it.
+ALTER TABLE provider_credentials
+    ADD COLUMN connection_id UUID REFERENCES provider_connections(id) ON DELETE RESTRICT,
+    ALTER COLUMN credentials DROP NOT NULL,
+    ADD CONSTRAINT provider_credentials_credentials_source_check CHECK ((credentials IS NULL) <> (connection_id IS NULL));
+
+CREATE INDEX idx_provider_credentials_connection ON provider_credentials(connection_id) WHERE connection_id IS NOT NULL;
@@ -0,0 +1,4 @@
+-- +goose Down
+-- Rollback migration for provider key mapping rules
+
+ALTER TABLE provider_credentials DROP COLUMN IF EXISTS key_mapping;
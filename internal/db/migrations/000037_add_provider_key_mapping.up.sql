@@ -0,0 +1,5 @@
+-- +goose Up
+-- Migration to add key mapping rules to provider targets. The rules filter, rename, recase and group
+-- the keys of a version before they are synced; an empty object leaves every key as it is.
+
+ALTER TABLE provider_credentials ADD COLUMN key_mapping JSONB NOT NULL DEFAULT '{}';
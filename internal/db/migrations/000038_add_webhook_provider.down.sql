@@ -0,0 +1,13 @@
+-- +goose Down
+-- Rollback migration for the webhook provider
+
+DELETE FROM provider_credentials WHERE provider = 'webhook';
+DELETE FROM provider_connections WHERE provider = 'webhook';
+
+ALTER TABLE provider_connections
+    DROP CONSTRAINT provider_connections_provider_check,
+    ADD CONSTRAINT provider_connections_provider_check CHECK (provider IN ('github', 'gcp', 'azure', 'aws_secrets_manager', 'aws_ssm', 'vault', 'kubernetes', 'gitlab'));
+
+ALTER TABLE provider_credentials
+    DROP CONSTRAINT provider_credentials_provider_check,
+    ADD CONSTRAINT provider_credentials_provider_check CHECK (provider IN ('github', 'gcp', 'azure', 'aws_secrets_manager', 'aws_ssm', 'vault', 'kubernetes', 'gitlab'));
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Migration to allow webhook provider credentials and connections
+
+ALTER TABLE provider_credentials
+    DROP CONSTRAINT provider_credentials_provider_check,
+    ADD CONSTRAINT provider_credentials_provider_check CHECK (provider IN ('github', 'gcp', 'azure', 'aws_secrets_manager', 'aws_ssm', 'vault', 'kubernetes', 'gitlab', 'webhook'));
+
+ALTER TABLE provider_connections
+    DROP CONSTRAINT provider_connections_provider_check,
+    ADD CONSTRAINT provider_connections_provider_check CHECK (provider IN ('github', 'gcp', 'azure', 'aws_secrets_manager', 'aws_ssm', 'vault', 'kubernetes', 'gitlab', 'webhook'));
@@ -0,0 +1,4 @@
+-- +goose Down
+-- Rollback migration for the group base version of secret versions
+
+ALTER TABLE secret_versions DROP COLUMN group_version_id;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Migration to record the secret group base version each environment version was created on top of
+
+ALTER TABLE secret_versions
+    ADD COLUMN group_version_id VARCHAR(8) REFERENCES secret_group_versions(id) ON DELETE SET NULL;
+
+-- Existing versions inherited the latest base that was created before them
+UPDATE secret_versions sv
+SET group_version_id = (
+    SELECT sgv.id
+    FROM secret_group_versions sgv
+    JOIN environments e ON e.secret_group_id = sgv.secret_group_id
+    WHERE e.id = sv.environment_id
+      AND sgv.created_at <= sv.created_at
+    ORDER BY sgv.created_at DESC
+    LIMIT 1
+);
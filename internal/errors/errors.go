@@ -49,6 +49,26 @@ var (
 	ErrEnvironmentNameNotAllowed          = NewAPIError("environment_name_not_allowed", "environment name  you entered is not allowed allowed names are:-prod,dev,staging", http.StatusConflict)
 	ErrForeignKeyViolation                = NewAPIError("foreign_key_constraint_violation", "violating foreign key constraint", http.StatusConflict)
 	ErrSecretVersionNotFound              = NewAPIError("secret_version_not_found", "the secret version you are trying to operate not exist", http.StatusBadRequest)
+	ErrSecretGroupVersionNotFound         = NewAPIError("secret_group_version_not_found", "the secret group version you are trying to operate not exist", http.StatusBadRequest)
+	ErrSecretPolicyNotFound               = NewAPIError("secret_policy_not_found", "no secret policy is configured for this secret group", http.StatusNotFound)
+	ErrInvalidSecretPolicy                = NewAPIError("invalid_secret_policy", "the secret policy schema is invalid", http.StatusBadRequest)
+	ErrSecretPolicyViolation              = NewAPIError("secret_policy_violation", "the secrets do not satisfy the secret group policy", http.StatusUnprocessableEntity)
+	ErrSecretReuseFindingNotFound         = NewAPIError("secret_reuse_finding_not_found", "the secret reuse finding you are trying to operate not exist", http.StatusNotFound)
+	ErrSecretKeyNotFound                  = NewAPIError("secret_key_not_found", "the secret key you are trying to operate not exist in the latest version", http.StatusBadRequest)
+	ErrSecretKeyConflict                  = NewAPIError("secret_key_conflict", "the target secret key already exists", http.StatusConflict)
+	ErrInvalidMoveTarget                  = NewAPIError("invalid_move_target", "secret keys can only be moved to another environment of the same secret group", http.StatusBadRequest)
+	ErrSyncRunNotFound                    = NewAPIError("sync_run_not_found", "the sync run you are trying to operate not exist", http.StatusNotFound)
+	ErrSyncJobNotFound                    = NewAPIError("sync_job_not_found", "the sync job you are trying to operate not exist", http.StatusNotFound)
+	ErrDriftCheckNotSupported             = NewAPIError("drift_check_not_supported", "the provider cannot report the secrets it holds, so drift cannot be checked", http.StatusBadRequest)
+	ErrSyncPlanNotFound                   = NewAPIError("sync_plan_not_found", "the sync plan you are trying to operate not exist", http.StatusNotFound)
+	ErrSyncPlanNotSupported               = NewAPIError("sync_plan_not_supported", "the provider cannot report the secrets it holds, so a sync cannot be planned", http.StatusBadRequest)
+	ErrSyncPlanAlreadyApplied             = NewAPIError("sync_plan_already_applied", "the sync plan has already been applied", http.StatusConflict)
+	ErrSyncPlanExpired                    = NewAPIError("sync_plan_expired", "the sync plan has expired; create a new plan", http.StatusConflict)
+	ErrSyncPlanHasCollisions              = NewAPIError("sync_plan_has_collisions", "the sync plan has keys that map to the same remote name; rename them and create a new plan", http.StatusConflict)
+	ErrSyncPlanStale                      = NewAPIError("sync_plan_stale", "the provider settings changed after the sync plan was created; create a new plan", http.StatusConflict)
+	ErrSecretShareNotFound                = NewAPIError("secret_share_not_found", "the share does not exist, has expired, was revoked or has already been viewed", http.StatusNotFound)
+	ErrInvalidSecretShare                 = NewAPIError("invalid_secret_share", "a share needs either a secret name or a value, at most 10 views and at most 7 days to expire", http.StatusBadRequest)
+	ErrInvalidSharePassphrase             = NewAPIError("invalid_share_passphrase", "the share passphrase is missing or incorrect", http.StatusUnauthorized)
 	ErrSecretNotFound                     = NewAPIError("secret_not_found", "the secret you are trying to operate not exist", http.StatusBadRequest)
 	ErrTargetSecretVersionNotFound        = NewAPIError("target_secret_version_not_found", "the target secret version you are trying to operate not exist", http.StatusBadRequest)
 	ErrEnvironmentsMisMatch               = NewAPIError("environment_mismatch", "the target secret version environment is different ", http.StatusBadRequest)
@@ -64,6 +84,8 @@ var (
 	ErrProviderCredentialNotFound         = NewAPIError("provider_credential_not_found", "provider credential not found", http.StatusNotFound)
 	ErrProviderCredentialExists           = NewAPIError("provider_credential_exists", "provider credential already exists", http.StatusConflict)
 	ErrInvalidProviderType                = NewAPIError("invalid_provider_type", "unsupported provider type", http.StatusBadRequest)
+	ErrInvalidProviderTargetName          = NewAPIError("invalid_provider_target_name", "target names must be 1-63 lowercase letters, digits, hyphens or underscores", http.StatusBadRequest)
+	ErrNoSyncTargets                      = NewAPIError("no_sync_targets", "no provider targets in the environment match the sync request", http.StatusBadRequest)
 	ErrInvalidProviderData                = NewAPIError("invalid_provider_data", "invalid provider credentials or configuration", http.StatusBadRequest)
 	ErrProviderCredentialCreateFailed     = NewAPIError("provider_credential_create_failed", "failed to create provider credential", http.StatusInternalServerError)
 	ErrProviderCredentialUpdateFailed     = NewAPIError("provider_credential_update_failed", "failed to update provider credential", http.StatusInternalServerError)
@@ -75,8 +97,34 @@ var (
 	ErrNoSecretsToSync                    = NewAPIError("no_secrets_to_sync", "❌ No secrets found to sync. Please ensure secrets exist in the environment", http.StatusBadRequest)
 	ErrGitHubEnvironmentNotFound          = NewAPIError("github_environment_not_found", "❌ GitHub environment specified in config was not found in the repository", http.StatusBadRequest)
 	ErrGCPInvalidLocation                 = NewAPIError("gcp_invalid_location", "❌ GCP Secret Manager location specified in config is invalid or not supported", http.StatusBadRequest)
+	ErrInvalidAutoSyncSettings            = NewAPIError("invalid_auto_sync_settings", "auto-sync debounce must be between 0 and 3600 seconds", http.StatusBadRequest)
+	ErrMirrorModeRequiresPrefix           = NewAPIError("mirror_mode_requires_prefix", "mirror mode on GitHub requires a prefix in the provider config to tell which secrets Kavach manages", http.StatusBadRequest)
+	ErrMirrorModeTargetOverlap            = NewAPIError("mirror_mode_target_overlap", "another GitHub target writes to the same repository or organization secrets under an overlapping prefix, so mirror mode would delete its secrets; give the targets distinct prefixes", http.StatusConflict)
+	ErrInvalidKeyMapping                  = NewAPIError("invalid_key_mapping", "key mapping rules need valid globs, non-empty rename targets, uniquely named groups and a case of upper, lower, kebab, snake or upper_snake", http.StatusBadRequest)
+	ErrKeyMappingCollision                = NewAPIError("key_mapping_collision", "the key mapping rules map several keys to the same remote name", http.StatusConflict)
+	ErrImportNotSupported                 = NewAPIError("import_not_supported", "the provider cannot read secret values back, so secrets cannot be imported from it", http.StatusBadRequest)
+	ErrImportHasCollisions                = NewAPIError("import_has_collisions", "several remote secrets map to the same key; run a dry run to review them", http.StatusConflict)
+	ErrSSMParameterTooLarge               = NewAPIError("ssm_parameter_too_large", "the secret value is larger than the parameter tier allows", http.StatusBadRequest)
+	ErrWorkloadIdentityNotConfigured      = NewAPIError("workload_identity_not_configured", "workload identity federation needs OIDC_ISSUER_URL and OIDC_SIGNING_KEY to be set on the server", http.StatusBadRequest)
+	ErrGitHubAppPermissionMissing         = NewAPIError("github_app_permission_missing", "the GitHub App installation lacks write permission for the configured secrets or variables", http.StatusBadRequest)
+	ErrGitLabMaskedValueInvalid           = NewAPIError("gitlab_masked_value_invalid", "masked GitLab variables must be at least 8 characters on a single line without spaces", http.StatusBadRequest)
+	ErrWebhookSecretRejected              = NewAPIError("webhook_secret_rejected", "the webhook receiver rejected the secret", http.StatusBadRequest)
+	ErrVaultSecretChanged                 = NewAPIError("vault_secret_changed", "the Vault secret changed outside Kavach since its last sync; sync once without check_and_set to overwrite it", http.StatusConflict)
 	ErrProviderCredentialValidationFailed = NewAPIError("provider_credential_validation_failed", "❌ Provider credential validation failed. Please check your credentials", http.StatusBadRequest)
 	ErrGitHubEncryptionFailed             = NewAPIError("github_encryption_failed", "❌ Failed to encrypt secret for GitHub. Please try again", http.StatusInternalServerError)
+	ErrProviderCircuitOpen                = NewAPIError("provider_circuit_open", "provider is failing repeatedly; writes are paused for a short while", http.StatusServiceUnavailable)
+
+	// Provider connection errors
+	ErrProviderConnectionNotFound      = NewAPIError("provider_connection_not_found", "provider connection not found", http.StatusNotFound)
+	ErrProviderConnectionExists        = NewAPIError("provider_connection_exists", "a provider connection with this name already exists in the organization", http.StatusConflict)
+	ErrInvalidProviderConnectionName   = NewAPIError("invalid_provider_connection_name", "connection names must be 1-63 lowercase letters, digits, hyphens or underscores", http.StatusBadRequest)
+	ErrProviderConnectionInUse         = NewAPIError("provider_connection_in_use", "provider targets still use this connection; delete them first", http.StatusConflict)
+	ErrProviderConnectionNotGranted    = NewAPIError("provider_connection_not_granted", "the environment has not been granted use of this provider connection", http.StatusForbidden)
+	ErrProviderConnectionMismatch      = NewAPIError("provider_connection_mismatch", "the provider connection is for a different provider type", http.StatusBadRequest)
+	ErrProviderConnectionFailed        = NewAPIError("provider_connection_failed", "failed to access provider connections", http.StatusInternalServerError)
+	ErrInvalidProviderConnectionGrant  = NewAPIError("invalid_provider_connection_grant", "a grant needs exactly one secret group or environment of the connection's organization", http.StatusBadRequest)
+	ErrProviderConnectionGrantExists   = NewAPIError("provider_connection_grant_exists", "the secret group or environment already has a grant for this connection", http.StatusConflict)
+	ErrProviderConnectionGrantNotFound = NewAPIError("provider_connection_grant_not_found", "provider connection grant not found", http.StatusNotFound)
 
 	// Role binding listing errors
 	ErrNoRoleBindingsFound             = NewAPIError("no_role_bindings_found", "No role bindings found for this resource", http.StatusNotFound)
@@ -124,7 +124,9 @@ func (am *AuthMiddleware) isSpecialRoute(r *http.Request) bool {
 		strings.Contains(path, "/permissions/revoke") ||
 		strings.Contains(path, "/members") ||
 		strings.Contains(path, "/secrets") ||
-		strings.Contains(path, "/providers")
+		strings.Contains(path, "/providers") ||
+		strings.Contains(path, "/secret-reuse") ||
+		strings.Contains(path, "/provider-connections")
 }
 
 // trimAPIPrefix removes the API version prefix from the URL path
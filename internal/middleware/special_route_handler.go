@@ -53,9 +53,127 @@ func (srh *SpecialRouteHandler) HandleSpecialRoute(c *gin.Context, userID string
 		return srh.handleProviderRoutes(c, userID)
 	}
 
+	// Handle secret reuse scan and findings routes
+	if strings.Contains(path, "/secret-reuse") {
+		return srh.handleSecretReuseRoutes(c, userID)
+	}
+
+	// Handle organization provider connection routes
+	if strings.Contains(path, "/provider-connections") {
+		return srh.handleProviderConnectionRoutes(c, userID)
+	}
+
 	return fmt.Errorf("unknown special route: %s", path)
 }
 
+// handleSecretReuseRoutes handles authorization for organization-wide secret reuse routes
+func (srh *SpecialRouteHandler) handleSecretReuseRoutes(c *gin.Context, userID string) error {
+	logEntry := srh.logger.WithFields(logrus.Fields{
+		"operation": "secret_reuse_routes",
+		"user_id":   userID,
+		"method":    c.Request.Method,
+	})
+
+	path := srh.trimAPIPrefix(c.Request.URL.Path)
+	logEntry = logEntry.WithField("path", path)
+
+	// Path format: /organizations/{orgID}/secret-reuse/*
+	parts := strings.Split(path, "/")
+	if len(parts) < 4 {
+		logEntry.WithField("error", "invalid_path_format").Error("Invalid secret reuse route path")
+		return fmt.Errorf("invalid secret reuse route path: %s", path)
+	}
+
+	orgID := parts[2]
+	parentResource := fmt.Sprintf("/organizations/%s", orgID)
+
+	// Determine action based on HTTP method
+	var action string
+	switch c.Request.Method {
+	case "GET":
+		action = "read" // For viewing findings
+	default:
+		action = "update" // For running scans and suppressing findings
+	}
+
+	hasPermission, explanations, err := srh.enforcer.CheckPermissionEx(userID, action, parentResource)
+	if err != nil {
+		logEntry.WithFields(logrus.Fields{
+			"error":      "permission_check_failed",
+			"permission": action,
+			"resource":   parentResource,
+		}).Error("Failed to check permission")
+		return fmt.Errorf("failed to check permission: %v", err)
+	}
+
+	if !hasPermission {
+		logEntry.WithFields(logrus.Fields{
+			"permission": action,
+			"resource":   parentResource,
+			"result":     "denied",
+			"reason":     explanations,
+		}).Warn("User does not have required permission")
+		return fmt.Errorf("user %s does not have %s permission on %s", userID, action, parentResource)
+	}
+
+	return nil
+}
+
+// handleProviderConnectionRoutes handles authorization for organization provider connection routes.
+// Managing a connection needs provider config permissions on the organization; using one from an
+// environment is decided by the connection's grants instead.
+func (srh *SpecialRouteHandler) handleProviderConnectionRoutes(c *gin.Context, userID string) error {
+	logEntry := srh.logger.WithFields(logrus.Fields{
+		"operation": "provider_connection_routes",
+		"user_id":   userID,
+		"method":    c.Request.Method,
+	})
+
+	path := srh.trimAPIPrefix(c.Request.URL.Path)
+	logEntry = logEntry.WithField("path", path)
+
+	// Path format: /organizations/{orgID}/provider-connections/*
+	parts := strings.Split(path, "/")
+	if len(parts) < 4 {
+		logEntry.WithField("error", "invalid_path_format").Error("Invalid provider connection route path")
+		return fmt.Errorf("invalid provider connection route path: %s", path)
+	}
+
+	orgID := parts[2]
+	parentResource := fmt.Sprintf("/organizations/%s", orgID)
+
+	// Determine action based on HTTP method
+	var action string
+	switch c.Request.Method {
+	case "GET":
+		action = "view_provider_config" // For viewing connections and their grants
+	default:
+		action = "manage_provider_config" // For creating, rotating, deleting and granting connections
+	}
+
+	hasPermission, explanations, err := srh.enforcer.CheckPermissionEx(userID, action, parentResource)
+	if err != nil {
+		logEntry.WithFields(logrus.Fields{
+			"error":      "permission_check_failed",
+			"permission": action,
+			"resource":   parentResource,
+		}).Error("Failed to check permission")
+		return fmt.Errorf("failed to check permission: %v", err)
+	}
+
+	if !hasPermission {
+		logEntry.WithFields(logrus.Fields{
+			"permission": action,
+			"resource":   parentResource,
+			"result":     "denied",
+			"reason":     explanations,
+		}).Warn("User does not have required permission")
+		return fmt.Errorf("user %s does not have %s permission on %s", userID, action, parentResource)
+	}
+
+	return nil
+}
+
 // handleProviderRoutes handles authorization for provider routes
 func (srh *SpecialRouteHandler) handleProviderRoutes(c *gin.Context, userID string) error {
 	logEntry := srh.logger.WithFields(logrus.Fields{
@@ -184,16 +302,25 @@ func (srh *SpecialRouteHandler) handleSecretRoutes(c *gin.Context, userID string
 
 	// Extract organization ID, secret group ID, and environment ID from path
 	// Path format: /organizations/{orgID}/secret-groups/{secretGroupID}/environments/{envID}/secrets/*
+	// Secret group base secrets use: /organizations/{orgID}/secret-groups/{secretGroupID}/secrets/*
 	parts := strings.Split(path, "/")
-	if len(parts) < 7 {
+	if len(parts) < 6 || (parts[5] == "environments" && len(parts) < 7) {
 		logEntry.WithField("error", "invalid_path_format").Error("Invalid secret route path")
 		return fmt.Errorf("invalid secret route path: %s", path)
 	}
 
 	orgID := parts[2]
 	secretGroupID := parts[4]
-	envID := parts[6]
-	parentResource := fmt.Sprintf("/organizations/%s/secret-groups/%s/environments/%s", orgID, secretGroupID, envID)
+	parentResource := fmt.Sprintf("/organizations/%s/secret-groups/%s", orgID, secretGroupID)
+	if parts[5] == "environments" {
+		envID := parts[6]
+		parentResource = fmt.Sprintf("%s/environments/%s", parentResource, envID)
+	}
+
+	// Moving keys also writes to a second environment of the same secret group, so it is authorized on the group
+	if parts[5] == "environments" && strings.HasSuffix(strings.TrimSuffix(path, "/"), "/secrets/move") {
+		parentResource = fmt.Sprintf("/organizations/%s/secret-groups/%s", orgID, secretGroupID)
+	}
 
 	// Determine action based on HTTP method and specific route
 	var action string
@@ -204,6 +331,10 @@ func (srh *SpecialRouteHandler) handleSecretRoutes(c *gin.Context, userID string
 		// Check if this is a sync operation
 		if strings.Contains(path, "/sync") {
 			action = "sync" // For syncing secrets
+		} else if strings.HasSuffix(strings.TrimSuffix(path, "/"), "/secrets/drift") {
+			action = "sync" // Drift checks read the provider with the stored credentials
+		} else if strings.HasSuffix(strings.TrimSuffix(path, "/"), "/secrets/policy") {
+			action = "update" // For managing the secret group value policy
 		} else {
 			action = "create" // For creating new secret versions, rollback, etc.
 		}
@@ -49,6 +49,8 @@ func RegisterOrganizationRoutes(handler *OrganizationHandler,
 
 	secretgroup.RegisterSecretGroupRoutes(secretGroupHandler, orgGroup, environmentHandler, secretHandler, providerHandler, jwtMiddleware)
 	groups.RegisterUserGroupRoutes(userGroupHandler, orgGroup, jwtMiddleware)
+	secret.RegisterSecretReuseRoutes(secretHandler, orgGroup)
+	provider.RegisterProviderConnectionRoutes(providerHandler, orgGroup)
 	// Now register organization routes
 	orgGroup.GET("/by-name/:orgName", handler.GetOrganizationByName)
 
@@ -0,0 +1,586 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/sirupsen/logrus"
+)
+
+// Tag marking the Secrets Manager secrets written by Kavach
+const (
+	awsManagedByTag      = "managed-by"
+	awsManagedByTagValue = "kavach-backend"
+)
+
+// awsDefaultRoleSessionName names the sessions of assumed roles unless configured otherwise
+const awsDefaultRoleSessionName = "kavach-backend"
+
+// AWSSecretsManagerProvider implements ProviderSync for AWS Secrets Manager. Keys are stored either
+// as one secret each or together as a single JSON secret, depending on the storage mode.
+type AWSSecretsManagerProvider struct {
+	credentials AWSCredentials
+	config      AWSSecretsManagerConfig
+	logger      *logrus.Logger
+	client      *secretsmanager.Client
+	executor    *SyncExecutor
+	owner       SecretOwner // Environment and target recorded on created secrets
+}
+
+// NewAWSSecretsManagerProvider creates a new AWS Secrets Manager provider instance
+func NewAWSSecretsManagerProvider(credentials map[string]interface{}, config map[string]interface{}, logger *logrus.Logger) (*AWSSecretsManagerProvider, error) {
+	// Parse credentials
+	credBytes, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	var awsCreds AWSCredentials
+	if err := json.Unmarshal(credBytes, &awsCreds); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal AWS credentials: %w", err)
+	}
+
+	// Parse config
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var awsConfig AWSSecretsManagerConfig
+	if err := json.Unmarshal(configBytes, &awsConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal AWS Secrets Manager config: %w", err)
+	}
+	if awsConfig.StorageMode == "" {
+		awsConfig.StorageMode = AWSStorageModePerKey
+	}
+
+	// Retries are left to the sync executor, which shares rate limits across syncs
+	client := secretsmanager.New(secretsmanager.Options{
+		Region:      awsConfig.Region,
+		Credentials: newAWSCredentialsProvider(awsCreds, awsConfig.Region, awsConfig.Endpoint),
+		Retryer:     aws.NopRetryer{},
+	}, func(o *secretsmanager.Options) {
+		if awsConfig.Endpoint != "" {
+			o.BaseEndpoint = aws.String(awsConfig.Endpoint)
+		}
+	})
+
+	return &AWSSecretsManagerProvider{
+		credentials: awsCreds,
+		config:      awsConfig,
+		logger:      logger,
+		client:      client,
+		executor:    NewSyncExecutor(DefaultSyncExecutorConfig(), logger),
+	}, nil
+}
+
+// newAWSCredentialsProvider returns the static access keys, or the credentials of the configured
+// role assumed with them. A custom endpoint also serves STS, so a local stand-in covers both.
+func newAWSCredentialsProvider(creds AWSCredentials, region, endpoint string) aws.CredentialsProvider {
+	static := credentials.NewStaticCredentialsProvider(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken)
+	if creds.RoleARN == "" {
+		return static
+	}
+
+	stsClient := sts.New(sts.Options{
+		Region:      region,
+		Credentials: static,
+	}, func(o *sts.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+
+	sessionName := creds.RoleSessionName
+	if sessionName == "" {
+		sessionName = awsDefaultRoleSessionName
+	}
+	return aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, creds.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = sessionName
+		if creds.ExternalID != "" {
+			o.ExternalID = aws.String(creds.ExternalID)
+		}
+	}))
+}
+
+// Sync syncs secrets to AWS Secrets Manager
+func (a *AWSSecretsManagerProvider) Sync(ctx context.Context, secrets []Secret) ([]SyncResult, error) {
+	logEntry := a.logger.WithFields(logrus.Fields{
+		"provider":     "aws_secrets_manager",
+		"region":       a.config.Region,
+		"storage_mode": a.config.StorageMode,
+		"secret_count": len(secrets),
+	})
+
+	logEntry.Info("Starting AWS Secrets Manager sync")
+
+	var results []SyncResult
+	if a.config.StorageMode == AWSStorageModeJSON {
+		// All keys go into one document, so they are written together and share the outcome
+		values := make(map[string]string, len(secrets))
+		for _, secret := range secrets {
+			values[secret.Name] = secret.Value
+		}
+		results = a.updateJSONSecret(ctx, secrets, func(document map[string]string) {
+			for name, value := range values {
+				document[name] = value
+			}
+		})
+	} else {
+		results = a.executor.Run(ctx, ProviderAWSSecretsManager, a.config.RetryConfig, secrets, func(ctx context.Context, secret Secret) error {
+			return a.createOrUpdateSecret(ctx, a.RemoteName(secret.Name), secret.Value)
+		})
+	}
+
+	for _, result := range results {
+		if !result.Success {
+			logEntry.WithFields(logrus.Fields{
+				"secret_name": result.Name,
+				"error":       result.Error,
+			}).Error("Failed to sync secret to AWS Secrets Manager after all retry attempts")
+		}
+	}
+
+	logEntry.WithFields(logrus.Fields{
+		"synced_count": len(results),
+		"total_count":  len(secrets),
+	}).Info("Completed AWS Secrets Manager sync")
+
+	return results, nil
+}
+
+// Delete removes secrets from AWS Secrets Manager. Deleted secrets stay recoverable for the
+// default recovery window and are restored if synced again within it.
+func (a *AWSSecretsManagerProvider) Delete(ctx context.Context, names []string) ([]SyncResult, error) {
+	remoteNames := make([]string, len(names))
+	for i, name := range names {
+		remoteNames[i] = a.RemoteName(name)
+	}
+
+	results, err := a.deleteSecrets(ctx, remoteNames)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Name = names[i]
+	}
+	return results, nil
+}
+
+// RemoteName returns the name a secret is stored under in AWS Secrets Manager. In json mode
+// this is the key of the secret within the JSON document.
+func (a *AWSSecretsManagerProvider) RemoteName(name string) string {
+	if a.config.StorageMode == AWSStorageModeJSON {
+		return name
+	}
+	if a.config.Prefix != "" {
+		name = strings.TrimSuffix(a.config.Prefix, "/") + "/" + name
+	}
+	return sanitizeAWSSecretName(name)
+}
+
+// List returns the secrets under the configured prefix, or the keys of the JSON secret in json mode
+func (a *AWSSecretsManagerProvider) List(ctx context.Context) ([]RemoteSecret, error) {
+	if a.config.StorageMode == AWSStorageModeJSON {
+		document, _, err := a.readJSONSecret(ctx)
+		if err != nil {
+			return nil, err
+		}
+		secrets := make([]RemoteSecret, 0, len(document))
+		for name := range document {
+			secrets = append(secrets, RemoteSecret{Name: name})
+		}
+		sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+		return secrets, nil
+	}
+
+	entries, err := a.listSecretEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]RemoteSecret, len(entries))
+	for i, entry := range entries {
+		secrets[i] = RemoteSecret{Name: aws.ToString(entry.Name), UpdatedAt: entry.LastChangedDate}
+	}
+	return secrets, nil
+}
+
+// Read returns the current value of each named secret
+func (a *AWSSecretsManagerProvider) Read(ctx context.Context, remoteNames []string) ([]RemoteSecret, error) {
+	if a.config.StorageMode == AWSStorageModeJSON {
+		document, _, err := a.readJSONSecret(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var secrets []RemoteSecret
+		for _, name := range remoteNames {
+			if value, ok := document[name]; ok {
+				secrets = append(secrets, RemoteSecret{Name: name, Value: value, HasValue: true})
+			}
+		}
+		return secrets, nil
+	}
+
+	var secrets []RemoteSecret
+	for _, name := range remoteNames {
+		resp, err := a.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(name)})
+		if err != nil {
+			var notFound *types.ResourceNotFoundException
+			if errors.As(err, &notFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read AWS secret '%s': %w", name, err)
+		}
+
+		secrets = append(secrets, RemoteSecret{
+			Name:      name,
+			Value:     aws.ToString(resp.SecretString),
+			HasValue:  true,
+			UpdatedAt: resp.CreatedDate,
+		})
+	}
+
+	return secrets, nil
+}
+
+// SetOwner sets the environment and target recorded on created secrets and required for pruning
+func (a *AWSSecretsManagerProvider) SetOwner(owner SecretOwner) {
+	a.owner = owner
+}
+
+// PruneCandidates returns the secrets under the configured prefix tagged as created by Kavach for the
+// owning environment and target that are not among the given secret names. In json mode the whole
+// secret belongs to the target that created it, so every other key in it is a candidate.
+func (a *AWSSecretsManagerProvider) PruneCandidates(ctx context.Context, keep []string) ([]string, error) {
+	if !a.owner.IsSet() {
+		return nil, errSecretOwnerUnset
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		kept[a.RemoteName(name)] = true
+	}
+
+	var candidates []string
+	if a.config.StorageMode == AWSStorageModeJSON {
+		owned, err := a.ownsJSONSecret(ctx)
+		if err != nil || !owned {
+			return nil, err
+		}
+		document, _, err := a.readJSONSecret(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for name := range document {
+			if !kept[name] {
+				candidates = append(candidates, name)
+			}
+		}
+		sort.Strings(candidates)
+		return candidates, nil
+	}
+
+	entries, err := a.listSecretEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := aws.ToString(entry.Name)
+		if !kept[name] && a.ownsSecret(entry.Tags) {
+			candidates = append(candidates, name)
+		}
+	}
+	return candidates, nil
+}
+
+// Prune deletes the given secrets, or the given keys of the JSON secret in json mode
+func (a *AWSSecretsManagerProvider) Prune(ctx context.Context, remoteNames []string) ([]SyncResult, error) {
+	results, err := a.deleteSecrets(ctx, remoteNames)
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"provider":     "aws_secrets_manager",
+		"region":       a.config.Region,
+		"pruned_count": len(results),
+	}).Info("Completed AWS Secrets Manager prune")
+	return results, nil
+}
+
+// ValidateCredentials validates AWS credentials by making a test API call
+func (a *AWSSecretsManagerProvider) ValidateCredentials(ctx context.Context) error {
+	logEntry := a.logger.WithFields(logrus.Fields{
+		"provider": "aws_secrets_manager",
+		"region":   a.config.Region,
+	})
+
+	logEntry.Info("Validating AWS Secrets Manager credentials")
+
+	_, err := a.client.ListSecrets(ctx, &secretsmanager.ListSecretsInput{MaxResults: aws.Int32(1)})
+	if err != nil {
+		return fmt.Errorf("failed to validate AWS Secrets Manager credentials: %w", err)
+	}
+
+	logEntry.Info("AWS Secrets Manager credentials validated successfully")
+	return nil
+}
+
+// GetProviderName returns the provider name
+func (a *AWSSecretsManagerProvider) GetProviderName() string {
+	return "aws_secrets_manager"
+}
+
+// createOrUpdateSecret stores a new value for a secret, creating the secret if it does not exist
+// and restoring it if it is scheduled for deletion
+func (a *AWSSecretsManagerProvider) createOrUpdateSecret(ctx context.Context, secretName, secretValue string) error {
+	logEntry := a.logger.WithField("secret_name", secretName)
+
+	_, err := a.client.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(secretName),
+		SecretString: aws.String(secretValue),
+	})
+	if err == nil {
+		logEntry.Info("Successfully updated AWS secret")
+		return nil
+	}
+
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return a.createSecret(ctx, secretName, secretValue)
+	}
+
+	// Secrets deleted earlier, for instance by a rename, are kept until their recovery window ends
+	var invalidRequest *types.InvalidRequestException
+	if errors.As(err, &invalidRequest) && strings.Contains(strings.ToLower(invalidRequest.ErrorMessage()), "deletion") {
+		logEntry.Info("Restoring AWS secret scheduled for deletion")
+		if _, err := a.client.RestoreSecret(ctx, &secretsmanager.RestoreSecretInput{SecretId: aws.String(secretName)}); err != nil {
+			return fmt.Errorf("failed to restore secret '%s': %w", secretName, err)
+		}
+		if _, err := a.client.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+			SecretId:     aws.String(secretName),
+			SecretString: aws.String(secretValue),
+		}); err != nil {
+			return fmt.Errorf("failed to update secret '%s': %w", secretName, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("failed to update secret '%s': %w", secretName, err)
+}
+
+// createSecret creates a secret tagged as managed by Kavach, encrypted with the configured KMS key
+func (a *AWSSecretsManagerProvider) createSecret(ctx context.Context, secretName, secretValue string) error {
+	input := &secretsmanager.CreateSecretInput{
+		Name:         aws.String(secretName),
+		SecretString: aws.String(secretValue),
+		Tags:         a.secretTags(),
+	}
+	if a.config.KMSKeyID != "" {
+		input.KmsKeyId = aws.String(a.config.KMSKeyID)
+	}
+
+	if _, err := a.client.CreateSecret(ctx, input); err != nil {
+		return fmt.Errorf("failed to create secret '%s': %w", secretName, err)
+	}
+
+	a.logger.WithField("secret_name", secretName).Info("Successfully created AWS secret")
+	return nil
+}
+
+// secretTags returns the configured tags of new secrets with the managed-by tag and the owner
+func (a *AWSSecretsManagerProvider) secretTags() []types.Tag {
+	markers := a.owner.Markers()
+	keys := make([]string, 0, len(a.config.Tags))
+	for key := range a.config.Tags {
+		if _, marker := markers[key]; key != awsManagedByTag && !marker {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	tags := []types.Tag{{Key: aws.String(awsManagedByTag), Value: aws.String(awsManagedByTagValue)}}
+	if a.owner.IsSet() {
+		tags = append(tags,
+			types.Tag{Key: aws.String(ownerEnvironmentKey), Value: aws.String(a.owner.EnvironmentID)},
+			types.Tag{Key: aws.String(ownerTargetKey), Value: aws.String(a.owner.Target)})
+	}
+	for _, key := range keys {
+		tags = append(tags, types.Tag{Key: aws.String(key), Value: aws.String(a.config.Tags[key])})
+	}
+	return tags
+}
+
+// deleteSecrets deletes secrets by remote name, or keys of the JSON secret in json mode.
+// Secrets that no longer exist are reported as successfully removed.
+func (a *AWSSecretsManagerProvider) deleteSecrets(ctx context.Context, remoteNames []string) ([]SyncResult, error) {
+	logEntry := a.logger.WithFields(logrus.Fields{
+		"provider":     "aws_secrets_manager",
+		"region":       a.config.Region,
+		"secret_count": len(remoteNames),
+	})
+
+	if a.config.StorageMode == AWSStorageModeJSON {
+		removed := make([]Secret, len(remoteNames))
+		for i, name := range remoteNames {
+			removed[i] = Secret{Name: name}
+		}
+		return a.updateJSONSecret(ctx, removed, func(document map[string]string) {
+			for _, name := range remoteNames {
+				delete(document, name)
+			}
+		}), nil
+	}
+
+	results := make([]SyncResult, 0, len(remoteNames))
+	for _, name := range remoteNames {
+		result := SyncResult{Name: name, Success: true}
+
+		_, err := a.client.DeleteSecret(ctx, &secretsmanager.DeleteSecretInput{SecretId: aws.String(name)})
+		var notFound *types.ResourceNotFoundException
+		if err != nil && !errors.As(err, &notFound) {
+			result.Success = false
+			result.Error = fmt.Sprintf("failed to delete secret: %v", err)
+			logEntry.WithFields(logrus.Fields{
+				"secret_name": name,
+				"error":       err.Error(),
+			}).Error("Failed to delete secret from AWS Secrets Manager")
+		}
+
+		results = append(results, result)
+	}
+
+	logEntry.Info("Completed AWS Secrets Manager removal")
+	return results, nil
+}
+
+// updateJSONSecret applies a change to the document of the JSON secret and writes it back once.
+// Every given secret shares the outcome of that write.
+func (a *AWSSecretsManagerProvider) updateJSONSecret(ctx context.Context, secrets []Secret, change func(document map[string]string)) []SyncResult {
+	document := []Secret{{Name: a.config.SecretName}}
+	outcome := a.executor.Run(ctx, ProviderAWSSecretsManager, a.config.RetryConfig, document, func(ctx context.Context, _ Secret) error {
+		// Read on every attempt so a retry does not overwrite keys written in between
+		current, exists, err := a.readJSONSecret(ctx)
+		if err != nil {
+			return err
+		}
+		change(current)
+
+		value, err := json.Marshal(current)
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON secret: %w", err)
+		}
+		if !exists {
+			return a.createSecret(ctx, a.config.SecretName, string(value))
+		}
+		return a.createOrUpdateSecret(ctx, a.config.SecretName, string(value))
+	})
+
+	results := make([]SyncResult, len(secrets))
+	for i, secret := range secrets {
+		results[i] = SyncResult{Name: secret.Name, Success: outcome[0].Success, Error: outcome[0].Error}
+	}
+	return results
+}
+
+// readJSONSecret returns the keys and values of the JSON secret, and whether the secret exists
+func (a *AWSSecretsManagerProvider) readJSONSecret(ctx context.Context) (map[string]string, bool, error) {
+	resp, err := a.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(a.config.SecretName)})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return map[string]string{}, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to read AWS secret '%s': %w", a.config.SecretName, err)
+	}
+
+	document := map[string]string{}
+	if value := aws.ToString(resp.SecretString); value != "" {
+		if err := json.Unmarshal([]byte(value), &document); err != nil {
+			return nil, true, fmt.Errorf("AWS secret '%s' is not a JSON object of string values: %w", a.config.SecretName, err)
+		}
+	}
+	return document, true, nil
+}
+
+// listSecretEntries returns the secrets under the configured prefix
+func (a *AWSSecretsManagerProvider) listSecretEntries(ctx context.Context) ([]types.SecretListEntry, error) {
+	input := &secretsmanager.ListSecretsInput{}
+	prefix := ""
+	if a.config.Prefix != "" {
+		prefix = sanitizeAWSSecretName(strings.TrimSuffix(a.config.Prefix, "/") + "/")
+		input.Filters = []types.Filter{{Key: types.FilterNameStringTypeName, Values: []string{prefix}}}
+	}
+
+	var entries []types.SecretListEntry
+	paginator := secretsmanager.NewListSecretsPaginator(a.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list AWS secrets: %w", err)
+		}
+
+		// The name filter matches prefixes of any word in the name, so check the prefix again
+		for _, entry := range page.SecretList {
+			if strings.HasPrefix(aws.ToString(entry.Name), prefix) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// ownsJSONSecret reports whether the JSON secret was created by Kavach for the owning environment and target
+func (a *AWSSecretsManagerProvider) ownsJSONSecret(ctx context.Context) (bool, error) {
+	resp, err := a.client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{SecretId: aws.String(a.config.SecretName)})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to describe AWS secret '%s': %w", a.config.SecretName, err)
+	}
+	return a.ownsSecret(resp.Tags), nil
+}
+
+// ownsSecret reports whether the tags mark a secret as created by Kavach for the owning environment and target
+func (a *AWSSecretsManagerProvider) ownsSecret(tags []types.Tag) bool {
+	values := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		values[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return values[awsManagedByTag] == awsManagedByTagValue && a.owner.Owns(values)
+}
+
+// sanitizeAWSSecretName sanitizes the secret name for AWS, which allows letters, digits and /_+=.@-
+func sanitizeAWSSecretName(name string) string {
+	result := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune("/_+=.@-", r):
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+
+	// Limit length to 512 characters (AWS Secrets Manager limit)
+	if len(result) > 512 {
+		result = result[:512]
+	}
+
+	return result
+}
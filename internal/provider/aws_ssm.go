@@ -0,0 +1,391 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/sirupsen/logrus"
+)
+
+// Largest parameter values of each tier. Intelligent-Tiering moves larger values to the advanced tier.
+const (
+	ssmStandardTierMaxBytes = 4 * 1024
+	ssmAdvancedTierMaxBytes = 8 * 1024
+)
+
+// ssmGetParametersBatchSize is the most parameters GetParameters returns per call
+const ssmGetParametersBatchSize = 10
+
+// AWSSSMProvider implements ProviderSync for AWS SSM Parameter Store. Each key is stored as a
+// SecureString parameter under the configured path.
+type AWSSSMProvider struct {
+	credentials AWSCredentials
+	config      AWSSSMConfig
+	logger      *logrus.Logger
+	client      *ssm.Client
+	executor    *SyncExecutor
+	owner       SecretOwner // Environment and target recorded on created parameters
+}
+
+// NewAWSSSMProvider creates a new AWS SSM Parameter Store provider instance
+func NewAWSSSMProvider(credentials map[string]interface{}, config map[string]interface{}, logger *logrus.Logger) (*AWSSSMProvider, error) {
+	// Parse credentials
+	credBytes, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	var awsCreds AWSCredentials
+	if err := json.Unmarshal(credBytes, &awsCreds); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal AWS credentials: %w", err)
+	}
+
+	// Parse config
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var ssmConfig AWSSSMConfig
+	if err := json.Unmarshal(configBytes, &ssmConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal AWS SSM config: %w", err)
+	}
+	if ssmConfig.Tier == "" {
+		ssmConfig.Tier = string(types.ParameterTierStandard)
+	}
+	ssmConfig.Path = "/" + strings.Trim(ssmConfig.Path, "/")
+
+	// Retries are left to the sync executor, which shares rate limits across syncs
+	client := ssm.New(ssm.Options{
+		Region:      ssmConfig.Region,
+		Credentials: newAWSCredentialsProvider(awsCreds, ssmConfig.Region, ssmConfig.Endpoint),
+		Retryer:     aws.NopRetryer{},
+	}, func(o *ssm.Options) {
+		if ssmConfig.Endpoint != "" {
+			o.BaseEndpoint = aws.String(ssmConfig.Endpoint)
+		}
+	})
+
+	return &AWSSSMProvider{
+		credentials: awsCreds,
+		config:      ssmConfig,
+		logger:      logger,
+		client:      client,
+		executor:    NewSyncExecutor(DefaultSyncExecutorConfig(), logger),
+	}, nil
+}
+
+// Sync syncs secrets to AWS SSM Parameter Store
+func (a *AWSSSMProvider) Sync(ctx context.Context, secrets []Secret) ([]SyncResult, error) {
+	logEntry := a.logger.WithFields(logrus.Fields{
+		"provider":     "aws_ssm",
+		"region":       a.config.Region,
+		"path":         a.config.Path,
+		"secret_count": len(secrets),
+	})
+
+	logEntry.Info("Starting AWS SSM parameters sync")
+
+	results := a.executor.Run(ctx, ProviderAWSSSM, a.config.RetryConfig, secrets, func(ctx context.Context, secret Secret) error {
+		return a.putParameter(ctx, a.RemoteName(secret.Name), secret.Value)
+	})
+
+	for _, result := range results {
+		if !result.Success {
+			logEntry.WithFields(logrus.Fields{
+				"secret_name": result.Name,
+				"error":       result.Error,
+			}).Error("Failed to sync secret to AWS SSM after all retry attempts")
+		}
+	}
+
+	logEntry.WithFields(logrus.Fields{
+		"synced_count": len(results),
+		"total_count":  len(secrets),
+	}).Info("Completed AWS SSM parameters sync")
+
+	return results, nil
+}
+
+// Delete removes parameters from AWS SSM Parameter Store
+func (a *AWSSSMProvider) Delete(ctx context.Context, names []string) ([]SyncResult, error) {
+	remoteNames := make([]string, len(names))
+	for i, name := range names {
+		remoteNames[i] = a.RemoteName(name)
+	}
+
+	results := a.deleteParameters(ctx, remoteNames)
+	for i := range results {
+		results[i].Name = names[i]
+	}
+	return results, nil
+}
+
+// RemoteName returns the parameter name a secret is stored under
+func (a *AWSSSMProvider) RemoteName(name string) string {
+	return strings.TrimSuffix(a.config.Path, "/") + "/" + sanitizeSSMParameterName(name)
+}
+
+// List returns the parameters directly under the configured path
+func (a *AWSSSMProvider) List(ctx context.Context) ([]RemoteSecret, error) {
+	parameters, err := a.describeParameters(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]RemoteSecret, len(parameters))
+	for i, parameter := range parameters {
+		secrets[i] = RemoteSecret{Name: aws.ToString(parameter.Name), UpdatedAt: parameter.LastModifiedDate}
+	}
+	return secrets, nil
+}
+
+// Read returns the decrypted value of each named parameter
+func (a *AWSSSMProvider) Read(ctx context.Context, remoteNames []string) ([]RemoteSecret, error) {
+	var secrets []RemoteSecret
+	for start := 0; start < len(remoteNames); start += ssmGetParametersBatchSize {
+		end := min(start+ssmGetParametersBatchSize, len(remoteNames))
+
+		// Parameters that do not exist are reported as invalid and left out
+		resp, err := a.client.GetParameters(ctx, &ssm.GetParametersInput{
+			Names:          remoteNames[start:end],
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read AWS SSM parameters: %w", err)
+		}
+
+		for _, parameter := range resp.Parameters {
+			secrets = append(secrets, RemoteSecret{
+				Name:      aws.ToString(parameter.Name),
+				Value:     aws.ToString(parameter.Value),
+				HasValue:  true,
+				UpdatedAt: parameter.LastModifiedDate,
+			})
+		}
+	}
+
+	return secrets, nil
+}
+
+// SetOwner sets the environment and target recorded on created parameters and required for pruning
+func (a *AWSSSMProvider) SetOwner(owner SecretOwner) {
+	a.owner = owner
+}
+
+// PruneCandidates returns the parameters under the configured path tagged as created by Kavach for
+// the owning environment and target that are not among the given secret names
+func (a *AWSSSMProvider) PruneCandidates(ctx context.Context, keep []string) ([]string, error) {
+	if !a.owner.IsSet() {
+		return nil, errSecretOwnerUnset
+	}
+
+	tags := a.owner.Markers()
+	tags[awsManagedByTag] = awsManagedByTagValue
+	managed, err := a.describeParameters(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		kept[a.RemoteName(name)] = true
+	}
+
+	var candidates []string
+	for _, parameter := range managed {
+		if name := aws.ToString(parameter.Name); !kept[name] {
+			candidates = append(candidates, name)
+		}
+	}
+	return candidates, nil
+}
+
+// Prune deletes the given parameters
+func (a *AWSSSMProvider) Prune(ctx context.Context, remoteNames []string) ([]SyncResult, error) {
+	results := a.deleteParameters(ctx, remoteNames)
+
+	a.logger.WithFields(logrus.Fields{
+		"provider":     "aws_ssm",
+		"path":         a.config.Path,
+		"pruned_count": len(results),
+	}).Info("Completed AWS SSM parameters prune")
+	return results, nil
+}
+
+// ValidateCredentials validates AWS credentials by making a test API call
+func (a *AWSSSMProvider) ValidateCredentials(ctx context.Context) error {
+	logEntry := a.logger.WithFields(logrus.Fields{
+		"provider": "aws_ssm",
+		"region":   a.config.Region,
+		"path":     a.config.Path,
+	})
+
+	logEntry.Info("Validating AWS SSM credentials")
+
+	_, err := a.client.DescribeParameters(ctx, &ssm.DescribeParametersInput{MaxResults: aws.Int32(1)})
+	if err != nil {
+		return fmt.Errorf("failed to validate AWS SSM credentials: %w", err)
+	}
+
+	logEntry.Info("AWS SSM credentials validated successfully")
+	return nil
+}
+
+// GetProviderName returns the provider name
+func (a *AWSSSMProvider) GetProviderName() string {
+	return "aws_ssm"
+}
+
+// putParameter writes a SecureString parameter. New parameters are created with the configured
+// tags; AWS does not accept tags when overwriting, so existing parameters keep theirs.
+func (a *AWSSSMProvider) putParameter(ctx context.Context, parameterName, value string) error {
+	if maxBytes := a.maxValueBytes(); len(value) > maxBytes {
+		return fmt.Errorf("parameter '%s' is %d bytes, over the %d byte limit of the %s tier: %w",
+			parameterName, len(value), maxBytes, a.config.Tier, appErrors.ErrSSMParameterTooLarge)
+	}
+
+	input := &ssm.PutParameterInput{
+		Name:  aws.String(parameterName),
+		Value: aws.String(value),
+		Type:  types.ParameterTypeSecureString,
+		Tier:  types.ParameterTier(a.config.Tier),
+		Tags:  a.parameterTags(),
+	}
+	if a.config.KMSKeyID != "" {
+		input.KeyId = aws.String(a.config.KMSKeyID)
+	}
+
+	_, err := a.client.PutParameter(ctx, input)
+	var exists *types.ParameterAlreadyExists
+	if errors.As(err, &exists) {
+		input.Tags = nil
+		input.Overwrite = aws.Bool(true)
+		_, err = a.client.PutParameter(ctx, input)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to put parameter '%s': %w", parameterName, err)
+	}
+
+	a.logger.WithField("parameter_name", parameterName).Info("Successfully put AWS SSM parameter")
+	return nil
+}
+
+// maxValueBytes returns the largest value the configured tier can store
+func (a *AWSSSMProvider) maxValueBytes() int {
+	if a.config.Tier == string(types.ParameterTierStandard) {
+		return ssmStandardTierMaxBytes
+	}
+	return ssmAdvancedTierMaxBytes
+}
+
+// parameterTags returns the configured tags of new parameters with the managed-by tag and the owner
+func (a *AWSSSMProvider) parameterTags() []types.Tag {
+	markers := a.owner.Markers()
+	tags := []types.Tag{{Key: aws.String(awsManagedByTag), Value: aws.String(awsManagedByTagValue)}}
+	if a.owner.IsSet() {
+		tags = append(tags,
+			types.Tag{Key: aws.String(ownerEnvironmentKey), Value: aws.String(a.owner.EnvironmentID)},
+			types.Tag{Key: aws.String(ownerTargetKey), Value: aws.String(a.owner.Target)})
+	}
+	for key, value := range a.config.Tags {
+		if _, marker := markers[key]; key != awsManagedByTag && !marker {
+			tags = append(tags, types.Tag{Key: aws.String(key), Value: aws.String(value)})
+		}
+	}
+	return tags
+}
+
+// deleteParameters deletes parameters by name. Parameters that no longer exist are reported
+// as successfully removed.
+func (a *AWSSSMProvider) deleteParameters(ctx context.Context, parameterNames []string) []SyncResult {
+	logEntry := a.logger.WithFields(logrus.Fields{
+		"provider":     "aws_ssm",
+		"path":         a.config.Path,
+		"secret_count": len(parameterNames),
+	})
+
+	results := make([]SyncResult, 0, len(parameterNames))
+	for _, name := range parameterNames {
+		result := SyncResult{Name: name, Success: true}
+
+		_, err := a.client.DeleteParameter(ctx, &ssm.DeleteParameterInput{Name: aws.String(name)})
+		var notFound *types.ParameterNotFound
+		if err != nil && !errors.As(err, &notFound) {
+			result.Success = false
+			result.Error = fmt.Sprintf("failed to delete parameter: %v", err)
+			logEntry.WithFields(logrus.Fields{
+				"parameter_name": name,
+				"error":          err.Error(),
+			}).Error("Failed to delete parameter from AWS SSM")
+		}
+
+		results = append(results, result)
+	}
+
+	logEntry.Info("Completed AWS SSM parameters removal")
+	return results
+}
+
+// describeParameters returns the metadata of the parameters directly under the configured path,
+// optionally only those carrying every given tag
+func (a *AWSSSMProvider) describeParameters(ctx context.Context, tags map[string]string) ([]types.ParameterMetadata, error) {
+	filters := []types.ParameterStringFilter{{
+		Key:    aws.String("Path"),
+		Option: aws.String("OneLevel"),
+		Values: []string{a.config.Path},
+	}}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		filters = append(filters, types.ParameterStringFilter{
+			Key:    aws.String("tag:" + key),
+			Values: []string{tags[key]},
+		})
+	}
+
+	var parameters []types.ParameterMetadata
+	paginator := ssm.NewDescribeParametersPaginator(a.client, &ssm.DescribeParametersInput{ParameterFilters: filters})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list AWS SSM parameters: %w", err)
+		}
+		parameters = append(parameters, page.Parameters...)
+	}
+
+	return parameters, nil
+}
+
+// sanitizeSSMParameterName sanitizes a key for use as the last segment of a parameter name,
+// which allows letters, digits and _.-
+func sanitizeSSMParameterName(name string) string {
+	result := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune("_.-", r):
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+
+	// Limit length to 1011 characters (AWS SSM parameter name limit)
+	if len(result) > 1011 {
+		result = result[:1011]
+	}
+
+	return result
+}
@@ -3,21 +3,36 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
-	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/keyvault/azsecrets"
 	"github.com/sirupsen/logrus"
 )
 
+// Tag marking the Key Vault secrets written by Kavach
+const (
+	azureManagedByTag      = "managed-by"
+	azureManagedByTagValue = "kavach-backend"
+)
+
+// azureFederatedAudience is the audience Azure AD expects in tokens presented through a federated identity credential
+const azureFederatedAudience = "api://AzureADTokenExchange"
+
 // AzureProvider implements ProviderSync for Azure Key Vault
 type AzureProvider struct {
 	credentials AzureCredentials
 	config      AzureConfig
 	logger      *logrus.Logger
 	client      *azsecrets.Client
+	executor    *SyncExecutor
+	issuer      *OIDCIssuer      // Mints workload tokens for federated auth
+	identity    WorkloadIdentity // Environment and provider the workload tokens are minted for
+	owner       SecretOwner      // Environment and target recorded on written secrets
 }
 
 // NewAzureProvider creates a new Azure provider instance
@@ -44,13 +59,19 @@ func NewAzureProvider(credentials map[string]interface{}, config map[string]inte
 		return nil, fmt.Errorf("failed to unmarshal Azure config: %w", err)
 	}
 
+	if azureCreds.AuthMethod == "" {
+		azureCreds.AuthMethod = AzureAuthClientSecret
+	}
+
+	provider := &AzureProvider{
+		credentials: azureCreds,
+		config:      azureConfig,
+		logger:      logger,
+		executor:    NewSyncExecutor(DefaultSyncExecutorConfig(), logger),
+	}
+
 	// Create Azure credential
-	cred, err := azidentity.NewClientSecretCredential(
-		azureCreds.TenantID,
-		azureCreds.ClientID,
-		azureCreds.ClientSecret,
-		nil,
-	)
+	cred, err := provider.newCredential(azcore.ClientOptions{}, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
 	}
@@ -62,12 +83,32 @@ func NewAzureProvider(credentials map[string]interface{}, config map[string]inte
 		return nil, fmt.Errorf("failed to create Key Vault client: %w", err)
 	}
 
-	return &AzureProvider{
-		credentials: azureCreds,
-		config:      azureConfig,
-		logger:      logger,
-		client:      client,
-	}, nil
+	provider.client = client
+	return provider, nil
+}
+
+// SetWorkloadIdentity sets the environment and provider the workload tokens are minted for
+func (a *AzureProvider) SetWorkloadIdentity(identity WorkloadIdentity) {
+	a.identity = identity
+}
+
+// newCredential returns the Azure AD credential for the configured auth method. A federated credential
+// presents a freshly minted workload token as its client assertion whenever Azure AD asks for one.
+// Instance discovery only needs to be disabled for authorities Azure AD does not know, such as a local stand-in.
+func (a *AzureProvider) newCredential(options azcore.ClientOptions, disableInstanceDiscovery bool) (azcore.TokenCredential, error) {
+	switch a.credentials.AuthMethod {
+	case AzureAuthClientSecret:
+		return azidentity.NewClientSecretCredential(a.credentials.TenantID, a.credentials.ClientID, a.credentials.ClientSecret,
+			&azidentity.ClientSecretCredentialOptions{ClientOptions: options, DisableInstanceDiscovery: disableInstanceDiscovery})
+	case AzureAuthFederated:
+		return azidentity.NewClientAssertionCredential(a.credentials.TenantID, a.credentials.ClientID,
+			func(context.Context) (string, error) {
+				return a.issuer.MintWorkloadToken(a.identity, azureFederatedAudience)
+			},
+			&azidentity.ClientAssertionCredentialOptions{ClientOptions: options, DisableInstanceDiscovery: disableInstanceDiscovery})
+	default:
+		return nil, fmt.Errorf("unsupported Azure auth method: %s", a.credentials.AuthMethod)
+	}
 }
 
 // Sync syncs secrets to Azure Key Vault
@@ -82,28 +123,17 @@ func (a *AzureProvider) Sync(ctx context.Context, secrets []Secret) ([]SyncResul
 
 	logEntry.Info("Starting Azure secrets sync")
 
-	var results []SyncResult
-
-	for _, secret := range secrets {
-		result := SyncResult{
-			Name:    secret.Name,
-			Success: false,
-		}
+	results := a.executor.Run(ctx, ProviderAzure, a.config.RetryConfig, secrets, func(ctx context.Context, secret Secret) error {
+		return a.createOrUpdateSecret(ctx, secret.Name, secret.Value)
+	})
 
-		// Use retry wrapper for creating/updating secret
-		err := a.retryCreateSecret(ctx, secret.Name, secret.Value)
-		if err != nil {
-			result.Error = err.Error()
+	for _, result := range results {
+		if !result.Success {
 			logEntry.WithFields(logrus.Fields{
-				"secret_name": secret.Name,
-				"error":       err.Error(),
+				"secret_name": result.Name,
+				"error":       result.Error,
 			}).Error("Failed to sync secret to Azure after all retry attempts")
-		} else {
-			result.Success = true
-			logEntry.WithField("secret_name", secret.Name).Info("Successfully synced secret to Azure")
 		}
-
-		results = append(results, result)
 	}
 
 	logEntry.WithFields(logrus.Fields{
@@ -114,75 +144,264 @@ func (a *AzureProvider) Sync(ctx context.Context, secrets []Secret) ([]SyncResul
 	return results, nil
 }
 
-// retryCreateSecret wraps the createSecret function with retry logic
-func (a *AzureProvider) retryCreateSecret(ctx context.Context, secretName, secretValue string) error {
+// Delete removes secrets from Azure Key Vault. Deleted secrets stay recoverable
+// for the vault's soft-delete retention period.
+func (a *AzureProvider) Delete(ctx context.Context, names []string) ([]SyncResult, error) {
 	logEntry := a.logger.WithFields(logrus.Fields{
-		"secret_name": secretName,
-		"max_retries": a.config.RetryConfig.MaxRetries,
+		"provider":     "azure",
+		"key_vault":    a.config.KeyVaultName,
+		"secret_count": len(names),
 	})
 
-	// Set default retry configuration if not provided
-	if a.config.RetryConfig.MaxRetries == 0 {
-		a.config.RetryConfig = RetryConfig{
-			MaxRetries:  3,
-			RetryDelay:  2 * time.Second,
-			MaxDelay:    30 * time.Second,
-			BackoffType: "exponential",
+	logEntry.Info("Starting Azure secrets removal")
+
+	var results []SyncResult
+	for _, name := range names {
+		result := SyncResult{Name: name, Success: true}
+
+		_, err := a.client.DeleteSecret(ctx, a.fullSecretName(name), nil)
+		var respErr *azcore.ResponseError
+		if err != nil && !(errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound) {
+			result.Success = false
+			result.Error = fmt.Sprintf("failed to delete secret: %v", err)
+			logEntry.WithFields(logrus.Fields{
+				"secret_name": name,
+				"error":       err.Error(),
+			}).Error("Failed to delete secret from Azure")
 		}
+
+		results = append(results, result)
 	}
 
-	var lastErr error
-	for attempt := 1; attempt <= a.config.RetryConfig.MaxRetries; attempt++ {
-		logEntry.WithField("attempt", attempt).Info("Attempting to create/update secret")
+	logEntry.Info("Completed Azure secrets removal")
+	return results, nil
+}
 
-		err := a.createOrUpdateSecret(ctx, secretName, secretValue)
-		if err != nil {
-			lastErr = err
+// RemoteName returns the name a secret is stored under in Azure Key Vault
+func (a *AzureProvider) RemoteName(name string) string {
+	return a.fullSecretName(name)
+}
+
+// List returns the secrets of the Key Vault, limited to the configured prefix when one is set
+func (a *AzureProvider) List(ctx context.Context) ([]RemoteSecret, error) {
+	items, err := a.listSecretItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]RemoteSecret, len(items))
+	for i, item := range items {
+		secrets[i] = RemoteSecret{Name: item.ID.Name()}
+		if item.Attributes != nil {
+			secrets[i].UpdatedAt = item.Attributes.Updated
+		}
+	}
+	return secrets, nil
+}
+
+// SetOwner sets the environment and target recorded on written secrets and required for pruning
+func (a *AzureProvider) SetOwner(owner SecretOwner) {
+	a.owner = owner
+}
+
+// PruneCandidates returns the secrets under the configured prefix tagged as written by Kavach for
+// the owning environment and target that are not among the given secret names
+func (a *AzureProvider) PruneCandidates(ctx context.Context, keep []string) ([]string, error) {
+	if !a.owner.IsSet() {
+		return nil, errSecretOwnerUnset
+	}
+
+	items, err := a.listSecretItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		kept[a.RemoteName(name)] = true
+	}
+
+	var candidates []string
+	for _, item := range items {
+		name := item.ID.Name()
+		tags := make(map[string]string, len(item.Tags))
+		for key, value := range item.Tags {
+			if value != nil {
+				tags[key] = *value
+			}
+		}
+		if kept[name] || tags[azureManagedByTag] != azureManagedByTagValue || !a.owner.Owns(tags) {
+			continue
+		}
+		candidates = append(candidates, name)
+	}
+	return candidates, nil
+}
+
+// Prune deletes the given secrets from the Key Vault
+func (a *AzureProvider) Prune(ctx context.Context, remoteNames []string) ([]SyncResult, error) {
+	logEntry := a.logger.WithFields(logrus.Fields{
+		"provider":  "azure",
+		"key_vault": a.config.KeyVaultName,
+	})
+
+	var results []SyncResult
+	for _, name := range remoteNames {
+		result := SyncResult{Name: name, Success: true}
+		_, err := a.client.DeleteSecret(ctx, name, nil)
+		var respErr *azcore.ResponseError
+		if err != nil && !(errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound) {
+			result.Success = false
+			result.Error = fmt.Sprintf("failed to delete secret: %v", err)
 			logEntry.WithFields(logrus.Fields{
-				"attempt": attempt,
-				"error":   err.Error(),
-			}).Warn("Failed to create/update secret, will retry")
-
-			if attempt < a.config.RetryConfig.MaxRetries {
-				delay := a.calculateRetryDelay(attempt)
-				logEntry.WithField("delay", delay).Info("Waiting before retry")
-				time.Sleep(delay)
+				"secret_name": name,
+				"error":       err.Error(),
+			}).Error("Failed to prune secret from Azure")
+		}
+		results = append(results, result)
+	}
+
+	logEntry.WithField("pruned_count", len(results)).Info("Completed Azure secrets prune")
+	return results, nil
+}
+
+// listSecretItems returns the secrets of the Key Vault under the configured prefix
+func (a *AzureProvider) listSecretItems(ctx context.Context) ([]*azsecrets.SecretItem, error) {
+	prefix := ""
+	if a.config.Prefix != "" {
+		prefix = sanitizeAzureSecretName(a.config.Prefix) + "-"
+	}
+
+	var items []*azsecrets.SecretItem
+	pager := a.client.NewListSecretsPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Azure secrets: %w", err)
+		}
+
+		for _, item := range page.Value {
+			if item.ID != nil && strings.HasPrefix(item.ID.Name(), prefix) {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// Read returns the value of the current version of each named secret
+func (a *AzureProvider) Read(ctx context.Context, remoteNames []string) ([]RemoteSecret, error) {
+	var secrets []RemoteSecret
+	for _, name := range remoteNames {
+		resp, err := a.client.GetSecret(ctx, name, "", nil)
+		if err != nil {
+			var respErr *azcore.ResponseError
+			if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
 				continue
 			}
-		} else {
-			logEntry.WithField("attempt", attempt).Info("Successfully created/updated secret")
-			return nil
+			return nil, fmt.Errorf("failed to read Azure secret '%s': %w", name, err)
 		}
+
+		secret := RemoteSecret{Name: name, HasValue: true}
+		if resp.Value != nil {
+			secret.Value = *resp.Value
+		}
+		if resp.Attributes != nil {
+			secret.UpdatedAt = resp.Attributes.Updated
+		}
+		secrets = append(secrets, secret)
 	}
 
-	return fmt.Errorf("failed after %d attempts: %w", a.config.RetryConfig.MaxRetries, lastErr)
+	return secrets, nil
 }
 
-// calculateRetryDelay calculates the delay for the next retry attempt
-func (a *AzureProvider) calculateRetryDelay(attempt int) time.Duration {
-	switch a.config.RetryConfig.BackoffType {
-	case "exponential":
-		delay := a.config.RetryConfig.RetryDelay * time.Duration(1<<(attempt-1))
-		if delay > a.config.RetryConfig.MaxDelay {
-			delay = a.config.RetryConfig.MaxDelay
+// Import returns the secrets of the Key Vault under the configured prefix with the value of their latest
+// enabled version
+func (a *AzureProvider) Import(ctx context.Context) ([]RemoteSecret, error) {
+	items, err := a.listSecretItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets []RemoteSecret
+	for _, item := range items {
+		name := item.ID.Name()
+
+		// A disabled secret has a disabled current version, but older versions may still be enabled
+		version := ""
+		if item.Attributes != nil && item.Attributes.Enabled != nil && !*item.Attributes.Enabled {
+			version, err = a.latestEnabledVersion(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			if version == "" {
+				continue
+			}
 		}
-		return delay
-	case "linear":
-		delay := a.config.RetryConfig.RetryDelay * time.Duration(attempt)
-		if delay > a.config.RetryConfig.MaxDelay {
-			delay = a.config.RetryConfig.MaxDelay
+
+		resp, err := a.client.GetSecret(ctx, name, version, nil)
+		if err != nil {
+			var respErr *azcore.ResponseError
+			if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read Azure secret '%s': %w", name, err)
 		}
-		return delay
-	case "constant":
-		return a.config.RetryConfig.RetryDelay
-	default:
-		// Default to exponential backoff
-		delay := a.config.RetryConfig.RetryDelay * time.Duration(1<<(attempt-1))
-		if delay > a.config.RetryConfig.MaxDelay {
-			delay = a.config.RetryConfig.MaxDelay
+
+		secret := RemoteSecret{Name: name, HasValue: true}
+		if resp.Value != nil {
+			secret.Value = *resp.Value
 		}
-		return delay
+		if resp.Attributes != nil {
+			secret.UpdatedAt = resp.Attributes.Updated
+		}
+		secrets = append(secrets, secret)
 	}
+
+	return secrets, nil
+}
+
+// ImportName removes the configured prefix from a Key Vault secret name and turns hyphens back into
+// underscores, since Key Vault names cannot hold underscores and syncs replace them with hyphens
+func (a *AzureProvider) ImportName(remoteName string) (string, bool) {
+	name := remoteName
+	if a.config.Prefix != "" {
+		prefix := sanitizeAzureSecretName(a.config.Prefix) + "-"
+		if !strings.HasPrefix(remoteName, prefix) || remoteName == prefix {
+			return "", false
+		}
+		name = strings.TrimPrefix(remoteName, prefix)
+	}
+	return strings.ReplaceAll(name, "-", "_"), true
+}
+
+// latestEnabledVersion returns the newest enabled version of a secret, or an empty string when every
+// version is disabled
+func (a *AzureProvider) latestEnabledVersion(ctx context.Context, name string) (string, error) {
+	var latest *azsecrets.SecretItem
+	pager := a.client.NewListSecretVersionsPager(name, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to list Azure secret versions: %w", err)
+		}
+
+		for _, item := range page.Value {
+			if item.ID == nil || item.Attributes == nil || item.Attributes.Enabled == nil || !*item.Attributes.Enabled ||
+				item.Attributes.Created == nil {
+				continue
+			}
+			if latest == nil || item.Attributes.Created.After(*latest.Attributes.Created) {
+				latest = item
+			}
+		}
+	}
+
+	if latest == nil {
+		return "", nil
+	}
+	return latest.ID.Version(), nil
 }
 
 // ValidateCredentials validates Azure credentials by making a test API call
@@ -222,14 +441,7 @@ func (a *AzureProvider) createOrUpdateSecret(ctx context.Context, secretName, se
 
 	logEntry.Info("Creating or updating Azure secret")
 
-	// Apply prefix if configured
-	fullSecretName := secretName
-	if a.config.Prefix != "" {
-		fullSecretName = fmt.Sprintf("%s-%s", a.config.Prefix, secretName)
-	}
-
-	// Sanitize secret name for Azure Key Vault
-	fullSecretName = sanitizeAzureSecretName(fullSecretName)
+	fullSecretName := a.fullSecretName(secretName)
 
 	// Check if secret exists to determine if we should delete older versions
 	secretExists := a.secretExists(ctx, fullSecretName)
@@ -242,9 +454,15 @@ func (a *AzureProvider) createOrUpdateSecret(ctx context.Context, secretName, se
 		}
 	}
 
-	// Create or update the secret
+	// Create or update the secret, tagged with the managed-by tag and the owner
+	managedBy := azureManagedByTagValue
+	tags := map[string]*string{azureManagedByTag: &managedBy}
+	for key, value := range a.owner.Markers() {
+		tags[key] = &value
+	}
 	_, err := a.client.SetSecret(ctx, fullSecretName, azsecrets.SetSecretParameters{
 		Value: &secretValue,
+		Tags:  tags,
 	}, nil)
 
 	if err != nil {
@@ -255,6 +473,18 @@ func (a *AzureProvider) createOrUpdateSecret(ctx context.Context, secretName, se
 	return nil
 }
 
+// fullSecretName applies the configured prefix and sanitizes the secret name for Azure Key Vault
+func (a *AzureProvider) fullSecretName(secretName string) string {
+	// Apply prefix if configured
+	fullSecretName := secretName
+	if a.config.Prefix != "" {
+		fullSecretName = fmt.Sprintf("%s-%s", a.config.Prefix, secretName)
+	}
+
+	// Sanitize secret name for Azure Key Vault
+	return sanitizeAzureSecretName(fullSecretName)
+}
+
 // sanitizeAzureSecretName sanitizes the secret name for Azure Key Vault
 func sanitizeAzureSecretName(name string) string {
 	// Replace invalid characters with hyphens
@@ -0,0 +1,433 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	providerdb "github.com/Gkemhcs/kavach-backend/internal/provider/gen"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateProviderConnection stores provider credentials once for an organization so environment targets can share them
+func (s *ProviderService) CreateProviderConnection(ctx context.Context, organizationID, userID string, req CreateProviderConnectionRequest) (*ProviderConnectionResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":          "CreateProviderConnection",
+		"organization_id": organizationID,
+		"provider":        req.Provider,
+		"connection":      req.Name,
+	})
+
+	logEntry.Info("Creating provider connection")
+
+	if !s.isValidProvider(req.Provider) {
+		logEntry.Error("Invalid provider type")
+		return nil, appErrors.ErrInvalidProviderType
+	}
+	if !providerTargetNamePattern.MatchString(req.Name) {
+		logEntry.Error("Invalid provider connection name")
+		return nil, appErrors.ErrInvalidProviderConnectionName
+	}
+
+	orgUUID, err := uuid.Parse(organizationID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Invalid organization ID")
+		return nil, appErrors.ErrInternalServer
+	}
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Invalid user ID")
+		return nil, appErrors.ErrInternalServer
+	}
+
+	if err := s.validateProviderCredentials(req.Provider, req.Credentials); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Invalid provider credentials")
+		return nil, appErrors.ErrInvalidProviderData
+	}
+
+	encryptedCredentials, err := s.encryptConnectionCredentials(req.Credentials)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to encrypt credentials")
+		return nil, appErrors.ErrProviderEncryptionFailed
+	}
+
+	connection, err := s.providerRepo.CreateProviderConnection(ctx, providerdb.CreateProviderConnectionParams{
+		OrganizationID: orgUUID,
+		Name:           req.Name,
+		Provider:       string(req.Provider),
+		Credentials:    encryptedCredentials,
+		CreatedBy:      userUUID,
+	})
+	if err != nil {
+		if appErrors.IsUniqueViolation(err) {
+			logEntry.Error("Provider connection already exists")
+			return nil, appErrors.ErrProviderConnectionExists
+		}
+		logEntry.WithField("error", err.Error()).Error("Failed to create provider connection")
+		return nil, appErrors.ErrProviderConnectionFailed
+	}
+
+	logEntry.WithField("connection_id", connection.ID).Info("Successfully created provider connection")
+	return toProviderConnectionResponse(connection, nil), nil
+}
+
+// GetProviderConnection retrieves a provider connection together with the targets that use it
+func (s *ProviderService) GetProviderConnection(ctx context.Context, organizationID, name string) (*ProviderConnectionResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":          "GetProviderConnection",
+		"organization_id": organizationID,
+		"connection":      name,
+	})
+
+	connection, err := s.getProviderConnection(ctx, organizationID, name)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to get provider connection")
+		return nil, err
+	}
+
+	targets, err := s.providerRepo.ListProviderConnectionTargets(ctx, uuid.NullUUID{UUID: connection.ID, Valid: true})
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list provider connection targets")
+		return nil, appErrors.ErrProviderConnectionFailed
+	}
+
+	return toProviderConnectionResponse(connection, targets), nil
+}
+
+// ListProviderConnections lists the provider connections of an organization
+func (s *ProviderService) ListProviderConnections(ctx context.Context, organizationID string) ([]ProviderConnectionResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":          "ListProviderConnections",
+		"organization_id": organizationID,
+	})
+
+	orgUUID, err := uuid.Parse(organizationID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Invalid organization ID")
+		return nil, appErrors.ErrInternalServer
+	}
+
+	connections, err := s.providerRepo.ListProviderConnections(ctx, orgUUID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list provider connections")
+		return nil, appErrors.ErrProviderConnectionFailed
+	}
+
+	response := make([]ProviderConnectionResponse, 0, len(connections))
+	for _, connection := range connections {
+		response = append(response, *toProviderConnectionResponse(connection, nil))
+	}
+	return response, nil
+}
+
+// RotateProviderConnection replaces the credentials of a connection. Targets read the connection's
+// credentials at sync time, so every dependent target uses the new credentials from then on.
+func (s *ProviderService) RotateProviderConnection(ctx context.Context, organizationID, name string, req RotateProviderConnectionRequest) (*ProviderConnectionResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":          "RotateProviderConnection",
+		"organization_id": organizationID,
+		"connection":      name,
+	})
+
+	logEntry.Info("Rotating provider connection credentials")
+
+	existing, err := s.getProviderConnection(ctx, organizationID, name)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to get provider connection")
+		return nil, err
+	}
+
+	if err := s.validateProviderCredentials(ProviderType(existing.Provider), req.Credentials); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Invalid provider credentials")
+		return nil, appErrors.ErrInvalidProviderData
+	}
+
+	encryptedCredentials, err := s.encryptConnectionCredentials(req.Credentials)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to encrypt credentials")
+		return nil, appErrors.ErrProviderEncryptionFailed
+	}
+
+	connection, err := s.providerRepo.UpdateProviderConnectionCredentials(ctx, providerdb.UpdateProviderConnectionCredentialsParams{
+		OrganizationID: existing.OrganizationID,
+		Name:           name,
+		Credentials:    encryptedCredentials,
+	})
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to update provider connection credentials")
+		return nil, appErrors.ErrProviderConnectionFailed
+	}
+
+	targets, err := s.providerRepo.ListProviderConnectionTargets(ctx, uuid.NullUUID{UUID: connection.ID, Valid: true})
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list provider connection targets")
+		return nil, appErrors.ErrProviderConnectionFailed
+	}
+
+	logEntry.WithField("targets", len(targets)).Info("Successfully rotated provider connection credentials")
+	return toProviderConnectionResponse(connection, targets), nil
+}
+
+// DeleteProviderConnection deletes a provider connection that no target uses anymore
+func (s *ProviderService) DeleteProviderConnection(ctx context.Context, organizationID, name string) error {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":          "DeleteProviderConnection",
+		"organization_id": organizationID,
+		"connection":      name,
+	})
+
+	connection, err := s.getProviderConnection(ctx, organizationID, name)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to get provider connection")
+		return err
+	}
+
+	err = s.providerRepo.DeleteProviderConnection(ctx, providerdb.DeleteProviderConnectionParams{
+		OrganizationID: connection.OrganizationID,
+		Name:           name,
+	})
+	if err != nil {
+		if appErrors.IsViolatingForeignKeyConstraints(err) {
+			logEntry.Error("Provider connection is still used by targets")
+			return appErrors.ErrProviderConnectionInUse
+		}
+		logEntry.WithField("error", err.Error()).Error("Failed to delete provider connection")
+		return appErrors.ErrProviderConnectionFailed
+	}
+
+	logEntry.Info("Successfully deleted provider connection")
+	return nil
+}
+
+// CreateProviderConnectionGrant lets a secret group, with all of its environments, or a single environment use a connection
+func (s *ProviderService) CreateProviderConnectionGrant(ctx context.Context, organizationID, name, userID string, req CreateProviderConnectionGrantRequest) (*ProviderConnectionGrantResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":          "CreateProviderConnectionGrant",
+		"organization_id": organizationID,
+		"connection":      name,
+	})
+
+	if (req.SecretGroupID == nil) == (req.EnvironmentID == nil) {
+		logEntry.Error("Grant needs exactly one secret group or environment")
+		return nil, appErrors.ErrInvalidProviderConnectionGrant
+	}
+
+	connection, err := s.getProviderConnection(ctx, organizationID, name)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to get provider connection")
+		return nil, err
+	}
+
+	params := providerdb.CreateProviderConnectionGrantParams{ConnectionID: connection.ID}
+	if req.SecretGroupID != nil {
+		params.SecretGroupID = uuid.NullUUID{UUID: *req.SecretGroupID, Valid: true}
+	} else {
+		params.EnvironmentID = uuid.NullUUID{UUID: *req.EnvironmentID, Valid: true}
+	}
+	if userUUID, err := uuid.Parse(userID); err == nil {
+		params.GrantedBy = uuid.NullUUID{UUID: userUUID, Valid: true}
+	}
+
+	// The insert only happens when the grantee belongs to the connection's organization
+	grant, err := s.providerRepo.CreateProviderConnectionGrant(ctx, params)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logEntry.Error("Grantee is not in the connection's organization")
+			return nil, appErrors.ErrInvalidProviderConnectionGrant
+		}
+		if appErrors.IsUniqueViolation(err) {
+			logEntry.Error("Provider connection grant already exists")
+			return nil, appErrors.ErrProviderConnectionGrantExists
+		}
+		logEntry.WithField("error", err.Error()).Error("Failed to create provider connection grant")
+		return nil, appErrors.ErrProviderConnectionFailed
+	}
+
+	logEntry.WithField("grant_id", grant.ID).Info("Successfully granted provider connection")
+	return toProviderConnectionGrantResponse(grant), nil
+}
+
+// ListProviderConnectionGrants lists who may use a provider connection
+func (s *ProviderService) ListProviderConnectionGrants(ctx context.Context, organizationID, name string) ([]ProviderConnectionGrantResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":          "ListProviderConnectionGrants",
+		"organization_id": organizationID,
+		"connection":      name,
+	})
+
+	connection, err := s.getProviderConnection(ctx, organizationID, name)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to get provider connection")
+		return nil, err
+	}
+
+	grants, err := s.providerRepo.ListProviderConnectionGrants(ctx, connection.ID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list provider connection grants")
+		return nil, appErrors.ErrProviderConnectionFailed
+	}
+
+	response := make([]ProviderConnectionGrantResponse, 0, len(grants))
+	for _, grant := range grants {
+		response = append(response, *toProviderConnectionGrantResponse(grant))
+	}
+	return response, nil
+}
+
+// DeleteProviderConnectionGrant revokes a grant. Targets created under it stop syncing until access is granted again.
+func (s *ProviderService) DeleteProviderConnectionGrant(ctx context.Context, organizationID, name, grantID string) error {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":          "DeleteProviderConnectionGrant",
+		"organization_id": organizationID,
+		"connection":      name,
+		"grant_id":        grantID,
+	})
+
+	grantUUID, err := uuid.Parse(grantID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Invalid grant ID")
+		return appErrors.ErrProviderConnectionGrantNotFound
+	}
+
+	connection, err := s.getProviderConnection(ctx, organizationID, name)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to get provider connection")
+		return err
+	}
+
+	deleted, err := s.providerRepo.DeleteProviderConnectionGrant(ctx, providerdb.DeleteProviderConnectionGrantParams{
+		ID:           grantUUID,
+		ConnectionID: connection.ID,
+	})
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to delete provider connection grant")
+		return appErrors.ErrProviderConnectionFailed
+	}
+	if deleted == 0 {
+		logEntry.Error("Provider connection grant not found")
+		return appErrors.ErrProviderConnectionGrantNotFound
+	}
+
+	logEntry.Info("Successfully revoked provider connection grant")
+	return nil
+}
+
+// getProviderConnection looks up a connection by organization and name
+func (s *ProviderService) getProviderConnection(ctx context.Context, organizationID, name string) (providerdb.ProviderConnection, error) {
+	orgUUID, err := uuid.Parse(organizationID)
+	if err != nil {
+		return providerdb.ProviderConnection{}, appErrors.ErrInternalServer
+	}
+
+	connection, err := s.providerRepo.GetProviderConnection(ctx, providerdb.GetProviderConnectionParams{
+		OrganizationID: orgUUID,
+		Name:           name,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return providerdb.ProviderConnection{}, appErrors.ErrProviderConnectionNotFound
+		}
+		return providerdb.ProviderConnection{}, appErrors.ErrProviderConnectionFailed
+	}
+	return connection, nil
+}
+
+// connectionForEnvironment looks up a connection of the environment's organization by name
+// and checks that the environment may use it
+func (s *ProviderService) connectionForEnvironment(ctx context.Context, environmentID uuid.UUID, name string) (providerdb.ProviderConnection, error) {
+	connection, err := s.providerRepo.GetProviderConnectionForEnvironment(ctx, providerdb.GetProviderConnectionForEnvironmentParams{
+		EnvironmentID: environmentID,
+		Name:          name,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return providerdb.ProviderConnection{}, appErrors.ErrProviderConnectionNotFound
+		}
+		return providerdb.ProviderConnection{}, appErrors.ErrProviderConnectionFailed
+	}
+
+	if err := s.checkConnectionGrant(ctx, connection.ID, environmentID); err != nil {
+		return providerdb.ProviderConnection{}, err
+	}
+	return connection, nil
+}
+
+// grantedConnection loads the connection a target references, as long as the environment still may use it
+func (s *ProviderService) grantedConnection(ctx context.Context, environmentID, connectionID uuid.UUID) (providerdb.ProviderConnection, error) {
+	if err := s.checkConnectionGrant(ctx, connectionID, environmentID); err != nil {
+		return providerdb.ProviderConnection{}, err
+	}
+
+	connection, err := s.providerRepo.GetProviderConnectionByID(ctx, connectionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return providerdb.ProviderConnection{}, appErrors.ErrProviderConnectionNotFound
+		}
+		return providerdb.ProviderConnection{}, appErrors.ErrProviderConnectionFailed
+	}
+	return connection, nil
+}
+
+func (s *ProviderService) checkConnectionGrant(ctx context.Context, connectionID, environmentID uuid.UUID) error {
+	granted, err := s.providerRepo.HasProviderConnectionGrant(ctx, providerdb.HasProviderConnectionGrantParams{
+		ConnectionID:  connectionID,
+		EnvironmentID: environmentID,
+	})
+	if err != nil {
+		return appErrors.ErrProviderConnectionFailed
+	}
+	if !granted {
+		return appErrors.ErrProviderConnectionNotGranted
+	}
+	return nil
+}
+
+func (s *ProviderService) encryptConnectionCredentials(credentials map[string]interface{}) ([]byte, error) {
+	credentialsJSON, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.encryptor.Encrypt(credentialsJSON)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(encrypted), nil
+}
+
+func toProviderConnectionResponse(connection providerdb.ProviderConnection, targets []providerdb.ProviderCredential) *ProviderConnectionResponse {
+	response := &ProviderConnectionResponse{
+		ID:             connection.ID.String(),
+		OrganizationID: connection.OrganizationID,
+		Name:           connection.Name,
+		Provider:       ProviderType(connection.Provider),
+		CreatedAt:      connection.CreatedAt,
+		UpdatedAt:      connection.UpdatedAt,
+	}
+	for _, target := range targets {
+		response.Targets = append(response.Targets, ProviderConnectionTarget{
+			EnvironmentID: target.EnvironmentID,
+			Name:          target.Name,
+		})
+	}
+	return response
+}
+
+func toProviderConnectionGrantResponse(grant providerdb.ProviderConnectionGrant) *ProviderConnectionGrantResponse {
+	return &ProviderConnectionGrantResponse{
+		ID:            grant.ID.String(),
+		ConnectionID:  grant.ConnectionID,
+		SecretGroupID: nullUUIDPtr(grant.SecretGroupID),
+		EnvironmentID: nullUUIDPtr(grant.EnvironmentID),
+		GrantedBy:     nullUUIDPtr(grant.GrantedBy),
+		CreatedAt:     grant.CreatedAt,
+	}
+}
+
+func nullUUIDPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
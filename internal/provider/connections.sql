@@ -0,0 +1,70 @@
+-- name: CreateProviderConnection :one
+INSERT INTO provider_connections (organization_id, name, provider, credentials, created_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetProviderConnection :one
+SELECT * FROM provider_connections
+WHERE organization_id = $1 AND name = $2;
+
+-- name: GetProviderConnectionByID :one
+SELECT * FROM provider_connections
+WHERE id = $1;
+
+-- name: GetProviderConnectionForEnvironment :one
+-- Looks up a connection by name in the organization that owns the environment
+SELECT c.* FROM provider_connections c
+JOIN secret_groups sg ON sg.organization_id = c.organization_id
+JOIN environments e ON e.secret_group_id = sg.id
+WHERE e.id = $1 AND c.name = $2;
+
+-- name: ListProviderConnections :many
+SELECT * FROM provider_connections
+WHERE organization_id = $1
+ORDER BY name;
+
+-- name: UpdateProviderConnectionCredentials :one
+UPDATE provider_connections
+SET credentials = $3, updated_at = now()
+WHERE organization_id = $1 AND name = $2
+RETURNING *;
+
+-- name: DeleteProviderConnection :exec
+DELETE FROM provider_connections
+WHERE organization_id = $1 AND name = $2;
+
+-- name: ListProviderConnectionTargets :many
+-- Lists the environment targets that use a connection's credentials
+SELECT * FROM provider_credentials
+WHERE connection_id = $1
+ORDER BY environment_id, name;
+
+-- name: CreateProviderConnectionGrant :one
+-- Inserts nothing unless the secret group or environment belongs to the connection's organization
+INSERT INTO provider_connection_grants (connection_id, secret_group_id, environment_id, granted_by)
+SELECT c.id, $2, $3, $4
+FROM provider_connections c
+WHERE c.id = $1
+  AND c.organization_id = COALESCE(
+      (SELECT sg.organization_id FROM secret_groups sg WHERE sg.id = $2),
+      (SELECT sg.organization_id FROM environments e JOIN secret_groups sg ON sg.id = e.secret_group_id WHERE e.id = $3)
+  )
+RETURNING *;
+
+-- name: ListProviderConnectionGrants :many
+SELECT * FROM provider_connection_grants
+WHERE connection_id = $1
+ORDER BY created_at;
+
+-- name: DeleteProviderConnectionGrant :execrows
+DELETE FROM provider_connection_grants
+WHERE id = $1 AND connection_id = $2;
+
+-- name: HasProviderConnectionGrant :one
+-- Reports whether the environment, or the secret group it belongs to, may use the connection
+SELECT EXISTS (
+    SELECT 1 FROM provider_connection_grants g
+    JOIN environments e ON e.id = $2
+    WHERE g.connection_id = $1
+      AND (g.environment_id = e.id OR g.secret_group_id = e.secret_group_id)
+)::boolean AS granted;
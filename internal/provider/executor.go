@@ -0,0 +1,502 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	"github.com/google/go-github/v74/github"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// gcpQuotaRetryAfter is how long a GCP provider pauses after a quota error, which carries no retry hint
+	gcpQuotaRetryAfter = 10 * time.Second
+	// defaultRateLimitRetryAfter is used when a provider rate limits without saying for how long
+	defaultRateLimitRetryAfter = time.Minute
+	// minRateLimitRetryAfter is the shortest rate-limit pause, used when a provider's reset time has already
+	// passed because of clock skew or a late response
+	minRateLimitRetryAfter = time.Second
+	// maxRateLimitWait caps how long a write waits in total for rate limits to lift before failing the secret
+	maxRateLimitWait = 5 * time.Minute
+	// maxRateLimitRetries caps how many times a write is retried after being rate limited
+	maxRateLimitRetries = 5
+)
+
+// awsThrottlingErrorCodes are the AWS error codes that mean the caller is being rate limited
+var awsThrottlingErrorCodes = map[string]bool{
+	"ThrottlingException":      true,
+	"Throttling":               true,
+	"TooManyRequestsException": true,
+	"RequestLimitExceeded":     true,
+}
+
+// SyncExecutorConfig configures the shared sync executor
+type SyncExecutorConfig struct {
+	Concurrency      int           // Secrets written in parallel per provider
+	BreakerThreshold int           // Consecutive transient failures that open a provider's circuit
+	BreakerCooldown  time.Duration // How long an open circuit rejects writes before letting one through
+}
+
+// DefaultSyncExecutorConfig returns the executor settings used when none are configured
+func DefaultSyncExecutorConfig() SyncExecutorConfig {
+	return SyncExecutorConfig{
+		Concurrency:      8,
+		BreakerThreshold: 5,
+		BreakerCooldown:  time.Minute,
+	}
+}
+
+// SyncExecutor writes secrets to providers concurrently. Concurrency, rate-limit pauses and the circuit
+// breaker are tracked per provider type and credentials, and shared by every sync that goes through the
+// executor with the same credentials. One tenant's revoked or throttled credentials never hold back
+// the writes of another.
+type SyncExecutor struct {
+	config SyncExecutorConfig
+	logger *logrus.Logger
+	scope  string // Fingerprint of the credentials the states are keyed by; empty for a standalone provider
+	states *syncStates
+}
+
+// syncStates holds the shared states of an executor and of every credential-scoped view of it
+type syncStates struct {
+	mu     sync.Mutex
+	states map[string]*providerSyncState
+}
+
+// providerSyncState is the shared state of one provider type and set of credentials
+type providerSyncState struct {
+	slots chan struct{}
+
+	mu          sync.Mutex
+	pausedUntil time.Time // Set when the provider rate limits; every write waits until then
+	failures    int       // Consecutive transient failures
+	openUntil   time.Time // Writes are rejected until then once the circuit opens
+}
+
+// failureKind classifies a failed provider write
+type failureKind int
+
+const (
+	failurePermanent failureKind = iota
+	failureTransient
+	failureRateLimited
+)
+
+// NewSyncExecutor creates a sync executor, filling unset settings with defaults
+func NewSyncExecutor(config SyncExecutorConfig, logger *logrus.Logger) *SyncExecutor {
+	defaults := DefaultSyncExecutorConfig()
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaults.Concurrency
+	}
+	if config.BreakerThreshold <= 0 {
+		config.BreakerThreshold = defaults.BreakerThreshold
+	}
+	if config.BreakerCooldown <= 0 {
+		config.BreakerCooldown = defaults.BreakerCooldown
+	}
+
+	return &SyncExecutor{
+		config: config,
+		logger: logger,
+		states: &syncStates{states: make(map[string]*providerSyncState)},
+	}
+}
+
+// ForCredentials returns a view of the executor whose state is kept apart for the given credentials.
+// Targets sharing credentials, such as through a provider connection, share the state.
+func (e *SyncExecutor) ForCredentials(credentials map[string]interface{}) *SyncExecutor {
+	// Map keys are marshalled in sorted order, so equal credentials give equal fingerprints
+	encoded, _ := json.Marshal(credentials)
+	sum := sha256.Sum256(encoded)
+
+	scoped := *e
+	scoped.scope = hex.EncodeToString(sum[:8])
+	return &scoped
+}
+
+// Run applies write to every secret, in parallel up to the provider's concurrency limit, retrying
+// transient failures with jittered backoff and waiting out rate limits. Results are returned in the
+// order of secrets.
+func (e *SyncExecutor) Run(ctx context.Context, providerType ProviderType, retry RetryConfig, secrets []Secret, write func(ctx context.Context, secret Secret) error) []SyncResult {
+	state := e.state(providerType)
+	retry = retry.withDefaults()
+
+	results := make([]SyncResult, len(secrets))
+	var wg sync.WaitGroup
+	for i, secret := range secrets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = SyncResult{Name: secret.Name, Success: true}
+			if err := e.writeWithRetry(ctx, providerType, state, retry, secret, write); err != nil {
+				results[i].Success = false
+				results[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// writeWithRetry writes one secret, waiting out rate limits and retrying transient failures until
+// the attempts run out, the circuit opens or ctx is cancelled. Rate-limited attempts do not use up
+// the retries; instead the sync gives up once it has waited maxRateLimitWait in total or been rate
+// limited maxRateLimitRetries times.
+func (e *SyncExecutor) writeWithRetry(ctx context.Context, providerType ProviderType, state *providerSyncState, retry RetryConfig, secret Secret, write func(ctx context.Context, secret Secret) error) error {
+	logEntry := e.logger.WithFields(logrus.Fields{
+		"provider":    providerType,
+		"secret_name": secret.Name,
+		"max_retries": retry.MaxRetries,
+	})
+
+	var lastErr error
+	var rateLimitWait time.Duration
+	var rateLimits int
+	for attempt := 1; attempt <= retry.MaxRetries; {
+		if err := state.waitForRateLimit(ctx); err != nil {
+			return err
+		}
+		if !state.allow() {
+			return appErrors.ErrProviderCircuitOpen
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case state.slots <- struct{}{}:
+		}
+		err := write(ctx, secret)
+		<-state.slots
+
+		if err == nil {
+			state.recordSuccess()
+			return nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		kind, retryAfter := classifySyncError(err)
+		switch kind {
+		case failurePermanent:
+			logEntry.WithFields(logrus.Fields{
+				"attempt": attempt,
+				"error":   err.Error(),
+			}).Error("Provider rejected secret, skipping retries")
+			return err
+		case failureTransient:
+			if state.recordFailure(e.config.BreakerThreshold, e.config.BreakerCooldown) {
+				logEntry.WithField("cooldown", e.config.BreakerCooldown).Warn("Opened provider circuit after repeated failures")
+			}
+		case failureRateLimited:
+			rateLimitWait += retryAfter
+			if rateLimitWait > maxRateLimitWait {
+				return fmt.Errorf("rate limited for %s: %w", rateLimitWait.Round(time.Second), err)
+			}
+			rateLimits++
+			if rateLimits > maxRateLimitRetries {
+				return fmt.Errorf("rate limited %d times: %w", rateLimits, err)
+			}
+			state.pause(retryAfter)
+			logEntry.WithFields(logrus.Fields{
+				"attempt":     attempt,
+				"retry_after": retryAfter,
+			}).Warn("Provider rate limited the write, waiting before retrying")
+			continue
+		}
+
+		logEntry.WithFields(logrus.Fields{
+			"attempt": attempt,
+			"error":   err.Error(),
+		}).Warn("Failed to write secret, will retry")
+
+		if attempt < retry.MaxRetries {
+			delay := retry.backoff(attempt)
+			logEntry.WithField("delay", delay).Info("Waiting before retry")
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+		attempt++
+	}
+
+	return fmt.Errorf("failed after %d attempts: %w", retry.MaxRetries, lastErr)
+}
+
+// state returns the shared state of a provider type and the executor's credentials, creating it on first use
+func (e *SyncExecutor) state(providerType ProviderType) *providerSyncState {
+	e.states.mu.Lock()
+	defer e.states.mu.Unlock()
+
+	key := string(providerType) + "/" + e.scope
+	state, ok := e.states.states[key]
+	if !ok {
+		state = &providerSyncState{slots: make(chan struct{}, e.config.Concurrency)}
+		e.states.states[key] = state
+	}
+	return state
+}
+
+// waitForRateLimit blocks until the provider's rate-limit pause is over or ctx is cancelled
+func (s *providerSyncState) waitForRateLimit(ctx context.Context) error {
+	s.mu.Lock()
+	wait := time.Until(s.pausedUntil)
+	s.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
+
+// pause holds back every write to the provider for the given duration
+func (s *providerSyncState) pause(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(s.pausedUntil) {
+		s.pausedUntil = until
+	}
+}
+
+// allow reports whether the circuit lets a write through. Once the cooldown has passed the circuit
+// is half-open: writes go through again, and a single transient failure reopens it.
+func (s *providerSyncState) allow() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return !time.Now().Before(s.openUntil)
+}
+
+// recordSuccess closes the circuit
+func (s *providerSyncState) recordSuccess() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = 0
+	s.openUntil = time.Time{}
+}
+
+// recordFailure counts a transient failure and reports whether it opened the circuit
+func (s *providerSyncState) recordFailure(threshold int, cooldown time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(s.openUntil) {
+		return false
+	}
+
+	s.failures++
+	if s.failures < threshold && s.openUntil.IsZero() {
+		return false
+	}
+	s.openUntil = now.Add(cooldown)
+	return true
+}
+
+// classifySyncError tells rate limits and transient failures, which are retried, from permanent
+// ones, and returns how long the provider asked to wait when it said
+func classifySyncError(err error) (failureKind, time.Duration) {
+	var apiErr *appErrors.APIError
+	if errors.As(err, &apiErr) {
+		return failurePermanent, 0
+	}
+
+	// GitHub: primary and secondary rate limits, then status codes
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return rateLimited(time.Until(rateLimitErr.Rate.Reset.Time))
+	}
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return rateLimited(*abuseErr.RetryAfter)
+		}
+		return failureRateLimited, defaultRateLimitRetryAfter
+	}
+	var githubErr *github.ErrorResponse
+	if errors.As(err, &githubErr) && githubErr.Response != nil {
+		return classifyHTTPStatus(githubErr.Response.StatusCode, githubErr.Response.Header)
+	}
+
+	// Azure: 429 carries Retry-After
+	var azureErr *azcore.ResponseError
+	if errors.As(err, &azureErr) {
+		var header http.Header
+		if azureErr.RawResponse != nil {
+			header = azureErr.RawResponse.Header
+		}
+		return classifyHTTPStatus(azureErr.StatusCode, header)
+	}
+
+	// AWS: throttling is reported by error code, often with a 400 status
+	var awsErr *awshttp.ResponseError
+	if errors.As(err, &awsErr) {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && awsThrottlingErrorCodes[apiErr.ErrorCode()] {
+			return failureRateLimited, defaultRateLimitRetryAfter
+		}
+		var header http.Header
+		if awsErr.Response != nil && awsErr.Response.Response != nil {
+			header = awsErr.Response.Header
+		}
+		return classifyHTTPStatus(awsErr.HTTPStatusCode(), header)
+	}
+
+	// Vault: a check-and-set mismatch means the secret changed outside Kavach, which a retry must not overwrite
+	var vaultErr *vaultResponseError
+	if errors.As(err, &vaultErr) {
+		if vaultErr.checkAndSetMismatch() {
+			return failurePermanent, 0
+		}
+		return classifyHTTPStatus(vaultErr.StatusCode, vaultErr.Header)
+	}
+
+	// GitLab: 429 carries Retry-After
+	var gitlabErr *gitlabResponseError
+	if errors.As(err, &gitlabErr) {
+		return classifyHTTPStatus(gitlabErr.StatusCode, gitlabErr.Header)
+	}
+
+	// Webhook: 429 carries Retry-After
+	var webhookErr *webhookResponseError
+	if errors.As(err, &webhookErr) {
+		return classifyHTTPStatus(webhookErr.StatusCode, webhookErr.Header)
+	}
+
+	// Kubernetes: a conflict means the Secret changed since it was read, so a retry reads it again
+	var kubeErr apierrors.APIStatus
+	if errors.As(err, &kubeErr) {
+		switch {
+		case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err):
+			return failureTransient, 0
+		case apierrors.IsTooManyRequests(err):
+			if seconds, ok := apierrors.SuggestsClientDelay(err); ok {
+				return rateLimited(time.Duration(seconds) * time.Second)
+			}
+			return failureRateLimited, defaultRateLimitRetryAfter
+		}
+		return classifyHTTPStatus(int(kubeErr.Status().Code), nil)
+	}
+
+	// GCP: gRPC status codes, with quota errors reported as resource exhausted
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.ResourceExhausted:
+			return failureRateLimited, gcpQuotaRetryAfter
+		case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted, codes.Internal, codes.Unknown:
+			return failureTransient, 0
+		default:
+			return failurePermanent, 0
+		}
+	}
+
+	// Anything else, such as a network error, is worth retrying
+	return failureTransient, 0
+}
+
+// classifyHTTPStatus classifies a failed HTTP response from a provider
+func classifyHTTPStatus(statusCode int, header http.Header) (failureKind, time.Duration) {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		if retryAfter, ok := parseRetryAfter(header); ok {
+			return rateLimited(retryAfter)
+		}
+		return failureRateLimited, defaultRateLimitRetryAfter
+	case statusCode == http.StatusForbidden && header.Get("X-RateLimit-Remaining") == "0":
+		if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return rateLimited(time.Until(time.Unix(reset, 0)))
+		}
+		return failureRateLimited, defaultRateLimitRetryAfter
+	case statusCode == http.StatusRequestTimeout || statusCode >= http.StatusInternalServerError:
+		return failureTransient, 0
+	default:
+		return failurePermanent, 0
+	}
+}
+
+// rateLimited classifies a rate limit, keeping the wait at least minRateLimitRetryAfter so a reset time
+// that has already passed does not retry at once
+func rateLimited(retryAfter time.Duration) (failureKind, time.Duration) {
+	return failureRateLimited, max(retryAfter, minRateLimitRetryAfter)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date, and reports whether
+// the header was present and valid
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at), true
+	}
+	return 0, false
+}
+
+// withDefaults fills an unset retry configuration with the defaults
+func (c RetryConfig) withDefaults() RetryConfig {
+	if c.MaxRetries == 0 {
+		return RetryConfig{
+			MaxRetries:  3,
+			RetryDelay:  2 * time.Second,
+			MaxDelay:    30 * time.Second,
+			BackoffType: "exponential",
+		}
+	}
+	return c
+}
+
+// backoff returns the delay before the next attempt, with jitter so parallel writes do not retry in step
+func (c RetryConfig) backoff(attempt int) time.Duration {
+	var delay time.Duration
+	switch c.BackoffType {
+	case "linear":
+		delay = c.RetryDelay * time.Duration(attempt)
+	case "constant":
+		delay = c.RetryDelay
+	default:
+		// Default to exponential backoff
+		delay = c.RetryDelay * time.Duration(1<<(attempt-1))
+	}
+	if c.MaxDelay > 0 && delay > c.MaxDelay {
+		delay = c.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	// Keep at least half of the delay and randomise the rest
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
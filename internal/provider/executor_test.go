@@ -0,0 +1,239 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failureKindNames maps the failure kinds used in test data to their values
+var failureKindNames = map[string]failureKind{
+	"permanent":    failurePermanent,
+	"transient":    failureTransient,
+	"rate_limited": failureRateLimited,
+}
+
+// SyncExecutorTestSuite tests error classification, retries, rate-limit waits and the circuit breaker
+type SyncExecutorTestSuite struct {
+	suite.Suite
+	logger *logrus.Logger
+	ctx    context.Context
+	retry  RetryConfig
+}
+
+// SetupTest sets up each individual test
+func (suite *SyncExecutorTestSuite) SetupTest() {
+	suite.logger = logrus.New()
+	suite.logger.SetOutput(io.Discard)
+	suite.ctx = context.Background()
+	suite.retry = RetryConfig{MaxRetries: 2, RetryDelay: time.Millisecond, BackoffType: "constant"}
+}
+
+// newExecutor creates an executor whose circuit opens after two transient failures for a short cooldown
+func (suite *SyncExecutorTestSuite) newExecutor() *SyncExecutor {
+	return NewSyncExecutor(SyncExecutorConfig{Concurrency: 2, BreakerThreshold: 2, BreakerCooldown: 100 * time.Millisecond}, suite.logger)
+}
+
+// run writes one secret through the executor and returns its result
+func (suite *SyncExecutorTestSuite) run(ctx context.Context, executor *SyncExecutor, write func() error) SyncResult {
+	results := executor.Run(ctx, ProviderGitLab, suite.retry, []Secret{{Name: "API_KEY", Value: "v"}}, func(context.Context, Secret) error {
+		return write()
+	})
+	require.Len(suite.T(), results, 1)
+	return results[0]
+}
+
+// TestClassifySyncErrorWithData tests which provider errors are retried and how long rate limits wait
+func (suite *SyncExecutorTestSuite) TestClassifySyncErrorWithData() {
+	data, err := testDataFS.ReadFile("test_data/classify_sync_error_test_cases.json")
+	require.NoError(suite.T(), err)
+	var testData TestData
+	require.NoError(suite.T(), json.Unmarshal(data, &testData))
+
+	for _, tc := range testData.TestCases {
+		suite.Run(tc.Name, func() {
+			kind, retryAfter := classifySyncError(buildSyncError(tc.Input))
+
+			assert.Equal(suite.T(), failureKindNames[tc.Expected.FailureKind], kind, "Failure kind mismatch")
+			expected := time.Duration(tc.Expected.RetryAfterSeconds * float64(time.Second))
+			assert.InDelta(suite.T(), expected.Seconds(), retryAfter.Seconds(), 2, "Retry-after mismatch")
+		})
+	}
+}
+
+// TestRateLimitDoesNotUseRetries tests that a write rate limited with a short Retry-After still succeeds
+// when it has no retries left
+func (suite *SyncExecutorTestSuite) TestRateLimitDoesNotUseRetries() {
+	suite.retry.MaxRetries = 1
+	var calls atomic.Int32
+	started := time.Now()
+
+	result := suite.run(suite.ctx, suite.newExecutor(), func() error {
+		if calls.Add(1) == 1 {
+			return rateLimitedError(1)
+		}
+		return nil
+	})
+
+	assert.True(suite.T(), result.Success, result.Error)
+	assert.EqualValues(suite.T(), 2, calls.Load())
+	assert.GreaterOrEqual(suite.T(), time.Since(started), 900*time.Millisecond, "The write waits for Retry-After")
+}
+
+// TestRateLimitWaitCutoff tests that a write fails at once when the provider asks it to wait too long
+func (suite *SyncExecutorTestSuite) TestRateLimitWaitCutoff() {
+	var calls atomic.Int32
+
+	result := suite.run(suite.ctx, suite.newExecutor(), func() error {
+		calls.Add(1)
+		return rateLimitedError(int(maxRateLimitWait/time.Second) + 60)
+	})
+
+	assert.False(suite.T(), result.Success)
+	assert.Contains(suite.T(), result.Error, "rate limited for 6m0s")
+	assert.EqualValues(suite.T(), 1, calls.Load())
+}
+
+// TestRateLimitRetryCap tests that a write whose rate limit keeps lifting at once fails after a bounded
+// number of retries, each waiting the minimum pause
+func (suite *SyncExecutorTestSuite) TestRateLimitRetryCap() {
+	var calls atomic.Int32
+	started := time.Now()
+
+	result := suite.run(suite.ctx, suite.newExecutor(), func() error {
+		calls.Add(1)
+		return rateLimitedError(0)
+	})
+
+	assert.False(suite.T(), result.Success)
+	assert.Contains(suite.T(), result.Error, "rate limited 6 times")
+	assert.EqualValues(suite.T(), maxRateLimitRetries+1, calls.Load())
+	assert.GreaterOrEqual(suite.T(), time.Since(started), maxRateLimitRetries*minRateLimitRetryAfter)
+}
+
+// TestContextCancelledWhileRateLimited tests that a cancelled sync stops waiting for a rate limit to lift
+func (suite *SyncExecutorTestSuite) TestContextCancelledWhileRateLimited() {
+	ctx, cancel := context.WithTimeout(suite.ctx, 100*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+
+	result := suite.run(ctx, suite.newExecutor(), func() error {
+		return rateLimitedError(120)
+	})
+
+	assert.False(suite.T(), result.Success)
+	assert.Contains(suite.T(), result.Error, context.DeadlineExceeded.Error())
+	assert.Less(suite.T(), time.Since(started), 2*time.Second)
+}
+
+// TestPermanentFailureNotRetried tests that a permanent failure is returned after one attempt
+func (suite *SyncExecutorTestSuite) TestPermanentFailureNotRetried() {
+	var calls atomic.Int32
+
+	result := suite.run(suite.ctx, suite.newExecutor(), func() error {
+		calls.Add(1)
+		return appErrors.ErrInvalidProviderData
+	})
+
+	assert.False(suite.T(), result.Success)
+	assert.EqualValues(suite.T(), 1, calls.Load())
+}
+
+// TestCircuitBreaker tests that repeated transient failures open the circuit for the failing credentials
+// only, that the circuit is half-open after the cooldown and that a success closes it
+func (suite *SyncExecutorTestSuite) TestCircuitBreaker() {
+	executor := suite.newExecutor()
+	failing := executor.ForCredentials(map[string]interface{}{"token": "revoked"})
+	healthy := executor.ForCredentials(map[string]interface{}{"token": "valid"})
+	var calls atomic.Int32
+	fail := func() error {
+		calls.Add(1)
+		return errors.New("connection reset")
+	}
+
+	result := suite.run(suite.ctx, failing, fail)
+	assert.Contains(suite.T(), result.Error, "failed after 2 attempts")
+	assert.EqualValues(suite.T(), 2, calls.Load())
+
+	// Open: writes with the failing credentials are rejected without being attempted
+	result = suite.run(suite.ctx, failing, fail)
+	assert.Equal(suite.T(), appErrors.ErrProviderCircuitOpen.Error(), result.Error)
+	assert.EqualValues(suite.T(), 2, calls.Load())
+
+	// Other credentials of the same provider are not held back
+	result = suite.run(suite.ctx, healthy, func() error { return nil })
+	assert.True(suite.T(), result.Success, result.Error)
+
+	// Half-open: one write goes through after the cooldown and a single failure reopens the circuit
+	time.Sleep(120 * time.Millisecond)
+	result = suite.run(suite.ctx, failing, fail)
+	assert.Equal(suite.T(), appErrors.ErrProviderCircuitOpen.Error(), result.Error)
+	assert.EqualValues(suite.T(), 3, calls.Load())
+
+	// A success after the cooldown closes the circuit, so failures count from zero again
+	time.Sleep(120 * time.Millisecond)
+	result = suite.run(suite.ctx, failing, func() error { return nil })
+	assert.True(suite.T(), result.Success, result.Error)
+	result = suite.run(suite.ctx, failing, fail)
+	assert.Contains(suite.T(), result.Error, "failed after 2 attempts")
+	assert.EqualValues(suite.T(), 5, calls.Load())
+}
+
+// buildSyncError creates the provider error described by a test case input
+func buildSyncError(input map[string]interface{}) error {
+	switch input["type"] {
+	case "api":
+		return appErrors.ErrInvalidProviderData
+	case "grpc":
+		codeNames := map[string]codes.Code{
+			"ResourceExhausted": codes.ResourceExhausted,
+			"Unavailable":       codes.Unavailable,
+			"PermissionDenied":  codes.PermissionDenied,
+		}
+		return status.Error(codeNames[input["code"].(string)], "gcp error")
+	case "vault_cas":
+		return &vaultResponseError{StatusCode: http.StatusBadRequest, Errors: []string{"check-and-set parameter did not match the current version"}}
+	case "http":
+		header := http.Header{}
+		if headers, ok := input["headers"].(map[string]interface{}); ok {
+			for name, value := range headers {
+				header.Set(name, value.(string))
+			}
+		}
+		if seconds, ok := input["retry_after_date_seconds"].(float64); ok {
+			header.Set("Retry-After", time.Now().Add(time.Duration(seconds)*time.Second).UTC().Format(http.TimeFormat))
+		}
+		if seconds, ok := input["rate_limit_reset_seconds"].(float64); ok {
+			header.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Duration(seconds)*time.Second).Unix(), 10))
+		}
+		return &gitlabResponseError{StatusCode: int(input["status"].(float64)), Header: header, Message: "provider error"}
+	default:
+		return errors.New("connection reset by peer")
+	}
+}
+
+// rateLimitedError returns a 429 response error asking to retry after the given seconds
+func rateLimitedError(seconds int) error {
+	header := http.Header{}
+	header.Set("Retry-After", strconv.Itoa(seconds))
+	return &gitlabResponseError{StatusCode: http.StatusTooManyRequests, Header: header, Message: "429 Too Many Requests"}
+}
+
+// TestSyncExecutorTestSuite runs the sync executor test suite
+func TestSyncExecutorTestSuite(t *testing.T) {
+	suite.Run(t, new(SyncExecutorTestSuite))
+}
@@ -10,20 +10,37 @@ import (
 type ProviderType string
 
 const (
-	ProviderGitHub ProviderType = "github"
-	ProviderGCP    ProviderType = "gcp"
-	ProviderAzure  ProviderType = "azure"
+	ProviderGitHub            ProviderType = "github"
+	ProviderGCP               ProviderType = "gcp"
+	ProviderAzure             ProviderType = "azure"
+	ProviderAWSSecretsManager ProviderType = "aws_secrets_manager"
+	ProviderAWSSSM            ProviderType = "aws_ssm"
+	ProviderVault             ProviderType = "vault"
+	ProviderKubernetes        ProviderType = "kubernetes"
+	ProviderGitLab            ProviderType = "gitlab"
+	ProviderWebhook           ProviderType = "webhook"
 )
 
 // ProviderFactoryImpl implements ProviderFactory interface
 type ProviderFactoryImpl struct {
-	logger *logrus.Logger
+	executor        *SyncExecutor
+	issuer          *OIDCIssuer
+	githubAppTokens *githubAppTokenCache // Installation tokens shared by every GitHub provider created
+	logger          *logrus.Logger
 }
 
-// NewProviderFactory creates a new provider factory instance
-func NewProviderFactory(logger *logrus.Logger) ProviderFactory {
+// NewProviderFactory creates a new provider factory instance. Every provider it creates
+// writes through the given executor, so concurrency and rate limits are shared across syncs
+// using the same credentials.
+// Providers using workload identity federation get their tokens from the issuer, which is nil
+// when federation is not configured. GitHub App installation tokens are cached by the factory, so
+// syncs of an installation mint a token only when the cached one is about to expire.
+func NewProviderFactory(executor *SyncExecutor, issuer *OIDCIssuer, logger *logrus.Logger) ProviderFactory {
 	return &ProviderFactoryImpl{
-		logger: logger,
+		executor:        executor,
+		issuer:          issuer,
+		githubAppTokens: newGitHubAppTokenCache(),
+		logger:          logger,
 	}
 }
 
@@ -31,11 +48,70 @@ func NewProviderFactory(logger *logrus.Logger) ProviderFactory {
 func (f *ProviderFactoryImpl) CreateProvider(providerType ProviderType, credentials map[string]interface{}, config map[string]interface{}) (ProviderSyncer, error) {
 	switch providerType {
 	case ProviderGitHub:
-		return NewGitHubProvider(credentials, config, f.logger)
+		provider, err := newGitHubProvider(credentials, config, f.logger, f.githubAppTokens)
+		if err != nil {
+			return nil, err
+		}
+		provider.executor = f.executor.ForCredentials(credentials)
+		return provider, nil
 	case ProviderGCP:
-		return NewGCPProvider(credentials, config, f.logger)
+		provider, err := NewGCPProvider(credentials, config, f.logger)
+		if err != nil {
+			return nil, err
+		}
+		provider.executor = f.executor.ForCredentials(credentials)
+		provider.issuer = f.issuer
+		return provider, nil
 	case ProviderAzure:
-		return NewAzureProvider(credentials, config, f.logger)
+		provider, err := NewAzureProvider(credentials, config, f.logger)
+		if err != nil {
+			return nil, err
+		}
+		provider.executor = f.executor.ForCredentials(credentials)
+		provider.issuer = f.issuer
+		return provider, nil
+	case ProviderAWSSecretsManager:
+		provider, err := NewAWSSecretsManagerProvider(credentials, config, f.logger)
+		if err != nil {
+			return nil, err
+		}
+		provider.executor = f.executor.ForCredentials(credentials)
+		return provider, nil
+	case ProviderAWSSSM:
+		provider, err := NewAWSSSMProvider(credentials, config, f.logger)
+		if err != nil {
+			return nil, err
+		}
+		provider.executor = f.executor.ForCredentials(credentials)
+		return provider, nil
+	case ProviderVault:
+		provider, err := NewVaultProvider(credentials, config, f.logger)
+		if err != nil {
+			return nil, err
+		}
+		provider.executor = f.executor.ForCredentials(credentials)
+		return provider, nil
+	case ProviderKubernetes:
+		provider, err := NewKubernetesProvider(credentials, config, f.logger)
+		if err != nil {
+			return nil, err
+		}
+		provider.executor = f.executor.ForCredentials(credentials)
+		return provider, nil
+	case ProviderGitLab:
+		provider, err := NewGitLabProvider(credentials, config, f.logger)
+		if err != nil {
+			return nil, err
+		}
+		provider.executor = f.executor.ForCredentials(credentials)
+		return provider, nil
+	case ProviderWebhook:
+		provider, err := NewWebhookProvider(credentials, config, f.logger)
+		if err != nil {
+			return nil, err
+		}
+		provider.executor = f.executor.ForCredentials(credentials)
+		return provider, nil
 	default:
 		return nil, fmt.Errorf("unsupported provider type: %s", providerType)
 	}
@@ -47,5 +123,11 @@ func (f *ProviderFactoryImpl) GetSupportedProviders() []ProviderType {
 		ProviderGitHub,
 		ProviderGCP,
 		ProviderAzure,
+		ProviderAWSSecretsManager,
+		ProviderAWSSSM,
+		ProviderVault,
+		ProviderKubernetes,
+		ProviderGitLab,
+		ProviderWebhook,
 	}
 }
@@ -4,25 +4,40 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"strings"
-	"time"
 
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
 	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google/externalaccount"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// gcpManagedLabelFilter selects the secrets created by Kavach, which carry the managed-by label
+const gcpManagedLabelFilter = "labels.managed-by=kavach-backend"
+
+// Endpoints for Workload Identity Federation: the STS token exchange and service account impersonation
+const (
+	gcpSTSTokenURL              = "https://sts.googleapis.com/v1/token"
+	gcpImpersonationURLTemplate = "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/%s:generateAccessToken"
+)
+
 // GCPProvider implements ProviderSync for Google Cloud Secret Manager
 type GCPProvider struct {
 	credentials GCPCredentials
 	config      GCPConfig
 	logger      *logrus.Logger
 	client      *secretmanager.Client
+	executor    *SyncExecutor
+	issuer      *OIDCIssuer      // Mints workload tokens for workload_identity auth
+	identity    WorkloadIdentity // Environment and provider the workload tokens are minted for
+	owner       SecretOwner      // Environment and target recorded on created secrets
 }
 
 // NewGCPProvider creates a new GCP provider instance
@@ -49,23 +64,34 @@ func NewGCPProvider(credentials map[string]interface{}, config map[string]interf
 		return nil, fmt.Errorf("failed to unmarshal GCP config: %w", err)
 	}
 
-	// Create service account credentials JSON
-	serviceAccountJSON := map[string]interface{}{
-		"type":                        gcpCreds.Type,
-		"project_id":                  gcpCreds.ProjectID,
-		"private_key_id":              gcpCreds.PrivateKeyID,
-		"private_key":                 gcpCreds.PrivateKey,
-		"client_email":                gcpCreds.ClientEmail,
-		"client_id":                   gcpCreds.ClientID,
-		"auth_uri":                    gcpCreds.AuthURI,
-		"token_uri":                   gcpCreds.TokenURI,
-		"auth_provider_x509_cert_url": gcpCreds.AuthProviderX509CertURL,
-		"client_x509_cert_url":        gcpCreds.ClientX509CertURL,
+	if gcpCreds.AuthMethod == "" {
+		gcpCreds.AuthMethod = GCPAuthServiceAccountKey
 	}
 
-	credentialsJSON, err := json.Marshal(serviceAccountJSON)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal service account credentials: %w", err)
+	provider := &GCPProvider{
+		credentials: gcpCreds,
+		config:      gcpConfig,
+		logger:      logger,
+		executor:    NewSyncExecutor(DefaultSyncExecutorConfig(), logger),
+	}
+
+	// Authenticate with the stored key, or with access tokens exchanged for Kavach workload tokens
+	var authOption option.ClientOption
+	switch gcpCreds.AuthMethod {
+	case GCPAuthServiceAccountKey:
+		credentialsJSON, err := gcpServiceAccountJSON(gcpCreds)
+		if err != nil {
+			return nil, err
+		}
+		authOption = option.WithCredentialsJSON(credentialsJSON)
+	case GCPAuthWorkloadIdentity:
+		tokenSource, err := provider.federatedTokenSource(context.Background(), gcpSTSTokenURL, gcpImpersonationURLTemplate)
+		if err != nil {
+			return nil, err
+		}
+		authOption = option.WithTokenSource(tokenSource)
+	default:
+		return nil, fmt.Errorf("unsupported GCP auth method: %s", gcpCreds.AuthMethod)
 	}
 
 	// Create Secret Manager client based on location configuration
@@ -76,25 +102,80 @@ func NewGCPProvider(credentials map[string]interface{}, config map[string]interf
 		// Create regional client for specific location
 		endpoint := fmt.Sprintf("secretmanager.%s.rep.googleapis.com:443", gcpConfig.SecretManagerLocation)
 		client, err = secretmanager.NewClient(ctx,
-			option.WithCredentialsJSON(credentialsJSON),
+			authOption,
 			option.WithEndpoint(endpoint))
 		if err != nil {
 			return nil, fmt.Errorf("failed to create regional Secret Manager client: %w", err)
 		}
 	} else {
 		// Create global client (default)
-		client, err = secretmanager.NewClient(ctx, option.WithCredentialsJSON(credentialsJSON))
+		client, err = secretmanager.NewClient(ctx, authOption)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create global Secret Manager client: %w", err)
 		}
 	}
 
-	return &GCPProvider{
-		credentials: gcpCreds,
-		config:      gcpConfig,
-		logger:      logger,
-		client:      client,
-	}, nil
+	provider.client = client
+	return provider, nil
+}
+
+// SetWorkloadIdentity sets the environment and provider the workload tokens are minted for
+func (g *GCPProvider) SetWorkloadIdentity(identity WorkloadIdentity) {
+	g.identity = identity
+}
+
+// gcpSubjectTokenSupplier supplies workload tokens to the STS exchange. It reads the provider's issuer
+// and identity when a token is needed, since both are set after the provider is created.
+type gcpSubjectTokenSupplier struct {
+	provider *GCPProvider
+}
+
+// SubjectToken mints a workload token for the pool provider's audience
+func (s gcpSubjectTokenSupplier) SubjectToken(ctx context.Context, options externalaccount.SupplierOptions) (string, error) {
+	return s.provider.issuer.MintWorkloadToken(s.provider.identity, options.Audience)
+}
+
+// federatedTokenSource returns access tokens exchanged at the STS endpoint for workload tokens, impersonating
+// the configured service account when one is set. Tokens are minted and exchanged lazily on first use.
+func (g *GCPProvider) federatedTokenSource(ctx context.Context, stsURL, impersonationURLTemplate string) (oauth2.TokenSource, error) {
+	conf := externalaccount.Config{
+		Audience:             g.credentials.Audience,
+		SubjectTokenType:     "urn:ietf:params:oauth:token-type:jwt",
+		TokenURL:             stsURL,
+		Scopes:               []string{"https://www.googleapis.com/auth/cloud-platform"},
+		SubjectTokenSupplier: gcpSubjectTokenSupplier{provider: g},
+	}
+	if g.credentials.ServiceAccountEmail != "" {
+		conf.ServiceAccountImpersonationURL = fmt.Sprintf(impersonationURLTemplate, g.credentials.ServiceAccountEmail)
+	}
+
+	tokenSource, err := externalaccount.NewTokenSource(ctx, conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCP workload identity token source: %w", err)
+	}
+	return tokenSource, nil
+}
+
+// gcpServiceAccountJSON builds the service account key file from the stored key fields
+func gcpServiceAccountJSON(gcpCreds GCPCredentials) ([]byte, error) {
+	serviceAccountJSON := map[string]interface{}{
+		"type":                        gcpCreds.Type,
+		"project_id":                  gcpCreds.ProjectID,
+		"private_key_id":              gcpCreds.PrivateKeyID,
+		"private_key":                 gcpCreds.PrivateKey,
+		"client_email":                gcpCreds.ClientEmail,
+		"client_id":                   gcpCreds.ClientID,
+		"auth_uri":                    gcpCreds.AuthURI,
+		"token_uri":                   gcpCreds.TokenURI,
+		"auth_provider_x509_cert_url": gcpCreds.AuthProviderX509CertURL,
+		"client_x509_cert_url":        gcpCreds.ClientX509CertURL,
+	}
+
+	credentialsJSON, err := json.Marshal(serviceAccountJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal service account credentials: %w", err)
+	}
+	return credentialsJSON, nil
 }
 
 // Sync syncs secrets to Google Cloud Secret Manager
@@ -108,28 +189,17 @@ func (g *GCPProvider) Sync(ctx context.Context, secrets []Secret) ([]SyncResult,
 
 	logEntry.Info("Starting GCP secrets sync")
 
-	var results []SyncResult
-
-	for _, secret := range secrets {
-		result := SyncResult{
-			Name:    secret.Name,
-			Success: false,
-		}
+	results := g.executor.Run(ctx, ProviderGCP, g.config.RetryConfig, secrets, func(ctx context.Context, secret Secret) error {
+		return g.createOrUpdateSecret(ctx, secret.Name, secret.Value)
+	})
 
-		// Use retry wrapper for creating/updating secret
-		err := g.retryCreateSecret(ctx, secret.Name, secret.Value)
-		if err != nil {
-			result.Error = err.Error()
+	for _, result := range results {
+		if !result.Success {
 			logEntry.WithFields(logrus.Fields{
-				"secret_name": secret.Name,
-				"error":       err.Error(),
+				"secret_name": result.Name,
+				"error":       result.Error,
 			}).Error("Failed to sync secret to GCP after all retry attempts")
-		} else {
-			result.Success = true
-			logEntry.WithField("secret_name", secret.Name).Info("Successfully synced secret to GCP")
 		}
-
-		results = append(results, result)
 	}
 
 	logEntry.WithFields(logrus.Fields{
@@ -140,57 +210,266 @@ func (g *GCPProvider) Sync(ctx context.Context, secrets []Secret) ([]SyncResult,
 	return results, nil
 }
 
-// retryCreateSecret wraps the createSecret function with retry logic
-func (g *GCPProvider) retryCreateSecret(ctx context.Context, secretName, secretValue string) error {
+// Delete removes secrets and all their versions from Google Cloud Secret Manager
+func (g *GCPProvider) Delete(ctx context.Context, names []string) ([]SyncResult, error) {
 	logEntry := g.logger.WithFields(logrus.Fields{
-		"secret_name": secretName,
-		"max_retries": g.config.RetryConfig.MaxRetries,
+		"provider":     "gcp",
+		"project_id":   g.config.ProjectID,
+		"location":     g.config.SecretManagerLocation,
+		"secret_count": len(names),
 	})
 
-	// Set default retry configuration if not provided
-	if g.config.RetryConfig.MaxRetries == 0 {
-		g.config.RetryConfig = RetryConfig{
-			MaxRetries:  3,
-			RetryDelay:  2 * time.Second,
-			MaxDelay:    30 * time.Second,
-			BackoffType: "exponential",
+	logEntry.Info("Starting GCP secrets removal")
+
+	var results []SyncResult
+	for _, name := range names {
+		result := SyncResult{Name: name, Success: true}
+
+		_, secretPath, _ := g.secretPaths(name)
+		err := g.client.DeleteSecret(ctx, &secretmanagerpb.DeleteSecretRequest{Name: secretPath})
+		if err != nil && status.Code(err) != codes.NotFound {
+			result.Success = false
+			result.Error = fmt.Sprintf("failed to delete secret: %v", err)
+			logEntry.WithFields(logrus.Fields{
+				"secret_name": name,
+				"error":       err.Error(),
+			}).Error("Failed to delete secret from GCP")
 		}
+
+		results = append(results, result)
 	}
 
-	var lastErr error
-	for attempt := 1; attempt <= g.config.RetryConfig.MaxRetries; attempt++ {
-		logEntry.WithField("attempt", attempt).Info("Attempting to create/update secret")
+	logEntry.Info("Completed GCP secrets removal")
+	return results, nil
+}
 
-		err := g.createOrUpdateSecret(ctx, secretName, secretValue)
-		if err != nil {
-			lastErr = err
+// RemoteName returns the secret ID a secret is stored under in Google Cloud Secret Manager
+func (g *GCPProvider) RemoteName(name string) string {
+	_, _, secretID := g.secretPaths(name)
+	return secretID
+}
+
+// List returns the secrets under the configured prefix, or the secrets labelled as managed by
+// Kavach when no prefix is configured
+func (g *GCPProvider) List(ctx context.Context) ([]RemoteSecret, error) {
+	filter := ""
+	if g.config.Prefix == "" {
+		filter = gcpManagedLabelFilter
+	}
+
+	secretIDs, err := g.listSecretIDs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]RemoteSecret, len(secretIDs))
+	for i, secretID := range secretIDs {
+		secrets[i] = RemoteSecret{Name: secretID}
+	}
+	return secrets, nil
+}
+
+// SetOwner sets the environment and target recorded on created secrets and required for pruning
+func (g *GCPProvider) SetOwner(owner SecretOwner) {
+	g.owner = owner
+}
+
+// PruneCandidates returns the secrets under the configured prefix labelled as created by Kavach for
+// the owning environment and target that are not among the given secret names
+func (g *GCPProvider) PruneCandidates(ctx context.Context, keep []string) ([]string, error) {
+	if !g.owner.IsSet() {
+		return nil, errSecretOwnerUnset
+	}
+
+	filter := fmt.Sprintf(`%s AND labels.%s="%s" AND labels.%s="%s"`, gcpManagedLabelFilter,
+		ownerEnvironmentKey, g.owner.EnvironmentID, ownerTargetKey, g.owner.Target)
+	managed, err := g.listSecretIDs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		kept[g.RemoteName(name)] = true
+	}
+
+	var candidates []string
+	for _, secretID := range managed {
+		if !kept[secretID] {
+			candidates = append(candidates, secretID)
+		}
+	}
+	return candidates, nil
+}
+
+// Prune deletes the given secrets from Secret Manager
+func (g *GCPProvider) Prune(ctx context.Context, remoteNames []string) ([]SyncResult, error) {
+	logEntry := g.logger.WithFields(logrus.Fields{
+		"provider":   "gcp",
+		"project_id": g.config.ProjectID,
+		"location":   g.config.SecretManagerLocation,
+	})
+
+	parent, _, _ := g.secretPaths("")
+	var results []SyncResult
+	for _, secretID := range remoteNames {
+		result := SyncResult{Name: secretID, Success: true}
+		err := g.client.DeleteSecret(ctx, &secretmanagerpb.DeleteSecretRequest{Name: fmt.Sprintf("%s/secrets/%s", parent, secretID)})
+		if err != nil && status.Code(err) != codes.NotFound {
+			result.Success = false
+			result.Error = fmt.Sprintf("failed to delete secret: %v", err)
 			logEntry.WithFields(logrus.Fields{
-				"attempt": attempt,
-				"error":   err.Error(),
-			}).Warn("Failed to create/update secret, will retry")
-
-			if attempt < g.config.RetryConfig.MaxRetries {
-				delay := g.calculateRetryDelay(attempt)
-				logEntry.WithField("delay", delay).Info("Waiting before retry")
-				time.Sleep(delay)
+				"secret_name": secretID,
+				"error":       err.Error(),
+			}).Error("Failed to prune secret from GCP")
+		}
+		results = append(results, result)
+	}
+
+	logEntry.WithField("pruned_count", len(results)).Info("Completed GCP secrets prune")
+	return results, nil
+}
+
+// listSecretIDs returns the IDs of the secrets under the configured prefix matching the filter
+func (g *GCPProvider) listSecretIDs(ctx context.Context, filter string) ([]string, error) {
+	parent, _, _ := g.secretPaths("")
+	req := &secretmanagerpb.ListSecretsRequest{Parent: parent, Filter: filter}
+
+	prefix := ""
+	if g.config.Prefix != "" {
+		prefix = sanitizeSecretName(g.config.Prefix + "_")
+	}
+
+	var secretIDs []string
+	it := g.client.ListSecrets(ctx, req)
+	for {
+		secret, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list GCP secrets: %w", err)
+		}
+
+		secretID := secret.GetName()[strings.LastIndex(secret.GetName(), "/")+1:]
+		if strings.HasPrefix(secretID, prefix) {
+			secretIDs = append(secretIDs, secretID)
+		}
+	}
+
+	return secretIDs, nil
+}
+
+// Read returns the value of the latest version of each named secret
+func (g *GCPProvider) Read(ctx context.Context, remoteNames []string) ([]RemoteSecret, error) {
+	parent, _, _ := g.secretPaths("")
+
+	var secrets []RemoteSecret
+	for _, name := range remoteNames {
+		versionPath := fmt.Sprintf("%s/secrets/%s/versions/latest", parent, name)
+		version, err := g.client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{Name: versionPath})
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
 				continue
 			}
-		} else {
-			logEntry.WithField("attempt", attempt).Info("Successfully created/updated secret")
-			return nil
+			return nil, fmt.Errorf("failed to read GCP secret '%s': %w", name, err)
+		}
+
+		secrets = append(secrets, RemoteSecret{
+			Name:     name,
+			Value:    string(version.GetPayload().GetData()),
+			HasValue: true,
+		})
+	}
+
+	return secrets, nil
+}
+
+// Import returns the secrets under the configured prefix, or every secret of the project when no prefix
+// is configured, with the value of their latest enabled version
+func (g *GCPProvider) Import(ctx context.Context) ([]RemoteSecret, error) {
+	secretIDs, err := g.listSecretIDs(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	parent, _, _ := g.secretPaths("")
+	var secrets []RemoteSecret
+	for _, secretID := range secretIDs {
+		version, err := g.latestEnabledVersion(ctx, fmt.Sprintf("%s/secrets/%s", parent, secretID))
+		if err != nil {
+			return nil, err
 		}
+		if version == nil {
+			continue
+		}
+
+		payload, err := g.client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{Name: version.Name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GCP secret '%s': %w", secretID, err)
+		}
+
+		createdAt := version.CreateTime.AsTime()
+		secrets = append(secrets, RemoteSecret{
+			Name:      secretID,
+			Value:     string(payload.GetPayload().GetData()),
+			HasValue:  true,
+			UpdatedAt: &createdAt,
+		})
+	}
+
+	return secrets, nil
+}
+
+// ImportName removes the configured prefix from a secret ID. Sanitization cannot be reversed, so the rest
+// of the ID is kept as it is.
+func (g *GCPProvider) ImportName(remoteName string) (string, bool) {
+	if g.config.Prefix == "" {
+		return remoteName, true
 	}
+	prefix := sanitizeSecretName(g.config.Prefix + "_")
+	if !strings.HasPrefix(remoteName, prefix) || remoteName == prefix {
+		return "", false
+	}
+	return strings.TrimPrefix(remoteName, prefix), true
+}
 
-	return fmt.Errorf("failed after %d attempts: %w", g.config.RetryConfig.MaxRetries, lastErr)
+// latestEnabledVersion returns the newest enabled version of a secret, or nil when every version is
+// disabled or destroyed
+func (g *GCPProvider) latestEnabledVersion(ctx context.Context, secretPath string) (*secretmanagerpb.SecretVersion, error) {
+	it := g.client.ListSecretVersions(ctx, &secretmanagerpb.ListSecretVersionsRequest{
+		Parent: secretPath,
+		Filter: "state:ENABLED",
+	})
+
+	var latest *secretmanagerpb.SecretVersion
+	for {
+		version, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list GCP secret versions: %w", err)
+		}
+		if version.State != secretmanagerpb.SecretVersion_ENABLED {
+			continue
+		}
+		if latest == nil || version.CreateTime.AsTime().After(latest.CreateTime.AsTime()) {
+			latest = version
+		}
+	}
+	return latest, nil
 }
 
-// buildSecretReplication builds the appropriate replication configuration for the secret
+// buildSecretReplication builds the appropriate replication configuration for the secret, labelled with
+// the managed-by label and the owning environment and target
 func (g *GCPProvider) buildSecretReplication() *secretmanagerpb.Secret {
 	secret := &secretmanagerpb.Secret{
 		Labels: map[string]string{
 			"managed-by": "kavach-backend",
 		},
 	}
+	maps.Copy(secret.Labels, g.owner.Markers())
 
 	if g.config.SecretManagerLocation != "" {
 		// Regional secret - replication is automatically handled by the regional endpoint
@@ -207,33 +486,6 @@ func (g *GCPProvider) buildSecretReplication() *secretmanagerpb.Secret {
 	return secret
 }
 
-// calculateRetryDelay calculates the delay for the next retry attempt
-func (g *GCPProvider) calculateRetryDelay(attempt int) time.Duration {
-	switch g.config.RetryConfig.BackoffType {
-	case "exponential":
-		delay := g.config.RetryConfig.RetryDelay * time.Duration(1<<(attempt-1))
-		if delay > g.config.RetryConfig.MaxDelay {
-			delay = g.config.RetryConfig.MaxDelay
-		}
-		return delay
-	case "linear":
-		delay := g.config.RetryConfig.RetryDelay * time.Duration(attempt)
-		if delay > g.config.RetryConfig.MaxDelay {
-			delay = g.config.RetryConfig.MaxDelay
-		}
-		return delay
-	case "constant":
-		return g.config.RetryConfig.RetryDelay
-	default:
-		// Default to exponential backoff
-		delay := g.config.RetryConfig.RetryDelay * time.Duration(1<<(attempt-1))
-		if delay > g.config.RetryConfig.MaxDelay {
-			delay = g.config.RetryConfig.MaxDelay
-		}
-		return delay
-	}
-}
-
 // ValidateCredentials validates GCP credentials by making a test API call
 func (g *GCPProvider) ValidateCredentials(ctx context.Context) error {
 	logEntry := g.logger.WithFields(logrus.Fields{
@@ -280,6 +532,26 @@ func (g *GCPProvider) createOrUpdateSecret(ctx context.Context, secretName, secr
 
 	logEntry.Info("Creating or updating GCP secret")
 
+	parent, secretPath, secretID := g.secretPaths(secretName)
+	_, err := g.client.GetSecret(ctx, &secretmanagerpb.GetSecretRequest{
+		Name: secretPath,
+	})
+
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			// Secret doesn't exist, create it
+			return g.createSecret(ctx, parent, secretID, secretValue)
+		}
+		return fmt.Errorf("failed to check if secret exists: %w", err)
+	}
+
+	// Secret exists, add a new version
+	return g.addSecretVersion(ctx, secretPath, secretValue)
+}
+
+// secretPaths returns the parent path, full secret path and secret ID for a secret name
+// after applying the configured prefix and sanitization
+func (g *GCPProvider) secretPaths(secretName string) (string, string, string) {
 	// Apply prefix if configured
 	fullSecretName := secretName
 	if g.config.Prefix != "" {
@@ -290,32 +562,16 @@ func (g *GCPProvider) createOrUpdateSecret(ctx context.Context, secretName, secr
 	fullSecretName = sanitizeSecretName(fullSecretName)
 
 	// Build parent path based on location configuration
-	var parent, secretPath string
+	var parent string
 	if g.config.SecretManagerLocation != "" {
 		// Regional secret
 		parent = fmt.Sprintf("projects/%s/locations/%s", g.config.ProjectID, g.config.SecretManagerLocation)
-		secretPath = fmt.Sprintf("%s/secrets/%s", parent, fullSecretName)
 	} else {
 		// Global secret
 		parent = fmt.Sprintf("projects/%s", g.config.ProjectID)
-		secretPath = fmt.Sprintf("%s/secrets/%s", parent, fullSecretName)
-	}
-
-	secretID := fullSecretName
-	_, err := g.client.GetSecret(ctx, &secretmanagerpb.GetSecretRequest{
-		Name: secretPath,
-	})
-
-	if err != nil {
-		if status.Code(err) == codes.NotFound {
-			// Secret doesn't exist, create it
-			return g.createSecret(ctx, parent, secretID, secretValue)
-		}
-		return fmt.Errorf("failed to check if secret exists: %w", err)
 	}
 
-	// Secret exists, add a new version
-	return g.addSecretVersion(ctx, secretPath, secretValue)
+	return parent, fmt.Sprintf("%s/secrets/%s", parent, fullSecretName), fullSecretName
 }
 
 // createSecret creates a new secret in GCP Secret Manager
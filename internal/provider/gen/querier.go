@@ -11,12 +11,32 @@ import (
 )
 
 type Querier interface {
+	CreateProviderConnection(ctx context.Context, arg CreateProviderConnectionParams) (ProviderConnection, error)
+	// Inserts nothing unless the secret group or environment belongs to the connection's organization
+	CreateProviderConnectionGrant(ctx context.Context, arg CreateProviderConnectionGrantParams) (ProviderConnectionGrant, error)
 	CreateProviderCredential(ctx context.Context, arg CreateProviderCredentialParams) (ProviderCredential, error)
+	DeleteProviderConnection(ctx context.Context, arg DeleteProviderConnectionParams) error
+	DeleteProviderConnectionGrant(ctx context.Context, arg DeleteProviderConnectionGrantParams) (int64, error)
 	DeleteProviderCredential(ctx context.Context, arg DeleteProviderCredentialParams) error
+	GetProviderConnection(ctx context.Context, arg GetProviderConnectionParams) (ProviderConnection, error)
+	GetProviderConnectionByID(ctx context.Context, id uuid.UUID) (ProviderConnection, error)
+	// Looks up a connection by name in the organization that owns the environment
+	GetProviderConnectionForEnvironment(ctx context.Context, arg GetProviderConnectionForEnvironmentParams) (ProviderConnection, error)
 	GetProviderCredential(ctx context.Context, arg GetProviderCredentialParams) (ProviderCredential, error)
 	GetProviderCredentialByID(ctx context.Context, id uuid.UUID) (ProviderCredential, error)
+	// Reports whether the environment, or the secret group it belongs to, may use the connection
+	HasProviderConnectionGrant(ctx context.Context, arg HasProviderConnectionGrantParams) (bool, error)
+	ListProviderConnectionGrants(ctx context.Context, connectionID uuid.UUID) ([]ProviderConnectionGrant, error)
+	// Lists the environment targets that use a connection's credentials
+	ListProviderConnectionTargets(ctx context.Context, connectionID uuid.NullUUID) ([]ProviderCredential, error)
+	ListProviderConnections(ctx context.Context, organizationID uuid.UUID) ([]ProviderConnection, error)
 	ListProviderCredentials(ctx context.Context, environmentID uuid.UUID) ([]ProviderCredential, error)
+	ListProviderCredentialsByProvider(ctx context.Context, provider string) ([]ProviderCredential, error)
+	UpdateProviderConnectionCredentials(ctx context.Context, arg UpdateProviderConnectionCredentialsParams) (ProviderConnection, error)
 	UpdateProviderCredential(ctx context.Context, arg UpdateProviderCredentialParams) (ProviderCredential, error)
+	UpdateProviderCredentialAutoSync(ctx context.Context, arg UpdateProviderCredentialAutoSyncParams) (ProviderCredential, error)
+	UpdateProviderCredentialKeyMapping(ctx context.Context, arg UpdateProviderCredentialKeyMappingParams) (ProviderCredential, error)
+	UpdateProviderCredentialMirrorMode(ctx context.Context, arg UpdateProviderCredentialMirrorModeParams) (ProviderCredential, error)
 }
 
 var _ Querier = (*Queries)(nil)
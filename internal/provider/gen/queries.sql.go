@@ -13,26 +13,30 @@ import (
 )
 
 const createProviderCredential = `-- name: CreateProviderCredential :one
-INSERT INTO provider_credentials (environment_id, provider, credentials, config,created_by)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, environment_id, provider, credentials, config, created_by, created_at, updated_at
+INSERT INTO provider_credentials (environment_id, provider, name, credentials, config, created_by, connection_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, environment_id, provider, credentials, config, created_by, created_at, updated_at, auto_sync, auto_sync_debounce_seconds, mirror_mode, name, connection_id, key_mapping
 `
 
 type CreateProviderCredentialParams struct {
 	EnvironmentID uuid.UUID       `json:"environment_id"`
 	Provider      string          `json:"provider"`
+	Name          string          `json:"name"`
 	Credentials   []byte          `json:"credentials"`
 	Config        json.RawMessage `json:"config"`
 	CreatedBy     uuid.UUID       `json:"created_by"`
+	ConnectionID  uuid.NullUUID   `json:"connection_id"`
 }
 
 func (q *Queries) CreateProviderCredential(ctx context.Context, arg CreateProviderCredentialParams) (ProviderCredential, error) {
 	row := q.db.QueryRowContext(ctx, createProviderCredential,
 		arg.EnvironmentID,
 		arg.Provider,
+		arg.Name,
 		arg.Credentials,
 		arg.Config,
 		arg.CreatedBy,
+		arg.ConnectionID,
 	)
 	var i ProviderCredential
 	err := row.Scan(
@@ -44,37 +48,43 @@ func (q *Queries) CreateProviderCredential(ctx context.Context, arg CreateProvid
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AutoSync,
+		&i.AutoSyncDebounceSeconds,
+		&i.MirrorMode,
+		&i.Name,
+		&i.ConnectionID,
+		&i.KeyMapping,
 	)
 	return i, err
 }
 
 const deleteProviderCredential = `-- name: DeleteProviderCredential :exec
 DELETE FROM provider_credentials 
-WHERE environment_id = $1 AND provider = $2
+WHERE environment_id = $1 AND name = $2
 `
 
 type DeleteProviderCredentialParams struct {
 	EnvironmentID uuid.UUID `json:"environment_id"`
-	Provider      string    `json:"provider"`
+	Name          string    `json:"name"`
 }
 
 func (q *Queries) DeleteProviderCredential(ctx context.Context, arg DeleteProviderCredentialParams) error {
-	_, err := q.db.ExecContext(ctx, deleteProviderCredential, arg.EnvironmentID, arg.Provider)
+	_, err := q.db.ExecContext(ctx, deleteProviderCredential, arg.EnvironmentID, arg.Name)
 	return err
 }
 
 const getProviderCredential = `-- name: GetProviderCredential :one
-SELECT id, environment_id, provider, credentials, config, created_by, created_at, updated_at FROM provider_credentials 
-WHERE environment_id = $1 AND provider = $2
+SELECT id, environment_id, provider, credentials, config, created_by, created_at, updated_at, auto_sync, auto_sync_debounce_seconds, mirror_mode, name, connection_id, key_mapping FROM provider_credentials 
+WHERE environment_id = $1 AND name = $2
 `
 
 type GetProviderCredentialParams struct {
 	EnvironmentID uuid.UUID `json:"environment_id"`
-	Provider      string    `json:"provider"`
+	Name          string    `json:"name"`
 }
 
 func (q *Queries) GetProviderCredential(ctx context.Context, arg GetProviderCredentialParams) (ProviderCredential, error) {
-	row := q.db.QueryRowContext(ctx, getProviderCredential, arg.EnvironmentID, arg.Provider)
+	row := q.db.QueryRowContext(ctx, getProviderCredential, arg.EnvironmentID, arg.Name)
 	var i ProviderCredential
 	err := row.Scan(
 		&i.ID,
@@ -85,12 +95,18 @@ func (q *Queries) GetProviderCredential(ctx context.Context, arg GetProviderCred
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AutoSync,
+		&i.AutoSyncDebounceSeconds,
+		&i.MirrorMode,
+		&i.Name,
+		&i.ConnectionID,
+		&i.KeyMapping,
 	)
 	return i, err
 }
 
 const getProviderCredentialByID = `-- name: GetProviderCredentialByID :one
-SELECT id, environment_id, provider, credentials, config, created_by, created_at, updated_at FROM provider_credentials 
+SELECT id, environment_id, provider, credentials, config, created_by, created_at, updated_at, auto_sync, auto_sync_debounce_seconds, mirror_mode, name, connection_id, key_mapping FROM provider_credentials 
 WHERE id = $1
 `
 
@@ -106,12 +122,18 @@ func (q *Queries) GetProviderCredentialByID(ctx context.Context, id uuid.UUID) (
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AutoSync,
+		&i.AutoSyncDebounceSeconds,
+		&i.MirrorMode,
+		&i.Name,
+		&i.ConnectionID,
+		&i.KeyMapping,
 	)
 	return i, err
 }
 
 const listProviderCredentials = `-- name: ListProviderCredentials :many
-SELECT id, environment_id, provider, credentials, config, created_by, created_at, updated_at FROM provider_credentials 
+SELECT id, environment_id, provider, credentials, config, created_by, created_at, updated_at, auto_sync, auto_sync_debounce_seconds, mirror_mode, name, connection_id, key_mapping FROM provider_credentials 
 WHERE environment_id = $1 
 ORDER BY created_at DESC
 `
@@ -134,6 +156,56 @@ func (q *Queries) ListProviderCredentials(ctx context.Context, environmentID uui
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.AutoSync,
+			&i.AutoSyncDebounceSeconds,
+			&i.MirrorMode,
+			&i.Name,
+			&i.ConnectionID,
+			&i.KeyMapping,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProviderCredentialsByProvider = `-- name: ListProviderCredentialsByProvider :many
+SELECT id, environment_id, provider, credentials, config, created_by, created_at, updated_at, auto_sync, auto_sync_debounce_seconds, mirror_mode, name, connection_id, key_mapping FROM provider_credentials
+WHERE provider = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListProviderCredentialsByProvider(ctx context.Context, provider string) ([]ProviderCredential, error) {
+	rows, err := q.db.QueryContext(ctx, listProviderCredentialsByProvider, provider)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProviderCredential
+	for rows.Next() {
+		var i ProviderCredential
+		if err := rows.Scan(
+			&i.ID,
+			&i.EnvironmentID,
+			&i.Provider,
+			&i.Credentials,
+			&i.Config,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AutoSync,
+			&i.AutoSyncDebounceSeconds,
+			&i.MirrorMode,
+			&i.Name,
+			&i.ConnectionID,
+			&i.KeyMapping,
 		); err != nil {
 			return nil, err
 		}
@@ -151,13 +223,13 @@ func (q *Queries) ListProviderCredentials(ctx context.Context, environmentID uui
 const updateProviderCredential = `-- name: UpdateProviderCredential :one
 UPDATE provider_credentials 
 SET credentials = $3, config = $4, updated_at = now()
-WHERE environment_id = $1 AND provider = $2
-RETURNING id, environment_id, provider, credentials, config, created_by, created_at, updated_at
+WHERE environment_id = $1 AND name = $2
+RETURNING id, environment_id, provider, credentials, config, created_by, created_at, updated_at, auto_sync, auto_sync_debounce_seconds, mirror_mode, name, connection_id, key_mapping
 `
 
 type UpdateProviderCredentialParams struct {
 	EnvironmentID uuid.UUID       `json:"environment_id"`
-	Provider      string          `json:"provider"`
+	Name          string          `json:"name"`
 	Credentials   []byte          `json:"credentials"`
 	Config        json.RawMessage `json:"config"`
 }
@@ -165,7 +237,7 @@ type UpdateProviderCredentialParams struct {
 func (q *Queries) UpdateProviderCredential(ctx context.Context, arg UpdateProviderCredentialParams) (ProviderCredential, error) {
 	row := q.db.QueryRowContext(ctx, updateProviderCredential,
 		arg.EnvironmentID,
-		arg.Provider,
+		arg.Name,
 		arg.Credentials,
 		arg.Config,
 	)
@@ -179,6 +251,123 @@ func (q *Queries) UpdateProviderCredential(ctx context.Context, arg UpdateProvid
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AutoSync,
+		&i.AutoSyncDebounceSeconds,
+		&i.MirrorMode,
+		&i.Name,
+		&i.ConnectionID,
+		&i.KeyMapping,
+	)
+	return i, err
+}
+
+const updateProviderCredentialAutoSync = `-- name: UpdateProviderCredentialAutoSync :one
+UPDATE provider_credentials
+SET auto_sync = $3, auto_sync_debounce_seconds = $4, updated_at = now()
+WHERE environment_id = $1 AND name = $2
+RETURNING id, environment_id, provider, credentials, config, created_by, created_at, updated_at, auto_sync, auto_sync_debounce_seconds, mirror_mode, name, connection_id, key_mapping
+`
+
+type UpdateProviderCredentialAutoSyncParams struct {
+	EnvironmentID           uuid.UUID `json:"environment_id"`
+	Name                    string    `json:"name"`
+	AutoSync                bool      `json:"auto_sync"`
+	AutoSyncDebounceSeconds int32     `json:"auto_sync_debounce_seconds"`
+}
+
+func (q *Queries) UpdateProviderCredentialAutoSync(ctx context.Context, arg UpdateProviderCredentialAutoSyncParams) (ProviderCredential, error) {
+	row := q.db.QueryRowContext(ctx, updateProviderCredentialAutoSync,
+		arg.EnvironmentID,
+		arg.Name,
+		arg.AutoSync,
+		arg.AutoSyncDebounceSeconds,
+	)
+	var i ProviderCredential
+	err := row.Scan(
+		&i.ID,
+		&i.EnvironmentID,
+		&i.Provider,
+		&i.Credentials,
+		&i.Config,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AutoSync,
+		&i.AutoSyncDebounceSeconds,
+		&i.MirrorMode,
+		&i.Name,
+		&i.ConnectionID,
+		&i.KeyMapping,
+	)
+	return i, err
+}
+
+const updateProviderCredentialKeyMapping = `-- name: UpdateProviderCredentialKeyMapping :one
+UPDATE provider_credentials
+SET key_mapping = $3, updated_at = now()
+WHERE environment_id = $1 AND name = $2
+RETURNING id, environment_id, provider, credentials, config, created_by, created_at, updated_at, auto_sync, auto_sync_debounce_seconds, mirror_mode, name, connection_id, key_mapping
+`
+
+type UpdateProviderCredentialKeyMappingParams struct {
+	EnvironmentID uuid.UUID       `json:"environment_id"`
+	Name          string          `json:"name"`
+	KeyMapping    json.RawMessage `json:"key_mapping"`
+}
+
+func (q *Queries) UpdateProviderCredentialKeyMapping(ctx context.Context, arg UpdateProviderCredentialKeyMappingParams) (ProviderCredential, error) {
+	row := q.db.QueryRowContext(ctx, updateProviderCredentialKeyMapping, arg.EnvironmentID, arg.Name, arg.KeyMapping)
+	var i ProviderCredential
+	err := row.Scan(
+		&i.ID,
+		&i.EnvironmentID,
+		&i.Provider,
+		&i.Credentials,
+		&i.Config,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AutoSync,
+		&i.AutoSyncDebounceSeconds,
+		&i.MirrorMode,
+		&i.Name,
+		&i.ConnectionID,
+		&i.KeyMapping,
+	)
+	return i, err
+}
+
+const updateProviderCredentialMirrorMode = `-- name: UpdateProviderCredentialMirrorMode :one
+UPDATE provider_credentials
+SET mirror_mode = $3, updated_at = now()
+WHERE environment_id = $1 AND name = $2
+RETURNING id, environment_id, provider, credentials, config, created_by, created_at, updated_at, auto_sync, auto_sync_debounce_seconds, mirror_mode, name, connection_id, key_mapping
+`
+
+type UpdateProviderCredentialMirrorModeParams struct {
+	EnvironmentID uuid.UUID `json:"environment_id"`
+	Name          string    `json:"name"`
+	MirrorMode    bool      `json:"mirror_mode"`
+}
+
+func (q *Queries) UpdateProviderCredentialMirrorMode(ctx context.Context, arg UpdateProviderCredentialMirrorModeParams) (ProviderCredential, error) {
+	row := q.db.QueryRowContext(ctx, updateProviderCredentialMirrorMode, arg.EnvironmentID, arg.Name, arg.MirrorMode)
+	var i ProviderCredential
+	err := row.Scan(
+		&i.ID,
+		&i.EnvironmentID,
+		&i.Provider,
+		&i.Credentials,
+		&i.Config,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AutoSync,
+		&i.AutoSyncDebounceSeconds,
+		&i.MirrorMode,
+		&i.Name,
+		&i.ConnectionID,
+		&i.KeyMapping,
 	)
 	return i, err
 }
@@ -5,7 +5,10 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -21,10 +24,17 @@ type GitHubProvider struct {
 	config      GitHubConfig
 	logger      *logrus.Logger
 	client      *github.Client
+	appTokens   *githubAppTokenSource // Set for GitHub App auth
+	executor    *SyncExecutor
 }
 
 // NewGitHubProvider creates a new GitHub provider instance
 func NewGitHubProvider(credentials map[string]interface{}, config map[string]interface{}, logger *logrus.Logger) (*GitHubProvider, error) {
+	return newGitHubProvider(credentials, config, logger, newGitHubAppTokenCache())
+}
+
+// newGitHubProvider creates a GitHub provider that takes GitHub App installation tokens from the given cache
+func newGitHubProvider(credentials map[string]interface{}, config map[string]interface{}, logger *logrus.Logger, appTokenCache *githubAppTokenCache) (*GitHubProvider, error) {
 	// Parse credentials
 	credBytes, err := json.Marshal(credentials)
 	if err != nil {
@@ -47,6 +57,16 @@ func NewGitHubProvider(credentials map[string]interface{}, config map[string]int
 		return nil, fmt.Errorf("failed to unmarshal GitHub config: %w", err)
 	}
 
+	if githubConfig.Scope == "" {
+		githubConfig.Scope = GitHubScopeRepository
+	}
+	if githubConfig.SecretType == "" {
+		githubConfig.SecretType = GitHubSecretTypeActions
+	}
+	if githubConfig.Scope == GitHubScopeOrganization && githubConfig.SecretVisibility == "" {
+		githubConfig.SecretVisibility = GitHubVisibilityPrivate
+	}
+
 	// Set default retry configuration if not provided
 	if githubConfig.RetryConfig.MaxRetries == 0 {
 		githubConfig.RetryConfig = RetryConfig{
@@ -57,51 +77,84 @@ func NewGitHubProvider(credentials map[string]interface{}, config map[string]int
 		}
 	}
 
-	// Create GitHub client
-	client := github.NewClient(nil).WithAuthToken(githubCreds.Token)
+	if githubCreds.AuthMethod == "" {
+		githubCreds.AuthMethod = GitHubAuthToken
+	}
+
+	// Create GitHub client. A GitHub App authenticates every request with an installation token
+	// that is minted on first use, shared through the cache and refreshed before it expires.
+	var client *github.Client
+	var appTokens *githubAppTokenSource
+	switch githubCreds.AuthMethod {
+	case GitHubAuthToken:
+		client = github.NewClient(nil).WithAuthToken(githubCreds.Token)
+	case GitHubAuthApp:
+		appTokens, err = appTokenCache.source(githubCreds.AppID, githubCreds.InstallationID, githubCreds.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		client = github.NewClient(&http.Client{Transport: &githubAppTransport{source: appTokens, base: http.DefaultTransport}})
+	default:
+		return nil, fmt.Errorf("unsupported GitHub auth method: %s", githubCreds.AuthMethod)
+	}
 
 	return &GitHubProvider{
 		credentials: githubCreds,
 		config:      githubConfig,
 		logger:      logger,
 		client:      client,
+		appTokens:   appTokens,
+		executor:    NewSyncExecutor(DefaultSyncExecutorConfig(), logger),
 	}, nil
 }
 
-// Sync syncs secrets to GitHub repository with retry logic
+// Sync syncs secrets and variables to GitHub with retry logic
 func (g *GitHubProvider) Sync(ctx context.Context, secrets []Secret) ([]SyncResult, error) {
 	logEntry := g.logger.WithFields(logrus.Fields{
 		"provider":     "github",
 		"owner":        g.config.Owner,
 		"repository":   g.config.Repository,
 		"environment":  g.config.Environment,
+		"scope":        g.config.Scope,
+		"secret_type":  g.config.SecretType,
 		"secret_count": len(secrets),
 	})
 
 	logEntry.Info("Starting GitHub secrets sync with retry logic")
 
-	var results []SyncResult
-
+	// Every secret is encrypted with the same public key, so fetch it once.
+	// Variables are stored in plain text and need no key.
+	withKey := false
 	for _, secret := range secrets {
-		result := SyncResult{
-			Name:    secret.Name,
-			Success: false,
+		if !g.isVariable(secret.Name) {
+			withKey = true
+			break
+		}
+	}
+	target, err := g.resolveSecretTarget(ctx, withKey)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to resolve GitHub secret target")
+		results := make([]SyncResult, len(secrets))
+		for i, secret := range secrets {
+			results[i] = SyncResult{Name: secret.Name, Error: err.Error()}
 		}
+		return results, nil
+	}
 
-		// Use retry wrapper for creating/updating secret
-		err := g.retryCreateSecret(ctx, secret.Name, secret.Value)
-		if err != nil {
-			result.Error = err.Error()
+	results := g.executor.Run(ctx, ProviderGitHub, g.config.RetryConfig, secrets, func(ctx context.Context, secret Secret) error {
+		if g.isVariable(secret.Name) {
+			return g.putVariable(ctx, target, g.RemoteName(secret.Name), secret.Value)
+		}
+		return g.putSecret(ctx, target, g.RemoteName(secret.Name), secret.Value)
+	})
+
+	for _, result := range results {
+		if !result.Success {
 			logEntry.WithFields(logrus.Fields{
-				"secret_name": secret.Name,
-				"error":       err.Error(),
+				"secret_name": result.Name,
+				"error":       result.Error,
 			}).Error("Failed to sync secret to GitHub after all retry attempts")
-		} else {
-			result.Success = true
-			logEntry.WithField("secret_name", secret.Name).Info("Successfully synced secret to GitHub")
 		}
-
-		results = append(results, result)
 	}
 
 	logEntry.WithFields(logrus.Fields{
@@ -112,192 +165,590 @@ func (g *GitHubProvider) Sync(ctx context.Context, secrets []Secret) ([]SyncResu
 	return results, nil
 }
 
-// retryCreateSecret wraps the createSecret function with retry logic
-func (g *GitHubProvider) retryCreateSecret(ctx context.Context, secretName, secretValue string) error {
+// Delete removes secrets and variables from GitHub
+func (g *GitHubProvider) Delete(ctx context.Context, names []string) ([]SyncResult, error) {
 	logEntry := g.logger.WithFields(logrus.Fields{
-		"secret_name": secretName,
-		"max_retries": g.config.RetryConfig.MaxRetries,
+		"provider":     "github",
+		"owner":        g.config.Owner,
+		"repository":   g.config.Repository,
+		"environment":  g.config.Environment,
+		"scope":        g.config.Scope,
+		"secret_type":  g.config.SecretType,
+		"secret_count": len(names),
 	})
 
-	var lastErr error
-	for attempt := 1; attempt <= g.config.RetryConfig.MaxRetries; attempt++ {
-		logEntry.WithField("attempt", attempt).Info("Attempting to create/update secret")
+	logEntry.Info("Starting GitHub secrets removal")
 
-		err := g.createSecret(ctx, secretName, secretValue)
-		if err != nil {
-			lastErr = err
-
-			// Don't retry for environment not found errors - they will always fail
-			if err == appErrors.ErrGitHubEnvironmentNotFound {
-				logEntry.WithFields(logrus.Fields{
-					"attempt": attempt,
-					"error":   err.Error(),
-				}).Error("GitHub environment not found, skipping retries")
-				return err
-			}
+	repoID, err := g.environmentRepositoryID(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-			logEntry.WithFields(logrus.Fields{
-				"attempt": attempt,
-				"error":   err.Error(),
-			}).Warn("Failed to create/update secret, will retry")
-
-			if attempt < g.config.RetryConfig.MaxRetries {
-				delay := g.calculateRetryDelay(attempt)
-				logEntry.WithField("delay", delay).Info("Waiting before retry")
-				time.Sleep(delay)
-				continue
-			}
+	var results []SyncResult
+	for _, name := range names {
+		result := SyncResult{Name: name, Success: true}
+		if g.isVariable(name) {
+			err = g.deleteVariable(ctx, g.RemoteName(name))
 		} else {
-			logEntry.WithField("attempt", attempt).Info("Successfully created/updated secret")
-			return nil
+			err = g.deleteSecret(ctx, repoID, g.RemoteName(name))
 		}
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			logEntry.WithFields(logrus.Fields{
+				"secret_name": name,
+				"error":       err.Error(),
+			}).Error("Failed to delete secret from GitHub")
+		}
+
+		results = append(results, result)
 	}
 
-	return fmt.Errorf("failed after %d attempts: %w", g.config.RetryConfig.MaxRetries, lastErr)
+	logEntry.Info("Completed GitHub secrets removal")
+	return results, nil
 }
 
-// createSecret creates or updates a GitHub repository or environment secret
-func (g *GitHubProvider) createSecret(ctx context.Context, secretName, secretValue string) error {
+// sharesManagedSecrets reports whether secrets written with the two configs could be pruned as each other's:
+// they go to the same secrets and variables, and one managed-name prefix starts with the other. Owners and
+// repositories are compared without case, as GitHub does.
+func (c GitHubConfig) sharesManagedSecrets(other GitHubConfig) bool {
+	return c.secretStore() == other.secretStore() &&
+		(strings.HasPrefix(c.managedPrefix(), other.managedPrefix()) || strings.HasPrefix(other.managedPrefix(), c.managedPrefix()))
+}
+
+// secretStore identifies the secrets and variables a config writes to
+func (c GitHubConfig) secretStore() string {
+	scope, secretType := c.Scope, c.SecretType
+	if scope == "" {
+		scope = GitHubScopeRepository
+	}
+	if secretType == "" {
+		secretType = GitHubSecretTypeActions
+	}
+
+	store := strings.ToLower(c.Owner) + "/" + scope + "/" + secretType
+	if scope == GitHubScopeOrganization {
+		return store
+	}
+	store += "/" + strings.ToLower(c.Repository)
+	if secretType == GitHubSecretTypeActions && c.Environment != "" && c.Environment != "default" {
+		store += "/" + c.Environment
+	}
+	return store
+}
+
+// managedPrefix returns the start of every remote name written with a config
+func (c GitHubConfig) managedPrefix() string {
+	if c.Prefix == "" {
+		return ""
+	}
+	return strings.ToUpper(c.Prefix + "_")
+}
+
+// SetOwner does nothing: GitHub secrets carry no metadata, so the prefix stands in for the owner.
+// The provider service refuses mirror mode for targets whose prefixes overlap with another target's.
+func (g *GitHubProvider) SetOwner(SecretOwner) {}
+
+// PruneCandidates returns the secrets under the managed-name prefix that are not among the given secret names.
+// GitHub secrets carry no labels, so the prefix is the only ownership marker and is required.
+func (g *GitHubProvider) PruneCandidates(ctx context.Context, keep []string) ([]string, error) {
+	if g.config.Prefix == "" {
+		return nil, appErrors.ErrMirrorModeRequiresPrefix
+	}
+
+	listed, err := g.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		kept[g.RemoteName(name)] = true
+	}
+
+	managedPrefix := g.RemoteName("")
+	var candidates []string
+	for _, secret := range listed {
+		if !kept[secret.Name] && strings.HasPrefix(secret.Name, managedPrefix) {
+			candidates = append(candidates, secret.Name)
+		}
+	}
+	return candidates, nil
+}
+
+// Prune deletes the given secrets and variables
+func (g *GitHubProvider) Prune(ctx context.Context, remoteNames []string) ([]SyncResult, error) {
 	logEntry := g.logger.WithFields(logrus.Fields{
-		"secret_name": secretName,
+		"provider":    "github",
+		"owner":       g.config.Owner,
+		"repository":  g.config.Repository,
 		"environment": g.config.Environment,
+		"scope":       g.config.Scope,
+		"secret_type": g.config.SecretType,
 	})
 
-	logEntry.Info("Creating or updating GitHub secret")
+	repoID, err := g.environmentRepositoryID(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	// Determine if we're using repository secrets or environment secrets
-	if g.config.Environment == "" || g.config.Environment == "default" {
-		return g.createRepositorySecret(ctx, secretName, secretValue)
-	} else {
-		return g.createEnvironmentSecret(ctx, secretName, secretValue)
+	// Remote names do not say whether they are secrets or variables, so look the variables up
+	variables, err := g.listVariables(ctx, "")
+	if err != nil {
+		return nil, err
 	}
+	isVariable := make(map[string]bool, len(variables))
+	for _, variable := range variables {
+		isVariable[variable.Name] = true
+	}
+
+	var results []SyncResult
+	for _, name := range remoteNames {
+		result := SyncResult{Name: name, Success: true}
+		if isVariable[name] {
+			err = g.deleteVariable(ctx, name)
+		} else {
+			err = g.deleteSecret(ctx, repoID, name)
+		}
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			logEntry.WithFields(logrus.Fields{
+				"secret_name": name,
+				"error":       err.Error(),
+			}).Error("Failed to prune secret from GitHub")
+		}
+		results = append(results, result)
+	}
+
+	logEntry.WithField("pruned_count", len(results)).Info("Completed GitHub secrets prune")
+	return results, nil
 }
 
-// createRepositorySecret creates or updates a repository secret
-func (g *GitHubProvider) createRepositorySecret(ctx context.Context, secretName, secretValue string) error {
-	logEntry := g.logger.WithFields(logrus.Fields{
-		"secret_name": secretName,
-		"secret_type": "repository",
-	})
+// RemoteName returns the name a secret is stored under on GitHub, which upper-cases secret names
+// and prepends the managed-name prefix when one is configured
+func (g *GitHubProvider) RemoteName(name string) string {
+	return g.config.managedPrefix() + strings.ToUpper(name)
+}
+
+// isOrganization reports whether secrets are written to the organization named by the owner
+func (g *GitHubProvider) isOrganization() bool {
+	return g.config.Scope == GitHubScopeOrganization
+}
+
+// usesEnvironment reports whether secrets are written to a repository environment.
+// Only Actions secrets and variables can belong to an environment.
+func (g *GitHubProvider) usesEnvironment() bool {
+	return !g.isOrganization() && g.config.SecretType == GitHubSecretTypeActions &&
+		g.config.Environment != "" && g.config.Environment != "default"
+}
+
+// isVariable reports whether a key is written as an Actions variable instead of a secret
+func (g *GitHubProvider) isVariable(name string) bool {
+	return slices.Contains(g.config.VariableKeys, name)
+}
 
-	logEntry.Info("Creating or updating repository secret")
+// level names where secrets are written, for error messages
+func (g *GitHubProvider) level() string {
+	switch {
+	case g.isOrganization():
+		return "organization"
+	case g.usesEnvironment():
+		return "environment"
+	default:
+		return "repository"
+	}
+}
 
-	// Get repository public key
-	publicKey, err := g.getRepositoryPublicKey(ctx)
+// environmentRepositoryID returns the repository ID needed for environment secrets,
+// or zero when repository or organization secrets are used
+func (g *GitHubProvider) environmentRepositoryID(ctx context.Context) (int64, error) {
+	if !g.usesEnvironment() {
+		return 0, nil
+	}
+	repo, _, err := g.client.Repositories.Get(ctx, g.config.Owner, g.config.Repository)
 	if err != nil {
-		return fmt.Errorf("failed to get repository public key: %w", err)
+		return 0, fmt.Errorf("failed to get repository: %w", err)
 	}
+	return repo.GetID(), nil
+}
 
-	// Encrypt secret value using libsodium (NaCl) box encryption
-	logEntry.WithFields(logrus.Fields{
-		"public_key_id":     publicKey.KeyID,
-		"public_key_length": len(publicKey.Key),
-	}).Debug("Encrypting secret value for repository")
+// deleteSecret removes a secret of the configured type, from the environment when repoID is set.
+// A secret that is already gone is not an error.
+func (g *GitHubProvider) deleteSecret(ctx context.Context, repoID int64, remoteName string) error {
+	var resp *github.Response
+	var err error
+	switch {
+	case g.config.SecretType == GitHubSecretTypeDependabot && g.isOrganization():
+		resp, err = g.client.Dependabot.DeleteOrgSecret(ctx, g.config.Owner, remoteName)
+	case g.config.SecretType == GitHubSecretTypeDependabot:
+		resp, err = g.client.Dependabot.DeleteRepoSecret(ctx, g.config.Owner, g.config.Repository, remoteName)
+	case g.config.SecretType == GitHubSecretTypeCodespaces && g.isOrganization():
+		resp, err = g.client.Codespaces.DeleteOrgSecret(ctx, g.config.Owner, remoteName)
+	case g.config.SecretType == GitHubSecretTypeCodespaces:
+		resp, err = g.client.Codespaces.DeleteRepoSecret(ctx, g.config.Owner, g.config.Repository, remoteName)
+	case g.isOrganization():
+		resp, err = g.client.Actions.DeleteOrgSecret(ctx, g.config.Owner, remoteName)
+	case repoID != 0:
+		resp, err = g.client.Actions.DeleteEnvSecret(ctx, int(repoID), g.config.Environment, remoteName)
+	default:
+		resp, err = g.client.Actions.DeleteRepoSecret(ctx, g.config.Owner, g.config.Repository, remoteName)
+	}
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	return nil
+}
 
-	encryptedValue, err := g.encryptSecretValueWithLibsodium(secretValue, publicKey.Key)
+// List returns the secrets and variables, limited to the managed-name prefix when one is configured.
+// GitHub never returns secret values, so only their names and update times are reported.
+func (g *GitHubProvider) List(ctx context.Context) ([]RemoteSecret, error) {
+	repoID, err := g.environmentRepositoryID(ctx)
 	if err != nil {
-		logEntry.WithField("error", err.Error()).Error("Failed to encrypt secret value")
-		return appErrors.ErrGitHubEncryptionFailed
+		return nil, err
 	}
 
-	logEntry.WithFields(logrus.Fields{
-		"encrypted_value_length": len(encryptedValue),
-	}).Debug("Secret value encrypted successfully for repository")
+	managedPrefix := ""
+	if g.config.Prefix != "" {
+		managedPrefix = g.RemoteName("")
+	}
 
-	// Create or update the secret using GitHub API
-	secret := &github.EncryptedSecret{
-		Name:           secretName,
-		KeyID:          publicKey.KeyID,
-		EncryptedValue: encryptedValue,
+	var secrets []RemoteSecret
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := g.listSecrets(ctx, repoID, opts)
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound && repoID != 0 {
+				return nil, appErrors.ErrGitHubEnvironmentNotFound
+			}
+			return nil, fmt.Errorf("failed to list GitHub secrets: %w", err)
+		}
+
+		for _, secret := range page.Secrets {
+			if !strings.HasPrefix(secret.Name, managedPrefix) {
+				continue
+			}
+			updatedAt := secret.UpdatedAt.Time
+			secrets = append(secrets, RemoteSecret{Name: secret.Name, UpdatedAt: &updatedAt})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
 	}
 
-	_, err = g.client.Actions.CreateOrUpdateRepoSecret(ctx, g.config.Owner, g.config.Repository, secret)
+	variables, err := g.listVariables(ctx, managedPrefix)
 	if err != nil {
-		return fmt.Errorf("failed to create/update repository secret: %w", err)
+		return nil, err
 	}
 
-	logEntry.Info("Successfully created/updated repository secret")
-	return nil
+	return append(secrets, variables...), nil
 }
 
-// createEnvironmentSecret creates or updates an environment secret
-func (g *GitHubProvider) createEnvironmentSecret(ctx context.Context, secretName, secretValue string) error {
-	logEntry := g.logger.WithFields(logrus.Fields{
-		"secret_name": secretName,
-		"environment": g.config.Environment,
-		"secret_type": "environment",
-	})
+// listSecrets lists one page of secrets of the configured type, from the environment when repoID is set
+func (g *GitHubProvider) listSecrets(ctx context.Context, repoID int64, opts *github.ListOptions) (*github.Secrets, *github.Response, error) {
+	switch {
+	case g.config.SecretType == GitHubSecretTypeDependabot && g.isOrganization():
+		return g.client.Dependabot.ListOrgSecrets(ctx, g.config.Owner, opts)
+	case g.config.SecretType == GitHubSecretTypeDependabot:
+		return g.client.Dependabot.ListRepoSecrets(ctx, g.config.Owner, g.config.Repository, opts)
+	case g.config.SecretType == GitHubSecretTypeCodespaces && g.isOrganization():
+		return g.client.Codespaces.ListOrgSecrets(ctx, g.config.Owner, opts)
+	case g.config.SecretType == GitHubSecretTypeCodespaces:
+		return g.client.Codespaces.ListRepoSecrets(ctx, g.config.Owner, g.config.Repository, opts)
+	case g.isOrganization():
+		return g.client.Actions.ListOrgSecrets(ctx, g.config.Owner, opts)
+	case repoID != 0:
+		return g.client.Actions.ListEnvSecrets(ctx, int(repoID), g.config.Environment, opts)
+	default:
+		return g.client.Actions.ListRepoSecrets(ctx, g.config.Owner, g.config.Repository, opts)
+	}
+}
 
-	logEntry.Info("Creating or updating environment secret")
+// listVariables returns the Actions variables under the given prefix together with their values.
+// Nothing is listed unless variable keys are configured, so existing variables are left alone.
+func (g *GitHubProvider) listVariables(ctx context.Context, managedPrefix string) ([]RemoteSecret, error) {
+	if len(g.config.VariableKeys) == 0 {
+		return nil, nil
+	}
 
-	// Get repository ID first
-	repo, _, err := g.client.Repositories.Get(ctx, g.config.Owner, g.config.Repository)
+	var variables []RemoteSecret
+	opts := &github.ListOptions{PerPage: 30}
+	for {
+		var page *github.ActionsVariables
+		var resp *github.Response
+		var err error
+		switch {
+		case g.isOrganization():
+			page, resp, err = g.client.Actions.ListOrgVariables(ctx, g.config.Owner, opts)
+		case g.usesEnvironment():
+			page, resp, err = g.client.Actions.ListEnvVariables(ctx, g.config.Owner, g.config.Repository, g.config.Environment, opts)
+		default:
+			page, resp, err = g.client.Actions.ListRepoVariables(ctx, g.config.Owner, g.config.Repository, opts)
+		}
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound && g.usesEnvironment() {
+				return nil, appErrors.ErrGitHubEnvironmentNotFound
+			}
+			return nil, fmt.Errorf("failed to list GitHub variables: %w", err)
+		}
+
+		for _, variable := range page.Variables {
+			if !strings.HasPrefix(variable.Name, managedPrefix) {
+				continue
+			}
+			remote := RemoteSecret{Name: variable.Name, Value: variable.Value, HasValue: true}
+			if variable.UpdatedAt != nil {
+				updatedAt := variable.UpdatedAt.Time
+				remote.UpdatedAt = &updatedAt
+			}
+			variables = append(variables, remote)
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return variables, nil
+}
+
+// Read returns the named variables with their values and the metadata of the named secrets,
+// whose values cannot be read back from GitHub
+func (g *GitHubProvider) Read(ctx context.Context, remoteNames []string) ([]RemoteSecret, error) {
+	listed, err := g.List(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get repository: %w", err)
+		return nil, err
 	}
 
-	// Get environment public key
-	publicKey, err := g.getEnvironmentPublicKey(ctx, repo.GetID())
+	wanted := make(map[string]bool, len(remoteNames))
+	for _, name := range remoteNames {
+		wanted[name] = true
+	}
+
+	var secrets []RemoteSecret
+	for _, secret := range listed {
+		if wanted[secret.Name] {
+			secrets = append(secrets, secret)
+		}
+	}
+
+	return secrets, nil
+}
+
+// githubSecretTarget is where secrets are written: the repository or organization, or a repository
+// environment when repoID is set, together with the public key secrets are encrypted with and the
+// repositories an organization secret is visible to
+type githubSecretTarget struct {
+	repoID          int64
+	publicKey       *GitHubPublicKey
+	selectedRepoIDs []int64
+}
+
+// resolveSecretTarget looks up the repository ID, the selected repository IDs and, when withKey is set,
+// the public key for the configured target
+func (g *GitHubProvider) resolveSecretTarget(ctx context.Context, withKey bool) (githubSecretTarget, error) {
+	repoID, err := g.environmentRepositoryID(ctx)
 	if err != nil {
-		// Check if it's a 404 error (environment not found)
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "Not Found") {
-			logEntry.WithField("environment", g.config.Environment).Error("GitHub environment not found")
-			return appErrors.ErrGitHubEnvironmentNotFound
+		return githubSecretTarget{}, err
+	}
+
+	var selectedRepoIDs []int64
+	if g.isOrganization() && g.config.SecretVisibility == GitHubVisibilitySelected {
+		selectedRepoIDs, err = g.selectedRepositoryIDs(ctx)
+		if err != nil {
+			return githubSecretTarget{}, err
 		}
-		return fmt.Errorf("failed to get environment public key: %w", err)
 	}
 
-	// Encrypt secret value using libsodium (NaCl) box encryption
-	logEntry.WithFields(logrus.Fields{
-		"public_key_id":     publicKey.KeyID,
-		"public_key_length": len(publicKey.Key),
-		"environment":       g.config.Environment,
-	}).Debug("Encrypting secret value for environment")
+	if !withKey {
+		return githubSecretTarget{repoID: repoID, selectedRepoIDs: selectedRepoIDs}, nil
+	}
 
-	encryptedValue, err := g.encryptSecretValueWithLibsodium(secretValue, publicKey.Key)
+	if repoID == 0 {
+		publicKey, err := g.getPublicKey(ctx)
+		if err != nil {
+			return githubSecretTarget{}, err
+		}
+		return githubSecretTarget{publicKey: publicKey, selectedRepoIDs: selectedRepoIDs}, nil
+	}
+
+	publicKey, err := g.getEnvironmentPublicKey(ctx, repoID)
+	if err != nil {
+		var githubErr *github.ErrorResponse
+		if errors.As(err, &githubErr) && githubErr.Response != nil && githubErr.Response.StatusCode == http.StatusNotFound {
+			g.logger.WithField("environment", g.config.Environment).Error("GitHub environment not found")
+			return githubSecretTarget{}, appErrors.ErrGitHubEnvironmentNotFound
+		}
+		return githubSecretTarget{}, err
+	}
+	return githubSecretTarget{repoID: repoID, publicKey: publicKey}, nil
+}
+
+// putSecret encrypts a value with the target's public key and creates or updates the secret
+func (g *GitHubProvider) putSecret(ctx context.Context, target githubSecretTarget, secretName, secretValue string) error {
+	logEntry := g.logger.WithFields(logrus.Fields{
+		"secret_name": secretName,
+		"environment": g.config.Environment,
+	})
+
+	encryptedValue, err := g.encryptSecretValueWithLibsodium(secretValue, target.publicKey.Key)
 	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to encrypt secret value")
 		return appErrors.ErrGitHubEncryptionFailed
 	}
 
-	logEntry.WithFields(logrus.Fields{
-		"encrypted_value_length": len(encryptedValue),
-		"environment":            g.config.Environment,
-	}).Debug("Secret value encrypted successfully for environment")
-
-	// Create or update the environment secret using GitHub API
 	secret := &github.EncryptedSecret{
 		Name:           secretName,
-		KeyID:          publicKey.KeyID,
+		KeyID:          target.publicKey.KeyID,
 		EncryptedValue: encryptedValue,
 	}
+	if g.isOrganization() {
+		secret.Visibility = g.config.SecretVisibility
+		secret.SelectedRepositoryIDs = target.selectedRepoIDs
+	}
 
-	_, err = g.client.Actions.CreateOrUpdateEnvSecret(ctx, int(repo.GetID()), g.config.Environment, secret)
+	var resp *github.Response
+	switch {
+	case g.config.SecretType == GitHubSecretTypeDependabot:
+		dependabotSecret := &github.DependabotEncryptedSecret{
+			Name:                  secret.Name,
+			KeyID:                 secret.KeyID,
+			EncryptedValue:        secret.EncryptedValue,
+			Visibility:            secret.Visibility,
+			SelectedRepositoryIDs: github.DependabotSecretsSelectedRepoIDs(secret.SelectedRepositoryIDs),
+		}
+		if g.isOrganization() {
+			resp, err = g.client.Dependabot.CreateOrUpdateOrgSecret(ctx, g.config.Owner, dependabotSecret)
+		} else {
+			resp, err = g.client.Dependabot.CreateOrUpdateRepoSecret(ctx, g.config.Owner, g.config.Repository, dependabotSecret)
+		}
+	case g.config.SecretType == GitHubSecretTypeCodespaces && g.isOrganization():
+		resp, err = g.client.Codespaces.CreateOrUpdateOrgSecret(ctx, g.config.Owner, secret)
+	case g.config.SecretType == GitHubSecretTypeCodespaces:
+		resp, err = g.client.Codespaces.CreateOrUpdateRepoSecret(ctx, g.config.Owner, g.config.Repository, secret)
+	case g.isOrganization():
+		resp, err = g.client.Actions.CreateOrUpdateOrgSecret(ctx, g.config.Owner, secret)
+	case target.repoID != 0:
+		resp, err = g.client.Actions.CreateOrUpdateEnvSecret(ctx, int(target.repoID), g.config.Environment, secret)
+	default:
+		resp, err = g.client.Actions.CreateOrUpdateRepoSecret(ctx, g.config.Owner, g.config.Repository, secret)
+	}
 	if err != nil {
-		// Check if it's a 404 error (environment not found)
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "Not Found") {
-			logEntry.WithField("environment", g.config.Environment).Error("GitHub environment not found")
+		if target.repoID != 0 && resp != nil && resp.StatusCode == http.StatusNotFound {
 			return appErrors.ErrGitHubEnvironmentNotFound
 		}
-		return fmt.Errorf("failed to create/update environment secret: %w", err)
+		return fmt.Errorf("failed to create/update %s secret: %w", g.level(), err)
 	}
 
-	logEntry.Info("Successfully created/updated environment secret")
+	logEntry.Debug("Successfully created/updated GitHub secret")
 	return nil
 }
 
-// getRepositoryPublicKey gets the repository's public key for encryption
-func (g *GitHubProvider) getRepositoryPublicKey(ctx context.Context) (*GitHubPublicKey, error) {
+// putVariable updates an Actions variable, creating it when it does not exist yet.
+// GitHub has no upsert for variables, so a missing variable shows up as a 404 on update.
+func (g *GitHubProvider) putVariable(ctx context.Context, target githubSecretTarget, variableName, value string) error {
+	variable := &github.ActionsVariable{Name: variableName, Value: value}
+	if g.isOrganization() {
+		variable.Visibility = github.Ptr(g.config.SecretVisibility)
+		if g.config.SecretVisibility == GitHubVisibilitySelected {
+			selectedRepoIDs := github.SelectedRepoIDs(target.selectedRepoIDs)
+			variable.SelectedRepositoryIDs = &selectedRepoIDs
+		}
+	}
+
+	var resp *github.Response
+	var err error
+	switch {
+	case g.isOrganization():
+		resp, err = g.client.Actions.UpdateOrgVariable(ctx, g.config.Owner, variable)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			resp, err = g.client.Actions.CreateOrgVariable(ctx, g.config.Owner, variable)
+		}
+	case g.usesEnvironment():
+		resp, err = g.client.Actions.UpdateEnvVariable(ctx, g.config.Owner, g.config.Repository, g.config.Environment, variable)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			resp, err = g.client.Actions.CreateEnvVariable(ctx, g.config.Owner, g.config.Repository, g.config.Environment, variable)
+		}
+	default:
+		resp, err = g.client.Actions.UpdateRepoVariable(ctx, g.config.Owner, g.config.Repository, variable)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			resp, err = g.client.Actions.CreateRepoVariable(ctx, g.config.Owner, g.config.Repository, variable)
+		}
+	}
+	if err != nil {
+		if g.usesEnvironment() && resp != nil && resp.StatusCode == http.StatusNotFound {
+			return appErrors.ErrGitHubEnvironmentNotFound
+		}
+		return fmt.Errorf("failed to create/update %s variable: %w", g.level(), err)
+	}
+
+	g.logger.WithField("variable_name", variableName).Debug("Successfully created/updated GitHub variable")
+	return nil
+}
+
+// deleteVariable removes an Actions variable. A variable that is already gone is not an error.
+func (g *GitHubProvider) deleteVariable(ctx context.Context, variableName string) error {
+	var resp *github.Response
+	var err error
+	switch {
+	case g.isOrganization():
+		resp, err = g.client.Actions.DeleteOrgVariable(ctx, g.config.Owner, variableName)
+	case g.usesEnvironment():
+		resp, err = g.client.Actions.DeleteEnvVariable(ctx, g.config.Owner, g.config.Repository, g.config.Environment, variableName)
+	default:
+		resp, err = g.client.Actions.DeleteRepoVariable(ctx, g.config.Owner, g.config.Repository, variableName)
+	}
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return fmt.Errorf("failed to delete variable: %w", err)
+	}
+	return nil
+}
+
+// selectedRepositoryIDs resolves the selected repository names to the IDs GitHub expects
+func (g *GitHubProvider) selectedRepositoryIDs(ctx context.Context) ([]int64, error) {
+	ids := make([]int64, 0, len(g.config.SelectedRepositories))
+	for _, name := range g.config.SelectedRepositories {
+		repo, _, err := g.client.Repositories.Get(ctx, g.config.Owner, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get selected repository %s: %w", name, err)
+		}
+		ids = append(ids, repo.GetID())
+	}
+	return ids, nil
+}
+
+// getPublicKey gets the repository's or organization's public key for the configured secret type
+func (g *GitHubProvider) getPublicKey(ctx context.Context) (*GitHubPublicKey, error) {
 	logEntry := g.logger.WithFields(logrus.Fields{
-		"owner":      g.config.Owner,
-		"repository": g.config.Repository,
+		"owner":       g.config.Owner,
+		"repository":  g.config.Repository,
+		"scope":       g.config.Scope,
+		"secret_type": g.config.SecretType,
 	})
 
-	logEntry.Info("Getting repository public key")
-
-	publicKey, _, err := g.client.Actions.GetRepoPublicKey(ctx, g.config.Owner, g.config.Repository)
+	logEntry.Infof("Getting %s public key", g.level())
+
+	var publicKey *github.PublicKey
+	var err error
+	switch {
+	case g.config.SecretType == GitHubSecretTypeDependabot && g.isOrganization():
+		publicKey, _, err = g.client.Dependabot.GetOrgPublicKey(ctx, g.config.Owner)
+	case g.config.SecretType == GitHubSecretTypeDependabot:
+		publicKey, _, err = g.client.Dependabot.GetRepoPublicKey(ctx, g.config.Owner, g.config.Repository)
+	case g.config.SecretType == GitHubSecretTypeCodespaces && g.isOrganization():
+		publicKey, _, err = g.client.Codespaces.GetOrgPublicKey(ctx, g.config.Owner)
+	case g.config.SecretType == GitHubSecretTypeCodespaces:
+		publicKey, _, err = g.client.Codespaces.GetRepoPublicKey(ctx, g.config.Owner, g.config.Repository)
+	case g.isOrganization():
+		publicKey, _, err = g.client.Actions.GetOrgPublicKey(ctx, g.config.Owner)
+	default:
+		publicKey, _, err = g.client.Actions.GetRepoPublicKey(ctx, g.config.Owner, g.config.Repository)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get repository public key: %w", err)
+		return nil, fmt.Errorf("failed to get %s public key: %w", g.level(), err)
 	}
 
 	return &GitHubPublicKey{
@@ -373,45 +824,40 @@ func (g *GitHubProvider) encryptSecretValueWithLibsodium(secretValue, publicKeyB
 	return result, nil
 }
 
-// calculateRetryDelay calculates the delay for the next retry attempt
-func (g *GitHubProvider) calculateRetryDelay(attempt int) time.Duration {
-	switch g.config.RetryConfig.BackoffType {
-	case "exponential":
-		delay := g.config.RetryConfig.RetryDelay * time.Duration(1<<(attempt-1))
-		if delay > g.config.RetryConfig.MaxDelay {
-			delay = g.config.RetryConfig.MaxDelay
-		}
-		return delay
-	case "linear":
-		delay := g.config.RetryConfig.RetryDelay * time.Duration(attempt)
-		if delay > g.config.RetryConfig.MaxDelay {
-			delay = g.config.RetryConfig.MaxDelay
-		}
-		return delay
-	case "constant":
-		return g.config.RetryConfig.RetryDelay
-	default:
-		// Default to exponential backoff
-		delay := g.config.RetryConfig.RetryDelay * time.Duration(1<<(attempt-1))
-		if delay > g.config.RetryConfig.MaxDelay {
-			delay = g.config.RetryConfig.MaxDelay
-		}
-		return delay
-	}
-}
-
 // ValidateCredentials validates GitHub credentials by making a test API call
 func (g *GitHubProvider) ValidateCredentials(ctx context.Context) error {
 	logEntry := g.logger.WithFields(logrus.Fields{
-		"provider":   "github",
-		"owner":      g.config.Owner,
-		"repository": g.config.Repository,
+		"provider":    "github",
+		"owner":       g.config.Owner,
+		"repository":  g.config.Repository,
+		"auth_method": g.credentials.AuthMethod,
 	})
 
 	logEntry.Info("Validating GitHub credentials")
 
-	// Test API call to verify credentials
-	_, _, err := g.client.Repositories.Get(ctx, g.config.Owner, g.config.Repository)
+	// An app installation must be able to write the configured secrets and variables
+	if g.appTokens != nil {
+		permissions, err := g.appTokens.Permissions(ctx)
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Error("GitHub App installation token could not be created")
+			return appErrors.ErrProviderCredentialValidationFailed
+		}
+		for _, permission := range g.requiredAppPermissions() {
+			if permissions[permission] != "write" {
+				logEntry.WithField("permission", permission).Error("GitHub App installation lacks a required permission")
+				return appErrors.ErrGitHubAppPermissionMissing
+			}
+		}
+	}
+
+	// Test API call to verify credentials. For an app, this also checks that the installation
+	// covers the repository.
+	var err error
+	if g.isOrganization() {
+		_, _, err = g.client.Organizations.Get(ctx, g.config.Owner)
+	} else {
+		_, _, err = g.client.Repositories.Get(ctx, g.config.Owner, g.config.Repository)
+	}
 	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("GitHub credential validation failed")
 		return appErrors.ErrProviderCredentialValidationFailed
@@ -421,6 +867,39 @@ func (g *GitHubProvider) ValidateCredentials(ctx context.Context) error {
 	return nil
 }
 
+// requiredAppPermissions returns the installation permissions needed to write the configured secrets and variables
+func (g *GitHubProvider) requiredAppPermissions() []string {
+	var required []string
+	switch {
+	case g.config.SecretType == GitHubSecretTypeDependabot && g.isOrganization():
+		required = append(required, "organization_dependabot_secrets")
+	case g.config.SecretType == GitHubSecretTypeDependabot:
+		required = append(required, "dependabot_secrets")
+	case g.config.SecretType == GitHubSecretTypeCodespaces && g.isOrganization():
+		required = append(required, "organization_codespaces_secrets")
+	case g.config.SecretType == GitHubSecretTypeCodespaces:
+		required = append(required, "codespaces_secrets")
+	case g.isOrganization():
+		required = append(required, "organization_secrets")
+	case g.usesEnvironment():
+		required = append(required, "environments")
+	default:
+		required = append(required, "secrets")
+	}
+
+	if len(g.config.VariableKeys) > 0 {
+		switch {
+		case g.isOrganization():
+			required = append(required, "organization_actions_variables")
+		case g.usesEnvironment():
+			// Environment variables fall under the environments permission already required above
+		default:
+			required = append(required, "actions_variables")
+		}
+	}
+	return required
+}
+
 // GetProviderName returns the name of the provider
 func (g *GitHubProvider) GetProviderName() string {
 	return "github"
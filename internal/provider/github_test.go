@@ -0,0 +1,392 @@
+package provider
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/go-github/v74/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// fakeGitHub is an in-memory stand-in for the GitHub secrets and variables API.
+// Secrets and variables are keyed by their full API path.
+type fakeGitHub struct {
+	mu           sync.Mutex
+	publicKey    *[32]byte
+	privateKey   *[32]byte
+	repositories map[string]int64
+	secrets      map[string]map[string]interface{}
+	variables    map[string]string
+	keyRequests  int
+
+	// GitHub App installation tokens; once one is minted, other requests must carry the latest
+	appKey         *rsa.PublicKey
+	appPermissions map[string]string
+	tokenLifetime  time.Duration
+	mints          int
+	token          string
+}
+
+// ServeHTTP implements the repository, organization, secret and variable endpoints the provider uses
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	p := r.URL.Path
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	if p == "/app/installations/7/access_tokens" {
+		f.mintToken(w, r)
+		return
+	}
+	if f.token != "" && r.Header.Get("Authorization") != "Bearer "+f.token {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && strings.HasSuffix(p, "/secrets/public-key"):
+		f.keyRequests++
+		respondJSON(w, "application/json", http.StatusOK, map[string]string{"key_id": "key-1", "key": base64.StdEncoding.EncodeToString(f.publicKey[:])})
+	case r.Method == http.MethodGet && len(segments) == 3 && segments[0] == "repos":
+		id, ok := f.repositories[segments[2]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		respondJSON(w, "application/json", http.StatusOK, map[string]interface{}{"id": id, "name": segments[2]})
+	case r.Method == http.MethodGet && len(segments) == 2 && segments[0] == "orgs":
+		respondJSON(w, "application/json", http.StatusOK, map[string]interface{}{"login": segments[1]})
+	case r.Method == http.MethodPut && strings.Contains(p, "/secrets/"):
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.secrets[p] = body
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet && strings.HasSuffix(p, "/secrets"):
+		var secrets []map[string]interface{}
+		for name := range f.secrets {
+			if path.Dir(name) == p {
+				secrets = append(secrets, map[string]interface{}{"name": path.Base(name), "created_at": time.Now(), "updated_at": time.Now()})
+			}
+		}
+		respondJSON(w, "application/json", http.StatusOK, map[string]interface{}{"total_count": len(secrets), "secrets": secrets})
+	case r.Method == http.MethodPatch && strings.Contains(p, "/variables/"):
+		if _, ok := f.variables[p]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body github.ActionsVariable
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.variables[p] = body.Value
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPost && strings.HasSuffix(p, "/variables"):
+		var body github.ActionsVariable
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.variables[p+"/"+body.Name] = body.Value
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet && strings.HasSuffix(p, "/variables"):
+		var variables []map[string]interface{}
+		for name, value := range f.variables {
+			if path.Dir(name) == p {
+				variables = append(variables, map[string]interface{}{"name": path.Base(name), "value": value, "updated_at": time.Now()})
+			}
+		}
+		respondJSON(w, "application/json", http.StatusOK, map[string]interface{}{"total_count": len(variables), "variables": variables})
+	case r.Method == http.MethodDelete && strings.Contains(p, "/secrets/"):
+		if _, ok := f.secrets[p]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.secrets, p)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete && strings.Contains(p, "/variables/"):
+		if _, ok := f.variables[p]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.variables, p)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// mintToken issues an installation token to a request authenticated with a JWT signed by the app key
+func (f *fakeGitHub) mintToken(w http.ResponseWriter, r *http.Request) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), claims, func(*jwt.Token) (interface{}, error) {
+		return f.appKey, nil
+	})
+	if err != nil || r.Method != http.MethodPost || claims.Issuer != "42" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	f.mints++
+	f.token = fmt.Sprintf("ghs_installation_%d", f.mints)
+	respondJSON(w, "application/json", http.StatusCreated, map[string]interface{}{
+		"token":       f.token,
+		"expires_at":  time.Now().Add(f.tokenLifetime),
+		"permissions": f.appPermissions,
+	})
+}
+
+// GitHubProviderTestSuite tests the GitHub provider against a fake GitHub API
+type GitHubProviderTestSuite struct {
+	standInSuite
+	fake *fakeGitHub
+}
+
+// SetupTest starts a fake GitHub API with two repositories in the acme organization
+func (suite *GitHubProviderTestSuite) SetupTest() {
+	publicKey, privateKey, err := box.GenerateKey(rand.Reader)
+	require.NoError(suite.T(), err)
+	suite.fake = &fakeGitHub{
+		publicKey:    publicKey,
+		privateKey:   privateKey,
+		repositories: map[string]int64{"payments": 101, "billing": 102},
+		secrets:      map[string]map[string]interface{}{},
+		variables:    map[string]string{},
+	}
+	suite.serve(suite.fake)
+}
+
+// newProvider creates a provider for the acme owner that talks to the fake GitHub API
+func (suite *GitHubProviderTestSuite) newProvider(config map[string]interface{}) *GitHubProvider {
+	config["owner"] = "acme"
+	provider, err := NewGitHubProvider(map[string]interface{}{"token": "ghp_test"}, config, suite.logger)
+	suite.prepareProvider(provider, err)
+	baseURL, err := url.Parse(suite.server.URL + "/")
+	require.NoError(suite.T(), err)
+	provider.client.BaseURL = baseURL
+	return provider
+}
+
+// decrypt opens a sealed secret value written to the fake GitHub API
+func (suite *GitHubProviderTestSuite) decrypt(secret map[string]interface{}) string {
+	require.NotNil(suite.T(), secret, "Expected the secret to be written")
+	sealed, err := base64.StdEncoding.DecodeString(secret["encrypted_value"].(string))
+	require.NoError(suite.T(), err)
+	value, ok := box.OpenAnonymous(nil, sealed, suite.fake.publicKey, suite.fake.privateKey)
+	require.True(suite.T(), ok, "Failed to decrypt the secret value")
+	return string(value)
+}
+
+// TestOrganizationSecrets tests that organization secrets carry their visibility and selected repositories
+func (suite *GitHubProviderTestSuite) TestOrganizationSecrets() {
+	provider := suite.newProvider(map[string]interface{}{
+		"scope":                 "organization",
+		"secret_visibility":     "selected",
+		"selected_repositories": []interface{}{"payments", "billing"},
+		"prefix":                "kavach",
+	})
+	require.NoError(suite.T(), provider.ValidateCredentials(suite.ctx))
+
+	results, err := provider.Sync(suite.ctx, []Secret{{Name: "api_key", Value: "sk-1"}})
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), results[0].Success, results[0].Error)
+
+	secret := suite.fake.secrets["/orgs/acme/actions/secrets/KAVACH_API_KEY"]
+	assert.Equal(suite.T(), "sk-1", suite.decrypt(secret))
+	assert.Equal(suite.T(), "selected", secret["visibility"])
+	assert.Equal(suite.T(), []interface{}{float64(101), float64(102)}, secret["selected_repository_ids"])
+
+	dependabot := suite.newProvider(map[string]interface{}{"scope": "organization", "secret_type": "dependabot"})
+	results, err = dependabot.Sync(suite.ctx, []Secret{{Name: "NPM_TOKEN", Value: "npm-1"}})
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), results[0].Success, results[0].Error)
+
+	secret = suite.fake.secrets["/orgs/acme/dependabot/secrets/NPM_TOKEN"]
+	assert.Equal(suite.T(), "npm-1", suite.decrypt(secret))
+	assert.Equal(suite.T(), "private", secret["visibility"], "Organization secrets default to private visibility")
+}
+
+// TestCodespacesSecrets tests that Codespaces repository secrets are written, listed and deleted
+func (suite *GitHubProviderTestSuite) TestCodespacesSecrets() {
+	provider := suite.newProvider(map[string]interface{}{"repository": "payments", "secret_type": "codespaces"})
+
+	results, err := provider.Sync(suite.ctx, []Secret{{Name: "DATABASE_URL", Value: "postgresql://localhost/db"}})
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), results[0].Success, results[0].Error)
+	assert.Equal(suite.T(), "postgresql://localhost/db", suite.decrypt(suite.fake.secrets["/repos/acme/payments/codespaces/secrets/DATABASE_URL"]))
+
+	listed, err := provider.List(suite.ctx)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), listed, 1)
+	assert.Equal(suite.T(), "DATABASE_URL", listed[0].Name)
+	assert.False(suite.T(), listed[0].HasValue)
+
+	results, err = provider.Delete(suite.ctx, []string{"DATABASE_URL", "MISSING"})
+	require.NoError(suite.T(), err)
+	for _, result := range results {
+		assert.True(suite.T(), result.Success, result.Error)
+	}
+	assert.Empty(suite.T(), suite.fake.secrets)
+}
+
+// TestVariables tests that variable keys are created, updated, read back and pruned as Actions variables
+func (suite *GitHubProviderTestSuite) TestVariables() {
+	provider := suite.newProvider(map[string]interface{}{
+		"repository":    "payments",
+		"prefix":        "kavach",
+		"variable_keys": []interface{}{"LOG_LEVEL"},
+	})
+
+	results, err := provider.Sync(suite.ctx, []Secret{{Name: "LOG_LEVEL", Value: "debug"}})
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), results[0].Success, results[0].Error)
+	assert.Equal(suite.T(), "debug", suite.fake.variables["/repos/acme/payments/actions/variables/KAVACH_LOG_LEVEL"])
+	assert.Zero(suite.T(), suite.fake.keyRequests, "Variables need no public key")
+
+	results, err = provider.Sync(suite.ctx, []Secret{{Name: "LOG_LEVEL", Value: "info"}, {Name: "API_KEY", Value: "sk-1"}})
+	require.NoError(suite.T(), err)
+	for _, result := range results {
+		require.True(suite.T(), result.Success, result.Error)
+	}
+	assert.Equal(suite.T(), "info", suite.fake.variables["/repos/acme/payments/actions/variables/KAVACH_LOG_LEVEL"])
+	assert.Equal(suite.T(), "sk-1", suite.decrypt(suite.fake.secrets["/repos/acme/payments/actions/secrets/KAVACH_API_KEY"]))
+
+	read, err := provider.Read(suite.ctx, []string{"KAVACH_LOG_LEVEL"})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), read, 1)
+	assert.True(suite.T(), read[0].HasValue)
+	assert.Equal(suite.T(), "info", read[0].Value)
+
+	suite.fake.variables["/repos/acme/payments/actions/variables/KAVACH_OLD_FLAG"] = "on"
+	candidates, err := provider.PruneCandidates(suite.ctx, []string{"LOG_LEVEL", "API_KEY"})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"KAVACH_OLD_FLAG"}, candidates)
+
+	pruned, err := provider.Prune(suite.ctx, candidates)
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), pruned[0].Success, pruned[0].Error)
+	assert.NotContains(suite.T(), suite.fake.variables, "/repos/acme/payments/actions/variables/KAVACH_OLD_FLAG")
+	assert.Len(suite.T(), suite.fake.variables, 1)
+}
+
+// appCredentials returns the credentials of a GitHub App installation whose key the fake GitHub API trusts
+func (suite *GitHubProviderTestSuite) appCredentials() map[string]interface{} {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(suite.T(), err)
+	suite.fake.appKey = &privateKey.PublicKey
+	suite.fake.tokenLifetime = time.Hour
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+
+	return map[string]interface{}{
+		"auth_method":     "app",
+		"app_id":          42,
+		"installation_id": 7,
+		"private_key":     string(privateKeyPEM),
+	}
+}
+
+// useFakeAPI points a provider, and the source of its installation tokens, at the fake GitHub API
+func (suite *GitHubProviderTestSuite) useFakeAPI(provider *GitHubProvider) {
+	baseURL, err := url.Parse(suite.server.URL + "/")
+	require.NoError(suite.T(), err)
+	provider.client.BaseURL = baseURL
+	if provider.appTokens != nil {
+		provider.appTokens.client.BaseURL = baseURL
+	}
+}
+
+// newAppProvider creates a provider for the payments repository that authenticates as a GitHub App installation
+func (suite *GitHubProviderTestSuite) newAppProvider(config map[string]interface{}) *GitHubProvider {
+	config["owner"] = "acme"
+	provider, err := NewGitHubProvider(suite.appCredentials(), config, suite.logger)
+	suite.prepareProvider(provider, err)
+	suite.useFakeAPI(provider)
+	return provider
+}
+
+// TestAppInstallationTokens tests that installation tokens are minted once, cached and refreshed before they expire
+func (suite *GitHubProviderTestSuite) TestAppInstallationTokens() {
+	suite.fake.appPermissions = map[string]string{"secrets": "write", "metadata": "read"}
+	provider := suite.newAppProvider(map[string]interface{}{"repository": "payments"})
+
+	require.NoError(suite.T(), provider.ValidateCredentials(suite.ctx))
+	results, err := provider.Sync(suite.ctx, []Secret{{Name: "API_KEY", Value: "sk-1"}})
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), results[0].Success, results[0].Error)
+	assert.Equal(suite.T(), 1, suite.fake.mints, "The installation token is cached")
+	assert.Equal(suite.T(), "sk-1", suite.decrypt(suite.fake.secrets["/repos/acme/payments/actions/secrets/API_KEY"]))
+
+	// A token close to its expiry is replaced before the next request
+	provider.appTokens.expiry = time.Now().Add(time.Minute)
+	results, err = provider.Sync(suite.ctx, []Secret{{Name: "API_KEY", Value: "sk-2"}})
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), results[0].Success, results[0].Error)
+	assert.Equal(suite.T(), 2, suite.fake.mints)
+	assert.Equal(suite.T(), "sk-2", suite.decrypt(suite.fake.secrets["/repos/acme/payments/actions/secrets/API_KEY"]))
+}
+
+// TestAppInstallationTokensSharedAcrossSyncs tests that providers created by the factory for the same
+// installation share its token, so a sync does not mint one while the last is still fresh
+func (suite *GitHubProviderTestSuite) TestAppInstallationTokensSharedAcrossSyncs() {
+	suite.fake.appPermissions = map[string]string{"secrets": "write"}
+	factory := NewProviderFactory(NewSyncExecutor(DefaultSyncExecutorConfig(), suite.logger), nil, suite.logger)
+	credentials := suite.appCredentials()
+
+	for _, value := range []string{"sk-1", "sk-2"} {
+		created, err := factory.CreateProvider(ProviderGitHub, credentials, map[string]interface{}{"owner": "acme", "repository": "payments"})
+		require.NoError(suite.T(), err)
+		provider := created.(*GitHubProvider)
+		suite.useFakeAPI(provider)
+
+		results, err := provider.Sync(suite.ctx, []Secret{{Name: "API_KEY", Value: value}})
+		require.NoError(suite.T(), err)
+		require.True(suite.T(), results[0].Success, results[0].Error)
+		assert.Equal(suite.T(), value, suite.decrypt(suite.fake.secrets["/repos/acme/payments/actions/secrets/API_KEY"]))
+	}
+	assert.Equal(suite.T(), 1, suite.fake.mints, "Syncs of the same installation share its token")
+
+	// A rotated private key gets a token source of its own
+	created, err := factory.CreateProvider(ProviderGitHub, suite.appCredentials(), map[string]interface{}{"owner": "acme", "repository": "payments"})
+	require.NoError(suite.T(), err)
+	suite.useFakeAPI(created.(*GitHubProvider))
+	require.NoError(suite.T(), created.ValidateCredentials(suite.ctx))
+	assert.Equal(suite.T(), 2, suite.fake.mints)
+}
+
+// TestAppPermissions tests that validation checks the installation's permissions and repository access
+func (suite *GitHubProviderTestSuite) TestAppPermissions() {
+	suite.fake.appPermissions = map[string]string{"secrets": "read", "actions_variables": "write"}
+	provider := suite.newAppProvider(map[string]interface{}{"repository": "payments"})
+	assert.Equal(suite.T(), appErrors.ErrGitHubAppPermissionMissing, provider.ValidateCredentials(suite.ctx))
+
+	suite.fake.appPermissions = map[string]string{"secrets": "write", "actions_variables": "write"}
+	provider = suite.newAppProvider(map[string]interface{}{"repository": "payments", "variable_keys": []interface{}{"LOG_LEVEL"}})
+	assert.NoError(suite.T(), provider.ValidateCredentials(suite.ctx))
+
+	suite.fake.appPermissions = map[string]string{"secrets": "write"}
+	provider = suite.newAppProvider(map[string]interface{}{"repository": "payments", "variable_keys": []interface{}{"LOG_LEVEL"}})
+	assert.Equal(suite.T(), appErrors.ErrGitHubAppPermissionMissing, provider.ValidateCredentials(suite.ctx),
+		"Variable keys need the variables permission")
+
+	provider = suite.newAppProvider(map[string]interface{}{"repository": "not-installed"})
+	assert.Equal(suite.T(), appErrors.ErrProviderCredentialValidationFailed, provider.ValidateCredentials(suite.ctx),
+		"A repository outside the installation fails validation")
+
+	provider = suite.newAppProvider(map[string]interface{}{"scope": "organization", "secret_type": "codespaces"})
+	assert.Equal(suite.T(), appErrors.ErrGitHubAppPermissionMissing, provider.ValidateCredentials(suite.ctx))
+}
+
+// TestGitHubProviderTestSuite runs the GitHub provider test suite
+func TestGitHubProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(GitHubProviderTestSuite))
+}
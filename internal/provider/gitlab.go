@@ -0,0 +1,467 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// gitlabDefaultBaseURL is the GitLab instance used when none is configured
+const gitlabDefaultBaseURL = "https://gitlab.com"
+
+// gitlabManagedDescription marks the variables created by Kavach in their description, followed by the owner
+const gitlabManagedDescription = "managed-by: kavach-backend"
+
+// gitlabMaskedMinLength is the shortest value GitLab accepts for a masked variable
+const gitlabMaskedMinLength = 8
+
+// GitLabProvider implements ProviderSync for GitLab CI/CD variables of a project or group
+type GitLabProvider struct {
+	credentials GitLabCredentials
+	config      GitLabConfig
+	logger      *logrus.Logger
+	httpClient  *http.Client
+	executor    *SyncExecutor
+	owner       SecretOwner // Environment and target recorded in the description of written variables
+}
+
+// gitlabVariable is a CI/CD variable as returned by the GitLab API
+type gitlabVariable struct {
+	Key              string  `json:"key"`
+	Value            *string `json:"value"`
+	VariableType     string  `json:"variable_type"`
+	EnvironmentScope string  `json:"environment_scope"`
+	Description      *string `json:"description"`
+}
+
+// gitlabResponseError is a failed GitLab API response
+type gitlabResponseError struct {
+	StatusCode int
+	Header     http.Header
+	Message    string
+}
+
+// Error returns the status and the message GitLab reported
+func (e *gitlabResponseError) Error() string {
+	return fmt.Sprintf("gitlab returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// NewGitLabProvider creates a new GitLab provider instance
+func NewGitLabProvider(credentials map[string]interface{}, config map[string]interface{}, logger *logrus.Logger) (*GitLabProvider, error) {
+	// Parse credentials
+	credBytes, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	var gitlabCreds GitLabCredentials
+	if err := json.Unmarshal(credBytes, &gitlabCreds); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal GitLab credentials: %w", err)
+	}
+
+	// Parse config
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var gitlabConfig GitLabConfig
+	if err := json.Unmarshal(configBytes, &gitlabConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal GitLab config: %w", err)
+	}
+	gitlabConfig.BaseURL = strings.TrimSuffix(gitlabConfig.BaseURL, "/")
+	if gitlabConfig.BaseURL == "" {
+		gitlabConfig.BaseURL = gitlabDefaultBaseURL
+	}
+	if gitlabConfig.EnvironmentScope == "" {
+		gitlabConfig.EnvironmentScope = "*"
+	}
+	if gitlabConfig.VariableType == "" {
+		gitlabConfig.VariableType = GitLabVariableTypeEnv
+	}
+
+	return &GitLabProvider{
+		credentials: gitlabCreds,
+		config:      gitlabConfig,
+		logger:      logger,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		executor:    NewSyncExecutor(DefaultSyncExecutorConfig(), logger),
+	}, nil
+}
+
+// Sync syncs secrets to GitLab CI/CD variables. Values GitLab cannot mask fail on their own
+// without affecting the other secrets.
+func (g *GitLabProvider) Sync(ctx context.Context, secrets []Secret) ([]SyncResult, error) {
+	logEntry := g.logger.WithFields(logrus.Fields{
+		"provider":          "gitlab",
+		"scope":             g.scopePath(),
+		"environment_scope": g.config.EnvironmentScope,
+		"secret_count":      len(secrets),
+	})
+
+	logEntry.Info("Starting GitLab variables sync")
+
+	fileKeys := make(map[string]bool, len(g.config.FileKeys))
+	for _, name := range g.config.FileKeys {
+		fileKeys[name] = true
+	}
+
+	results := g.executor.Run(ctx, ProviderGitLab, g.config.RetryConfig, secrets, func(ctx context.Context, secret Secret) error {
+		variableType := g.config.VariableType
+		if fileKeys[secret.Name] {
+			variableType = GitLabVariableTypeFile
+		}
+		return g.putVariable(ctx, g.RemoteName(secret.Name), secret.Value, variableType)
+	})
+
+	for _, result := range results {
+		if !result.Success {
+			logEntry.WithFields(logrus.Fields{
+				"secret_name": result.Name,
+				"error":       result.Error,
+			}).Error("Failed to sync secret to GitLab after all retry attempts")
+		}
+	}
+
+	logEntry.WithFields(logrus.Fields{
+		"synced_count": len(results),
+		"total_count":  len(secrets),
+	}).Info("Completed GitLab variables sync")
+
+	return results, nil
+}
+
+// Delete removes variables from GitLab
+func (g *GitLabProvider) Delete(ctx context.Context, names []string) ([]SyncResult, error) {
+	remoteNames := make([]string, len(names))
+	for i, name := range names {
+		remoteNames[i] = g.RemoteName(name)
+	}
+
+	results := g.deleteVariables(ctx, remoteNames)
+	for i := range results {
+		results[i].Name = names[i]
+	}
+	return results, nil
+}
+
+// RemoteName returns the key a secret is stored under, with the prefix when one is configured
+func (g *GitLabProvider) RemoteName(name string) string {
+	if g.config.Prefix != "" {
+		name = g.config.Prefix + "_" + name
+	}
+	return sanitizeGitLabKey(name)
+}
+
+// List returns the variables in the configured environment scope, limited to the prefix when one
+// is configured
+func (g *GitLabProvider) List(ctx context.Context) ([]RemoteSecret, error) {
+	variables, err := g.listVariables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]RemoteSecret, len(variables))
+	for i, variable := range variables {
+		secrets[i] = RemoteSecret{Name: variable.Key}
+	}
+	return secrets, nil
+}
+
+// Read returns the current value of each named variable. Hidden variables are reported without a value.
+func (g *GitLabProvider) Read(ctx context.Context, remoteNames []string) ([]RemoteSecret, error) {
+	variables, err := g.listVariables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]gitlabVariable, len(variables))
+	for _, variable := range variables {
+		byKey[variable.Key] = variable
+	}
+
+	var secrets []RemoteSecret
+	for _, name := range remoteNames {
+		variable, ok := byKey[name]
+		if !ok {
+			continue
+		}
+		secret := RemoteSecret{Name: name}
+		if variable.Value != nil {
+			secret.Value = *variable.Value
+			secret.HasValue = true
+		}
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, nil
+}
+
+// SetOwner sets the environment and target recorded in the description of written variables and required for pruning
+func (g *GitLabProvider) SetOwner(owner SecretOwner) {
+	g.owner = owner
+}
+
+// PruneCandidates returns the variables in the configured environment scope whose description marks
+// them as written by Kavach for the owning environment and target and that are not among the given
+// secret names
+func (g *GitLabProvider) PruneCandidates(ctx context.Context, keep []string) ([]string, error) {
+	if !g.owner.IsSet() {
+		return nil, errSecretOwnerUnset
+	}
+
+	variables, err := g.listVariables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		kept[g.RemoteName(name)] = true
+	}
+
+	var candidates []string
+	for _, variable := range variables {
+		managed := variable.Description != nil && *variable.Description == g.description()
+		if managed && !kept[variable.Key] {
+			candidates = append(candidates, variable.Key)
+		}
+	}
+	return candidates, nil
+}
+
+// Prune deletes the given variables
+func (g *GitLabProvider) Prune(ctx context.Context, remoteNames []string) ([]SyncResult, error) {
+	results := g.deleteVariables(ctx, remoteNames)
+
+	g.logger.WithFields(logrus.Fields{
+		"provider":     "gitlab",
+		"scope":        g.scopePath(),
+		"pruned_count": len(results),
+	}).Info("Completed GitLab variables prune")
+	return results, nil
+}
+
+// ValidateCredentials validates the GitLab token by looking up the configured project or group
+func (g *GitLabProvider) ValidateCredentials(ctx context.Context) error {
+	logEntry := g.logger.WithFields(logrus.Fields{
+		"provider": "gitlab",
+		"base_url": g.config.BaseURL,
+		"scope":    g.scopePath(),
+	})
+
+	logEntry.Info("Validating GitLab credentials")
+
+	if err := g.do(ctx, http.MethodGet, g.scopePath(), nil, nil); err != nil {
+		return fmt.Errorf("failed to validate GitLab credentials: %w", err)
+	}
+
+	logEntry.Info("GitLab credentials validated successfully")
+	return nil
+}
+
+// GetProviderName returns the provider name
+func (g *GitLabProvider) GetProviderName() string {
+	return "gitlab"
+}
+
+// putVariable updates a variable in the configured environment scope, creating it if it does not exist
+func (g *GitLabProvider) putVariable(ctx context.Context, key, value, variableType string) error {
+	if g.config.Masked && (len(value) < gitlabMaskedMinLength || strings.ContainsAny(value, " \t\r\n")) {
+		return fmt.Errorf("variable '%s' cannot be masked: %w", key, appErrors.ErrGitLabMaskedValueInvalid)
+	}
+
+	body := map[string]interface{}{
+		"key":               key,
+		"value":             value,
+		"variable_type":     variableType,
+		"environment_scope": g.config.EnvironmentScope,
+		"masked":            g.config.Masked,
+		"protected":         g.config.Protected,
+		"raw":               true,
+		"description":       g.description(),
+	}
+
+	err := g.do(ctx, http.MethodPut, g.variablePath(key), body, nil)
+	if gitlabNotFound(err) {
+		err = g.do(ctx, http.MethodPost, g.scopePath()+"/variables", body, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to put GitLab variable '%s': %w", key, err)
+	}
+
+	g.logger.WithField("variable_key", key).Info("Successfully put GitLab variable")
+	return nil
+}
+
+// description returns the description marking a variable as written by Kavach for the owning environment and target
+func (g *GitLabProvider) description() string {
+	if !g.owner.IsSet() {
+		return gitlabManagedDescription
+	}
+	return gitlabManagedDescription + ", owner: " + g.owner.String()
+}
+
+// deleteVariables deletes variables in the configured environment scope. Variables that no longer
+// exist are reported as successfully removed.
+func (g *GitLabProvider) deleteVariables(ctx context.Context, keys []string) []SyncResult {
+	logEntry := g.logger.WithFields(logrus.Fields{
+		"provider":     "gitlab",
+		"scope":        g.scopePath(),
+		"secret_count": len(keys),
+	})
+
+	results := make([]SyncResult, 0, len(keys))
+	for _, key := range keys {
+		result := SyncResult{Name: key, Success: true}
+
+		err := g.do(ctx, http.MethodDelete, g.variablePath(key), nil, nil)
+		if err != nil && !gitlabNotFound(err) {
+			result.Success = false
+			result.Error = fmt.Sprintf("failed to delete variable: %v", err)
+			logEntry.WithFields(logrus.Fields{
+				"variable_key": key,
+				"error":        err.Error(),
+			}).Error("Failed to delete variable from GitLab")
+		}
+
+		results = append(results, result)
+	}
+
+	logEntry.Info("Completed GitLab variables removal")
+	return results
+}
+
+// listVariables returns the variables in the configured environment scope, limited to the prefix
+// when one is configured
+func (g *GitLabProvider) listVariables(ctx context.Context) ([]gitlabVariable, error) {
+	managedPrefix := ""
+	if g.config.Prefix != "" {
+		managedPrefix = g.RemoteName("")
+	}
+
+	var variables []gitlabVariable
+	for page := "1"; page != ""; {
+		var pageVariables []gitlabVariable
+		header, err := g.doWithHeader(ctx, http.MethodGet, g.scopePath()+"/variables?per_page=100&page="+page, nil, &pageVariables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list GitLab variables: %w", err)
+		}
+
+		for _, variable := range pageVariables {
+			if variable.EnvironmentScope == g.config.EnvironmentScope && strings.HasPrefix(variable.Key, managedPrefix) {
+				variables = append(variables, variable)
+			}
+		}
+		page = header.Get("X-Next-Page")
+	}
+
+	return variables, nil
+}
+
+// scopePath returns the API path of the configured project or group
+func (g *GitLabProvider) scopePath() string {
+	if g.config.GroupID != "" {
+		return "groups/" + url.PathEscape(g.config.GroupID)
+	}
+	return "projects/" + url.PathEscape(g.config.ProjectID)
+}
+
+// variablePath returns the API path of a variable in the configured environment scope
+func (g *GitLabProvider) variablePath(key string) string {
+	return g.scopePath() + "/variables/" + url.PathEscape(key) + "?filter[environment_scope]=" + url.QueryEscape(g.config.EnvironmentScope)
+}
+
+// do sends a request to the GitLab API and decodes the JSON response into out
+func (g *GitLabProvider) do(ctx context.Context, method, path string, body, out interface{}) error {
+	_, err := g.doWithHeader(ctx, method, path, body, out)
+	return err
+}
+
+// doWithHeader sends a request to the GitLab API and returns the response headers
+func (g *GitLabProvider) doWithHeader(ctx context.Context, method, path string, body, out interface{}) (http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal GitLab request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, g.config.BaseURL+"/api/v4/"+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitLab request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("PRIVATE-TOKEN", g.credentials.Token)
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call GitLab: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		// GitLab reports errors as a message string or as messages per field
+		var payload struct {
+			Message json.RawMessage `json:"message"`
+			Error   string          `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&payload)
+		message := payload.Error
+		if len(payload.Message) > 0 {
+			var text string
+			if json.Unmarshal(payload.Message, &text) == nil {
+				message = text
+			} else {
+				message = string(payload.Message)
+			}
+		}
+		return resp.Header, &gitlabResponseError{StatusCode: resp.StatusCode, Header: resp.Header, Message: message}
+	}
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.Header, fmt.Errorf("failed to decode GitLab response: %w", err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// gitlabNotFound reports whether a request failed because the resource does not exist
+func gitlabNotFound(err error) bool {
+	var gitlabErr *gitlabResponseError
+	return errors.As(err, &gitlabErr) && gitlabErr.StatusCode == http.StatusNotFound
+}
+
+// sanitizeGitLabKey sanitizes a name for use as a variable key, which allows letters, digits and _
+func sanitizeGitLabKey(name string) string {
+	result := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+
+	// Limit length to 255 characters (GitLab variable key limit)
+	if len(result) > 255 {
+		result = result[:255]
+	}
+
+	return result
+}
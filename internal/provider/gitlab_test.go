@@ -0,0 +1,304 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeGitLabMaskable is the value format the stand-in accepts for masked variables
+var fakeGitLabMaskable = regexp.MustCompile(`^[a-zA-Z0-9+/=@:.~_-]{8,}$`)
+
+// fakeGitLabPageSize is the page size of the stand-in, small so listing needs several pages
+const fakeGitLabPageSize = 2
+
+// fakeGitLab is a local HTTP stand-in of the GitLab CI/CD variables API of one project and one group,
+// served under a path prefix like a self-managed instance
+type fakeGitLab struct {
+	mu        sync.Mutex
+	variables map[string]map[string]*gitlabStoredVariable // scope path, then key and environment scope
+	creates   int
+	updates   int
+}
+
+// gitlabStoredVariable is a variable held by the stand-in
+type gitlabStoredVariable struct {
+	Key              string `json:"key"`
+	Value            string `json:"value"`
+	VariableType     string `json:"variable_type"`
+	EnvironmentScope string `json:"environment_scope"`
+	Masked           bool   `json:"masked"`
+	Protected        bool   `json:"protected"`
+	Description      string `json:"description"`
+}
+
+// ServeHTTP routes a GitLab API request
+func (f *fakeGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("PRIVATE-TOKEN") != "glpat-test" {
+		f.fail(w, http.StatusUnauthorized, `"401 Unauthorized"`)
+		return
+	}
+
+	// Project and group paths arrive URL-encoded, as GitLab requires
+	path, ok := strings.CutPrefix(r.URL.EscapedPath(), "/gitlab/api/v4/")
+	if !ok {
+		f.fail(w, http.StatusNotFound, `"404 Not Found"`)
+		return
+	}
+	parts := strings.Split(path, "/")
+	scope := parts[0] + "/" + parts[1]
+	if _, known := f.variables[scope]; !known {
+		f.fail(w, http.StatusNotFound, `"404 Project Not Found"`)
+		return
+	}
+
+	switch {
+	case len(parts) == 2:
+		respondJSON(w, "application/json", http.StatusOK, map[string]interface{}{"id": 42})
+	case len(parts) == 3 && r.Method == http.MethodGet:
+		f.list(w, r, scope)
+	case len(parts) == 3 && r.Method == http.MethodPost:
+		f.write(w, r, scope, "")
+	case len(parts) == 4:
+		f.write(w, r, scope, parts[3])
+	default:
+		f.fail(w, http.StatusNotFound, `"404 Not Found"`)
+	}
+}
+
+// list returns one page of the variables of a project or group
+func (f *fakeGitLab) list(w http.ResponseWriter, r *http.Request, scope string) {
+	ids := make([]string, 0, len(f.variables[scope]))
+	for id := range f.variables[scope] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	start := min((page-1)*fakeGitLabPageSize, len(ids))
+	end := min(start+fakeGitLabPageSize, len(ids))
+	variables := []*gitlabStoredVariable{}
+	for _, id := range ids[start:end] {
+		variables = append(variables, f.variables[scope][id])
+	}
+	if end < len(ids) {
+		w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+	}
+	respondJSON(w, "application/json", http.StatusOK, variables)
+}
+
+// write creates, updates or deletes a variable
+func (f *fakeGitLab) write(w http.ResponseWriter, r *http.Request, scope, key string) {
+	var input gitlabStoredVariable
+	if r.Method != http.MethodDelete {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			f.fail(w, http.StatusBadRequest, strconv.Quote(err.Error()))
+			return
+		}
+	}
+
+	environmentScope := r.URL.Query().Get("filter[environment_scope]")
+	if r.Method == http.MethodPost {
+		key, environmentScope = input.Key, input.EnvironmentScope
+	}
+	id := key + "|" + environmentScope
+	existing := f.variables[scope][id]
+
+	switch r.Method {
+	case http.MethodPost:
+		if existing != nil {
+			f.fail(w, http.StatusBadRequest, `{"key":["(`+key+`) has already been taken"]}`)
+			return
+		}
+	case http.MethodPut, http.MethodDelete:
+		if existing == nil {
+			f.fail(w, http.StatusNotFound, `"404 Variable Not Found"`)
+			return
+		}
+	}
+
+	if r.Method == http.MethodDelete {
+		delete(f.variables[scope], id)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if input.Masked && !fakeGitLabMaskable.MatchString(input.Value) {
+		f.fail(w, http.StatusBadRequest, `{"value":["is invalid"]}`)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		f.creates++
+	} else {
+		f.updates++
+	}
+	f.variables[scope][id] = &input
+	respondJSON(w, "application/json", http.StatusOK, input)
+}
+
+// fail writes a GitLab error response with the given JSON message
+func (f *fakeGitLab) fail(w http.ResponseWriter, status int, message string) {
+	respondJSON(w, "application/json", status, map[string]json.RawMessage{"message": json.RawMessage(message)})
+}
+
+// GitLabProviderTestSuite tests the GitLab provider against a local stand-in
+type GitLabProviderTestSuite struct {
+	standInSuite
+	fake *fakeGitLab
+}
+
+// SetupTest starts an empty stand-in for each test
+func (suite *GitLabProviderTestSuite) SetupTest() {
+	suite.fake = &fakeGitLab{variables: map[string]map[string]*gitlabStoredVariable{
+		"projects/acme%2Fpayments": {},
+		"groups/acme":              {},
+	}}
+	suite.serve(suite.fake)
+}
+
+// newProvider creates a provider pointed at the stand-in with the given token and config
+func (suite *GitLabProviderTestSuite) newProvider(token string, config map[string]interface{}) *GitLabProvider {
+	config["base_url"] = suite.server.URL + "/gitlab/"
+	provider, err := NewGitLabProvider(map[string]interface{}{"token": token}, config, suite.logger)
+	suite.prepareProvider(provider, err)
+	return provider
+}
+
+// TestSyncProjectVariables tests that project variables are created, then updated, in the environment scope
+func (suite *GitLabProviderTestSuite) TestSyncProjectVariables() {
+	provider := suite.newProvider("glpat-test", map[string]interface{}{
+		"project_id":        "acme/payments",
+		"environment_scope": "production",
+		"masked":            true,
+		"protected":         true,
+		"file_keys":         []interface{}{"TLS_CERT"},
+	})
+	require.NoError(suite.T(), provider.ValidateCredentials(suite.ctx))
+
+	secrets := []Secret{
+		{Name: "API_KEY", Value: "sk-live-0123456789"},
+		{Name: "TLS_CERT", Value: "LS0tLS1CRUdJTi1DRVJU"},
+		{Name: "PIN", Value: "1234"},
+		{Name: "PASSWORD", Value: "pa$$word-with-dollars"},
+		{Name: "PASSPHRASE", Value: "correct horse battery"},
+		{Name: "CA_BUNDLE", Value: "line-one\nline-two"},
+	}
+	results, err := provider.Sync(suite.ctx, secrets)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), results, 6)
+
+	byName := map[string]SyncResult{}
+	for _, result := range results {
+		byName[result.Name] = result
+	}
+	assert.True(suite.T(), byName["API_KEY"].Success, byName["API_KEY"].Error)
+	assert.True(suite.T(), byName["TLS_CERT"].Success, byName["TLS_CERT"].Error)
+	assert.False(suite.T(), byName["PIN"].Success, "Too short to mask")
+	assert.Contains(suite.T(), byName["PIN"].Error, "masked GitLab variables must be at least 8 characters")
+	assert.False(suite.T(), byName["PASSWORD"].Success, "Rejected by GitLab's masking rules")
+	assert.Contains(suite.T(), byName["PASSWORD"].Error, "is invalid")
+	for _, name := range []string{"PASSPHRASE", "CA_BUNDLE"} {
+		assert.False(suite.T(), byName[name].Success, "Whitespace cannot be masked")
+		assert.Contains(suite.T(), byName[name].Error, "masked GitLab variables must be at least 8 characters")
+	}
+
+	project := suite.fake.variables["projects/acme%2Fpayments"]
+	require.Len(suite.T(), project, 2)
+	assert.Equal(suite.T(), gitlabStoredVariable{
+		Key: "API_KEY", Value: "sk-live-0123456789", VariableType: "env_var", EnvironmentScope: "production",
+		Masked: true, Protected: true, Description: "managed-by: kavach-backend, owner: " + testOwner.String(),
+	}, *project["API_KEY|production"])
+	assert.Equal(suite.T(), "file", project["TLS_CERT|production"].VariableType)
+
+	// A second sync updates the variables in place
+	results, err = provider.Sync(suite.ctx, []Secret{{Name: "API_KEY", Value: "sk-live-9876543210"}})
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), results[0].Success, results[0].Error)
+	assert.Equal(suite.T(), 2, suite.fake.creates)
+	assert.Equal(suite.T(), 1, suite.fake.updates)
+	assert.Equal(suite.T(), "sk-live-9876543210", project["API_KEY|production"].Value)
+}
+
+// TestSyncUnmaskedShortValues tests that values too short to mask are written when masking is off
+func (suite *GitLabProviderTestSuite) TestSyncUnmaskedShortValues() {
+	provider := suite.newProvider("glpat-test", map[string]interface{}{"project_id": "acme/payments"})
+
+	results, err := provider.Sync(suite.ctx, []Secret{{Name: "PIN", Value: "1234"}})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), results, 1)
+	assert.True(suite.T(), results[0].Success, results[0].Error)
+	assert.Equal(suite.T(), "1234", suite.fake.variables["projects/acme%2Fpayments"]["PIN|*"].Value)
+}
+
+// TestGroupVariablesPrune tests listing across pages and pruning only managed variables of the scope
+func (suite *GitLabProviderTestSuite) TestGroupVariablesPrune() {
+	provider := suite.newProvider("glpat-test", map[string]interface{}{
+		"group_id":          "acme",
+		"environment_scope": "staging",
+		"prefix":            "PAY",
+	})
+	group := suite.fake.variables["groups/acme"]
+	group["PAY_UNMANAGED|staging"] = &gitlabStoredVariable{Key: "PAY_UNMANAGED", Value: "x", EnvironmentScope: "staging"}
+	group["PAY_OLD|production"] = &gitlabStoredVariable{Key: "PAY_OLD", Value: "x", EnvironmentScope: "production", Description: "managed-by: kavach-backend"}
+	group["OTHER|staging"] = &gitlabStoredVariable{Key: "OTHER", Value: "x", EnvironmentScope: "staging", Description: "managed-by: kavach-backend"}
+	group["PAY_BEFORE_OWNERS|staging"] = &gitlabStoredVariable{Key: "PAY_BEFORE_OWNERS", Value: "x", EnvironmentScope: "staging", Description: "managed-by: kavach-backend"}
+	group["PAY_OTHER_TARGET|staging"] = &gitlabStoredVariable{Key: "PAY_OTHER_TARGET", Value: "x", EnvironmentScope: "staging",
+		Description: "managed-by: kavach-backend, owner: " + testOwner.EnvironmentID + "/gitlab-ops"}
+
+	_, err := provider.Sync(suite.ctx, []Secret{{Name: "KEEP", Value: "1"}, {Name: "OLD", Value: "2"}, {Name: "db.url", Value: "3"}})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "PAY_db_url", provider.RemoteName("db.url"))
+
+	listed, err := provider.List(suite.ctx)
+	require.NoError(suite.T(), err)
+	names := []string{}
+	for _, secret := range listed {
+		names = append(names, secret.Name)
+	}
+	assert.ElementsMatch(suite.T(), []string{"PAY_KEEP", "PAY_OLD", "PAY_db_url", "PAY_UNMANAGED", "PAY_BEFORE_OWNERS", "PAY_OTHER_TARGET"}, names)
+
+	read, err := provider.Read(suite.ctx, []string{"PAY_OLD", "PAY_MISSING"})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), read, 1)
+	assert.Equal(suite.T(), "2", read[0].Value)
+
+	candidates, err := provider.PruneCandidates(suite.ctx, []string{"KEEP", "db.url"})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"PAY_OLD"}, candidates)
+
+	pruned, err := provider.Prune(suite.ctx, append(candidates, "PAY_GONE"))
+	require.NoError(suite.T(), err)
+	for _, result := range pruned {
+		assert.True(suite.T(), result.Success, "Prune of %s failed: %s", result.Name, result.Error)
+	}
+	assert.NotContains(suite.T(), group, "PAY_OLD|staging")
+	assert.Contains(suite.T(), group, "PAY_OLD|production", "Other environment scopes are left alone")
+}
+
+// TestInvalidToken tests that a rejected token fails validation without retries
+func (suite *GitLabProviderTestSuite) TestInvalidToken() {
+	provider := suite.newProvider("glpat-revoked", map[string]interface{}{"project_id": "acme/payments"})
+
+	err := provider.ValidateCredentials(suite.ctx)
+	require.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "401 Unauthorized")
+	kind, _ := classifySyncError(err)
+	assert.Equal(suite.T(), failurePermanent, kind)
+}
+
+// TestGitLabProviderTestSuite runs the GitLab provider test suite
+func TestGitLabProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(GitLabProviderTestSuite))
+}
@@ -31,13 +31,31 @@ func RegisterProviderRoutes(handler *ProviderHandler, envGroup *gin.RouterGroup)
 		// Provider credentials CRUD operations
 		providerGroup.POST("/credentials", handler.CreateProviderCredential)
 		providerGroup.GET("/credentials", handler.ListProviderCredentials)
-		providerGroup.GET("/credentials/:provider", handler.GetProviderCredential)
-		providerGroup.PUT("/credentials/:provider", handler.UpdateProviderCredential)
-		providerGroup.DELETE("/credentials/:provider", handler.DeleteProviderCredential)
+		providerGroup.GET("/credentials/:name", handler.GetProviderCredential)
+		providerGroup.PUT("/credentials/:name", handler.UpdateProviderCredential)
+		providerGroup.DELETE("/credentials/:name", handler.DeleteProviderCredential)
+		providerGroup.PUT("/credentials/:name/auto-sync", handler.UpdateAutoSync)
+		providerGroup.PUT("/credentials/:name/mirror-mode", handler.UpdateMirrorMode)
+		providerGroup.PUT("/credentials/:name/key-mapping", handler.UpdateKeyMapping)
 
 	}
 }
 
+// RegisterProviderConnectionRoutes registers the organization-level provider connection routes
+func RegisterProviderConnectionRoutes(handler *ProviderHandler, orgGroup *gin.RouterGroup) {
+	connectionGroup := orgGroup.Group("/:orgID/provider-connections")
+	{
+		connectionGroup.POST("/", handler.CreateProviderConnection)
+		connectionGroup.GET("/", handler.ListProviderConnections)
+		connectionGroup.GET("/:name", handler.GetProviderConnection)
+		connectionGroup.PUT("/:name/credentials", handler.RotateProviderConnection)
+		connectionGroup.DELETE("/:name", handler.DeleteProviderConnection)
+		connectionGroup.POST("/:name/grants", handler.CreateProviderConnectionGrant)
+		connectionGroup.GET("/:name/grants", handler.ListProviderConnectionGrants)
+		connectionGroup.DELETE("/:name/grants/:grantID", handler.DeleteProviderConnectionGrant)
+	}
+}
+
 // CreateProviderCredential handles POST /orgs/:orgID/secret-groups/:groupID/environments/:envID/providers/credentials
 func (h *ProviderHandler) CreateProviderCredential(c *gin.Context) {
 	environmentID := c.Param("envID")
@@ -70,6 +88,9 @@ func (h *ProviderHandler) CreateProviderCredential(c *gin.Context) {
 		case appErrors.ErrInvalidProviderType:
 			utils.RespondError(c, appErrors.ErrInvalidProviderType.Status, appErrors.ErrInvalidProviderType.Code, appErrors.ErrInvalidProviderType.Message)
 			return
+		case appErrors.ErrInvalidProviderTargetName:
+			utils.RespondError(c, appErrors.ErrInvalidProviderTargetName.Status, appErrors.ErrInvalidProviderTargetName.Code, appErrors.ErrInvalidProviderTargetName.Message)
+			return
 		case appErrors.ErrInvalidProviderData:
 			utils.RespondError(c, appErrors.ErrInvalidProviderData.Status, appErrors.ErrInvalidProviderData.Code, appErrors.ErrInvalidProviderData.Message)
 			return
@@ -85,6 +106,11 @@ func (h *ProviderHandler) CreateProviderCredential(c *gin.Context) {
 		case appErrors.ErrProviderCredentialCreateFailed:
 			utils.RespondError(c, appErrors.ErrProviderCredentialCreateFailed.Status, appErrors.ErrProviderCredentialCreateFailed.Code, appErrors.ErrProviderCredentialCreateFailed.Message)
 			return
+		case appErrors.ErrProviderConnectionNotFound, appErrors.ErrProviderConnectionNotGranted,
+			appErrors.ErrProviderConnectionMismatch, appErrors.ErrProviderConnectionFailed:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
 		case appErrors.ErrInternalServer:
 			utils.RespondError(c, appErrors.ErrInternalServer.Status, appErrors.ErrInternalServer.Code, appErrors.ErrInternalServer.Message)
 			return
@@ -97,27 +123,28 @@ func (h *ProviderHandler) CreateProviderCredential(c *gin.Context) {
 	logEntry.WithFields(logrus.Fields{
 		"credential_id": result.ID,
 		"provider":      result.Provider,
+		"target":        result.Name,
 	}).Info("Successfully created provider credential")
 
 	utils.RespondSuccess(c, http.StatusCreated, result)
 }
 
-// GetProviderCredential handles GET /orgs/:orgID/secret-groups/:groupID/environments/:envID/providers/credentials/:provider
+// GetProviderCredential handles GET /orgs/:orgID/secret-groups/:groupID/environments/:envID/providers/credentials/:name
 func (h *ProviderHandler) GetProviderCredential(c *gin.Context) {
 	environmentID := c.Param("envID")
-	provider := c.Param("provider")
+	name := c.Param("name")
 
 	logEntry := h.logger.WithFields(logrus.Fields{
 		"handler":        "GetProviderCredential",
 		"environment_id": environmentID,
-		"provider":       provider,
+		"target":         name,
 		"method":         c.Request.Method,
 		"path":           c.Request.URL.Path,
 	})
 
 	logEntry.Info("Processing get provider credential request")
 
-	result, err := h.service.GetProviderCredential(c.Request.Context(), environmentID, provider)
+	result, err := h.service.GetProviderCredential(c.Request.Context(), environmentID, name)
 	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to get provider credential")
 		switch err {
@@ -175,15 +202,15 @@ func (h *ProviderHandler) ListProviderCredentials(c *gin.Context) {
 	utils.RespondSuccess(c, http.StatusOK, result)
 }
 
-// UpdateProviderCredential handles PUT /orgs/:orgID/secret-groups/:groupID/environments/:envID/providers/credentials/:provider
+// UpdateProviderCredential handles PUT /orgs/:orgID/secret-groups/:groupID/environments/:envID/providers/credentials/:name
 func (h *ProviderHandler) UpdateProviderCredential(c *gin.Context) {
 	environmentID := c.Param("envID")
-	provider := c.Param("provider")
+	name := c.Param("name")
 
 	logEntry := h.logger.WithFields(logrus.Fields{
 		"handler":        "UpdateProviderCredential",
 		"environment_id": environmentID,
-		"provider":       provider,
+		"target":         name,
 		"method":         c.Request.Method,
 		"path":           c.Request.URL.Path,
 	})
@@ -199,7 +226,7 @@ func (h *ProviderHandler) UpdateProviderCredential(c *gin.Context) {
 
 	logEntry.Info("Request validated successfully")
 
-	result, err := h.service.UpdateProviderCredential(c.Request.Context(), environmentID, provider, req)
+	result, err := h.service.UpdateProviderCredential(c.Request.Context(), environmentID, name, req)
 	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to update provider credential")
 		switch err {
@@ -212,6 +239,9 @@ func (h *ProviderHandler) UpdateProviderCredential(c *gin.Context) {
 		case appErrors.ErrInvalidProviderData:
 			utils.RespondError(c, appErrors.ErrInvalidProviderData.Status, appErrors.ErrInvalidProviderData.Code, appErrors.ErrInvalidProviderData.Message)
 			return
+		case appErrors.ErrProviderCredentialGetFailed:
+			utils.RespondError(c, appErrors.ErrProviderCredentialGetFailed.Status, appErrors.ErrProviderCredentialGetFailed.Code, appErrors.ErrProviderCredentialGetFailed.Message)
+			return
 		case appErrors.ErrProviderEncryptionFailed:
 			utils.RespondError(c, appErrors.ErrProviderEncryptionFailed.Status, appErrors.ErrProviderEncryptionFailed.Code, appErrors.ErrProviderEncryptionFailed.Message)
 			return
@@ -235,22 +265,156 @@ func (h *ProviderHandler) UpdateProviderCredential(c *gin.Context) {
 	utils.RespondSuccess(c, http.StatusOK, result)
 }
 
-// DeleteProviderCredential handles DELETE /orgs/:orgID/secret-groups/:groupID/environments/:envID/providers/credentials/:provider
+// UpdateAutoSync handles PUT /orgs/:orgID/secret-groups/:groupID/environments/:envID/providers/credentials/:name/auto-sync
+func (h *ProviderHandler) UpdateAutoSync(c *gin.Context) {
+	environmentID := c.Param("envID")
+	name := c.Param("name")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "UpdateAutoSync",
+		"environment_id": environmentID,
+		"target":         name,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing update auto-sync request")
+
+	var req UpdateAutoSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	settings := AutoSyncSettings{Enabled: *req.Enabled, DebounceSeconds: DefaultAutoSyncDebounceSeconds}
+	if req.DebounceSeconds != nil {
+		settings.DebounceSeconds = *req.DebounceSeconds
+	}
+
+	result, err := h.service.UpdateAutoSync(c.Request.Context(), environmentID, name, settings)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to update auto-sync settings")
+		switch err {
+		case appErrors.ErrProviderCredentialNotFound, appErrors.ErrInvalidProviderType,
+			appErrors.ErrInvalidAutoSyncSettings, appErrors.ErrProviderCredentialUpdateFailed, appErrors.ErrInternalServer:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			utils.RespondError(c, http.StatusInternalServerError, "update_auto_sync_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.WithFields(logrus.Fields{
+		"enabled":          result.AutoSync.Enabled,
+		"debounce_seconds": result.AutoSync.DebounceSeconds,
+	}).Info("Successfully updated auto-sync settings")
+
+	utils.RespondSuccess(c, http.StatusOK, result)
+}
+
+// UpdateMirrorMode handles PUT /orgs/:orgID/secret-groups/:groupID/environments/:envID/providers/credentials/:name/mirror-mode
+func (h *ProviderHandler) UpdateMirrorMode(c *gin.Context) {
+	environmentID := c.Param("envID")
+	name := c.Param("name")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "UpdateMirrorMode",
+		"environment_id": environmentID,
+		"target":         name,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing update mirror mode request")
+
+	var req UpdateMirrorModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	result, err := h.service.UpdateMirrorMode(c.Request.Context(), environmentID, name, *req.Enabled)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to update mirror mode")
+		switch err {
+		case appErrors.ErrProviderCredentialNotFound, appErrors.ErrInvalidProviderType, appErrors.ErrMirrorModeRequiresPrefix,
+			appErrors.ErrProviderCredentialGetFailed, appErrors.ErrProviderCredentialUpdateFailed, appErrors.ErrInternalServer:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			utils.RespondError(c, http.StatusInternalServerError, "update_mirror_mode_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.WithField("mirror_mode", result.MirrorMode).Info("Successfully updated mirror mode")
+
+	utils.RespondSuccess(c, http.StatusOK, result)
+}
+
+// UpdateKeyMapping handles PUT /orgs/:orgID/secret-groups/:groupID/environments/:envID/providers/credentials/:name/key-mapping
+func (h *ProviderHandler) UpdateKeyMapping(c *gin.Context) {
+	environmentID := c.Param("envID")
+	name := c.Param("name")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "UpdateKeyMapping",
+		"environment_id": environmentID,
+		"target":         name,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing update key mapping request")
+
+	var req KeyMappingRules
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	result, err := h.service.UpdateKeyMapping(c.Request.Context(), environmentID, name, req)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to update key mapping")
+		switch err {
+		case appErrors.ErrProviderCredentialNotFound, appErrors.ErrInvalidKeyMapping,
+			appErrors.ErrProviderCredentialUpdateFailed, appErrors.ErrInternalServer:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			utils.RespondError(c, http.StatusInternalServerError, "update_key_mapping_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.Info("Successfully updated key mapping")
+
+	utils.RespondSuccess(c, http.StatusOK, result)
+}
+
+// DeleteProviderCredential handles DELETE /orgs/:orgID/secret-groups/:groupID/environments/:envID/providers/credentials/:name
 func (h *ProviderHandler) DeleteProviderCredential(c *gin.Context) {
 	environmentID := c.Param("envID")
-	provider := c.Param("provider")
+	name := c.Param("name")
 
 	logEntry := h.logger.WithFields(logrus.Fields{
 		"handler":        "DeleteProviderCredential",
 		"environment_id": environmentID,
-		"provider":       provider,
+		"target":         name,
 		"method":         c.Request.Method,
 		"path":           c.Request.URL.Path,
 	})
 
 	logEntry.Info("Processing delete provider credential request")
 
-	err := h.service.DeleteProviderCredential(c.Request.Context(), environmentID, provider)
+	err := h.service.DeleteProviderCredential(c.Request.Context(), environmentID, name)
 	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to delete provider credential")
 		switch err {
@@ -269,6 +433,301 @@ func (h *ProviderHandler) DeleteProviderCredential(c *gin.Context) {
 	logEntry.Info("Successfully deleted provider credential")
 
 	utils.RespondSuccess(c, http.StatusOK, map[string]any{
-		"message": fmt.Sprintf(" %s provider config deleted successfully", provider),
+		"message": fmt.Sprintf(" %s provider config deleted successfully", name),
+	})
+}
+
+// CreateProviderConnection handles POST /orgs/:orgID/provider-connections
+func (h *ProviderHandler) CreateProviderConnection(c *gin.Context) {
+	organizationID := c.Param("orgID")
+	userID := c.GetString("user_id")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "CreateProviderConnection",
+		"organization_id": organizationID,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing create provider connection request")
+
+	var req CreateProviderConnectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	result, err := h.service.CreateProviderConnection(c.Request.Context(), organizationID, userID, req)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to create provider connection")
+		switch err {
+		case appErrors.ErrInvalidProviderType, appErrors.ErrInvalidProviderConnectionName, appErrors.ErrInvalidProviderData,
+			appErrors.ErrProviderConnectionExists, appErrors.ErrProviderEncryptionFailed,
+			appErrors.ErrProviderConnectionFailed, appErrors.ErrInternalServer:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			utils.RespondError(c, http.StatusInternalServerError, "create_provider_connection_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.WithField("connection_id", result.ID).Info("Successfully created provider connection")
+
+	utils.RespondSuccess(c, http.StatusCreated, result)
+}
+
+// ListProviderConnections handles GET /orgs/:orgID/provider-connections
+func (h *ProviderHandler) ListProviderConnections(c *gin.Context) {
+	organizationID := c.Param("orgID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "ListProviderConnections",
+		"organization_id": organizationID,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing list provider connections request")
+
+	result, err := h.service.ListProviderConnections(c.Request.Context(), organizationID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list provider connections")
+		switch err {
+		case appErrors.ErrProviderConnectionFailed, appErrors.ErrInternalServer:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			utils.RespondError(c, http.StatusInternalServerError, "list_provider_connections_failed", err.Error())
+			return
+		}
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, result)
+}
+
+// GetProviderConnection handles GET /orgs/:orgID/provider-connections/:name
+func (h *ProviderHandler) GetProviderConnection(c *gin.Context) {
+	organizationID := c.Param("orgID")
+	name := c.Param("name")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "GetProviderConnection",
+		"organization_id": organizationID,
+		"connection":      name,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing get provider connection request")
+
+	result, err := h.service.GetProviderConnection(c.Request.Context(), organizationID, name)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to get provider connection")
+		switch err {
+		case appErrors.ErrProviderConnectionNotFound, appErrors.ErrProviderConnectionFailed, appErrors.ErrInternalServer:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			utils.RespondError(c, http.StatusInternalServerError, "get_provider_connection_failed", err.Error())
+			return
+		}
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, result)
+}
+
+// RotateProviderConnection handles PUT /orgs/:orgID/provider-connections/:name/credentials
+func (h *ProviderHandler) RotateProviderConnection(c *gin.Context) {
+	organizationID := c.Param("orgID")
+	name := c.Param("name")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "RotateProviderConnection",
+		"organization_id": organizationID,
+		"connection":      name,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing rotate provider connection request")
+
+	var req RotateProviderConnectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	result, err := h.service.RotateProviderConnection(c.Request.Context(), organizationID, name, req)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to rotate provider connection")
+		switch err {
+		case appErrors.ErrProviderConnectionNotFound, appErrors.ErrInvalidProviderData, appErrors.ErrProviderEncryptionFailed,
+			appErrors.ErrProviderConnectionFailed, appErrors.ErrInternalServer:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			utils.RespondError(c, http.StatusInternalServerError, "rotate_provider_connection_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.WithField("targets", len(result.Targets)).Info("Successfully rotated provider connection")
+
+	utils.RespondSuccess(c, http.StatusOK, result)
+}
+
+// DeleteProviderConnection handles DELETE /orgs/:orgID/provider-connections/:name
+func (h *ProviderHandler) DeleteProviderConnection(c *gin.Context) {
+	organizationID := c.Param("orgID")
+	name := c.Param("name")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "DeleteProviderConnection",
+		"organization_id": organizationID,
+		"connection":      name,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing delete provider connection request")
+
+	if err := h.service.DeleteProviderConnection(c.Request.Context(), organizationID, name); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to delete provider connection")
+		switch err {
+		case appErrors.ErrProviderConnectionNotFound, appErrors.ErrProviderConnectionInUse,
+			appErrors.ErrProviderConnectionFailed, appErrors.ErrInternalServer:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			utils.RespondError(c, http.StatusInternalServerError, "delete_provider_connection_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.Info("Successfully deleted provider connection")
+
+	utils.RespondSuccess(c, http.StatusOK, map[string]any{
+		"message": fmt.Sprintf("provider connection %s deleted successfully", name),
+	})
+}
+
+// CreateProviderConnectionGrant handles POST /orgs/:orgID/provider-connections/:name/grants
+func (h *ProviderHandler) CreateProviderConnectionGrant(c *gin.Context) {
+	organizationID := c.Param("orgID")
+	name := c.Param("name")
+	userID := c.GetString("user_id")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "CreateProviderConnectionGrant",
+		"organization_id": organizationID,
+		"connection":      name,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing create provider connection grant request")
+
+	var req CreateProviderConnectionGrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	result, err := h.service.CreateProviderConnectionGrant(c.Request.Context(), organizationID, name, userID, req)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to create provider connection grant")
+		switch err {
+		case appErrors.ErrProviderConnectionNotFound, appErrors.ErrInvalidProviderConnectionGrant, appErrors.ErrProviderConnectionGrantExists,
+			appErrors.ErrProviderConnectionFailed, appErrors.ErrInternalServer:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			utils.RespondError(c, http.StatusInternalServerError, "create_provider_connection_grant_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.WithField("grant_id", result.ID).Info("Successfully created provider connection grant")
+
+	utils.RespondSuccess(c, http.StatusCreated, result)
+}
+
+// ListProviderConnectionGrants handles GET /orgs/:orgID/provider-connections/:name/grants
+func (h *ProviderHandler) ListProviderConnectionGrants(c *gin.Context) {
+	organizationID := c.Param("orgID")
+	name := c.Param("name")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "ListProviderConnectionGrants",
+		"organization_id": organizationID,
+		"connection":      name,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing list provider connection grants request")
+
+	result, err := h.service.ListProviderConnectionGrants(c.Request.Context(), organizationID, name)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list provider connection grants")
+		switch err {
+		case appErrors.ErrProviderConnectionNotFound, appErrors.ErrProviderConnectionFailed, appErrors.ErrInternalServer:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			utils.RespondError(c, http.StatusInternalServerError, "list_provider_connection_grants_failed", err.Error())
+			return
+		}
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, result)
+}
+
+// DeleteProviderConnectionGrant handles DELETE /orgs/:orgID/provider-connections/:name/grants/:grantID
+func (h *ProviderHandler) DeleteProviderConnectionGrant(c *gin.Context) {
+	organizationID := c.Param("orgID")
+	name := c.Param("name")
+	grantID := c.Param("grantID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "DeleteProviderConnectionGrant",
+		"organization_id": organizationID,
+		"connection":      name,
+		"grant_id":        grantID,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing delete provider connection grant request")
+
+	if err := h.service.DeleteProviderConnectionGrant(c.Request.Context(), organizationID, name, grantID); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to delete provider connection grant")
+		switch err {
+		case appErrors.ErrProviderConnectionNotFound, appErrors.ErrProviderConnectionGrantNotFound,
+			appErrors.ErrProviderConnectionFailed, appErrors.ErrInternalServer:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			utils.RespondError(c, http.StatusInternalServerError, "delete_provider_connection_grant_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.Info("Successfully deleted provider connection grant")
+
+	utils.RespondSuccess(c, http.StatusOK, map[string]any{
+		"message": "provider connection grant deleted successfully",
 	})
 }
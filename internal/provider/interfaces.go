@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"time"
 )
 
 // Secret represents a secret to be synced to a provider
@@ -17,6 +18,14 @@ type SyncResult struct {
 	Error   string `json:"error,omitempty"`
 }
 
+// RemoteSecret represents a secret as it currently exists on a provider
+type RemoteSecret struct {
+	Name      string     `json:"name"`
+	Value     string     `json:"-"`
+	HasValue  bool       `json:"has_value"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
 // ProviderConfig represents the configuration and credentials for a provider
 type ProviderConfig struct {
 	Provider    ProviderType           `json:"provider"`
@@ -45,6 +54,67 @@ type ProviderSyncer interface {
 	GetProviderName() string
 }
 
+// ProviderDeleter is implemented by providers that can remove secrets they previously synced.
+// It is optional: keys renamed or moved away are left in place on providers that do not implement it.
+type ProviderDeleter interface {
+	// Delete removes the named secrets from the provider
+	// Secrets that no longer exist on the provider are reported as successfully removed
+	Delete(ctx context.Context, names []string) ([]SyncResult, error)
+}
+
+// ProviderPruner is implemented by providers that can tell which remote secrets Kavach created for a target, by
+// label, tag or a managed-name prefix. It backs mirror mode; secrets created for other targets are never pruned.
+type ProviderPruner interface {
+	// SetOwner sets the environment and target recorded on the secrets the following syncs create
+	// and required of the secrets PruneCandidates returns
+	SetOwner(owner SecretOwner)
+	// PruneCandidates returns the remote names of the owned secrets whose names are not among the given secret names
+	PruneCandidates(ctx context.Context, keep []string) ([]string, error)
+	// Prune deletes the given remote secrets, which must come from PruneCandidates.
+	// Results are reported by remote name.
+	Prune(ctx context.Context, remoteNames []string) ([]SyncResult, error)
+}
+
+// ProviderReader is implemented by providers that can report the secrets they currently hold.
+// It is optional: drift detection is unavailable for providers that do not implement it.
+type ProviderReader interface {
+	// RemoteName returns the name a secret is stored under on the provider after prefixing and sanitization
+	RemoteName(name string) string
+
+	// List returns the metadata of the secrets in the scope this provider syncs to
+	List(ctx context.Context) ([]RemoteSecret, error)
+
+	// Read returns the named remote secrets with their current values. Secrets that do not exist
+	// are left out. Providers that cannot read values back return metadata with HasValue false.
+	Read(ctx context.Context, remoteNames []string) ([]RemoteSecret, error)
+}
+
+// ProviderImporter is implemented by providers whose secret values can be read back, so an environment can be
+// imported from them. It is optional: write-only providers such as GitHub cannot be imported from.
+type ProviderImporter interface {
+	// Import returns every secret in the scope this provider syncs to, with the value of its latest enabled
+	// version. Secrets without an enabled version are left out.
+	Import(ctx context.Context) ([]RemoteSecret, error)
+
+	// ImportName returns the key a remote secret is imported as, removing the configured prefix and reversing
+	// sanitization where it can. It reports false for names outside the prefix.
+	ImportName(remoteName string) (string, bool)
+}
+
+// ProviderVersionRecorder is implemented by providers that record the version a sync came from,
+// such as in labels or annotations. It is optional.
+type ProviderVersionRecorder interface {
+	// SetSyncVersion sets the version ID recorded by the following syncs
+	SetSyncVersion(versionID string)
+}
+
+// ProviderWorkloadIdentityUser is implemented by providers that can authenticate with workload tokens
+// from Kavach's OIDC issuer instead of stored cloud credentials. It is optional.
+type ProviderWorkloadIdentityUser interface {
+	// SetWorkloadIdentity sets the environment and provider the workload tokens are minted for
+	SetWorkloadIdentity(identity WorkloadIdentity)
+}
+
 // ProviderFactory creates provider sync instances
 type ProviderFactory interface {
 	// CreateProvider creates a new provider sync instance for the given provider type
@@ -0,0 +1,273 @@
+package provider
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+)
+
+// Key mapping case conversions
+const (
+	KeyCaseUpper      = "upper"       // API_KEY
+	KeyCaseLower      = "lower"       // api_key
+	KeyCaseKebab      = "kebab"       // api-key
+	KeyCaseSnake      = "snake"       // api_key, with hyphens and dots turned into underscores
+	KeyCaseUpperSnake = "upper_snake" // API_KEY, with hyphens and dots turned into underscores
+)
+
+// KeyMappingRules decide which keys of a version a provider target receives and under which names.
+// They apply in order: include and exclude filters, groups, renames, then case, prefix and suffix.
+// Renamed keys and group names are used exactly as given; case, prefix and suffix apply to the other keys.
+// Providers may still sanitize the resulting names, as RemoteName reports.
+type KeyMappingRules struct {
+	Include []string          `json:"include,omitempty"` // Globs; when set, only matching keys are synced
+	Exclude []string          `json:"exclude,omitempty"` // Globs of keys never synced
+	Rename  map[string]string `json:"rename,omitempty"`  // Key to the exact name it is synced under
+	Case    string            `json:"case,omitempty"`    // upper, lower, kebab, snake or upper_snake
+	Prefix  string            `json:"prefix,omitempty"`
+	Suffix  string            `json:"suffix,omitempty"`
+	Groups  []KeyMappingGroup `json:"groups,omitempty"` // Keys combined into one JSON-valued secret
+}
+
+// KeyMappingGroup combines the keys matching its globs into one secret whose value is a JSON object
+// of the original keys and their values
+type KeyMappingGroup struct {
+	Name string   `json:"name"`
+	Keys []string `json:"keys"` // Globs; a key joins the first group it matches
+}
+
+// MappedKey describes where one key of a version ends up on a provider target
+type MappedKey struct {
+	Name       string `json:"name"`                  // Key in the version
+	MappedName string `json:"mapped_name,omitempty"` // Name after the mapping rules; empty when excluded
+	Group      string `json:"group,omitempty"`       // Group the key is combined into
+	Excluded   bool   `json:"excluded,omitempty"`
+}
+
+// IsZero reports whether the rules leave every key as it is
+func (r KeyMappingRules) IsZero() bool {
+	return len(r.Include) == 0 && len(r.Exclude) == 0 && len(r.Rename) == 0 && r.Case == "" &&
+		r.Prefix == "" && r.Suffix == "" && len(r.Groups) == 0
+}
+
+// Validate checks that the globs parse and that renames and groups name something
+func (r KeyMappingRules) Validate() error {
+	patterns := append(append([]string{}, r.Include...), r.Exclude...)
+	groupNames := make(map[string]bool, len(r.Groups))
+	for _, group := range r.Groups {
+		if group.Name == "" || len(group.Keys) == 0 || groupNames[group.Name] {
+			return appErrors.ErrInvalidKeyMapping
+		}
+		groupNames[group.Name] = true
+		patterns = append(patterns, group.Keys...)
+	}
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return appErrors.ErrInvalidKeyMapping
+		}
+	}
+	for key, name := range r.Rename {
+		if key == "" || name == "" {
+			return appErrors.ErrInvalidKeyMapping
+		}
+	}
+	switch r.Case {
+	case "", KeyCaseUpper, KeyCaseLower, KeyCaseKebab, KeyCaseSnake, KeyCaseUpperSnake:
+	default:
+		return appErrors.ErrInvalidKeyMapping
+	}
+	return nil
+}
+
+// MapKeys reports where each of the given keys ends up, in the order given
+func (r KeyMappingRules) MapKeys(names []string) []MappedKey {
+	mapped := make([]MappedKey, len(names))
+	for i, name := range names {
+		mapped[i] = r.mapKey(name)
+	}
+	return mapped
+}
+
+// MapName returns the name a single key is synced under. It reports false for keys that are
+// excluded or combined into a group, since they are not synced under a name of their own.
+func (r KeyMappingRules) MapName(name string) (string, bool) {
+	mapped := r.mapKey(name)
+	if mapped.Excluded || mapped.Group != "" {
+		return "", false
+	}
+	return mapped.MappedName, true
+}
+
+// UnmapName returns the key a mapped name most likely came from, reversing renames, the prefix and the suffix.
+// Case conversion cannot be reversed, so the rest of the name is kept as it is. It reports true for group
+// names, whose value holds the original keys.
+func (r KeyMappingRules) UnmapName(mapped string) (string, bool) {
+	for _, group := range r.Groups {
+		if group.Name == mapped {
+			return "", true
+		}
+	}
+	for key, renamed := range r.Rename {
+		if renamed == mapped {
+			return key, false
+		}
+	}
+
+	name := mapped
+	if r.Prefix != "" && strings.HasPrefix(name, r.Prefix) && len(name) > len(r.Prefix) {
+		name = strings.TrimPrefix(name, r.Prefix)
+	}
+	if r.Suffix != "" && strings.HasSuffix(name, r.Suffix) && len(name) > len(r.Suffix) {
+		name = strings.TrimSuffix(name, r.Suffix)
+	}
+	return name, false
+}
+
+// Apply maps secrets to the secrets a provider target receives. It fails when two keys, or a key
+// and a group, end up under the same name, since one would silently overwrite the other.
+func (r KeyMappingRules) Apply(secrets []Secret) ([]Secret, error) {
+	if r.IsZero() {
+		return secrets, nil
+	}
+
+	names := make([]string, len(secrets))
+	for i, secret := range secrets {
+		names[i] = secret.Name
+	}
+	if len(KeyMappingCollisions(r.MapKeys(names))) > 0 {
+		return nil, appErrors.ErrKeyMappingCollision
+	}
+
+	var mapped []Secret
+	groupValues := make(map[string]map[string]string)
+	for _, secret := range secrets {
+		key := r.mapKey(secret.Name)
+		switch {
+		case key.Excluded:
+		case key.Group != "":
+			if groupValues[key.Group] == nil {
+				groupValues[key.Group] = make(map[string]string)
+			}
+			groupValues[key.Group][secret.Name] = secret.Value
+		default:
+			mapped = append(mapped, Secret{Name: key.MappedName, Value: secret.Value})
+		}
+	}
+
+	// Groups follow the individual keys in rule order; keys inside a group keep their original names
+	for _, group := range r.Groups {
+		values, ok := groupValues[group.Name]
+		if !ok {
+			continue
+		}
+		value, err := json.Marshal(values)
+		if err != nil {
+			return nil, err
+		}
+		mapped = append(mapped, Secret{Name: group.Name, Value: string(value)})
+	}
+
+	return mapped, nil
+}
+
+// KeyMappingCollisions returns the mapped names that more than one key or group ends up under,
+// with the keys involved. Keys of the same group share their group name without colliding.
+func KeyMappingCollisions(mapped []MappedKey) map[string][]string {
+	keysByName := make(map[string][]string)
+	groupByName := make(map[string]string)
+	for _, key := range mapped {
+		if key.Excluded {
+			continue
+		}
+		keysByName[key.MappedName] = append(keysByName[key.MappedName], key.Name)
+		if key.Group != "" {
+			groupByName[key.MappedName] = key.Group
+		}
+	}
+
+	collisions := make(map[string][]string)
+	for name, keys := range keysByName {
+		if len(keys) < 2 {
+			continue
+		}
+		group, grouped := groupByName[name]
+		if grouped {
+			// Only a key outside the group that lands on the group's name collides
+			collides := false
+			for _, key := range mapped {
+				if !key.Excluded && key.MappedName == name && key.Group != group {
+					collides = true
+				}
+			}
+			if !collides {
+				continue
+			}
+		}
+		sort.Strings(keys)
+		collisions[name] = keys
+	}
+	return collisions
+}
+
+func (r KeyMappingRules) mapKey(name string) MappedKey {
+	if len(r.Include) > 0 && !matchesAny(r.Include, name) || matchesAny(r.Exclude, name) {
+		return MappedKey{Name: name, Excluded: true}
+	}
+	for _, group := range r.Groups {
+		if matchesAny(group.Keys, name) {
+			return MappedKey{Name: name, MappedName: group.Name, Group: group.Name}
+		}
+	}
+	if renamed, ok := r.Rename[name]; ok {
+		return MappedKey{Name: name, MappedName: renamed}
+	}
+	return MappedKey{Name: name, MappedName: r.Prefix + convertKeyCase(name, r.Case) + r.Suffix}
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func convertKeyCase(name, keyCase string) string {
+	switch keyCase {
+	case KeyCaseUpper:
+		return strings.ToUpper(name)
+	case KeyCaseLower:
+		return strings.ToLower(name)
+	case KeyCaseKebab:
+		return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+	case KeyCaseSnake:
+		return strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToLower(name))
+	case KeyCaseUpperSnake:
+		return strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToUpper(name))
+	default:
+		return name
+	}
+}
+
+// parseKeyMapping decodes the rules stored on a provider target; targets without rules decode to zero rules
+func parseKeyMapping(raw json.RawMessage) (KeyMappingRules, error) {
+	var rules KeyMappingRules
+	if len(raw) == 0 {
+		return rules, nil
+	}
+	err := json.Unmarshal(raw, &rules)
+	return rules, err
+}
+
+// keyMappingPtr returns the stored rules for responses, or nil when the target has none
+func keyMappingPtr(raw json.RawMessage) *KeyMappingRules {
+	rules, err := parseKeyMapping(raw)
+	if err != nil || rules.IsZero() {
+		return nil
+	}
+	return &rules
+}
@@ -0,0 +1,442 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Labels and annotations Kavach sets on the Secret and restarted Deployments
+const (
+	kubernetesManagedByLabel        = "app.kubernetes.io/managed-by"
+	kubernetesManagedByValue        = "kavach-backend"
+	kubernetesVersionIDKey          = "kavach.io/version-id"
+	kubernetesEnvironmentIDKey      = "kavach.io/environment-id"
+	kubernetesTargetKey             = "kavach.io/target"
+	kubernetesRestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+)
+
+// KubernetesProvider implements ProviderSync for Kubernetes. Every key is stored in one Secret,
+// which Kavach owns.
+type KubernetesProvider struct {
+	credentials KubernetesCredentials
+	config      KubernetesConfig
+	logger      *logrus.Logger
+	client      kubernetes.Interface
+	executor    *SyncExecutor
+	versionID   string
+	owner       SecretOwner // Environment and target recorded on the Secret when it is created
+}
+
+// NewKubernetesProvider creates a new Kubernetes provider instance
+func NewKubernetesProvider(credentials map[string]interface{}, config map[string]interface{}, logger *logrus.Logger) (*KubernetesProvider, error) {
+	// Parse credentials
+	credBytes, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	var kubeCreds KubernetesCredentials
+	if err := json.Unmarshal(credBytes, &kubeCreds); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Kubernetes credentials: %w", err)
+	}
+
+	// Parse config
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var kubeConfig KubernetesConfig
+	if err := json.Unmarshal(configBytes, &kubeConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Kubernetes config: %w", err)
+	}
+	if kubeConfig.SecretType == "" {
+		kubeConfig.SecretType = string(corev1.SecretTypeOpaque)
+	}
+
+	restConfig, err := newKubernetesRESTConfig(kubeCreds)
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	return &KubernetesProvider{
+		credentials: kubeCreds,
+		config:      kubeConfig,
+		logger:      logger,
+		client:      client,
+		executor:    NewSyncExecutor(DefaultSyncExecutorConfig(), logger),
+	}, nil
+}
+
+// newKubernetesRESTConfig returns the client configuration for a kubeconfig, or for a server
+// with a bearer token
+func newKubernetesRESTConfig(creds KubernetesCredentials) (*rest.Config, error) {
+	if creds.Kubeconfig == "" {
+		return &rest.Config{
+			Host:            creds.Server,
+			BearerToken:     creds.Token,
+			TLSClientConfig: rest.TLSClientConfig{CAData: []byte(creds.CAData)},
+		}, nil
+	}
+
+	kubeconfig, err := clientcmd.Load([]byte(creds.Kubeconfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
+	restConfig, err := clientcmd.NewDefaultClientConfig(*kubeconfig, &clientcmd.ConfigOverrides{CurrentContext: creds.Context}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	return restConfig, nil
+}
+
+// SetSyncVersion sets the version ID recorded on the Secret by the following syncs
+func (k *KubernetesProvider) SetSyncVersion(versionID string) {
+	k.versionID = versionID
+}
+
+// Sync syncs secrets to the Kubernetes Secret
+func (k *KubernetesProvider) Sync(ctx context.Context, secrets []Secret) ([]SyncResult, error) {
+	logEntry := k.logger.WithFields(logrus.Fields{
+		"provider":     "kubernetes",
+		"namespace":    k.config.Namespace,
+		"secret_name":  k.config.SecretName,
+		"secret_count": len(secrets),
+	})
+
+	logEntry.Info("Starting Kubernetes sync")
+
+	// All keys go into one Secret, so they are written together and share the outcome
+	results := k.updateSecret(ctx, secrets, func(data map[string][]byte) {
+		for _, secret := range secrets {
+			data[k.RemoteName(secret.Name)] = []byte(secret.Value)
+		}
+	})
+
+	for _, result := range results {
+		if !result.Success {
+			logEntry.WithFields(logrus.Fields{
+				"secret_name": result.Name,
+				"error":       result.Error,
+			}).Error("Failed to sync secret to Kubernetes after all retry attempts")
+		}
+	}
+
+	logEntry.WithFields(logrus.Fields{
+		"synced_count": len(results),
+		"total_count":  len(secrets),
+	}).Info("Completed Kubernetes sync")
+
+	return results, nil
+}
+
+// Delete removes keys from the Kubernetes Secret
+func (k *KubernetesProvider) Delete(ctx context.Context, names []string) ([]SyncResult, error) {
+	remoteNames := make([]string, len(names))
+	for i, name := range names {
+		remoteNames[i] = k.RemoteName(name)
+	}
+
+	results := k.removeKeys(ctx, remoteNames)
+	for i := range results {
+		results[i].Name = names[i]
+	}
+	return results, nil
+}
+
+// RemoteName returns the key a secret is stored under in the Secret's data
+func (k *KubernetesProvider) RemoteName(name string) string {
+	return sanitizeKubernetesKey(name)
+}
+
+// List returns the keys of the Secret
+func (k *KubernetesProvider) List(ctx context.Context) ([]RemoteSecret, error) {
+	secret, err := k.getSecret(ctx)
+	if err != nil || secret == nil {
+		return nil, err
+	}
+
+	secrets := make([]RemoteSecret, 0, len(secret.Data))
+	for name := range secret.Data {
+		secrets = append(secrets, RemoteSecret{Name: name})
+	}
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+	return secrets, nil
+}
+
+// Read returns the current value of each named key of the Secret
+func (k *KubernetesProvider) Read(ctx context.Context, remoteNames []string) ([]RemoteSecret, error) {
+	secret, err := k.getSecret(ctx)
+	if err != nil || secret == nil {
+		return nil, err
+	}
+
+	var secrets []RemoteSecret
+	for _, name := range remoteNames {
+		if value, ok := secret.Data[name]; ok {
+			secrets = append(secrets, RemoteSecret{Name: name, Value: string(value), HasValue: true})
+		}
+	}
+	return secrets, nil
+}
+
+// SetOwner sets the environment and target recorded on the Secret when it is created and required for pruning
+func (k *KubernetesProvider) SetOwner(owner SecretOwner) {
+	k.owner = owner
+}
+
+// PruneCandidates returns the keys of the Secret that are not among the given secret names. The
+// whole Secret belongs to the target that created it, so every other key in it is a candidate.
+// Secrets created for another environment or target have no candidates.
+func (k *KubernetesProvider) PruneCandidates(ctx context.Context, keep []string) ([]string, error) {
+	if !k.owner.IsSet() {
+		return nil, errSecretOwnerUnset
+	}
+
+	secret, err := k.getSecret(ctx)
+	if err != nil || secret == nil {
+		return nil, err
+	}
+	owner := map[string]string{
+		ownerEnvironmentKey: secret.Annotations[kubernetesEnvironmentIDKey],
+		ownerTargetKey:      secret.Annotations[kubernetesTargetKey],
+	}
+	if secret.Labels[kubernetesManagedByLabel] != kubernetesManagedByValue || !k.owner.Owns(owner) {
+		return nil, nil
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		kept[k.RemoteName(name)] = true
+	}
+
+	var candidates []string
+	for name := range secret.Data {
+		if !kept[name] {
+			candidates = append(candidates, name)
+		}
+	}
+	sort.Strings(candidates)
+	return candidates, nil
+}
+
+// Prune removes the given keys from the Secret
+func (k *KubernetesProvider) Prune(ctx context.Context, remoteNames []string) ([]SyncResult, error) {
+	results := k.removeKeys(ctx, remoteNames)
+
+	k.logger.WithFields(logrus.Fields{
+		"provider":     "kubernetes",
+		"namespace":    k.config.Namespace,
+		"secret_name":  k.config.SecretName,
+		"pruned_count": len(results),
+	}).Info("Completed Kubernetes prune")
+	return results, nil
+}
+
+// ValidateCredentials validates Kubernetes credentials by looking up the Secret
+func (k *KubernetesProvider) ValidateCredentials(ctx context.Context) error {
+	logEntry := k.logger.WithFields(logrus.Fields{
+		"provider":    "kubernetes",
+		"namespace":   k.config.Namespace,
+		"secret_name": k.config.SecretName,
+	})
+
+	logEntry.Info("Validating Kubernetes credentials")
+
+	if _, err := k.getSecret(ctx); err != nil {
+		return fmt.Errorf("failed to validate Kubernetes credentials: %w", err)
+	}
+
+	logEntry.Info("Kubernetes credentials validated successfully")
+	return nil
+}
+
+// GetProviderName returns the provider name
+func (k *KubernetesProvider) GetProviderName() string {
+	return "kubernetes"
+}
+
+// removeKeys removes keys from the Secret. Keys that no longer exist are reported as
+// successfully removed.
+func (k *KubernetesProvider) removeKeys(ctx context.Context, remoteNames []string) []SyncResult {
+	removed := make([]Secret, len(remoteNames))
+	for i, name := range remoteNames {
+		removed[i] = Secret{Name: name}
+	}
+	return k.updateSecret(ctx, removed, func(data map[string][]byte) {
+		for _, name := range remoteNames {
+			delete(data, name)
+		}
+	})
+}
+
+// updateSecret applies a change to the data of the Secret and writes it back once, creating the
+// Secret if needed. Every given secret shares the outcome of that write. When the data changed,
+// the configured Deployments are restarted.
+func (k *KubernetesProvider) updateSecret(ctx context.Context, secrets []Secret, change func(data map[string][]byte)) []SyncResult {
+	changed := false
+	document := []Secret{{Name: k.config.SecretName}}
+	outcome := k.executor.Run(ctx, ProviderKubernetes, k.config.RetryConfig, document, func(ctx context.Context, _ Secret) error {
+		// Read on every attempt so a retry after a conflict keeps keys written in between
+		current, err := k.getSecret(ctx)
+		if err != nil {
+			return err
+		}
+
+		if current == nil {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: k.config.SecretName, Namespace: k.config.Namespace},
+				Type:       corev1.SecretType(k.config.SecretType),
+				Data:       map[string][]byte{},
+			}
+			change(secret.Data)
+			if len(secret.Data) == 0 {
+				// Nothing to store, such as when removing keys from a Secret that does not exist
+				return nil
+			}
+			k.applyMetadata(&secret.ObjectMeta)
+			if k.owner.IsSet() {
+				secret.Annotations[kubernetesEnvironmentIDKey] = k.owner.EnvironmentID
+				secret.Annotations[kubernetesTargetKey] = k.owner.Target
+			}
+			if _, err := k.client.CoreV1().Secrets(k.config.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+				return fmt.Errorf("failed to create Kubernetes secret '%s': %w", k.config.SecretName, err)
+			}
+			changed = true
+			return nil
+		}
+
+		before := maps.Clone(current.Data)
+		if current.Data == nil {
+			current.Data = map[string][]byte{}
+		}
+		change(current.Data)
+		k.applyMetadata(&current.ObjectMeta)
+
+		// The update carries the resource version that was read, so a concurrent change is a conflict
+		if _, err := k.client.CoreV1().Secrets(k.config.Namespace).Update(ctx, current, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update Kubernetes secret '%s': %w", k.config.SecretName, err)
+		}
+		changed = !maps.EqualFunc(before, current.Data, func(a, b []byte) bool { return string(a) == string(b) })
+		return nil
+	})
+
+	if outcome[0].Success && changed {
+		k.restartDeployments(ctx)
+	}
+
+	results := make([]SyncResult, len(secrets))
+	for i, secret := range secrets {
+		results[i] = SyncResult{Name: secret.Name, Success: outcome[0].Success, Error: outcome[0].Error}
+	}
+	return results
+}
+
+// applyMetadata sets the configured labels and annotations on the Secret with the managed-by
+// label and the version ID of the sync
+func (k *KubernetesProvider) applyMetadata(meta *metav1.ObjectMeta) {
+	if meta.Labels == nil {
+		meta.Labels = map[string]string{}
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	maps.Copy(meta.Labels, k.config.Labels)
+	maps.Copy(meta.Annotations, k.config.Annotations)
+
+	meta.Labels[kubernetesManagedByLabel] = kubernetesManagedByValue
+	if k.versionID != "" {
+		meta.Labels[kubernetesVersionIDKey] = k.versionID
+		meta.Annotations[kubernetesVersionIDKey] = k.versionID
+	}
+}
+
+// restartDeployments triggers a rolling restart of the configured Deployments, the way
+// kubectl rollout restart does. Failures are logged and do not fail the sync, since the
+// Secret was already written.
+func (k *KubernetesProvider) restartDeployments(ctx context.Context) {
+	if len(k.config.RestartDeployments) == 0 {
+		return
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{kubernetesRestartedAtAnnotation: time.Now().Format(time.RFC3339)},
+				},
+			},
+		},
+	})
+	if err != nil {
+		k.logger.WithField("error", err.Error()).Error("Failed to build Kubernetes restart patch")
+		return
+	}
+
+	for _, name := range k.config.RestartDeployments {
+		logEntry := k.logger.WithFields(logrus.Fields{
+			"provider":   "kubernetes",
+			"namespace":  k.config.Namespace,
+			"deployment": name,
+		})
+
+		_, err := k.client.AppsV1().Deployments(k.config.Namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Error("Failed to restart Kubernetes deployment")
+			continue
+		}
+		logEntry.Info("Restarted Kubernetes deployment")
+	}
+}
+
+// getSecret returns the Secret, or nil if it does not exist
+func (k *KubernetesProvider) getSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret, err := k.client.CoreV1().Secrets(k.config.Namespace).Get(ctx, k.config.SecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes secret '%s': %w", k.config.SecretName, err)
+	}
+	return secret, nil
+}
+
+// sanitizeKubernetesKey sanitizes a name for use as a Secret data key, which allows letters,
+// digits and _.-
+func sanitizeKubernetesKey(name string) string {
+	result := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune("_.-", r):
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+
+	// Limit length to 253 characters (Kubernetes Secret key limit)
+	if len(result) > 253 {
+		result = result[:253]
+	}
+
+	return result
+}
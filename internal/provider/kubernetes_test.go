@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// KubernetesProviderTestSuite tests the Kubernetes provider against a fake API server
+type KubernetesProviderTestSuite struct {
+	suite.Suite
+	client *fake.Clientset
+	logger *logrus.Logger
+	ctx    context.Context
+}
+
+// SetupTest creates a fake cluster with one Deployment for each test
+func (suite *KubernetesProviderTestSuite) SetupTest() {
+	suite.logger = logrus.New()
+	suite.logger.SetOutput(io.Discard)
+	suite.client = fake.NewClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments-api", Namespace: "payments"},
+	})
+	suite.ctx = context.Background()
+}
+
+// newProvider creates a provider writing to the fake cluster with the given config
+func (suite *KubernetesProviderTestSuite) newProvider(config map[string]interface{}) *KubernetesProvider {
+	config["namespace"] = "payments"
+	config["secret_name"] = "payments-env"
+	provider, err := NewKubernetesProvider(map[string]interface{}{
+		"server": "https://kubernetes.example.com",
+		"token":  "service-account-token",
+	}, config, suite.logger)
+	require.NoError(suite.T(), err, "Failed to create provider")
+	provider.client = suite.client
+	provider.SetOwner(testOwner)
+	return provider
+}
+
+// getSecret returns the Secret from the fake cluster
+func (suite *KubernetesProviderTestSuite) getSecret() *corev1.Secret {
+	secret, err := suite.client.CoreV1().Secrets("payments").Get(suite.ctx, "payments-env", metav1.GetOptions{})
+	require.NoError(suite.T(), err)
+	return secret
+}
+
+// restartedAt returns the restart annotation of the Deployment's pod template
+func (suite *KubernetesProviderTestSuite) restartedAt() string {
+	deployment, err := suite.client.AppsV1().Deployments("payments").Get(suite.ctx, "payments-api", metav1.GetOptions{})
+	require.NoError(suite.T(), err)
+	return deployment.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"]
+}
+
+// TestSyncWritesSecret tests that keys are merged into the Secret with labels, annotations and the version ID
+func (suite *KubernetesProviderTestSuite) TestSyncWritesSecret() {
+	provider := suite.newProvider(map[string]interface{}{
+		"labels":              map[string]interface{}{"team": "platform"},
+		"annotations":         map[string]interface{}{"owner": "payments"},
+		"restart_deployments": []interface{}{"payments-api", "missing-deployment"},
+	})
+	provider.SetSyncVersion("550e8400-e29b-41d4-a716-446655440010")
+
+	results, err := provider.Sync(suite.ctx, []Secret{{Name: "DATABASE_URL", Value: "postgresql://localhost/db"}, {Name: "API_KEY", Value: "sk-1"}})
+	require.NoError(suite.T(), err)
+	for _, result := range results {
+		require.True(suite.T(), result.Success, result.Error)
+	}
+
+	secret := suite.getSecret()
+	assert.Equal(suite.T(), corev1.SecretTypeOpaque, secret.Type)
+	assert.Equal(suite.T(), map[string][]byte{"DATABASE_URL": []byte("postgresql://localhost/db"), "API_KEY": []byte("sk-1")}, secret.Data)
+	assert.Equal(suite.T(), map[string]string{
+		"app.kubernetes.io/managed-by": "kavach-backend",
+		"kavach.io/version-id":         "550e8400-e29b-41d4-a716-446655440010",
+		"team":                         "platform",
+	}, secret.Labels)
+	assert.Equal(suite.T(), "550e8400-e29b-41d4-a716-446655440010", secret.Annotations["kavach.io/version-id"])
+	assert.Equal(suite.T(), "payments", secret.Annotations["owner"])
+	assert.Equal(suite.T(), testOwner.EnvironmentID, secret.Annotations["kavach.io/environment-id"])
+	assert.Equal(suite.T(), testOwner.Target, secret.Annotations["kavach.io/target"])
+	assert.NotEmpty(suite.T(), suite.restartedAt(), "Expected the deployment to be restarted")
+
+	// Syncing the same values changes nothing, so nothing is restarted
+	deployment, err := suite.client.AppsV1().Deployments("payments").Get(suite.ctx, "payments-api", metav1.GetOptions{})
+	require.NoError(suite.T(), err)
+	deployment.Spec.Template.Annotations = nil
+	_, err = suite.client.AppsV1().Deployments("payments").Update(suite.ctx, deployment, metav1.UpdateOptions{})
+	require.NoError(suite.T(), err)
+
+	provider.SetSyncVersion("550e8400-e29b-41d4-a716-446655440011")
+	results, err = provider.Sync(suite.ctx, []Secret{{Name: "API_KEY", Value: "sk-1"}})
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), results[0].Success, results[0].Error)
+	assert.Empty(suite.T(), suite.restartedAt())
+	assert.Equal(suite.T(), "550e8400-e29b-41d4-a716-446655440011", suite.getSecret().Labels["kavach.io/version-id"])
+
+	read, err := provider.Read(suite.ctx, []string{"DATABASE_URL", "MISSING"})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), read, 1, "Missing keys are left out")
+	assert.Equal(suite.T(), "postgresql://localhost/db", read[0].Value)
+}
+
+// TestSyncRetriesConflicts tests that a conflicting update is read again and retried
+func (suite *KubernetesProviderTestSuite) TestSyncRetriesConflicts() {
+	provider := suite.newProvider(map[string]interface{}{
+		"retry_config": map[string]interface{}{"max_retries": 3, "retry_delay": 1000000},
+	})
+	_, err := provider.Sync(suite.ctx, []Secret{{Name: "API_KEY", Value: "sk-1"}})
+	require.NoError(suite.T(), err)
+
+	conflicts := 0
+	suite.client.PrependReactor("update", "secrets", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if conflicts > 0 {
+			return false, nil, nil
+		}
+		conflicts++
+		return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "secrets"}, "payments-env", nil)
+	})
+
+	results, err := provider.Sync(suite.ctx, []Secret{{Name: "API_KEY", Value: "sk-2"}})
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), results[0].Success, results[0].Error)
+	assert.Equal(suite.T(), 1, conflicts)
+	assert.Equal(suite.T(), []byte("sk-2"), suite.getSecret().Data["API_KEY"])
+}
+
+// TestPruneAndDelete tests that keys outside the version are pruned and deletes never create the Secret
+func (suite *KubernetesProviderTestSuite) TestPruneAndDelete() {
+	provider := suite.newProvider(map[string]interface{}{})
+
+	results, err := provider.Delete(suite.ctx, []string{"API_KEY"})
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), results[0].Success, results[0].Error)
+	_, err = suite.client.CoreV1().Secrets("payments").Get(suite.ctx, "payments-env", metav1.GetOptions{})
+	assert.True(suite.T(), apierrors.IsNotFound(err), "Expected no Secret to be created")
+
+	_, err = provider.Sync(suite.ctx, []Secret{{Name: "KEEP", Value: "1"}, {Name: "OLD", Value: "2"}, {Name: "db.url", Value: "3"}})
+	require.NoError(suite.T(), err)
+
+	candidates, err := provider.PruneCandidates(suite.ctx, []string{"KEEP", "db.url"})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"OLD"}, candidates)
+
+	// Another target writing to the same Secret prunes nothing from it
+	other := suite.newProvider(map[string]interface{}{})
+	other.SetOwner(SecretOwner{EnvironmentID: testOwner.EnvironmentID, Target: "kubernetes-canary"})
+	otherCandidates, err := other.PruneCandidates(suite.ctx, []string{"KEEP"})
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), otherCandidates)
+
+	pruned, err := provider.Prune(suite.ctx, candidates)
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), pruned[0].Success, pruned[0].Error)
+	assert.Equal(suite.T(), map[string][]byte{"KEEP": []byte("1"), "db.url": []byte("3")}, suite.getSecret().Data)
+}
+
+// TestBearerTokenAuth tests that a bearer token and CA reach the API server
+func (suite *KubernetesProviderTestSuite) TestBearerTokenAuth() {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer service-account-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(metav1.Status{TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}, Status: metav1.StatusFailure, Reason: metav1.StatusReasonUnauthorized, Code: http.StatusUnauthorized})
+			return
+		}
+		assert.Equal(suite.T(), "/api/v1/namespaces/payments/secrets/payments-env", r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(metav1.Status{TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}, Status: metav1.StatusFailure, Reason: metav1.StatusReasonNotFound, Code: http.StatusNotFound})
+	}))
+	defer server.Close()
+	caData := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	config := map[string]interface{}{"namespace": "payments", "secret_name": "payments-env"}
+	provider, err := NewKubernetesProvider(map[string]interface{}{"server": server.URL, "token": "service-account-token", "ca_data": caData}, config, suite.logger)
+	require.NoError(suite.T(), err)
+	assert.NoError(suite.T(), provider.ValidateCredentials(suite.ctx), "A missing Secret still validates the credentials")
+
+	rejected, err := NewKubernetesProvider(map[string]interface{}{"server": server.URL, "token": "revoked", "ca_data": caData}, config, suite.logger)
+	require.NoError(suite.T(), err)
+	err = rejected.ValidateCredentials(suite.ctx)
+	require.Error(suite.T(), err)
+	kind, _ := classifySyncError(err)
+	assert.Equal(suite.T(), failurePermanent, kind)
+}
+
+// TestKubernetesProviderTestSuite runs the Kubernetes provider test suite
+func TestKubernetesProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(KubernetesProviderTestSuite))
+}
@@ -49,6 +49,15 @@ func (m *MockProviderRepository) ListProviderCredentials(ctx context.Context, en
 	return args.Get(0).([]providerdb.ProviderCredential), args.Error(1)
 }
 
+// ListProviderCredentialsByProvider mocks the ListProviderCredentialsByProvider method
+func (m *MockProviderRepository) ListProviderCredentialsByProvider(ctx context.Context, provider string) ([]providerdb.ProviderCredential, error) {
+	args := m.Called(ctx, provider)
+	if args.Get(0) == nil {
+		return []providerdb.ProviderCredential{}, args.Error(1)
+	}
+	return args.Get(0).([]providerdb.ProviderCredential), args.Error(1)
+}
+
 // UpdateProviderCredential mocks the UpdateProviderCredential method
 func (m *MockProviderRepository) UpdateProviderCredential(ctx context.Context, arg providerdb.UpdateProviderCredentialParams) (providerdb.ProviderCredential, error) {
 	args := m.Called(ctx, arg)
@@ -63,3 +72,129 @@ func (m *MockProviderRepository) DeleteProviderCredential(ctx context.Context, a
 	args := m.Called(ctx, arg)
 	return args.Error(0)
 }
+
+// UpdateProviderCredentialAutoSync mocks the UpdateProviderCredentialAutoSync method
+func (m *MockProviderRepository) UpdateProviderCredentialAutoSync(ctx context.Context, arg providerdb.UpdateProviderCredentialAutoSyncParams) (providerdb.ProviderCredential, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return providerdb.ProviderCredential{}, args.Error(1)
+	}
+	return args.Get(0).(providerdb.ProviderCredential), args.Error(1)
+}
+
+// UpdateProviderCredentialKeyMapping mocks the UpdateProviderCredentialKeyMapping method
+func (m *MockProviderRepository) UpdateProviderCredentialKeyMapping(ctx context.Context, arg providerdb.UpdateProviderCredentialKeyMappingParams) (providerdb.ProviderCredential, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return providerdb.ProviderCredential{}, args.Error(1)
+	}
+	return args.Get(0).(providerdb.ProviderCredential), args.Error(1)
+}
+
+// UpdateProviderCredentialMirrorMode mocks the UpdateProviderCredentialMirrorMode method
+func (m *MockProviderRepository) UpdateProviderCredentialMirrorMode(ctx context.Context, arg providerdb.UpdateProviderCredentialMirrorModeParams) (providerdb.ProviderCredential, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return providerdb.ProviderCredential{}, args.Error(1)
+	}
+	return args.Get(0).(providerdb.ProviderCredential), args.Error(1)
+}
+
+// CreateProviderConnection mocks the CreateProviderConnection method
+func (m *MockProviderRepository) CreateProviderConnection(ctx context.Context, arg providerdb.CreateProviderConnectionParams) (providerdb.ProviderConnection, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return providerdb.ProviderConnection{}, args.Error(1)
+	}
+	return args.Get(0).(providerdb.ProviderConnection), args.Error(1)
+}
+
+// GetProviderConnection mocks the GetProviderConnection method
+func (m *MockProviderRepository) GetProviderConnection(ctx context.Context, arg providerdb.GetProviderConnectionParams) (providerdb.ProviderConnection, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return providerdb.ProviderConnection{}, args.Error(1)
+	}
+	return args.Get(0).(providerdb.ProviderConnection), args.Error(1)
+}
+
+// GetProviderConnectionByID mocks the GetProviderConnectionByID method
+func (m *MockProviderRepository) GetProviderConnectionByID(ctx context.Context, id uuid.UUID) (providerdb.ProviderConnection, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return providerdb.ProviderConnection{}, args.Error(1)
+	}
+	return args.Get(0).(providerdb.ProviderConnection), args.Error(1)
+}
+
+// GetProviderConnectionForEnvironment mocks the GetProviderConnectionForEnvironment method
+func (m *MockProviderRepository) GetProviderConnectionForEnvironment(ctx context.Context, arg providerdb.GetProviderConnectionForEnvironmentParams) (providerdb.ProviderConnection, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return providerdb.ProviderConnection{}, args.Error(1)
+	}
+	return args.Get(0).(providerdb.ProviderConnection), args.Error(1)
+}
+
+// ListProviderConnections mocks the ListProviderConnections method
+func (m *MockProviderRepository) ListProviderConnections(ctx context.Context, organizationID uuid.UUID) ([]providerdb.ProviderConnection, error) {
+	args := m.Called(ctx, organizationID)
+	if args.Get(0) == nil {
+		return []providerdb.ProviderConnection{}, args.Error(1)
+	}
+	return args.Get(0).([]providerdb.ProviderConnection), args.Error(1)
+}
+
+// UpdateProviderConnectionCredentials mocks the UpdateProviderConnectionCredentials method
+func (m *MockProviderRepository) UpdateProviderConnectionCredentials(ctx context.Context, arg providerdb.UpdateProviderConnectionCredentialsParams) (providerdb.ProviderConnection, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return providerdb.ProviderConnection{}, args.Error(1)
+	}
+	return args.Get(0).(providerdb.ProviderConnection), args.Error(1)
+}
+
+// DeleteProviderConnection mocks the DeleteProviderConnection method
+func (m *MockProviderRepository) DeleteProviderConnection(ctx context.Context, arg providerdb.DeleteProviderConnectionParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+// ListProviderConnectionTargets mocks the ListProviderConnectionTargets method
+func (m *MockProviderRepository) ListProviderConnectionTargets(ctx context.Context, connectionID uuid.NullUUID) ([]providerdb.ProviderCredential, error) {
+	args := m.Called(ctx, connectionID)
+	if args.Get(0) == nil {
+		return []providerdb.ProviderCredential{}, args.Error(1)
+	}
+	return args.Get(0).([]providerdb.ProviderCredential), args.Error(1)
+}
+
+// CreateProviderConnectionGrant mocks the CreateProviderConnectionGrant method
+func (m *MockProviderRepository) CreateProviderConnectionGrant(ctx context.Context, arg providerdb.CreateProviderConnectionGrantParams) (providerdb.ProviderConnectionGrant, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return providerdb.ProviderConnectionGrant{}, args.Error(1)
+	}
+	return args.Get(0).(providerdb.ProviderConnectionGrant), args.Error(1)
+}
+
+// ListProviderConnectionGrants mocks the ListProviderConnectionGrants method
+func (m *MockProviderRepository) ListProviderConnectionGrants(ctx context.Context, connectionID uuid.UUID) ([]providerdb.ProviderConnectionGrant, error) {
+	args := m.Called(ctx, connectionID)
+	if args.Get(0) == nil {
+		return []providerdb.ProviderConnectionGrant{}, args.Error(1)
+	}
+	return args.Get(0).([]providerdb.ProviderConnectionGrant), args.Error(1)
+}
+
+// DeleteProviderConnectionGrant mocks the DeleteProviderConnectionGrant method
+func (m *MockProviderRepository) DeleteProviderConnectionGrant(ctx context.Context, arg providerdb.DeleteProviderConnectionGrantParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// HasProviderConnectionGrant mocks the HasProviderConnectionGrant method
+func (m *MockProviderRepository) HasProviderConnectionGrant(ctx context.Context, arg providerdb.HasProviderConnectionGrantParams) (bool, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(bool), args.Error(1)
+}
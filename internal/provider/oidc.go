@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+// oidcTokenTTL is how long a minted workload token is valid. Cloud STS endpoints exchange it right away.
+const oidcTokenTTL = 5 * time.Minute
+
+// WorkloadIdentity identifies the environment and provider target a workload token is minted for
+type WorkloadIdentity struct {
+	EnvironmentID string
+	Provider      string
+	Target        string
+}
+
+// Subject returns the token subject that cloud trust policies match on. It names the target, so a trust
+// policy grants one target of an environment rather than every target of the same provider.
+func (w WorkloadIdentity) Subject() string {
+	return "kavach:environment:" + w.EnvironmentID + ":provider:" + w.Provider + ":target:" + w.Target
+}
+
+// workloadClaims are the claims of a workload token. The environment, provider and target are repeated
+// as their own claims so cloud attribute mappings can use them.
+type workloadClaims struct {
+	EnvironmentID string `json:"environment_id"`
+	Provider      string `json:"provider"`
+	Target        string `json:"target,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// OIDCIssuer makes Kavach an OpenID Connect issuer for its own workloads. It signs short-lived,
+// audience-bound tokens that cloud providers exchange through workload identity federation,
+// so no long-lived cloud credential has to be stored.
+type OIDCIssuer struct {
+	issuerURL  string
+	privateKey *rsa.PrivateKey
+	keyID      string
+}
+
+// NewOIDCIssuer creates an issuer for the given public URL that signs tokens with a PEM-encoded RSA private key
+func NewOIDCIssuer(issuerURL, privateKeyPEM string) (*OIDCIssuer, error) {
+	parsed, err := url.Parse(issuerURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid OIDC issuer URL: %s", issuerURL)
+	}
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(privateKeyPEM))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OIDC signing key: %w", err)
+	}
+
+	// The key ID is the RFC 7638 thumbprint of the public key, so it changes whenever the key does
+	thumbprint := sha256.Sum256([]byte(fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`,
+		base64URLUint(big.NewInt(int64(privateKey.PublicKey.E))), base64URLUint(privateKey.PublicKey.N))))
+
+	return &OIDCIssuer{
+		issuerURL:  strings.TrimSuffix(issuerURL, "/"),
+		privateKey: privateKey,
+		keyID:      base64.RawURLEncoding.EncodeToString(thumbprint[:]),
+	}, nil
+}
+
+// MintWorkloadToken signs a token for the workload that only the given audience accepts
+func (i *OIDCIssuer) MintWorkloadToken(identity WorkloadIdentity, audience string) (string, error) {
+	if i == nil {
+		return "", appErrors.ErrWorkloadIdentityNotConfigured
+	}
+	if identity.EnvironmentID == "" {
+		return "", fmt.Errorf("workload identity is not set for the provider")
+	}
+
+	now := time.Now()
+	claims := workloadClaims{
+		EnvironmentID: identity.EnvironmentID,
+		Provider:      identity.Provider,
+		Target:        identity.Target,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    i.issuerURL,
+			Subject:   identity.Subject(),
+			Audience:  jwt.ClaimStrings{audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(oidcTokenTTL)),
+			ID:        uuid.NewString(),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = i.keyID
+	signed, err := token.SignedString(i.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign workload token: %w", err)
+	}
+	return signed, nil
+}
+
+// DiscoveryDocument returns the OpenID Connect discovery document
+func (i *OIDCIssuer) DiscoveryDocument() map[string]interface{} {
+	return map[string]interface{}{
+		"issuer":                                i.issuerURL,
+		"jwks_uri":                              i.issuerURL + "/.well-known/jwks.json",
+		"response_types_supported":              []string{"id_token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"claims_supported":                      []string{"iss", "sub", "aud", "iat", "nbf", "exp", "jti", "environment_id", "provider", "target"},
+	}
+}
+
+// JWKS returns the JSON Web Key Set holding the public key tokens are verified with
+func (i *OIDCIssuer) JWKS() map[string]interface{} {
+	return map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": i.keyID,
+			"n":   base64URLUint(i.privateKey.PublicKey.N),
+			"e":   base64URLUint(big.NewInt(int64(i.privateKey.PublicKey.E))),
+		}},
+	}
+}
+
+// base64URLUint encodes an unsigned integer as JWKs expect: big-endian bytes, base64url without padding
+func base64URLUint(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+// RegisterOIDCRoutes registers the discovery and JWKS routes under the issuer URL's path. They must be
+// registered outside the JWT-protected group since cloud providers fetch them anonymously.
+func RegisterOIDCRoutes(issuer *OIDCIssuer, router gin.IRouter) {
+	basePath := ""
+	if parsed, err := url.Parse(issuer.issuerURL); err == nil {
+		basePath = strings.TrimSuffix(parsed.Path, "/")
+	}
+
+	group := router.Group(basePath + "/.well-known")
+	group.GET("/openid-configuration", func(c *gin.Context) {
+		c.JSON(http.StatusOK, issuer.DiscoveryDocument())
+	})
+	group.GET("/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, issuer.JWKS())
+	})
+}
@@ -0,0 +1,237 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// OIDCIssuerTestSuite tests Kavach's OIDC issuer and the GCP and Azure token exchanges that trust it
+type OIDCIssuerTestSuite struct {
+	suite.Suite
+	issuer   *OIDCIssuer
+	server   *httptest.Server // Serves the issuer's discovery document and JWKS
+	identity WorkloadIdentity
+	logger   *logrus.Logger
+	ctx      context.Context
+}
+
+// SetupTest creates an issuer under a path of a local server that serves its OIDC routes
+func (suite *OIDCIssuerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	suite.server = httptest.NewServer(engine)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(suite.T(), err)
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+
+	suite.issuer, err = NewOIDCIssuer(suite.server.URL+"/oidc/", string(privateKeyPEM))
+	require.NoError(suite.T(), err)
+	RegisterOIDCRoutes(suite.issuer, engine)
+
+	suite.identity = WorkloadIdentity{EnvironmentID: "550e8400-e29b-41d4-a716-446655440001", Provider: "gcp", Target: "gcp-prod"}
+	suite.logger = logrus.New()
+	suite.logger.SetOutput(io.Discard)
+	suite.ctx = context.Background()
+}
+
+// TearDownTest stops the issuer's server
+func (suite *OIDCIssuerTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+// verify checks a workload token the way a cloud provider does: against the key published
+// in the JWKS that the discovery document points to
+func (suite *OIDCIssuerTestSuite) verify(token, audience string) *workloadClaims {
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	suite.getJSON(suite.server.URL+"/oidc/.well-known/openid-configuration", &discovery)
+	assert.Equal(suite.T(), suite.server.URL+"/oidc", discovery.Issuer)
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	suite.getJSON(discovery.JWKSURI, &jwks)
+	require.Len(suite.T(), jwks.Keys, 1)
+
+	claims := &workloadClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		assert.Equal(suite.T(), jwks.Keys[0].Kid, token.Header["kid"])
+		n, err := base64.RawURLEncoding.DecodeString(jwks.Keys[0].N)
+		require.NoError(suite.T(), err)
+		e, err := base64.RawURLEncoding.DecodeString(jwks.Keys[0].E)
+		require.NoError(suite.T(), err)
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	})
+	require.NoError(suite.T(), err, "The workload token must verify against the published JWKS")
+	assert.Equal(suite.T(), discovery.Issuer, claims.Issuer)
+	assert.True(suite.T(), claims.VerifyAudience(audience, true), "Unexpected audience %v", claims.Audience)
+	return claims
+}
+
+// getJSON fetches and decodes a JSON document
+func (suite *OIDCIssuerTestSuite) getJSON(url string, target interface{}) {
+	resp, err := http.Get(url)
+	require.NoError(suite.T(), err)
+	defer resp.Body.Close()
+	require.Equal(suite.T(), http.StatusOK, resp.StatusCode, url)
+	require.NoError(suite.T(), json.NewDecoder(resp.Body).Decode(target))
+}
+
+// TestMintWorkloadToken tests that tokens are short-lived, audience-bound and name the environment, provider and target
+func (suite *OIDCIssuerTestSuite) TestMintWorkloadToken() {
+	token, err := suite.issuer.MintWorkloadToken(suite.identity, "https://example.com/audience")
+	require.NoError(suite.T(), err)
+
+	claims := suite.verify(token, "https://example.com/audience")
+	assert.Equal(suite.T(), "kavach:environment:550e8400-e29b-41d4-a716-446655440001:provider:gcp:target:gcp-prod", claims.Subject)
+	assert.Equal(suite.T(), "550e8400-e29b-41d4-a716-446655440001", claims.EnvironmentID)
+	assert.Equal(suite.T(), "gcp", claims.Provider)
+	assert.Equal(suite.T(), "gcp-prod", claims.Target)
+	assert.NotEmpty(suite.T(), claims.ID)
+	assert.WithinDuration(suite.T(), time.Now().Add(oidcTokenTTL), claims.ExpiresAt.Time, 5*time.Second)
+
+	var unconfigured *OIDCIssuer
+	_, err = unconfigured.MintWorkloadToken(suite.identity, "https://example.com/audience")
+	assert.Equal(suite.T(), appErrors.ErrWorkloadIdentityNotConfigured, err)
+	_, err = suite.issuer.MintWorkloadToken(WorkloadIdentity{}, "https://example.com/audience")
+	assert.Error(suite.T(), err, "Tokens are never minted without an environment")
+}
+
+// TestGCPWorkloadIdentityFederation tests that a workload token is exchanged at STS and the federated
+// token is used to impersonate the service account
+func (suite *OIDCIssuerTestSuite) TestGCPWorkloadIdentityFederation() {
+	audience := "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/kavach/providers/kavach"
+	gcp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/token":
+			require.NoError(suite.T(), r.ParseForm())
+			assert.Equal(suite.T(), "urn:ietf:params:oauth:grant-type:token-exchange", r.PostForm.Get("grant_type"))
+			assert.Equal(suite.T(), audience, r.PostForm.Get("audience"))
+			assert.Equal(suite.T(), "urn:ietf:params:oauth:token-type:jwt", r.PostForm.Get("subject_token_type"))
+			claims := suite.verify(r.PostForm.Get("subject_token"), audience)
+			assert.Equal(suite.T(), suite.identity.Subject(), claims.Subject)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":      "federated-token",
+				"issued_token_type": "urn:ietf:params:oauth:token-type:access_token",
+				"token_type":        "Bearer",
+				"expires_in":        3600,
+			})
+		case "/v1/projects/-/serviceAccounts/kavach@acme.iam.gserviceaccount.com:generateAccessToken":
+			assert.Equal(suite.T(), "Bearer federated-token", r.Header.Get("Authorization"))
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"accessToken": "service-account-token",
+				"expireTime":  time.Now().Add(time.Hour).Format(time.RFC3339),
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer gcp.Close()
+
+	provider, err := NewGCPProvider(map[string]interface{}{
+		"auth_method":           "workload_identity",
+		"audience":              audience,
+		"service_account_email": "kavach@acme.iam.gserviceaccount.com",
+	}, map[string]interface{}{"project_id": "acme"}, suite.logger)
+	require.NoError(suite.T(), err)
+	provider.issuer = suite.issuer
+	provider.SetWorkloadIdentity(suite.identity)
+
+	tokenSource, err := provider.federatedTokenSource(suite.ctx, gcp.URL+"/v1/token", gcp.URL+"/v1/projects/-/serviceAccounts/%s:generateAccessToken")
+	require.NoError(suite.T(), err)
+	token, err := tokenSource.Token()
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "service-account-token", token.AccessToken)
+
+	// Without an issuer there is nothing to exchange
+	provider.issuer = nil
+	tokenSource, err = provider.federatedTokenSource(suite.ctx, gcp.URL+"/v1/token", gcp.URL+"/v1/projects/-/serviceAccounts/%s:generateAccessToken")
+	require.NoError(suite.T(), err)
+	_, err = tokenSource.Token()
+	assert.ErrorContains(suite.T(), err, "workload identity federation")
+}
+
+// TestAzureFederatedCredential tests that a workload token is presented to Azure AD as the client assertion
+func (suite *OIDCIssuerTestSuite) TestAzureFederatedCredential() {
+	suite.identity.Provider = "azure"
+	var authority *httptest.Server
+	authority = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/.well-known/openid-configuration"):
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"issuer":                 authority.URL + "/tenant-id/v2.0",
+				"authorization_endpoint": authority.URL + "/tenant-id/oauth2/v2.0/authorize",
+				"token_endpoint":         authority.URL + "/tenant-id/oauth2/v2.0/token",
+			})
+		case r.URL.Path == "/tenant-id/oauth2/v2.0/token":
+			require.NoError(suite.T(), r.ParseForm())
+			assert.Equal(suite.T(), "client-id", r.PostForm.Get("client_id"))
+			assert.Equal(suite.T(), "urn:ietf:params:oauth:client-assertion-type:jwt-bearer", r.PostForm.Get("client_assertion_type"))
+			claims := suite.verify(r.PostForm.Get("client_assertion"), "api://AzureADTokenExchange")
+			assert.Equal(suite.T(), suite.identity.Subject(), claims.Subject)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": "key-vault-token",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer authority.Close()
+
+	provider, err := NewAzureProvider(map[string]interface{}{
+		"auth_method": "federated",
+		"tenant_id":   "tenant-id",
+		"client_id":   "client-id",
+	}, map[string]interface{}{"key_vault_name": "acme"}, suite.logger)
+	require.NoError(suite.T(), err)
+	provider.issuer = suite.issuer
+	provider.SetWorkloadIdentity(suite.identity)
+
+	credential, err := provider.newCredential(azcore.ClientOptions{
+		Cloud:     cloud.Configuration{ActiveDirectoryAuthorityHost: authority.URL + "/"},
+		Transport: authority.Client(),
+	}, true)
+	require.NoError(suite.T(), err)
+	token, err := credential.GetToken(suite.ctx, policy.TokenRequestOptions{Scopes: []string{"https://vault.azure.net/.default"}})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "key-vault-token", token.Token)
+}
+
+// TestOIDCIssuerTestSuite runs the OIDC issuer test suite
+func TestOIDCIssuerTestSuite(t *testing.T) {
+	suite.Run(t, new(OIDCIssuerTestSuite))
+}
@@ -0,0 +1,44 @@
+package provider
+
+import "errors"
+
+// Keys of the labels, tags and metadata entries that record the environment and target a remote secret was created for
+const (
+	ownerEnvironmentKey = "kavach-environment"
+	ownerTargetKey      = "kavach-target"
+)
+
+// errSecretOwnerUnset is returned when pruning without an owner, since no remote secret can be told apart as ours
+var errSecretOwnerUnset = errors.New("the owning environment and target must be set before pruning")
+
+// SecretOwner identifies the environment and provider target a remote secret was created for. Pruners record
+// it on the secrets they create and only prune secrets recorded with their own owner, so targets of other
+// environments writing to the same project, vault, account or mount are left alone.
+type SecretOwner struct {
+	EnvironmentID string
+	Target        string
+}
+
+// IsSet reports whether both the environment and the target are known
+func (o SecretOwner) IsSet() bool {
+	return o.EnvironmentID != "" && o.Target != ""
+}
+
+// Markers returns the owner as the key-value pairs recorded on remote secrets, or nil when it is not set
+func (o SecretOwner) Markers() map[string]string {
+	if !o.IsSet() {
+		return nil
+	}
+	return map[string]string{ownerEnvironmentKey: o.EnvironmentID, ownerTargetKey: o.Target}
+}
+
+// Owns reports whether the recorded key-value pairs name this owner. Secrets created before owners were
+// recorded carry no pairs, so they are owned by no one and never pruned.
+func (o SecretOwner) Owns(markers map[string]string) bool {
+	return o.IsSet() && markers[ownerEnvironmentKey] == o.EnvironmentID && markers[ownerTargetKey] == o.Target
+}
+
+// String returns the owner as environment/target, for markers that hold a single value
+func (o SecretOwner) String() string {
+	return o.EnvironmentID + "/" + o.Target
+}
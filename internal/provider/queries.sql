@@ -1,27 +1,50 @@
 -- name: CreateProviderCredential :one
-INSERT INTO provider_credentials (environment_id, provider, credentials, config,created_by)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO provider_credentials (environment_id, provider, name, credentials, config, created_by, connection_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING *;
 
 -- name: GetProviderCredential :one
 SELECT * FROM provider_credentials 
-WHERE environment_id = $1 AND provider = $2;
+WHERE environment_id = $1 AND name = $2;
 
 -- name: ListProviderCredentials :many
 SELECT * FROM provider_credentials 
 WHERE environment_id = $1 
 ORDER BY created_at DESC;
 
+-- name: ListProviderCredentialsByProvider :many
+SELECT * FROM provider_credentials
+WHERE provider = $1
+ORDER BY created_at;
+
 -- name: UpdateProviderCredential :one
 UPDATE provider_credentials 
 SET credentials = $3, config = $4, updated_at = now()
-WHERE environment_id = $1 AND provider = $2
+WHERE environment_id = $1 AND name = $2
 RETURNING *;
 
 -- name: DeleteProviderCredential :exec
 DELETE FROM provider_credentials 
-WHERE environment_id = $1 AND provider = $2;
+WHERE environment_id = $1 AND name = $2;
 
 -- name: GetProviderCredentialByID :one
 SELECT * FROM provider_credentials 
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: UpdateProviderCredentialAutoSync :one
+UPDATE provider_credentials
+SET auto_sync = $3, auto_sync_debounce_seconds = $4, updated_at = now()
+WHERE environment_id = $1 AND name = $2
+RETURNING *;
+
+-- name: UpdateProviderCredentialMirrorMode :one
+UPDATE provider_credentials
+SET mirror_mode = $3, updated_at = now()
+WHERE environment_id = $1 AND name = $2
+RETURNING *;
+
+-- name: UpdateProviderCredentialKeyMapping :one
+UPDATE provider_credentials
+SET key_mapping = $3, updated_at = now()
+WHERE environment_id = $1 AND name = $2
+RETURNING *;
@@ -2,8 +2,13 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
 	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
 
 	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
 	providerdb "github.com/Gkemhcs/kavach-backend/internal/provider/gen"
@@ -12,6 +17,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// providerTargetNamePattern is what a target name may look like; names appear in URLs and run history
+var providerTargetNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
 // ProviderService handles business logic for provider credentials
 type ProviderService struct {
 	providerRepo providerdb.Querier
@@ -31,12 +39,18 @@ func NewProviderService(providerRepo providerdb.Querier, factory ProviderFactory
 	}
 }
 
-// CreateProviderCredential creates a new provider credential for an environment
+// CreateProviderCredential creates a new named provider target for an environment.
+// The name defaults to the provider type, so environments with one target per provider keep their routes.
 func (s *ProviderService) CreateProviderCredential(ctx context.Context, environmentID, userID string, req CreateProviderCredentialRequest) (*ProviderCredentialResponse, error) {
+	name := req.Name
+	if name == "" {
+		name = string(req.Provider)
+	}
 	logEntry := s.logger.WithFields(logrus.Fields{
 		"method":         "CreateProviderCredential",
 		"environment_id": environmentID,
 		"provider":       req.Provider,
+		"target":         name,
 	})
 
 	logEntry.Info("Creating provider credential")
@@ -46,6 +60,10 @@ func (s *ProviderService) CreateProviderCredential(ctx context.Context, environm
 		logEntry.Error("Invalid provider type")
 		return nil, appErrors.ErrInvalidProviderType
 	}
+	if !providerTargetNamePattern.MatchString(name) {
+		logEntry.Error("Invalid provider target name")
+		return nil, appErrors.ErrInvalidProviderTargetName
+	}
 
 	// Parse environment ID
 	envUUID, err := uuid.Parse(environmentID)
@@ -59,34 +77,61 @@ func (s *ProviderService) CreateProviderCredential(ctx context.Context, environm
 		return nil, appErrors.ErrInternalServer
 	}
 
-	// Validate credentials and config based on provider type
-	if err := s.validateProviderData(req.Provider, req.Credentials, req.Config); err != nil {
-		logEntry.WithField("error", err.Error()).Error("Invalid provider data")
-		return nil, appErrors.ErrInvalidProviderData
+	// A target either carries its own credentials or uses a connection it has been granted
+	var encryptedCredentials []byte
+	var connectionID uuid.NullUUID
+	if req.Connection != "" {
+		if len(req.Credentials) > 0 {
+			logEntry.Error("Both credentials and a connection were given")
+			return nil, appErrors.ErrInvalidProviderData
+		}
+		connection, err := s.connectionForEnvironment(ctx, envUUID, req.Connection)
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Error("Provider connection is not usable")
+			return nil, err
+		}
+		if ProviderType(connection.Provider) != req.Provider {
+			logEntry.Error("Provider connection is for a different provider type")
+			return nil, appErrors.ErrProviderConnectionMismatch
+		}
+		if err := s.validateProviderConfig(req.Provider, req.Config); err != nil {
+			logEntry.WithField("error", err.Error()).Error("Invalid provider config")
+			return nil, appErrors.ErrInvalidProviderData
+		}
+		connectionID = uuid.NullUUID{UUID: connection.ID, Valid: true}
+	} else {
+		// Validate credentials and config based on provider type
+		if err := s.validateProviderData(req.Provider, req.Credentials, req.Config); err != nil {
+			logEntry.WithField("error", err.Error()).Error("Invalid provider data")
+			return nil, appErrors.ErrInvalidProviderData
+		}
 	}
 
-	// Check if provider credential already exists
+	// Check if a target with this name already exists
 	existing, err := s.providerRepo.GetProviderCredential(ctx, providerdb.GetProviderCredentialParams{
 		EnvironmentID: envUUID,
-		Provider:      string(req.Provider),
+		Name:          name,
 	})
 	if err == nil && existing.ID != uuid.Nil {
 		logEntry.Error("Provider credential already exists")
 		return nil, appErrors.ErrProviderCredentialExists
 	}
 
-	// Convert map[string]interface{} to json.RawMessage
-	credentialsJSON, err := json.Marshal(req.Credentials)
-	if err != nil {
-		logEntry.WithField("error", err.Error()).Error("Failed to marshal credentials")
-		return nil, appErrors.ErrProviderCredentialCreateFailed
-	}
+	if !connectionID.Valid {
+		// Convert map[string]interface{} to json.RawMessage
+		credentialsJSON, err := json.Marshal(req.Credentials)
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Error("Failed to marshal credentials")
+			return nil, appErrors.ErrProviderCredentialCreateFailed
+		}
 
-	// Encrypt credentials before storing
-	encryptedCredentials, err := s.encryptor.Encrypt(credentialsJSON)
-	if err != nil {
-		logEntry.WithField("error", err.Error()).Error("Failed to encrypt credentials")
-		return nil, appErrors.ErrProviderEncryptionFailed
+		// Encrypt credentials before storing
+		encrypted, err := s.encryptor.Encrypt(credentialsJSON)
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Error("Failed to encrypt credentials")
+			return nil, appErrors.ErrProviderEncryptionFailed
+		}
+		encryptedCredentials = []byte(encrypted)
 	}
 
 	configJSON, err := json.Marshal(req.Config)
@@ -95,44 +140,45 @@ func (s *ProviderService) CreateProviderCredential(ctx context.Context, environm
 		return nil, appErrors.ErrProviderCredentialCreateFailed
 	}
 
+	if req.Provider == ProviderGitHub {
+		if err := s.checkGitHubMirrorOverlap(ctx, uuid.Nil, configJSON, false); err != nil {
+			logEntry.WithField("error", err.Error()).Error("GitHub target overlaps a target in mirror mode")
+			return nil, err
+		}
+	}
+
 	// Create provider credential
 	credential, err := s.providerRepo.CreateProviderCredential(ctx, providerdb.CreateProviderCredentialParams{
 		EnvironmentID: envUUID,
 		Provider:      string(req.Provider),
-		Credentials:   json.RawMessage(encryptedCredentials),
+		Name:          name,
+		Credentials:   encryptedCredentials,
 		Config:        configJSON,
 		CreatedBy:     userUUID,
+		ConnectionID:  connectionID,
 	})
 	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to create provider credential")
 		return nil, appErrors.ErrProviderCredentialCreateFailed
 	}
 
-	// Convert json.RawMessage back to map[string]interface{} for response
-	var configMap map[string]interface{}
-	if err := json.Unmarshal(credential.Config, &configMap); err != nil {
+	response, err := toProviderCredentialResponse(credential)
+	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to unmarshal config")
 		return nil, appErrors.ErrProviderCredentialCreateFailed
 	}
 
 	logEntry.WithField("credential_id", credential.ID).Info("Successfully created provider credential")
 
-	return &ProviderCredentialResponse{
-		ID:            credential.ID.String(),
-		EnvironmentID: credential.EnvironmentID,
-		Provider:      ProviderType(credential.Provider),
-		Config:        configMap,
-		CreatedAt:     credential.CreatedAt,
-		UpdatedAt:     credential.UpdatedAt,
-	}, nil
+	return response, nil
 }
 
-// GetProviderCredential retrieves a provider credential by environment ID and provider
-func (s *ProviderService) GetProviderCredential(ctx context.Context, environmentID, provider string) (*ProviderCredentialResponse, error) {
+// GetProviderCredential retrieves a provider target by environment ID and name
+func (s *ProviderService) GetProviderCredential(ctx context.Context, environmentID, name string) (*ProviderCredentialResponse, error) {
 	logEntry := s.logger.WithFields(logrus.Fields{
 		"method":         "GetProviderCredential",
 		"environment_id": environmentID,
-		"provider":       provider,
+		"target":         name,
 	})
 
 	logEntry.Info("Retrieving provider credential")
@@ -145,7 +191,7 @@ func (s *ProviderService) GetProviderCredential(ctx context.Context, environment
 
 	credential, err := s.providerRepo.GetProviderCredential(ctx, providerdb.GetProviderCredentialParams{
 		EnvironmentID: envUUID,
-		Provider:      provider,
+		Name:          name,
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -156,23 +202,15 @@ func (s *ProviderService) GetProviderCredential(ctx context.Context, environment
 		return nil, appErrors.ErrProviderCredentialGetFailed
 	}
 
-	// Convert json.RawMessage to map[string]interface{}
-	var configMap map[string]interface{}
-	if err := json.Unmarshal(credential.Config, &configMap); err != nil {
+	response, err := toProviderCredentialResponse(credential)
+	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to unmarshal config")
 		return nil, appErrors.ErrProviderCredentialGetFailed
 	}
 
 	logEntry.Info("Successfully retrieved provider credential")
 
-	return &ProviderCredentialResponse{
-		ID:            credential.ID.String(),
-		EnvironmentID: credential.EnvironmentID,
-		Provider:      ProviderType(credential.Provider),
-		Config:        configMap,
-		CreatedAt:     credential.CreatedAt,
-		UpdatedAt:     credential.UpdatedAt,
-	}, nil
+	return response, nil
 }
 
 // ListProviderCredentials lists all provider credentials for an environment
@@ -198,21 +236,12 @@ func (s *ProviderService) ListProviderCredentials(ctx context.Context, environme
 
 	var response []ProviderCredentialResponse
 	for _, cred := range credentials {
-		// Convert json.RawMessage to map[string]interface{}
-		var configMap map[string]interface{}
-		if err := json.Unmarshal(cred.Config, &configMap); err != nil {
+		credentialResponse, err := toProviderCredentialResponse(cred)
+		if err != nil {
 			logEntry.WithField("error", err.Error()).Error("Failed to unmarshal config")
 			return nil, appErrors.ErrProviderCredentialListFailed
 		}
-
-		response = append(response, ProviderCredentialResponse{
-			ID:            cred.ID.String(),
-			EnvironmentID: cred.EnvironmentID,
-			Provider:      ProviderType(cred.Provider),
-			Config:        configMap,
-			CreatedAt:     cred.CreatedAt,
-			UpdatedAt:     cred.UpdatedAt,
-		})
+		response = append(response, *credentialResponse)
 	}
 
 	logEntry.WithField("count", len(response)).Info("Successfully listed provider credentials")
@@ -220,12 +249,12 @@ func (s *ProviderService) ListProviderCredentials(ctx context.Context, environme
 	return response, nil
 }
 
-// UpdateProviderCredential updates an existing provider credential
-func (s *ProviderService) UpdateProviderCredential(ctx context.Context, environmentID, provider string, req UpdateProviderCredentialRequest) (*ProviderCredentialResponse, error) {
+// UpdateProviderCredential updates the credentials and config of an existing provider target
+func (s *ProviderService) UpdateProviderCredential(ctx context.Context, environmentID, name string, req UpdateProviderCredentialRequest) (*ProviderCredentialResponse, error) {
 	logEntry := s.logger.WithFields(logrus.Fields{
 		"method":         "UpdateProviderCredential",
 		"environment_id": environmentID,
-		"provider":       provider,
+		"target":         name,
 	})
 
 	logEntry.Info("Updating provider credential")
@@ -236,31 +265,52 @@ func (s *ProviderService) UpdateProviderCredential(ctx context.Context, environm
 		return nil, appErrors.ErrInternalServer
 	}
 
-	// Validate provider type
-	providerType := ProviderType(provider)
-	if !s.isValidProvider(providerType) {
-		logEntry.Error("Invalid provider type")
-		return nil, appErrors.ErrInvalidProviderType
+	// The target's provider type decides how the credentials and config are validated
+	existing, err := s.providerRepo.GetProviderCredential(ctx, providerdb.GetProviderCredentialParams{
+		EnvironmentID: envUUID,
+		Name:          name,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logEntry.Error("Provider credential not found")
+			return nil, appErrors.ErrProviderCredentialNotFound
+		}
+		logEntry.WithField("error", err.Error()).Error("Failed to get provider credential")
+		return nil, appErrors.ErrProviderCredentialGetFailed
 	}
 
-	// Validate credentials and config
-	if err := s.validateProviderData(providerType, req.Credentials, req.Config); err != nil {
-		logEntry.WithField("error", err.Error()).Error("Invalid provider data")
-		return nil, appErrors.ErrInvalidProviderData
-	}
+	// Targets using a connection keep its credentials; they change by rotating the connection
+	var encryptedCredentials []byte
+	if existing.ConnectionID.Valid {
+		if len(req.Credentials) > 0 {
+			logEntry.Error("Credentials given for a target that uses a connection")
+			return nil, appErrors.ErrInvalidProviderData
+		}
+		if err := s.validateProviderConfig(ProviderType(existing.Provider), req.Config); err != nil {
+			logEntry.WithField("error", err.Error()).Error("Invalid provider config")
+			return nil, appErrors.ErrInvalidProviderData
+		}
+	} else {
+		// Validate credentials and config
+		if err := s.validateProviderData(ProviderType(existing.Provider), req.Credentials, req.Config); err != nil {
+			logEntry.WithField("error", err.Error()).Error("Invalid provider data")
+			return nil, appErrors.ErrInvalidProviderData
+		}
 
-	// Convert map[string]interface{} to json.RawMessage
-	credentialsJSON, err := json.Marshal(req.Credentials)
-	if err != nil {
-		logEntry.WithField("error", err.Error()).Error("Failed to marshal credentials")
-		return nil, appErrors.ErrProviderCredentialUpdateFailed
-	}
+		// Convert map[string]interface{} to json.RawMessage
+		credentialsJSON, err := json.Marshal(req.Credentials)
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Error("Failed to marshal credentials")
+			return nil, appErrors.ErrProviderCredentialUpdateFailed
+		}
 
-	// Encrypt credentials before storing
-	encryptedCredentials, err := s.encryptor.Encrypt(credentialsJSON)
-	if err != nil {
-		logEntry.WithField("error", err.Error()).Error("Failed to encrypt credentials")
-		return nil, appErrors.ErrProviderEncryptionFailed
+		// Encrypt credentials before storing
+		encrypted, err := s.encryptor.Encrypt(credentialsJSON)
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Error("Failed to encrypt credentials")
+			return nil, appErrors.ErrProviderEncryptionFailed
+		}
+		encryptedCredentials = []byte(encrypted)
 	}
 
 	configJSON, err := json.Marshal(req.Config)
@@ -269,10 +319,17 @@ func (s *ProviderService) UpdateProviderCredential(ctx context.Context, environm
 		return nil, appErrors.ErrProviderCredentialUpdateFailed
 	}
 
+	if ProviderType(existing.Provider) == ProviderGitHub {
+		if err := s.checkGitHubMirrorOverlap(ctx, existing.ID, configJSON, existing.MirrorMode); err != nil {
+			logEntry.WithField("error", err.Error()).Error("GitHub target overlaps a target in mirror mode")
+			return nil, err
+		}
+	}
+
 	credential, err := s.providerRepo.UpdateProviderCredential(ctx, providerdb.UpdateProviderCredentialParams{
 		EnvironmentID: envUUID,
-		Provider:      provider,
-		Credentials:   json.RawMessage(encryptedCredentials),
+		Name:          name,
+		Credentials:   encryptedCredentials,
 		Config:        configJSON,
 	})
 	if err != nil {
@@ -280,31 +337,223 @@ func (s *ProviderService) UpdateProviderCredential(ctx context.Context, environm
 		return nil, appErrors.ErrProviderCredentialUpdateFailed
 	}
 
-	// Convert json.RawMessage back to map[string]interface{} for response
-	var configMap map[string]interface{}
-	if err := json.Unmarshal(credential.Config, &configMap); err != nil {
+	response, err := toProviderCredentialResponse(credential)
+	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to unmarshal config")
 		return nil, appErrors.ErrProviderCredentialUpdateFailed
 	}
 
 	logEntry.Info("Successfully updated provider credential")
 
-	return &ProviderCredentialResponse{
-		ID:            credential.ID.String(),
-		EnvironmentID: credential.EnvironmentID,
-		Provider:      ProviderType(credential.Provider),
-		Config:        configMap,
-		CreatedAt:     credential.CreatedAt,
-		UpdatedAt:     credential.UpdatedAt,
-	}, nil
+	return response, nil
 }
 
-// DeleteProviderCredential deletes a provider credential
-func (s *ProviderService) DeleteProviderCredential(ctx context.Context, environmentID, provider string) error {
+// UpdateAutoSync enables or disables automatic syncs of new versions to a provider target
+func (s *ProviderService) UpdateAutoSync(ctx context.Context, environmentID, name string, req AutoSyncSettings) (*ProviderCredentialResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":           "UpdateAutoSync",
+		"environment_id":   environmentID,
+		"target":           name,
+		"enabled":          req.Enabled,
+		"debounce_seconds": req.DebounceSeconds,
+	})
+
+	logEntry.Info("Updating provider auto-sync settings")
+
+	envUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Invalid environment ID")
+		return nil, appErrors.ErrInternalServer
+	}
+
+	if req.DebounceSeconds < 0 || req.DebounceSeconds > MaxAutoSyncDebounceSeconds {
+		logEntry.Error("Invalid auto-sync debounce")
+		return nil, appErrors.ErrInvalidAutoSyncSettings
+	}
+
+	credential, err := s.providerRepo.UpdateProviderCredentialAutoSync(ctx, providerdb.UpdateProviderCredentialAutoSyncParams{
+		EnvironmentID:           envUUID,
+		Name:                    name,
+		AutoSync:                req.Enabled,
+		AutoSyncDebounceSeconds: int32(req.DebounceSeconds),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logEntry.Error("Provider credential not found")
+			return nil, appErrors.ErrProviderCredentialNotFound
+		}
+		logEntry.WithField("error", err.Error()).Error("Failed to update provider auto-sync settings")
+		return nil, appErrors.ErrProviderCredentialUpdateFailed
+	}
+
+	response, err := toProviderCredentialResponse(credential)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to unmarshal config")
+		return nil, appErrors.ErrProviderCredentialUpdateFailed
+	}
+
+	logEntry.Info("Successfully updated provider auto-sync settings")
+
+	return response, nil
+}
+
+// UpdateMirrorMode enables or disables deleting managed remote secrets that are no longer in the synced version.
+// GitHub cannot label secrets, so mirror mode there requires a prefix marking the secrets Kavach manages,
+// which no other target writing to the same secrets may share.
+func (s *ProviderService) UpdateMirrorMode(ctx context.Context, environmentID, name string, enabled bool) (*ProviderCredentialResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":         "UpdateMirrorMode",
+		"environment_id": environmentID,
+		"target":         name,
+		"enabled":        enabled,
+	})
+
+	logEntry.Info("Updating provider mirror mode")
+
+	envUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Invalid environment ID")
+		return nil, appErrors.ErrInternalServer
+	}
+
+	if enabled {
+		existing, err := s.providerRepo.GetProviderCredential(ctx, providerdb.GetProviderCredentialParams{
+			EnvironmentID: envUUID,
+			Name:          name,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				logEntry.Error("Provider credential not found")
+				return nil, appErrors.ErrProviderCredentialNotFound
+			}
+			logEntry.WithField("error", err.Error()).Error("Failed to get provider credential")
+			return nil, appErrors.ErrProviderCredentialGetFailed
+		}
+
+		if ProviderType(existing.Provider) == ProviderGitHub {
+			var githubConfig GitHubConfig
+			if err := json.Unmarshal(existing.Config, &githubConfig); err != nil || githubConfig.Prefix == "" {
+				logEntry.Error("GitHub mirror mode requires a prefix")
+				return nil, appErrors.ErrMirrorModeRequiresPrefix
+			}
+			if err := s.checkGitHubMirrorOverlap(ctx, existing.ID, existing.Config, true); err != nil {
+				logEntry.WithField("error", err.Error()).Error("GitHub target overlaps another target")
+				return nil, err
+			}
+		}
+	}
+
+	credential, err := s.providerRepo.UpdateProviderCredentialMirrorMode(ctx, providerdb.UpdateProviderCredentialMirrorModeParams{
+		EnvironmentID: envUUID,
+		Name:          name,
+		MirrorMode:    enabled,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logEntry.Error("Provider credential not found")
+			return nil, appErrors.ErrProviderCredentialNotFound
+		}
+		logEntry.WithField("error", err.Error()).Error("Failed to update provider mirror mode")
+		return nil, appErrors.ErrProviderCredentialUpdateFailed
+	}
+
+	response, err := toProviderCredentialResponse(credential)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to unmarshal config")
+		return nil, appErrors.ErrProviderCredentialUpdateFailed
+	}
+
+	logEntry.Info("Successfully updated provider mirror mode")
+
+	return response, nil
+}
+
+// checkGitHubMirrorOverlap refuses a GitHub target config whose secrets could be pruned as another GitHub
+// target's, or the other way round, when either target is in mirror mode. GitHub secrets carry no owner, so
+// targets in any environment that write to the same secrets must keep their prefixes apart.
+func (s *ProviderService) checkGitHubMirrorOverlap(ctx context.Context, targetID uuid.UUID, config json.RawMessage, mirrorMode bool) error {
+	var githubConfig GitHubConfig
+	if err := json.Unmarshal(config, &githubConfig); err != nil {
+		return appErrors.ErrInvalidProviderData
+	}
+
+	targets, err := s.providerRepo.ListProviderCredentialsByProvider(ctx, string(ProviderGitHub))
+	if err != nil {
+		return appErrors.ErrProviderCredentialGetFailed
+	}
+	for _, target := range targets {
+		if target.ID == targetID || (!mirrorMode && !target.MirrorMode) {
+			continue
+		}
+		var other GitHubConfig
+		if err := json.Unmarshal(target.Config, &other); err != nil {
+			continue
+		}
+		if githubConfig.sharesManagedSecrets(other) {
+			return appErrors.ErrMirrorModeTargetOverlap
+		}
+	}
+	return nil
+}
+
+// UpdateKeyMapping replaces the key mapping rules of a provider target. Empty rules sync every key as it is.
+func (s *ProviderService) UpdateKeyMapping(ctx context.Context, environmentID, name string, rules KeyMappingRules) (*ProviderCredentialResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":         "UpdateKeyMapping",
+		"environment_id": environmentID,
+		"target":         name,
+	})
+
+	logEntry.Info("Updating provider key mapping")
+
+	envUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Invalid environment ID")
+		return nil, appErrors.ErrInternalServer
+	}
+
+	if err := rules.Validate(); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Invalid key mapping rules")
+		return nil, err
+	}
+
+	keyMappingJSON, err := json.Marshal(rules)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to marshal key mapping")
+		return nil, appErrors.ErrProviderCredentialUpdateFailed
+	}
+
+	credential, err := s.providerRepo.UpdateProviderCredentialKeyMapping(ctx, providerdb.UpdateProviderCredentialKeyMappingParams{
+		EnvironmentID: envUUID,
+		Name:          name,
+		KeyMapping:    keyMappingJSON,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logEntry.Error("Provider credential not found")
+			return nil, appErrors.ErrProviderCredentialNotFound
+		}
+		logEntry.WithField("error", err.Error()).Error("Failed to update provider key mapping")
+		return nil, appErrors.ErrProviderCredentialUpdateFailed
+	}
+
+	response, err := toProviderCredentialResponse(credential)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to unmarshal config")
+		return nil, appErrors.ErrProviderCredentialUpdateFailed
+	}
+
+	logEntry.Info("Successfully updated provider key mapping")
+
+	return response, nil
+}
+
+// DeleteProviderCredential deletes a provider target
+func (s *ProviderService) DeleteProviderCredential(ctx context.Context, environmentID, name string) error {
 	logEntry := s.logger.WithFields(logrus.Fields{
 		"method":         "DeleteProviderCredential",
 		"environment_id": environmentID,
-		"provider":       provider,
+		"target":         name,
 	})
 
 	logEntry.Info("Deleting provider credential")
@@ -317,7 +566,7 @@ func (s *ProviderService) DeleteProviderCredential(ctx context.Context, environm
 
 	err = s.providerRepo.DeleteProviderCredential(ctx, providerdb.DeleteProviderCredentialParams{
 		EnvironmentID: envUUID,
-		Provider:      provider,
+		Name:          name,
 	})
 	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to delete provider credential")
@@ -328,12 +577,18 @@ func (s *ProviderService) DeleteProviderCredential(ctx context.Context, environm
 	return nil
 }
 
-// GetProviderSyncer creates a ProviderSyncer instance for the given environment and provider
-func (s *ProviderService) GetProviderSyncer(ctx context.Context, environmentID, provider string) (ProviderSyncer, error) {
+// GetProviderSyncer creates a ProviderSyncer instance for the given environment and provider target
+func (s *ProviderService) GetProviderSyncer(ctx context.Context, environmentID, name string) (ProviderSyncer, error) {
+	providerSyncer, _, err := s.GetProviderSyncerWithOptions(ctx, environmentID, name)
+	return providerSyncer, err
+}
+
+// GetProviderSyncerWithOptions creates a ProviderSyncer instance together with the sync options of the credential
+func (s *ProviderService) GetProviderSyncerWithOptions(ctx context.Context, environmentID, name string) (ProviderSyncer, SyncOptions, error) {
 	logEntry := s.logger.WithFields(logrus.Fields{
 		"method":         "GetProviderSyncer",
 		"environment_id": environmentID,
-		"provider":       provider,
+		"target":         name,
 	})
 
 	logEntry.Info("Creating provider syncer instance")
@@ -342,51 +597,92 @@ func (s *ProviderService) GetProviderSyncer(ctx context.Context, environmentID,
 	envUUID, err := uuid.Parse(environmentID)
 	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("Invalid environment ID")
-		return nil, appErrors.ErrInternalServer
+		return nil, SyncOptions{}, appErrors.ErrInternalServer
 	}
 
 	credential, err := s.providerRepo.GetProviderCredential(ctx, providerdb.GetProviderCredentialParams{
 		EnvironmentID: envUUID,
-		Provider:      provider,
+		Name:          name,
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			logEntry.Error("Provider credential not found")
-			return nil, appErrors.ErrProviderCredentialNotFound
+			return nil, SyncOptions{}, appErrors.ErrProviderCredentialNotFound
 		}
 		logEntry.WithField("error", err.Error()).Error("Failed to get provider credential")
-		return nil, appErrors.ErrProviderCredentialGetFailed
+		return nil, SyncOptions{}, appErrors.ErrProviderCredentialGetFailed
+	}
+
+	// Targets using a connection sync with its current credentials, so rotating the connection reaches all of them
+	encryptedCredentials := credential.Credentials
+	updatedAt := credential.UpdatedAt
+	if credential.ConnectionID.Valid {
+		connection, err := s.grantedConnection(ctx, envUUID, credential.ConnectionID.UUID)
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Error("Provider connection is not usable")
+			return nil, SyncOptions{}, err
+		}
+		encryptedCredentials = connection.Credentials
+		if connection.UpdatedAt.After(updatedAt) {
+			updatedAt = connection.UpdatedAt
+		}
 	}
 
 	// Decrypt credentials
-	decryptedCredentialsBytes, err := s.encryptor.Decrypt(string(credential.Credentials))
+	decryptedCredentialsBytes, err := s.encryptor.Decrypt(string(encryptedCredentials))
 	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to decrypt credentials")
-		return nil, appErrors.ErrProviderDecryptionFailed
+		return nil, SyncOptions{}, appErrors.ErrProviderDecryptionFailed
 	}
 
 	// Convert json.RawMessage to map[string]interface{}
 	var credentialsMap map[string]interface{}
 	if err := json.Unmarshal(decryptedCredentialsBytes, &credentialsMap); err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to unmarshal credentials")
-		return nil, appErrors.ErrProviderCredentialGetFailed
+		return nil, SyncOptions{}, appErrors.ErrProviderCredentialGetFailed
 	}
 
 	var configMap map[string]interface{}
 	if err := json.Unmarshal(credential.Config, &configMap); err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to unmarshal config")
-		return nil, appErrors.ErrProviderCredentialGetFailed
+		return nil, SyncOptions{}, appErrors.ErrProviderCredentialGetFailed
 	}
 
 	// Create provider syncer instance using factory
 	providerSyncer, err := s.factory.CreateProvider(ProviderType(credential.Provider), credentialsMap, configMap)
 	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to create provider syncer")
-		return nil, appErrors.ErrProviderSyncFailed
+		return nil, SyncOptions{}, appErrors.ErrProviderSyncFailed
+	}
+
+	// Workload tokens name the environment, provider and target, so cloud trust can be scoped to them
+	if federated, ok := providerSyncer.(ProviderWorkloadIdentityUser); ok {
+		federated.SetWorkloadIdentity(WorkloadIdentity{EnvironmentID: environmentID, Provider: credential.Provider, Target: credential.Name})
+	}
+
+	// Mirror mode only prunes the remote secrets created for this environment and target
+	if pruner, ok := providerSyncer.(ProviderPruner); ok {
+		pruner.SetOwner(SecretOwner{EnvironmentID: environmentID, Target: credential.Name})
+	}
+
+	keyMapping, err := parseKeyMapping(credential.KeyMapping)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to unmarshal key mapping")
+		return nil, SyncOptions{}, appErrors.ErrProviderCredentialGetFailed
 	}
 
 	logEntry.Info("Successfully created provider syncer instance")
-	return providerSyncer, nil
+	return providerSyncer, SyncOptions{
+		Provider:   ProviderType(credential.Provider),
+		MirrorMode: credential.MirrorMode,
+		KeyMapping: keyMapping,
+		UpdatedAt:  updatedAt,
+	}, nil
+}
+
+// IsSupportedProvider reports whether secrets can be synced to the given provider type
+func (s *ProviderService) IsSupportedProvider(provider ProviderType) bool {
+	return s.isValidProvider(provider)
 }
 
 // Helper methods
@@ -402,44 +698,164 @@ func (s *ProviderService) isValidProvider(provider ProviderType) bool {
 }
 
 func (s *ProviderService) validateProviderData(provider ProviderType, credentials, config map[string]interface{}) error {
+	if err := s.validateProviderCredentials(provider, credentials); err != nil {
+		return err
+	}
+	return s.validateProviderConfig(provider, config)
+}
+
+// validateProviderCredentials validates credentials on their own, as stored on a provider connection
+func (s *ProviderService) validateProviderCredentials(provider ProviderType, credentials map[string]interface{}) error {
 	switch provider {
 	case ProviderGitHub:
-		return s.validateGitHubData(credentials, config)
+		return s.validateGitHubCredentials(credentials)
 	case ProviderGCP:
-		return s.validateGCPData(credentials, config)
+		return s.validateGCPCredentials(credentials)
 	case ProviderAzure:
-		return s.validateAzureData(credentials, config)
+		return s.validateAzureCredentials(credentials)
+	case ProviderAWSSecretsManager:
+		return s.validateAWSSecretsManagerCredentials(credentials)
+	case ProviderAWSSSM:
+		return s.validateAWSSSMCredentials(credentials)
+	case ProviderVault:
+		return s.validateVaultCredentials(credentials)
+	case ProviderKubernetes:
+		return s.validateKubernetesCredentials(credentials)
+	case ProviderGitLab:
+		return s.validateGitLabCredentials(credentials)
+	case ProviderWebhook:
+		return s.validateWebhookCredentials(credentials)
 	default:
 		return appErrors.ErrInvalidProviderType
 	}
 }
 
-func (s *ProviderService) validateGitHubData(credentials, config map[string]interface{}) error {
-	// Validate credentials
-	if token, ok := credentials["token"].(string); !ok || token == "" {
-		return appErrors.ErrProviderCredentialValidationFailed
+// validateProviderConfig validates the per-target config, which connection-backed targets supply without credentials
+func (s *ProviderService) validateProviderConfig(provider ProviderType, config map[string]interface{}) error {
+	switch provider {
+	case ProviderGitHub:
+		return s.validateGitHubConfig(config)
+	case ProviderGCP:
+		return s.validateGCPConfig(config)
+	case ProviderAzure:
+		return s.validateAzureConfig(config)
+	case ProviderAWSSecretsManager:
+		return s.validateAWSSecretsManagerConfig(config)
+	case ProviderAWSSSM:
+		return s.validateAWSSSMConfig(config)
+	case ProviderVault:
+		return s.validateVaultConfig(config)
+	case ProviderKubernetes:
+		return s.validateKubernetesConfig(config)
+	case ProviderGitLab:
+		return s.validateGitLabConfig(config)
+	case ProviderWebhook:
+		return s.validateWebhookConfig(config)
+	default:
+		return appErrors.ErrInvalidProviderType
 	}
+}
 
+func (s *ProviderService) validateGitHubCredentials(credentials map[string]interface{}) error {
+	// Validate credentials for the auth method
+	authMethod, _ := credentials["auth_method"].(string)
+	switch authMethod {
+	case "", GitHubAuthToken:
+		if token, ok := credentials["token"].(string); !ok || token == "" {
+			return appErrors.ErrProviderCredentialValidationFailed
+		}
+	case GitHubAuthApp:
+		for _, field := range []string{"app_id", "installation_id"} {
+			if id, ok := credentials[field].(float64); !ok || id <= 0 || id != float64(int64(id)) {
+				return appErrors.ErrInvalidProviderData
+			}
+		}
+		if privateKey, ok := credentials["private_key"].(string); !ok || !strings.Contains(privateKey, "PRIVATE KEY") {
+			return appErrors.ErrInvalidProviderData
+		}
+	default:
+		return appErrors.ErrInvalidProviderData
+	}
+
+	return nil
+}
+
+func (s *ProviderService) validateGitHubConfig(config map[string]interface{}) error {
 	// Validate config
 	if owner, ok := config["owner"].(string); !ok || owner == "" {
 		return appErrors.ErrProviderCredentialValidationFailed
 	}
-	if repo, ok := config["repository"].(string); !ok || repo == "" {
-		return appErrors.ErrProviderCredentialValidationFailed
+	scope, _ := config["scope"].(string)
+	switch scope {
+	case "", GitHubScopeRepository:
+		if repo, ok := config["repository"].(string); !ok || repo == "" {
+			return appErrors.ErrProviderCredentialValidationFailed
+		}
+	case GitHubScopeOrganization:
+		if environment, ok := config["environment"].(string); ok && environment != "" {
+			return appErrors.ErrInvalidProviderData
+		}
+		visibility, _ := config["secret_visibility"].(string)
+		selected, _ := config["selected_repositories"].([]interface{})
+		switch visibility {
+		case "", GitHubVisibilityAll, GitHubVisibilityPrivate:
+			if len(selected) > 0 {
+				return appErrors.ErrInvalidProviderData
+			}
+		case GitHubVisibilitySelected:
+			if len(selected) == 0 {
+				return appErrors.ErrInvalidProviderData
+			}
+		default:
+			return appErrors.ErrInvalidProviderData
+		}
+	default:
+		return appErrors.ErrInvalidProviderData
+	}
+
+	// Environments and variables only exist for Actions
+	secretType, _ := config["secret_type"].(string)
+	switch secretType {
+	case "", GitHubSecretTypeActions:
+	case GitHubSecretTypeDependabot, GitHubSecretTypeCodespaces:
+		if environment, ok := config["environment"].(string); ok && environment != "" && environment != "default" {
+			return appErrors.ErrInvalidProviderData
+		}
+		if variableKeys, ok := config["variable_keys"].([]interface{}); ok && len(variableKeys) > 0 {
+			return appErrors.ErrInvalidProviderData
+		}
+	default:
+		return appErrors.ErrInvalidProviderData
 	}
 
 	return nil
 }
 
-func (s *ProviderService) validateGCPData(credentials, config map[string]interface{}) error {
-	// Validate credentials
-	requiredCredFields := []string{"type", "project_id", "private_key_id", "private_key", "client_email"}
+func (s *ProviderService) validateGCPCredentials(credentials map[string]interface{}) error {
+	// Validate credentials for the auth method
+	var requiredCredFields []string
+	authMethod, _ := credentials["auth_method"].(string)
+	switch authMethod {
+	case "", GCPAuthServiceAccountKey:
+		requiredCredFields = []string{"type", "project_id", "private_key_id", "private_key", "client_email"}
+	case GCPAuthWorkloadIdentity:
+		audience, _ := credentials["audience"].(string)
+		if !strings.HasPrefix(audience, "//iam.googleapis.com/") {
+			return appErrors.ErrInvalidProviderData
+		}
+	default:
+		return appErrors.ErrInvalidProviderData
+	}
 	for _, field := range requiredCredFields {
 		if val, ok := credentials[field].(string); !ok || val == "" {
 			return appErrors.ErrProviderCredentialValidationFailed
 		}
 	}
 
+	return nil
+}
+
+func (s *ProviderService) validateGCPConfig(config map[string]interface{}) error {
 	// Validate config
 	if projectID, ok := config["project_id"].(string); !ok || projectID == "" {
 		return appErrors.ErrProviderCredentialValidationFailed
@@ -448,15 +864,28 @@ func (s *ProviderService) validateGCPData(credentials, config map[string]interfa
 	return nil
 }
 
-func (s *ProviderService) validateAzureData(credentials, config map[string]interface{}) error {
-	// Validate credentials
-	requiredCredFields := []string{"tenant_id", "client_id", "client_secret"}
+func (s *ProviderService) validateAzureCredentials(credentials map[string]interface{}) error {
+	// Validate credentials for the auth method
+	var requiredCredFields []string
+	authMethod, _ := credentials["auth_method"].(string)
+	switch authMethod {
+	case "", AzureAuthClientSecret:
+		requiredCredFields = []string{"tenant_id", "client_id", "client_secret"}
+	case AzureAuthFederated:
+		requiredCredFields = []string{"tenant_id", "client_id"}
+	default:
+		return appErrors.ErrInvalidProviderData
+	}
 	for _, field := range requiredCredFields {
 		if val, ok := credentials[field].(string); !ok || val == "" {
 			return appErrors.ErrInvalidProviderData
 		}
 	}
 
+	return nil
+}
+
+func (s *ProviderService) validateAzureConfig(config map[string]interface{}) error {
 	// Validate config
 	requiredConfigFields := []string{"subscription_id", "resource_group", "key_vault_name"}
 	for _, field := range requiredConfigFields {
@@ -467,3 +896,254 @@ func (s *ProviderService) validateAzureData(credentials, config map[string]inter
 
 	return nil
 }
+
+func (s *ProviderService) validateAWSSecretsManagerCredentials(credentials map[string]interface{}) error {
+	// Validate credentials
+	requiredCredFields := []string{"access_key_id", "secret_access_key"}
+	for _, field := range requiredCredFields {
+		if val, ok := credentials[field].(string); !ok || val == "" {
+			return appErrors.ErrInvalidProviderData
+		}
+	}
+
+	return nil
+}
+
+func (s *ProviderService) validateAWSSecretsManagerConfig(config map[string]interface{}) error {
+	// Validate config
+	if region, ok := config["region"].(string); !ok || region == "" {
+		return appErrors.ErrInvalidProviderData
+	}
+	storageMode, _ := config["storage_mode"].(string)
+	switch storageMode {
+	case "", AWSStorageModePerKey:
+	case AWSStorageModeJSON:
+		if secretName, ok := config["secret_name"].(string); !ok || secretName == "" {
+			return appErrors.ErrInvalidProviderData
+		}
+	default:
+		return appErrors.ErrInvalidProviderData
+	}
+
+	return nil
+}
+
+func (s *ProviderService) validateAWSSSMCredentials(credentials map[string]interface{}) error {
+	// Validate credentials
+	requiredCredFields := []string{"access_key_id", "secret_access_key"}
+	for _, field := range requiredCredFields {
+		if val, ok := credentials[field].(string); !ok || val == "" {
+			return appErrors.ErrInvalidProviderData
+		}
+	}
+
+	return nil
+}
+
+func (s *ProviderService) validateAWSSSMConfig(config map[string]interface{}) error {
+	// Validate config
+	if region, ok := config["region"].(string); !ok || region == "" {
+		return appErrors.ErrInvalidProviderData
+	}
+	if path, ok := config["path"].(string); !ok || !strings.HasPrefix(path, "/") || strings.Trim(path, "/") == "" {
+		return appErrors.ErrInvalidProviderData
+	}
+	tier, _ := config["tier"].(string)
+	switch tier {
+	case "", "Standard", "Advanced", "Intelligent-Tiering":
+	default:
+		return appErrors.ErrInvalidProviderData
+	}
+
+	return nil
+}
+
+func (s *ProviderService) validateVaultCredentials(credentials map[string]interface{}) error {
+	// Validate credentials for the auth method
+	var requiredCredFields []string
+	authMethod, _ := credentials["auth_method"].(string)
+	switch authMethod {
+	case "", VaultAuthToken:
+		requiredCredFields = []string{"token"}
+	case VaultAuthAppRole:
+		requiredCredFields = []string{"role_id", "secret_id"}
+	case VaultAuthKubernetes:
+		requiredCredFields = []string{"role"}
+	default:
+		return appErrors.ErrInvalidProviderData
+	}
+	for _, field := range requiredCredFields {
+		if val, ok := credentials[field].(string); !ok || val == "" {
+			return appErrors.ErrInvalidProviderData
+		}
+	}
+
+	return nil
+}
+
+func (s *ProviderService) validateVaultConfig(config map[string]interface{}) error {
+	// Validate config
+	if address, ok := config["address"].(string); !ok || !strings.HasPrefix(address, "http") {
+		return appErrors.ErrInvalidProviderData
+	}
+	if path, ok := config["path"].(string); !ok || strings.Trim(path, "/") == "" {
+		return appErrors.ErrInvalidProviderData
+	}
+	storageMode, _ := config["storage_mode"].(string)
+	switch storageMode {
+	case "", VaultStorageModeSingle, VaultStorageModePerKey:
+	default:
+		return appErrors.ErrInvalidProviderData
+	}
+
+	return nil
+}
+
+func (s *ProviderService) validateKubernetesCredentials(credentials map[string]interface{}) error {
+	// Validate credentials: a kubeconfig, or a server with a bearer token
+	if kubeconfig, ok := credentials["kubeconfig"].(string); !ok || kubeconfig == "" {
+		if server, ok := credentials["server"].(string); !ok || !strings.HasPrefix(server, "http") {
+			return appErrors.ErrInvalidProviderData
+		}
+		if token, ok := credentials["token"].(string); !ok || token == "" {
+			return appErrors.ErrInvalidProviderData
+		}
+	}
+
+	return nil
+}
+
+func (s *ProviderService) validateKubernetesConfig(config map[string]interface{}) error {
+	// Validate config
+	requiredConfigFields := []string{"namespace", "secret_name"}
+	for _, field := range requiredConfigFields {
+		if val, ok := config[field].(string); !ok || val == "" {
+			return appErrors.ErrInvalidProviderData
+		}
+	}
+
+	return nil
+}
+
+func (s *ProviderService) validateGitLabCredentials(credentials map[string]interface{}) error {
+	// Validate credentials
+	if token, ok := credentials["token"].(string); !ok || token == "" {
+		return appErrors.ErrInvalidProviderData
+	}
+
+	return nil
+}
+
+func (s *ProviderService) validateGitLabConfig(config map[string]interface{}) error {
+	// Validate config: exactly one of a project or a group
+	projectID, _ := config["project_id"].(string)
+	groupID, _ := config["group_id"].(string)
+	if (projectID == "") == (groupID == "") {
+		return appErrors.ErrInvalidProviderData
+	}
+	if baseURL, ok := config["base_url"].(string); ok && !strings.HasPrefix(baseURL, "http") {
+		return appErrors.ErrInvalidProviderData
+	}
+	variableType, _ := config["variable_type"].(string)
+	switch variableType {
+	case "", GitLabVariableTypeEnv, GitLabVariableTypeFile:
+	default:
+		return appErrors.ErrInvalidProviderData
+	}
+
+	return nil
+}
+
+func (s *ProviderService) validateWebhookCredentials(credentials map[string]interface{}) error {
+	// Validate credentials: a signing secret, and a client certificate with its key for mTLS
+	if signingSecret, ok := credentials["signing_secret"].(string); !ok || signingSecret == "" {
+		return appErrors.ErrInvalidProviderData
+	}
+	certificate, _ := credentials["client_certificate"].(string)
+	key, _ := credentials["client_key"].(string)
+	if certificate != "" || key != "" {
+		if _, err := tls.X509KeyPair([]byte(certificate), []byte(key)); err != nil {
+			return appErrors.ErrInvalidProviderData
+		}
+	}
+	if !isStringMap(credentials["headers"]) {
+		return appErrors.ErrInvalidProviderData
+	}
+
+	return nil
+}
+
+func (s *ProviderService) validateWebhookConfig(config map[string]interface{}) error {
+	// Validate config: secrets are only ever posted over HTTPS
+	rawURL, _ := config["url"].(string)
+	if endpoint, err := url.Parse(rawURL); err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return appErrors.ErrInvalidProviderData
+	}
+	payloadMode, _ := config["payload_mode"].(string)
+	switch payloadMode {
+	case "", WebhookPayloadFull, WebhookPayloadPerKey:
+	default:
+		return appErrors.ErrInvalidProviderData
+	}
+	encryption, _ := config["encryption"].(string)
+	switch encryption {
+	case "":
+	case WebhookEncryptionSealedBox:
+		publicKey, _ := config["recipient_public_key"].(string)
+		if _, err := parseWebhookPublicKey(publicKey); err != nil {
+			return err
+		}
+	default:
+		return appErrors.ErrInvalidProviderData
+	}
+	if caCertificate, ok := config["ca_certificate"].(string); ok && caCertificate != "" {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(caCertificate)) {
+			return appErrors.ErrInvalidProviderData
+		}
+	}
+	if !isStringMap(config["headers"]) {
+		return appErrors.ErrInvalidProviderData
+	}
+
+	return nil
+}
+
+// isStringMap reports whether a decoded JSON value is absent or an object of strings
+func isStringMap(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for _, field := range fields {
+		if _, ok := field.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func toProviderCredentialResponse(credential providerdb.ProviderCredential) (*ProviderCredentialResponse, error) {
+	var configMap map[string]interface{}
+	if err := json.Unmarshal(credential.Config, &configMap); err != nil {
+		return nil, err
+	}
+	return &ProviderCredentialResponse{
+		ID:            credential.ID.String(),
+		EnvironmentID: credential.EnvironmentID,
+		Name:          credential.Name,
+		Provider:      ProviderType(credential.Provider),
+		ConnectionID:  nullUUIDPtr(credential.ConnectionID),
+		Config:        configMap,
+		CreatedAt:     credential.CreatedAt,
+		UpdatedAt:     credential.UpdatedAt,
+		AutoSync: AutoSyncSettings{
+			Enabled:         credential.AutoSync,
+			DebounceSeconds: int(credential.AutoSyncDebounceSeconds),
+		},
+		MirrorMode: credential.MirrorMode,
+		KeyMapping: keyMappingPtr(credential.KeyMapping),
+	}, nil
+}
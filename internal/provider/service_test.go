@@ -16,6 +16,7 @@ import (
 	providerdb "github.com/Gkemhcs/kavach-backend/internal/provider/gen"
 	"github.com/Gkemhcs/kavach-backend/internal/utils"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -26,6 +27,9 @@ import (
 //go:embed test_data/*.json
 var testDataFS embed.FS
 
+// testOwner is the environment and target the provider tests sync and prune for
+var testOwner = SecretOwner{EnvironmentID: "550e8400-e29b-41d4-a716-446655440000", Target: "primary"}
+
 // TestData represents the structure of our test data files
 type TestData struct {
 	TestCases []TestCase `json:"test_cases"`
@@ -48,6 +52,10 @@ type ExpectedResult struct {
 	// Additional fields for specific test types
 	ProviderCredential  interface{}              `json:"provider_credential,omitempty"`
 	ProviderCredentials []map[string]interface{} `json:"provider_credentials,omitempty"`
+	ProviderConnection  map[string]interface{}   `json:"provider_connection,omitempty"`
+	Secrets             []Secret                 `json:"secrets,omitempty"`
+	FailureKind         string                   `json:"failure_kind,omitempty"`
+	RetryAfterSeconds   float64                  `json:"retry_after_seconds,omitempty"`
 }
 
 // MockSetup represents the mock configuration for a test case
@@ -164,6 +172,13 @@ func (suite *ProviderServiceTestSuite) TestCreateProviderCredentialWithData() {
 				expectedCredential := tc.Expected.ProviderCredential.(map[string]interface{})
 				assert.Equal(suite.T(), expectedCredential["provider"].(string), string(result.Provider), "Provider mismatch")
 				assert.Equal(suite.T(), expectedCredential["environment_id"].(string), result.EnvironmentID.String(), "Environment ID mismatch")
+				if expectedName, ok := expectedCredential["name"].(string); ok {
+					assert.Equal(suite.T(), expectedName, result.Name, "Name mismatch")
+				}
+				if expectedConnectionID, ok := expectedCredential["connection_id"].(string); ok {
+					require.NotNil(suite.T(), result.ConnectionID, "Expected the target to reference a connection")
+					assert.Equal(suite.T(), expectedConnectionID, result.ConnectionID.String(), "Connection ID mismatch")
+				}
 			} else {
 				require.Error(suite.T(), err, "Expected error but got success")
 				// Validate error code or message if specified
@@ -192,10 +207,10 @@ func (suite *ProviderServiceTestSuite) TestGetProviderCredentialWithData() {
 
 			// Get input parameters
 			environmentID := tc.Input["environment_id"].(string)
-			provider := tc.Input["provider"].(string)
+			name := tc.Input["name"].(string)
 
 			// Call the service method
-			result, err := suite.service.GetProviderCredential(suite.ctx, environmentID, provider)
+			result, err := suite.service.GetProviderCredential(suite.ctx, environmentID, name)
 
 			// Assert results
 			if tc.Expected.Success {
@@ -278,10 +293,10 @@ func (suite *ProviderServiceTestSuite) TestUpdateProviderCredentialWithData() {
 			// Build request from test input
 			req := suite.buildUpdateProviderCredentialRequest(tc.Input)
 			environmentID := tc.Input["environment_id"].(string)
-			provider := tc.Input["provider"].(string)
+			name := tc.Input["name"].(string)
 
 			// Call the service method
-			result, err := suite.service.UpdateProviderCredential(suite.ctx, environmentID, provider, req)
+			result, err := suite.service.UpdateProviderCredential(suite.ctx, environmentID, name, req)
 
 			// Assert results
 			if tc.Expected.Success {
@@ -309,6 +324,161 @@ func (suite *ProviderServiceTestSuite) TestUpdateProviderCredentialWithData() {
 	}
 }
 
+// TestUpdateAutoSyncWithData tests UpdateAutoSync with data-driven test cases
+func (suite *ProviderServiceTestSuite) TestUpdateAutoSyncWithData() {
+	testData := suite.loadTestData("update_auto_sync_test_cases.json")
+
+	for _, tc := range testData.TestCases {
+		suite.Run(tc.Name, func() {
+			// Setup mocks based on test case
+			suite.setupUpdateProviderCredentialMocks(tc.MockSetup)
+
+			// Build settings from test input
+			environmentID := tc.Input["environment_id"].(string)
+			name := tc.Input["name"].(string)
+			settings := AutoSyncSettings{
+				Enabled:         tc.Input["enabled"].(bool),
+				DebounceSeconds: int(tc.Input["debounce_seconds"].(float64)),
+			}
+
+			// Call the service method
+			result, err := suite.service.UpdateAutoSync(suite.ctx, environmentID, name, settings)
+
+			// Assert results
+			if tc.Expected.Success {
+				require.NoError(suite.T(), err, "Expected success but got error: %v", err)
+				require.NotNil(suite.T(), result, "Expected result but got nil")
+
+				// Validate result matches expected
+				assert.Equal(suite.T(), settings, result.AutoSync, "Auto-sync settings mismatch")
+				assert.Equal(suite.T(), name, result.Name, "Name mismatch")
+			} else {
+				require.Error(suite.T(), err, "Expected error but got success")
+				// Validate error code or message if specified
+				if tc.Expected.ErrorCode != "" {
+					suite.validateErrorCode(err, tc.Expected.ErrorCode)
+				} else if tc.Expected.Error != nil {
+					expectedError := strings.ToLower(fmt.Sprintf("%v", tc.Expected.Error))
+					actualError := strings.ToLower(err.Error())
+					require.Contains(suite.T(), actualError, expectedError, "Error message mismatch")
+				}
+				// Settings are not stored when they are rejected up front
+				if strings.HasPrefix(tc.Name, "invalid_") {
+					suite.mockRepo.AssertNotCalled(suite.T(), "UpdateProviderCredentialAutoSync")
+				}
+			}
+		})
+	}
+}
+
+// TestUpdateMirrorModeWithData tests UpdateMirrorMode with data-driven test cases
+func (suite *ProviderServiceTestSuite) TestUpdateMirrorModeWithData() {
+	testData := suite.loadTestData("update_mirror_mode_test_cases.json")
+
+	for _, tc := range testData.TestCases {
+		suite.Run(tc.Name, func() {
+			// Setup mocks based on test case
+			suite.mockRepo.Calls = nil
+			suite.setupUpdateProviderCredentialMocks(tc.MockSetup)
+
+			environmentID := tc.Input["environment_id"].(string)
+			name := tc.Input["name"].(string)
+			enabled := tc.Input["enabled"].(bool)
+
+			// Call the service method
+			result, err := suite.service.UpdateMirrorMode(suite.ctx, environmentID, name, enabled)
+
+			// Assert results
+			if tc.Expected.Success {
+				require.NoError(suite.T(), err, "Expected success but got error: %v", err)
+				require.NotNil(suite.T(), result, "Expected result but got nil")
+
+				assert.Equal(suite.T(), enabled, result.MirrorMode, "Mirror mode mismatch")
+				assert.Equal(suite.T(), name, result.Name, "Name mismatch")
+			} else {
+				require.Error(suite.T(), err, "Expected error but got success")
+				if tc.Expected.ErrorCode != "" {
+					suite.validateErrorCode(err, tc.Expected.ErrorCode)
+				}
+				// The setting is not stored when it is rejected up front
+				if strings.HasPrefix(tc.Name, "invalid_") {
+					suite.mockRepo.AssertNotCalled(suite.T(), "UpdateProviderCredentialMirrorMode")
+				}
+			}
+		})
+	}
+}
+
+// TestUpdateKeyMappingWithData tests UpdateKeyMapping with data-driven test cases
+func (suite *ProviderServiceTestSuite) TestUpdateKeyMappingWithData() {
+	testData := suite.loadTestData("update_key_mapping_test_cases.json")
+
+	for _, tc := range testData.TestCases {
+		suite.Run(tc.Name, func() {
+			// Setup mocks based on test case
+			suite.mockRepo.Calls = nil
+			suite.setupUpdateProviderCredentialMocks(tc.MockSetup)
+
+			environmentID := tc.Input["environment_id"].(string)
+			name := tc.Input["name"].(string)
+			rules := buildKeyMappingRules(suite.T(), tc.Input["rules"])
+
+			// Call the service method
+			result, err := suite.service.UpdateKeyMapping(suite.ctx, environmentID, name, rules)
+
+			// Assert results
+			if tc.Expected.Success {
+				require.NoError(suite.T(), err, "Expected success but got error: %v", err)
+				require.NotNil(suite.T(), result, "Expected result but got nil")
+
+				assert.Equal(suite.T(), name, result.Name, "Name mismatch")
+				if rules.IsZero() {
+					assert.Nil(suite.T(), result.KeyMapping, "Empty rules should not be returned")
+				} else {
+					require.NotNil(suite.T(), result.KeyMapping, "Expected stored key mapping")
+					assert.Equal(suite.T(), rules, *result.KeyMapping, "Key mapping mismatch")
+				}
+			} else {
+				require.Error(suite.T(), err, "Expected error but got success")
+				if tc.Expected.ErrorCode != "" {
+					suite.validateErrorCode(err, tc.Expected.ErrorCode)
+				}
+				// Invalid rules are rejected before they are stored
+				if strings.HasPrefix(tc.Name, "invalid_") {
+					suite.mockRepo.AssertNotCalled(suite.T(), "UpdateProviderCredentialKeyMapping")
+				}
+			}
+		})
+	}
+}
+
+// TestApplyKeyMappingWithData tests KeyMappingRules.Apply with data-driven test cases
+func (suite *ProviderServiceTestSuite) TestApplyKeyMappingWithData() {
+	testData := suite.loadTestData("apply_key_mapping_test_cases.json")
+
+	for _, tc := range testData.TestCases {
+		suite.Run(tc.Name, func() {
+			rules := buildKeyMappingRules(suite.T(), tc.Input["rules"])
+			var secrets []Secret
+			secretsJSON, err := json.Marshal(tc.Input["secrets"])
+			require.NoError(suite.T(), err, "Failed to marshal secrets")
+			require.NoError(suite.T(), json.Unmarshal(secretsJSON, &secrets), "Failed to unmarshal secrets")
+
+			result, err := rules.Apply(secrets)
+
+			if tc.Expected.Success {
+				require.NoError(suite.T(), err, "Expected success but got error: %v", err)
+				assert.Equal(suite.T(), tc.Expected.Secrets, result, "Mapped secrets mismatch")
+			} else {
+				require.Error(suite.T(), err, "Expected error but got success")
+				if tc.Expected.ErrorCode != "" {
+					suite.validateErrorCode(err, tc.Expected.ErrorCode)
+				}
+			}
+		})
+	}
+}
+
 // TestDeleteProviderCredentialWithData tests DeleteProviderCredential with data-driven test cases
 func (suite *ProviderServiceTestSuite) TestDeleteProviderCredentialWithData() {
 	testData := suite.loadTestData("delete_provider_credential_test_cases.json")
@@ -320,10 +490,10 @@ func (suite *ProviderServiceTestSuite) TestDeleteProviderCredentialWithData() {
 
 			// Get input parameters
 			environmentID := tc.Input["environment_id"].(string)
-			provider := tc.Input["provider"].(string)
+			name := tc.Input["name"].(string)
 
 			// Call the service method
-			err := suite.service.DeleteProviderCredential(suite.ctx, environmentID, provider)
+			err := suite.service.DeleteProviderCredential(suite.ctx, environmentID, name)
 
 			// Assert results
 			if tc.Expected.Success {
@@ -354,10 +524,10 @@ func (suite *ProviderServiceTestSuite) TestGetProviderSyncerWithData() {
 
 			// Get input parameters
 			environmentID := tc.Input["environment_id"].(string)
-			provider := tc.Input["provider"].(string)
+			name := tc.Input["name"].(string)
 
 			// Call the service method
-			result, err := suite.service.GetProviderSyncer(suite.ctx, environmentID, provider)
+			result, err := suite.service.GetProviderSyncer(suite.ctx, environmentID, name)
 
 			// Assert results
 			if tc.Expected.Success {
@@ -378,6 +548,108 @@ func (suite *ProviderServiceTestSuite) TestGetProviderSyncerWithData() {
 	}
 }
 
+// TestCreateProviderConnectionWithData tests CreateProviderConnection with data-driven test cases
+func (suite *ProviderServiceTestSuite) TestCreateProviderConnectionWithData() {
+	testData := suite.loadTestData("create_provider_connection_test_cases.json")
+
+	for _, tc := range testData.TestCases {
+		suite.Run(tc.Name, func() {
+			// Setup mocks based on test case
+			suite.setupCreateProviderCredentialMocks(tc.MockSetup)
+
+			req := CreateProviderConnectionRequest{
+				Name:     tc.Input["name"].(string),
+				Provider: ProviderType(tc.Input["provider"].(string)),
+			}
+			if tc.Input["credentials"] != nil {
+				req.Credentials = tc.Input["credentials"].(map[string]interface{})
+			}
+
+			// Call the service method
+			result, err := suite.service.CreateProviderConnection(suite.ctx, tc.Input["organization_id"].(string), tc.Input["user_id"].(string), req)
+
+			// Assert results
+			if tc.Expected.Success {
+				require.NoError(suite.T(), err, "Expected success but got error: %v", err)
+				assert.Equal(suite.T(), tc.Expected.ProviderConnection["name"], result.Name, "Name mismatch")
+				assert.Equal(suite.T(), tc.Expected.ProviderConnection["provider"], string(result.Provider), "Provider mismatch")
+			} else {
+				require.Error(suite.T(), err, "Expected error but got success")
+				suite.validateErrorCode(err, tc.Expected.ErrorCode)
+				if strings.HasPrefix(tc.Name, "error_invalid") {
+					suite.mockRepo.AssertNotCalled(suite.T(), "CreateProviderConnection")
+				}
+			}
+		})
+	}
+}
+
+// TestRotateProviderConnectionWithData tests RotateProviderConnection with data-driven test cases
+func (suite *ProviderServiceTestSuite) TestRotateProviderConnectionWithData() {
+	testData := suite.loadTestData("rotate_provider_connection_test_cases.json")
+
+	for _, tc := range testData.TestCases {
+		suite.Run(tc.Name, func() {
+			// Setup mocks based on test case
+			suite.setupProviderRepoMocks(tc.MockSetup.ProviderRepo)
+
+			req := RotateProviderConnectionRequest{Credentials: tc.Input["credentials"].(map[string]interface{})}
+
+			// Call the service method
+			result, err := suite.service.RotateProviderConnection(suite.ctx, tc.Input["organization_id"].(string), tc.Input["name"].(string), req)
+
+			// Assert results
+			if tc.Expected.Success {
+				require.NoError(suite.T(), err, "Expected success but got error: %v", err)
+				expectedTargets := tc.Expected.ProviderConnection["targets"].([]interface{})
+				require.Len(suite.T(), result.Targets, len(expectedTargets), "Dependent target count mismatch")
+				for i, target := range expectedTargets {
+					assert.Equal(suite.T(), target.(string), result.Targets[i].Name, "Dependent target mismatch")
+				}
+			} else {
+				require.Error(suite.T(), err, "Expected error but got success")
+				suite.validateErrorCode(err, tc.Expected.ErrorCode)
+				suite.mockRepo.AssertNotCalled(suite.T(), "UpdateProviderConnectionCredentials")
+			}
+		})
+	}
+}
+
+// TestCreateProviderConnectionGrantWithData tests CreateProviderConnectionGrant with data-driven test cases
+func (suite *ProviderServiceTestSuite) TestCreateProviderConnectionGrantWithData() {
+	testData := suite.loadTestData("create_provider_connection_grant_test_cases.json")
+
+	for _, tc := range testData.TestCases {
+		suite.Run(tc.Name, func() {
+			// Setup mocks based on test case
+			suite.setupProviderRepoMocks(tc.MockSetup.ProviderRepo)
+
+			var req CreateProviderConnectionGrantRequest
+			if secretGroupID, ok := tc.Input["secret_group_id"].(string); ok {
+				id := uuid.MustParse(secretGroupID)
+				req.SecretGroupID = &id
+			}
+			if environmentID, ok := tc.Input["environment_id"].(string); ok {
+				id := uuid.MustParse(environmentID)
+				req.EnvironmentID = &id
+			}
+
+			// Call the service method
+			result, err := suite.service.CreateProviderConnectionGrant(suite.ctx, tc.Input["organization_id"].(string), tc.Input["name"].(string), tc.Input["user_id"].(string), req)
+
+			// Assert results
+			if tc.Expected.Success {
+				require.NoError(suite.T(), err, "Expected success but got error: %v", err)
+				assert.Equal(suite.T(), req.SecretGroupID, result.SecretGroupID, "Secret group mismatch")
+				assert.Equal(suite.T(), req.EnvironmentID, result.EnvironmentID, "Environment mismatch")
+			} else {
+				require.Error(suite.T(), err, "Expected error but got success")
+				suite.validateErrorCode(err, tc.Expected.ErrorCode)
+			}
+		})
+	}
+}
+
 // Mock implementations for the factory interface
 func (m *MockProviderFactory) CreateProvider(providerType ProviderType, credentials map[string]interface{}, config map[string]interface{}) (ProviderSyncer, error) {
 	args := m.Called(providerType, credentials, config)
@@ -511,14 +783,21 @@ func (suite *ProviderServiceTestSuite) setupProviderRepoMock(config MockConfig)
 			credential := providerdb.ProviderCredential{
 				ID:            uuid.MustParse(credentialData["id"].(string)),
 				EnvironmentID: uuid.MustParse(credentialData["environment_id"].(string)),
+				Name:          mockCredentialName(credentialData),
 				Provider:      credentialData["provider"].(string),
 				Config:        json.RawMessage(`{"test": "config"}`),
 			}
 			credential.CreatedAt = time.Now()
 			credential.UpdatedAt = time.Now()
 
-			suite.mockRepo.On("CreateProviderCredential", suite.ctx, mock.AnythingOfType("providerdb.CreateProviderCredentialParams")).
-				Return(credential, nil).Once()
+			// Targets using a connection store no credentials of their own
+			call := suite.mockRepo.On("CreateProviderCredential", suite.ctx, mock.AnythingOfType("providerdb.CreateProviderCredentialParams")).Once()
+			call.Run(func(args mock.Arguments) {
+				params := args.Get(1).(providerdb.CreateProviderCredentialParams)
+				assert.Equal(suite.T(), params.ConnectionID.Valid, params.Credentials == nil, "Exactly one of credentials and connection must be stored")
+				credential.ConnectionID = params.ConnectionID
+				call.ReturnArguments = mock.Arguments{credential, nil}
+			})
 		}
 	case "GetProviderCredential":
 		if config.Return["error"] != nil {
@@ -541,9 +820,19 @@ func (suite *ProviderServiceTestSuite) setupProviderRepoMock(config MockConfig)
 			credential := providerdb.ProviderCredential{
 				ID:            uuid.MustParse(credentialData["id"].(string)),
 				EnvironmentID: uuid.MustParse(credentialData["environment_id"].(string)),
+				Name:          mockCredentialName(credentialData),
 				Provider:      credentialData["provider"].(string),
 				Config:        json.RawMessage(`{"test": "config"}`),
 			}
+			if configData, ok := credentialData["config"]; ok {
+				configJSON, err := json.Marshal(configData)
+				require.NoError(suite.T(), err, "Failed to marshal provider config")
+				credential.Config = configJSON
+			}
+			if connectionID, ok := credentialData["connection_id"].(string); ok {
+				credential.ConnectionID = uuid.NullUUID{UUID: uuid.MustParse(connectionID), Valid: true}
+			}
+			credential.MirrorMode, _ = credentialData["mirror_mode"].(bool)
 			credential.CreatedAt = time.Now()
 			credential.UpdatedAt = time.Now()
 
@@ -563,6 +852,7 @@ func (suite *ProviderServiceTestSuite) setupProviderRepoMock(config MockConfig)
 					credential := providerdb.ProviderCredential{
 						ID:            uuid.MustParse(credMap["id"].(string)),
 						EnvironmentID: uuid.MustParse(credMap["environment_id"].(string)),
+						Name:          mockCredentialName(credMap),
 						Provider:      credMap["provider"].(string),
 						Config:        json.RawMessage(`{"test": "config"}`),
 					}
@@ -575,6 +865,33 @@ func (suite *ProviderServiceTestSuite) setupProviderRepoMock(config MockConfig)
 			suite.mockRepo.On("ListProviderCredentials", suite.ctx, mock.AnythingOfType("uuid.UUID")).
 				Return(credentials, nil).Once()
 		}
+	case "ListProviderCredentialsByProvider":
+		if config.Return["error"] != nil {
+			suite.mockRepo.On("ListProviderCredentialsByProvider", suite.ctx, mock.AnythingOfType("string")).
+				Return([]providerdb.ProviderCredential{}, errors.New(config.Return["error"].(string))).Once()
+		} else {
+			// Targets of the provider type in any environment, with the config and mirror mode the overlap check reads
+			credentials := []providerdb.ProviderCredential{}
+			if config.Return["provider_credentials"] != nil {
+				for _, cred := range config.Return["provider_credentials"].([]interface{}) {
+					credMap := cred.(map[string]interface{})
+					configJSON, err := json.Marshal(credMap["config"])
+					require.NoError(suite.T(), err, "Failed to marshal provider config")
+					mirrorMode, _ := credMap["mirror_mode"].(bool)
+					credentials = append(credentials, providerdb.ProviderCredential{
+						ID:            uuid.MustParse(credMap["id"].(string)),
+						EnvironmentID: uuid.MustParse(credMap["environment_id"].(string)),
+						Name:          mockCredentialName(credMap),
+						Provider:      credMap["provider"].(string),
+						Config:        configJSON,
+						MirrorMode:    mirrorMode,
+					})
+				}
+			}
+
+			suite.mockRepo.On("ListProviderCredentialsByProvider", suite.ctx, mock.AnythingOfType("string")).
+				Return(credentials, nil).Once()
+		}
 	case "UpdateProviderCredential":
 		if config.Return["error"] != nil {
 			suite.mockRepo.On("UpdateProviderCredential", suite.ctx, mock.AnythingOfType("providerdb.UpdateProviderCredentialParams")).
@@ -585,6 +902,7 @@ func (suite *ProviderServiceTestSuite) setupProviderRepoMock(config MockConfig)
 			credential := providerdb.ProviderCredential{
 				ID:            uuid.MustParse(credentialData["id"].(string)),
 				EnvironmentID: uuid.MustParse(credentialData["environment_id"].(string)),
+				Name:          mockCredentialName(credentialData),
 				Provider:      credentialData["provider"].(string),
 				Config:        json.RawMessage(`{"test": "config"}`),
 			}
@@ -594,6 +912,180 @@ func (suite *ProviderServiceTestSuite) setupProviderRepoMock(config MockConfig)
 			suite.mockRepo.On("UpdateProviderCredential", suite.ctx, mock.AnythingOfType("providerdb.UpdateProviderCredentialParams")).
 				Return(credential, nil).Once()
 		}
+	case "UpdateProviderCredentialAutoSync":
+		if config.Return["error"] != nil {
+			errorMsg := config.Return["error"].(string)
+			var err error
+
+			// Handle specific error types
+			switch errorMsg {
+			case "sql: no rows in result set":
+				err = sql.ErrNoRows
+			default:
+				err = errors.New(errorMsg)
+			}
+
+			suite.mockRepo.On("UpdateProviderCredentialAutoSync", suite.ctx, mock.AnythingOfType("providerdb.UpdateProviderCredentialAutoSyncParams")).
+				Return(providerdb.ProviderCredential{}, err).Once()
+		} else {
+			// Echo the stored settings back on the credential from test data
+			credentialData := config.Return["provider_credential"].(map[string]interface{})
+			call := suite.mockRepo.On("UpdateProviderCredentialAutoSync", suite.ctx, mock.AnythingOfType("providerdb.UpdateProviderCredentialAutoSyncParams")).Once()
+			call.Run(func(args mock.Arguments) {
+				params := args.Get(1).(providerdb.UpdateProviderCredentialAutoSyncParams)
+				call.ReturnArguments = mock.Arguments{providerdb.ProviderCredential{
+					ID:                      uuid.MustParse(credentialData["id"].(string)),
+					EnvironmentID:           params.EnvironmentID,
+					Name:                    params.Name,
+					Provider:                credentialData["provider"].(string),
+					Config:                  json.RawMessage(`{"test": "config"}`),
+					CreatedAt:               time.Now(),
+					UpdatedAt:               time.Now(),
+					AutoSync:                params.AutoSync,
+					AutoSyncDebounceSeconds: params.AutoSyncDebounceSeconds,
+				}, nil}
+			})
+		}
+	case "UpdateProviderCredentialMirrorMode":
+		if config.Return["error"] != nil {
+			errorMsg := config.Return["error"].(string)
+			var err error
+
+			// Handle specific error types
+			switch errorMsg {
+			case "sql: no rows in result set":
+				err = sql.ErrNoRows
+			default:
+				err = errors.New(errorMsg)
+			}
+
+			suite.mockRepo.On("UpdateProviderCredentialMirrorMode", suite.ctx, mock.AnythingOfType("providerdb.UpdateProviderCredentialMirrorModeParams")).
+				Return(providerdb.ProviderCredential{}, err).Once()
+		} else {
+			// Echo the stored setting back on the credential from test data
+			credentialData := config.Return["provider_credential"].(map[string]interface{})
+			call := suite.mockRepo.On("UpdateProviderCredentialMirrorMode", suite.ctx, mock.AnythingOfType("providerdb.UpdateProviderCredentialMirrorModeParams")).Once()
+			call.Run(func(args mock.Arguments) {
+				params := args.Get(1).(providerdb.UpdateProviderCredentialMirrorModeParams)
+				call.ReturnArguments = mock.Arguments{providerdb.ProviderCredential{
+					ID:            uuid.MustParse(credentialData["id"].(string)),
+					EnvironmentID: params.EnvironmentID,
+					Name:          params.Name,
+					Provider:      credentialData["provider"].(string),
+					Config:        json.RawMessage(`{"test": "config"}`),
+					CreatedAt:     time.Now(),
+					UpdatedAt:     time.Now(),
+					MirrorMode:    params.MirrorMode,
+				}, nil}
+			})
+		}
+	case "UpdateProviderCredentialKeyMapping":
+		if config.Return["error"] != nil {
+			suite.mockRepo.On("UpdateProviderCredentialKeyMapping", suite.ctx, mock.AnythingOfType("providerdb.UpdateProviderCredentialKeyMappingParams")).
+				Return(providerdb.ProviderCredential{}, mockRepoError(config.Return["error"].(string))).Once()
+		} else {
+			// Echo the stored rules back on the credential from test data
+			credentialData := config.Return["provider_credential"].(map[string]interface{})
+			call := suite.mockRepo.On("UpdateProviderCredentialKeyMapping", suite.ctx, mock.AnythingOfType("providerdb.UpdateProviderCredentialKeyMappingParams")).Once()
+			call.Run(func(args mock.Arguments) {
+				params := args.Get(1).(providerdb.UpdateProviderCredentialKeyMappingParams)
+				call.ReturnArguments = mock.Arguments{providerdb.ProviderCredential{
+					ID:            uuid.MustParse(credentialData["id"].(string)),
+					EnvironmentID: params.EnvironmentID,
+					Name:          params.Name,
+					Provider:      credentialData["provider"].(string),
+					Config:        json.RawMessage(`{"test": "config"}`),
+					CreatedAt:     time.Now(),
+					UpdatedAt:     time.Now(),
+					KeyMapping:    params.KeyMapping,
+				}, nil}
+			})
+		}
+	case "CreateProviderConnection":
+		if config.Return["error"] != nil {
+			suite.mockRepo.On("CreateProviderConnection", suite.ctx, mock.AnythingOfType("providerdb.CreateProviderConnectionParams")).
+				Return(providerdb.ProviderConnection{}, mockRepoError(config.Return["error"].(string))).Once()
+		} else {
+			// Echo the stored connection back, with the ID from test data
+			connectionData := config.Return["provider_connection"].(map[string]interface{})
+			call := suite.mockRepo.On("CreateProviderConnection", suite.ctx, mock.AnythingOfType("providerdb.CreateProviderConnectionParams")).Once()
+			call.Run(func(args mock.Arguments) {
+				params := args.Get(1).(providerdb.CreateProviderConnectionParams)
+				call.ReturnArguments = mock.Arguments{providerdb.ProviderConnection{
+					ID:             uuid.MustParse(connectionData["id"].(string)),
+					OrganizationID: params.OrganizationID,
+					Name:           params.Name,
+					Provider:       params.Provider,
+					Credentials:    params.Credentials,
+					CreatedBy:      params.CreatedBy,
+					CreatedAt:      time.Now(),
+					UpdatedAt:      time.Now(),
+				}, nil}
+			})
+		}
+	case "GetProviderConnection", "GetProviderConnectionForEnvironment", "GetProviderConnectionByID":
+		argType := map[string]string{
+			"GetProviderConnection":               "providerdb.GetProviderConnectionParams",
+			"GetProviderConnectionForEnvironment": "providerdb.GetProviderConnectionForEnvironmentParams",
+			"GetProviderConnectionByID":           "uuid.UUID",
+		}[config.Method]
+		if config.Return["error"] != nil {
+			suite.mockRepo.On(config.Method, suite.ctx, mock.AnythingOfType(argType)).
+				Return(providerdb.ProviderConnection{}, mockRepoError(config.Return["error"].(string))).Once()
+		} else {
+			suite.mockRepo.On(config.Method, suite.ctx, mock.AnythingOfType(argType)).
+				Return(mockProviderConnection(config.Return["provider_connection"].(map[string]interface{})), nil).Once()
+		}
+	case "UpdateProviderConnectionCredentials":
+		if config.Return["error"] != nil {
+			suite.mockRepo.On("UpdateProviderConnectionCredentials", suite.ctx, mock.AnythingOfType("providerdb.UpdateProviderConnectionCredentialsParams")).
+				Return(providerdb.ProviderConnection{}, mockRepoError(config.Return["error"].(string))).Once()
+		} else {
+			connection := mockProviderConnection(config.Return["provider_connection"].(map[string]interface{}))
+			call := suite.mockRepo.On("UpdateProviderConnectionCredentials", suite.ctx, mock.AnythingOfType("providerdb.UpdateProviderConnectionCredentialsParams")).Once()
+			call.Run(func(args mock.Arguments) {
+				connection.Credentials = args.Get(1).(providerdb.UpdateProviderConnectionCredentialsParams).Credentials
+				call.ReturnArguments = mock.Arguments{connection, nil}
+			})
+		}
+	case "ListProviderConnectionTargets":
+		targets := []providerdb.ProviderCredential{}
+		if config.Return["provider_credentials"] != nil {
+			for _, cred := range config.Return["provider_credentials"].([]interface{}) {
+				credMap := cred.(map[string]interface{})
+				targets = append(targets, providerdb.ProviderCredential{
+					ID:            uuid.MustParse(credMap["id"].(string)),
+					EnvironmentID: uuid.MustParse(credMap["environment_id"].(string)),
+					Name:          mockCredentialName(credMap),
+					Provider:      credMap["provider"].(string),
+				})
+			}
+		}
+		suite.mockRepo.On("ListProviderConnectionTargets", suite.ctx, mock.AnythingOfType("uuid.NullUUID")).
+			Return(targets, nil).Once()
+	case "HasProviderConnectionGrant":
+		granted, _ := config.Return["granted"].(bool)
+		suite.mockRepo.On("HasProviderConnectionGrant", suite.ctx, mock.AnythingOfType("providerdb.HasProviderConnectionGrantParams")).
+			Return(granted, nil).Once()
+	case "CreateProviderConnectionGrant":
+		if config.Return["error"] != nil {
+			suite.mockRepo.On("CreateProviderConnectionGrant", suite.ctx, mock.AnythingOfType("providerdb.CreateProviderConnectionGrantParams")).
+				Return(providerdb.ProviderConnectionGrant{}, mockRepoError(config.Return["error"].(string))).Once()
+		} else {
+			grantData := config.Return["provider_connection_grant"].(map[string]interface{})
+			call := suite.mockRepo.On("CreateProviderConnectionGrant", suite.ctx, mock.AnythingOfType("providerdb.CreateProviderConnectionGrantParams")).Once()
+			call.Run(func(args mock.Arguments) {
+				params := args.Get(1).(providerdb.CreateProviderConnectionGrantParams)
+				call.ReturnArguments = mock.Arguments{providerdb.ProviderConnectionGrant{
+					ID:            uuid.MustParse(grantData["id"].(string)),
+					ConnectionID:  params.ConnectionID,
+					SecretGroupID: params.SecretGroupID,
+					EnvironmentID: params.EnvironmentID,
+					GrantedBy:     params.GrantedBy,
+					CreatedAt:     time.Now(),
+				}, nil}
+			})
+		}
 	case "DeleteProviderCredential":
 		if config.Return["error"] != nil {
 			suite.mockRepo.On("DeleteProviderCredential", suite.ctx, mock.AnythingOfType("providerdb.DeleteProviderCredentialParams")).
@@ -605,6 +1097,56 @@ func (suite *ProviderServiceTestSuite) setupProviderRepoMock(config MockConfig)
 	}
 }
 
+// mockRepoError turns an error message from test data into the error the database driver would return
+func mockRepoError(message string) error {
+	switch message {
+	case "sql: no rows in result set":
+		return sql.ErrNoRows
+	case "unique_violation":
+		return &pq.Error{Code: "23505"}
+	case "foreign_key_violation":
+		return &pq.Error{Code: "23503"}
+	default:
+		return errors.New(message)
+	}
+}
+
+// buildKeyMappingRules builds key mapping rules from test data
+func buildKeyMappingRules(t *testing.T, input interface{}) KeyMappingRules {
+	var rules KeyMappingRules
+	if input == nil {
+		return rules
+	}
+	rulesJSON, err := json.Marshal(input)
+	require.NoError(t, err, "Failed to marshal key mapping rules")
+	require.NoError(t, json.Unmarshal(rulesJSON, &rules), "Failed to unmarshal key mapping rules")
+	return rules
+}
+
+// mockProviderConnection builds a provider connection from test data
+func mockProviderConnection(connectionData map[string]interface{}) providerdb.ProviderConnection {
+	connection := providerdb.ProviderConnection{
+		ID:             uuid.MustParse(connectionData["id"].(string)),
+		OrganizationID: uuid.MustParse(connectionData["organization_id"].(string)),
+		Name:           connectionData["name"].(string),
+		Provider:       connectionData["provider"].(string),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if credentials, ok := connectionData["credentials"].(string); ok {
+		connection.Credentials = []byte(credentials)
+	}
+	return connection
+}
+
+// mockCredentialName returns the target name of a mocked credential, which defaults to its provider type
+func mockCredentialName(credentialData map[string]interface{}) string {
+	if name, ok := credentialData["name"].(string); ok {
+		return name
+	}
+	return credentialData["provider"].(string)
+}
+
 // setupFactoryMock sets up the factory mock
 func (suite *ProviderServiceTestSuite) setupFactoryMock(config MockConfig) {
 	switch config.Method {
@@ -636,6 +1178,12 @@ func (suite *ProviderServiceTestSuite) buildCreateProviderCredentialRequest(inpu
 	req := CreateProviderCredentialRequest{
 		Provider: ProviderType(input["provider"].(string)),
 	}
+	if name, ok := input["name"].(string); ok {
+		req.Name = name
+	}
+	if connection, ok := input["connection"].(string); ok {
+		req.Connection = connection
+	}
 
 	if input["credentials"] != nil {
 		req.Credentials = input["credentials"].(map[string]interface{})
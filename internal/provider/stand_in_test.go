@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// awsJSONContentType is the content type of the AWS JSON protocol used by Secrets Manager and SSM
+const awsJSONContentType = "application/x-amz-json-1.1"
+
+// standInSuite is embedded by the suites that test a provider against a local HTTP stand-in of its API.
+// A suite's SetupTest creates its stand-in and serves it; the server is stopped after each test.
+type standInSuite struct {
+	suite.Suite
+	server *httptest.Server
+	logger *logrus.Logger
+	ctx    context.Context
+}
+
+// serve starts serving the stand-in for the current test
+func (s *standInSuite) serve(handler http.Handler) {
+	s.prepare(handler)
+	s.server.Start()
+}
+
+// prepare creates the server of the stand-in without starting it, for tests that configure TLS first
+func (s *standInSuite) prepare(handler http.Handler) {
+	s.logger = logrus.New()
+	s.logger.SetOutput(io.Discard)
+	s.ctx = context.Background()
+	s.server = httptest.NewUnstartedServer(handler)
+}
+
+// TearDownTest stops the stand-in
+func (s *standInSuite) TearDownTest() {
+	s.server.Close()
+}
+
+// prepareProvider fails the test when the provider could not be created, and sets the test owner on pruners
+func (s *standInSuite) prepareProvider(provider ProviderSyncer, err error) {
+	require.NoError(s.T(), err, "Failed to create provider")
+	if pruner, ok := provider.(ProviderPruner); ok {
+		pruner.SetOwner(testOwner)
+	}
+}
+
+// respondJSON writes a JSON response with the given content type and status
+func respondJSON(w http.ResponseWriter, contentType string, status int, output interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(output)
+}
+
+// decodeAWSRequest decodes the input of an AWS JSON protocol request and returns the operation named by its
+// X-Amz-Target header without the service prefix. It writes the error response when the body is invalid.
+func decodeAWSRequest(w http.ResponseWriter, r *http.Request, service string, input interface{}) (string, bool) {
+	if err := json.NewDecoder(r.Body).Decode(input); err != nil {
+		failAWS(w, "ValidationException", err.Error())
+		return "", false
+	}
+	return strings.TrimPrefix(r.Header.Get("X-Amz-Target"), service+"."), true
+}
+
+// respondAWS writes a successful AWS JSON protocol response
+func respondAWS(w http.ResponseWriter, output interface{}) {
+	respondJSON(w, awsJSONContentType, http.StatusOK, output)
+}
+
+// failAWS writes an AWS JSON protocol error
+func failAWS(w http.ResponseWriter, code, message string) {
+	w.Header().Set("X-Amzn-ErrorType", code)
+	respondJSON(w, awsJSONContentType, http.StatusBadRequest, map[string]string{"__type": code, "message": message})
+}
@@ -6,16 +6,20 @@ import (
 	"github.com/google/uuid"
 )
 
-// CreateProviderCredentialRequest represents the request to create a new provider credential
+// CreateProviderCredentialRequest represents the request to create a new provider target.
+// A target carries either its own credentials or the name of an organization connection to use.
 type CreateProviderCredentialRequest struct {
 	Provider    ProviderType           `json:"provider" binding:"required"`
-	Credentials map[string]interface{} `json:"credentials" binding:"required"`
+	Name        string                 `json:"name,omitempty"` // Unique within the environment; defaults to the provider type
+	Credentials map[string]interface{} `json:"credentials,omitempty"`
+	Connection  string                 `json:"connection,omitempty"` // Provider connection whose credentials the target uses
 	Config      map[string]interface{} `json:"config" binding:"required"`
 }
 
-// UpdateProviderCredentialRequest represents the request to update an existing provider credential
+// UpdateProviderCredentialRequest represents the request to update an existing provider credential.
+// Targets using a connection only take config; their credentials change by rotating the connection.
 type UpdateProviderCredentialRequest struct {
-	Credentials map[string]interface{} `json:"credentials" binding:"required"`
+	Credentials map[string]interface{} `json:"credentials,omitempty"`
 	Config      map[string]interface{} `json:"config" binding:"required"`
 }
 
@@ -23,16 +27,108 @@ type UpdateProviderCredentialRequest struct {
 type ProviderCredentialResponse struct {
 	ID            string                 `json:"id"`
 	EnvironmentID uuid.UUID              `json:"environment_id"`
+	Name          string                 `json:"name"` // Addresses the target in routes and sync requests
 	Provider      ProviderType           `json:"provider"`
-	Credentials   map[string]interface{} `json:"credentials,omitempty"` // Decrypted credentials
+	Credentials   map[string]interface{} `json:"credentials,omitempty"`   // Decrypted credentials
+	ConnectionID  *uuid.UUID             `json:"connection_id,omitempty"` // Set when the target uses a provider connection
 	Config        map[string]interface{} `json:"config"`
 	CreatedAt     time.Time              `json:"created_at"`
 	UpdatedAt     time.Time              `json:"updated_at"`
+	AutoSync      AutoSyncSettings       `json:"auto_sync"`
+	MirrorMode    bool                   `json:"mirror_mode"` // Sync also deletes managed remote secrets that are no longer in the version
+	KeyMapping    *KeyMappingRules       `json:"key_mapping,omitempty"`
+}
+
+// CreateProviderConnectionRequest represents the request to create an organization provider connection
+type CreateProviderConnectionRequest struct {
+	Name        string                 `json:"name" binding:"required"`
+	Provider    ProviderType           `json:"provider" binding:"required"`
+	Credentials map[string]interface{} `json:"credentials" binding:"required"`
+}
+
+// RotateProviderConnectionRequest represents the request to replace the credentials of a provider connection
+type RotateProviderConnectionRequest struct {
+	Credentials map[string]interface{} `json:"credentials" binding:"required"`
+}
+
+// ProviderConnectionResponse represents a provider connection. Credentials are never returned.
+type ProviderConnectionResponse struct {
+	ID             string                     `json:"id"`
+	OrganizationID uuid.UUID                  `json:"organization_id"`
+	Name           string                     `json:"name"`
+	Provider       ProviderType               `json:"provider"`
+	CreatedAt      time.Time                  `json:"created_at"`
+	UpdatedAt      time.Time                  `json:"updated_at"`
+	Targets        []ProviderConnectionTarget `json:"targets,omitempty"` // Environment targets using the connection's credentials
+}
+
+// ProviderConnectionTarget identifies an environment target that uses a provider connection
+type ProviderConnectionTarget struct {
+	EnvironmentID uuid.UUID `json:"environment_id"`
+	Name          string    `json:"name"`
+}
+
+// CreateProviderConnectionGrantRequest represents the request to let a secret group or an environment use a connection
+type CreateProviderConnectionGrantRequest struct {
+	SecretGroupID *uuid.UUID `json:"secret_group_id,omitempty"` // Covers every environment of the secret group
+	EnvironmentID *uuid.UUID `json:"environment_id,omitempty"`
 }
 
-// GitHubCredentials represents GitHub Personal Access Token credentials
+// ProviderConnectionGrantResponse represents a grant to use a provider connection
+type ProviderConnectionGrantResponse struct {
+	ID            string     `json:"id"`
+	ConnectionID  uuid.UUID  `json:"connection_id"`
+	SecretGroupID *uuid.UUID `json:"secret_group_id,omitempty"`
+	EnvironmentID *uuid.UUID `json:"environment_id,omitempty"`
+	GrantedBy     *uuid.UUID `json:"granted_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// UpdateAutoSyncRequest represents the request to change the auto-sync settings of a provider
+type UpdateAutoSyncRequest struct {
+	Enabled         *bool `json:"enabled" binding:"required"`
+	DebounceSeconds *int  `json:"debounce_seconds,omitempty"` // Defaults to DefaultAutoSyncDebounceSeconds
+}
+
+// DefaultAutoSyncDebounceSeconds is how long auto-sync waits for further versions unless configured otherwise
+const DefaultAutoSyncDebounceSeconds = 60
+
+// MaxAutoSyncDebounceSeconds is the longest a provider may wait for further versions before auto-syncing
+const MaxAutoSyncDebounceSeconds = 3600
+
+// AutoSyncSettings controls whether new versions of an environment are synced to a provider automatically.
+// The sync waits DebounceSeconds for further versions so bursts coalesce into one sync.
+type AutoSyncSettings struct {
+	Enabled         bool `json:"enabled"`
+	DebounceSeconds int  `json:"debounce_seconds"`
+}
+
+// SyncOptions are the settings of a provider credential that change how a sync behaves
+type SyncOptions struct {
+	Provider   ProviderType // Provider type of the target
+	MirrorMode bool
+	KeyMapping KeyMappingRules // Applied to the version before its secrets reach the provider
+	UpdatedAt  time.Time       // When the credential, its settings or its connection last changed
+}
+
+// UpdateMirrorModeRequest represents the request to enable or disable mirror mode for a provider
+type UpdateMirrorModeRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// GitHub auth methods
+const (
+	GitHubAuthToken = "token" // A personal access token (default)
+	GitHubAuthApp   = "app"   // A GitHub App installation, authenticated with the app's private key
+)
+
+// GitHubCredentials represents a GitHub personal access token or GitHub App credentials
 type GitHubCredentials struct {
-	Token string `json:"token" binding:"required"`
+	AuthMethod     string `json:"auth_method,omitempty"`     // token or app
+	Token          string `json:"token,omitempty"`           // Personal access token for token auth
+	AppID          int64  `json:"app_id,omitempty"`          // GitHub App ID for app auth
+	InstallationID int64  `json:"installation_id,omitempty"` // Installation of the app on the owner for app auth
+	PrivateKey     string `json:"private_key,omitempty"`     // PEM-encoded app private key for app auth
 }
 
 // RetryConfig represents retry configuration for provider operations
@@ -43,22 +139,60 @@ type RetryConfig struct {
 	BackoffType string        `json:"backoff_type"` // "exponential", "linear", "constant"
 }
 
+// GitHub secret scopes
+const (
+	GitHubScopeRepository   = "repository"   // Secrets belong to the repository or one of its environments (default)
+	GitHubScopeOrganization = "organization" // Secrets belong to the organization named by the owner
+)
+
+// GitHub secret types
+const (
+	GitHubSecretTypeActions    = "actions"    // GitHub Actions secrets (default)
+	GitHubSecretTypeDependabot = "dependabot" // Secrets available to Dependabot updates
+	GitHubSecretTypeCodespaces = "codespaces" // Secrets available to Codespaces
+)
+
+// GitHub organization secret visibilities
+const (
+	GitHubVisibilityAll      = "all"      // Every repository in the organization
+	GitHubVisibilityPrivate  = "private"  // Private and internal repositories (default)
+	GitHubVisibilitySelected = "selected" // Only the selected repositories
+)
+
 // GitHubConfig represents GitHub-specific configuration
 type GitHubConfig struct {
-	Owner            string      `json:"owner" binding:"required"`
-	Repository       string      `json:"repository" binding:"required"`
-	Environment      string      `json:"environment,omitempty"`
-	SecretVisibility string      `json:"secret_visibility,omitempty"` // all, selected, private
-	RetryConfig      RetryConfig `json:"retry_config,omitempty"`
+	Owner                string      `json:"owner" binding:"required"`
+	Repository           string      `json:"repository,omitempty"` // Required unless the scope is organization
+	Environment          string      `json:"environment,omitempty"`
+	Scope                string      `json:"scope,omitempty"`                 // repository, organization
+	SecretType           string      `json:"secret_type,omitempty"`           // actions, dependabot, codespaces
+	SecretVisibility     string      `json:"secret_visibility,omitempty"`     // all, selected, private; organization scope only
+	SelectedRepositories []string    `json:"selected_repositories,omitempty"` // Repository names for selected visibility
+	VariableKeys         []string    `json:"variable_keys,omitempty"`         // Keys written as Actions variables instead of secrets
+	Prefix               string      `json:"prefix,omitempty"`                // Marks the secrets managed by Kavach; required for mirror mode
+	RetryConfig          RetryConfig `json:"retry_config,omitempty"`
 }
 
-// GCPCredentials represents GCP Service Account key credentials
+// GCP auth methods
+const (
+	GCPAuthServiceAccountKey = "service_account_key" // A stored service account JSON key (default)
+	GCPAuthWorkloadIdentity  = "workload_identity"   // Kavach workload tokens exchanged through Workload Identity Federation
+)
+
+// GCPCredentials represents GCP Service Account key credentials, or the Workload Identity Federation
+// settings used instead of a key
 type GCPCredentials struct {
-	Type                    string `json:"type" binding:"required"`
-	ProjectID               string `json:"project_id" binding:"required"`
-	PrivateKeyID            string `json:"private_key_id" binding:"required"`
-	PrivateKey              string `json:"private_key" binding:"required"`
-	ClientEmail             string `json:"client_email" binding:"required"`
+	AuthMethod string `json:"auth_method,omitempty"` // service_account_key or workload_identity
+	// Workload identity pool provider, such as //iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/kavach/providers/kavach
+	Audience            string `json:"audience,omitempty"`
+	ServiceAccountEmail string `json:"service_account_email,omitempty"` // Service account to impersonate; the federated principal is used directly otherwise
+
+	// Service account key fields, required for service_account_key auth
+	Type                    string `json:"type,omitempty"`
+	ProjectID               string `json:"project_id,omitempty"`
+	PrivateKeyID            string `json:"private_key_id,omitempty"`
+	PrivateKey              string `json:"private_key,omitempty"`
+	ClientEmail             string `json:"client_email,omitempty"`
 	ClientID                string `json:"client_id,omitempty"`
 	AuthURI                 string `json:"auth_uri,omitempty"`
 	TokenURI                string `json:"token_uri,omitempty"`
@@ -76,11 +210,18 @@ type GCPConfig struct {
 	RetryConfig           RetryConfig `json:"retry_config,omitempty"`
 }
 
+// Azure auth methods
+const (
+	AzureAuthClientSecret = "client_secret" // A stored service principal client secret (default)
+	AzureAuthFederated    = "federated"     // Kavach workload tokens presented through a federated identity credential
+)
+
 // AzureCredentials represents Azure Service Principal credentials
 type AzureCredentials struct {
+	AuthMethod   string `json:"auth_method,omitempty"` // client_secret or federated
 	TenantID     string `json:"tenant_id" binding:"required"`
 	ClientID     string `json:"client_id" binding:"required"`
-	ClientSecret string `json:"client_secret" binding:"required"`
+	ClientSecret string `json:"client_secret,omitempty"` // Required for client_secret auth
 }
 
 // AzureConfig represents Azure-specific configuration
@@ -93,6 +234,154 @@ type AzureConfig struct {
 	RetryConfig          RetryConfig `json:"retry_config,omitempty"`
 }
 
+// AWSCredentials represents AWS access keys, optionally used to assume a role
+type AWSCredentials struct {
+	AccessKeyID     string `json:"access_key_id" binding:"required"`
+	SecretAccessKey string `json:"secret_access_key" binding:"required"`
+	SessionToken    string `json:"session_token,omitempty"`
+	RoleARN         string `json:"role_arn,omitempty"`          // If set, the access keys assume this role
+	ExternalID      string `json:"external_id,omitempty"`       // External ID required by the role's trust policy
+	RoleSessionName string `json:"role_session_name,omitempty"` // Defaults to kavach-backend
+}
+
+// AWS Secrets Manager storage modes
+const (
+	AWSStorageModePerKey = "per_key" // Each key is stored as its own secret (default)
+	AWSStorageModeJSON   = "json"    // All keys are stored as one JSON secret
+)
+
+// AWSSecretsManagerConfig represents AWS Secrets Manager-specific configuration
+type AWSSecretsManagerConfig struct {
+	Region      string            `json:"region" binding:"required"`
+	Prefix      string            `json:"prefix,omitempty"`       // Path the secret names are stored under, such as myapp/prod
+	KMSKeyID    string            `json:"kms_key_id,omitempty"`   // Customer managed key for new secrets; the AWS managed key otherwise
+	Tags        map[string]string `json:"tags,omitempty"`         // Tags added to new secrets
+	StorageMode string            `json:"storage_mode,omitempty"` // per_key or json
+	SecretName  string            `json:"secret_name,omitempty"`  // Name of the JSON secret; required in json mode
+	Endpoint    string            `json:"endpoint,omitempty"`     // Overrides the Secrets Manager endpoint, such as a local stand-in
+	RetryConfig RetryConfig       `json:"retry_config,omitempty"`
+}
+
+// AWSSSMConfig represents AWS SSM Parameter Store-specific configuration
+type AWSSSMConfig struct {
+	Region      string            `json:"region" binding:"required"`
+	Path        string            `json:"path" binding:"required"` // Hierarchy the parameters are stored under, such as /org/group/env
+	KMSKeyID    string            `json:"kms_key_id,omitempty"`    // Customer managed key; the AWS managed key otherwise
+	Tier        string            `json:"tier,omitempty"`          // Standard (default), Advanced or Intelligent-Tiering
+	Tags        map[string]string `json:"tags,omitempty"`          // Tags added to new parameters
+	Endpoint    string            `json:"endpoint,omitempty"`      // Overrides the SSM endpoint, such as a local stand-in
+	RetryConfig RetryConfig       `json:"retry_config,omitempty"`
+}
+
+// Vault auth methods
+const (
+	VaultAuthToken      = "token"      // A Vault token used as is (default)
+	VaultAuthAppRole    = "approle"    // Logs in with a role ID and secret ID
+	VaultAuthKubernetes = "kubernetes" // Logs in with a Kubernetes service account token
+)
+
+// Vault storage modes
+const (
+	VaultStorageModeSingle = "single"  // All keys are stored as fields of one secret (default)
+	VaultStorageModePerKey = "per_key" // Each key is stored at its own path
+)
+
+// VaultCredentials represents the credentials used to authenticate to Vault
+type VaultCredentials struct {
+	AuthMethod string `json:"auth_method,omitempty"` // token, approle or kubernetes
+	Token      string `json:"token,omitempty"`       // Token auth
+	RoleID     string `json:"role_id,omitempty"`     // AppRole auth
+	SecretID   string `json:"secret_id,omitempty"`   // AppRole auth
+	Role       string `json:"role,omitempty"`        // Kubernetes auth role
+	JWT        string `json:"jwt,omitempty"`         // Kubernetes auth; the pod's service account token otherwise
+	AuthMount  string `json:"auth_mount,omitempty"`  // Auth method mount path; defaults to the method name
+}
+
+// VaultConfig represents Vault KV v2-specific configuration
+type VaultConfig struct {
+	Address     string      `json:"address" binding:"required"` // Vault address, such as https://vault.example.com:8200
+	Namespace   string      `json:"namespace,omitempty"`        // Vault Enterprise namespace
+	Mount       string      `json:"mount,omitempty"`            // KV v2 mount path; defaults to secret
+	Path        string      `json:"path" binding:"required"`    // Secret path within the mount, or the parent path in per_key mode
+	StorageMode string      `json:"storage_mode,omitempty"`     // single or per_key
+	CheckAndSet bool        `json:"check_and_set,omitempty"`    // If true, writes only apply to the version that was read
+	RetryConfig RetryConfig `json:"retry_config,omitempty"`
+}
+
+// KubernetesCredentials represents the credentials used to reach a Kubernetes API server,
+// either a kubeconfig or a server URL with a bearer token
+type KubernetesCredentials struct {
+	Kubeconfig string `json:"kubeconfig,omitempty"` // Contents of a kubeconfig file
+	Context    string `json:"context,omitempty"`    // kubeconfig context; the current context otherwise
+	Server     string `json:"server,omitempty"`     // API server URL, used with token
+	Token      string `json:"token,omitempty"`      // Bearer token, such as a service account token
+	CAData     string `json:"ca_data,omitempty"`    // PEM-encoded CA certificate of the API server
+}
+
+// KubernetesConfig represents Kubernetes Secret-specific configuration
+type KubernetesConfig struct {
+	Namespace          string            `json:"namespace" binding:"required"`
+	SecretName         string            `json:"secret_name" binding:"required"`
+	SecretType         string            `json:"secret_type,omitempty"`         // Type of a new Secret; defaults to Opaque
+	Labels             map[string]string `json:"labels,omitempty"`              // Labels set on the Secret
+	Annotations        map[string]string `json:"annotations,omitempty"`         // Annotations set on the Secret
+	RestartDeployments []string          `json:"restart_deployments,omitempty"` // Deployments in the namespace restarted when a sync changes the Secret
+	RetryConfig        RetryConfig       `json:"retry_config,omitempty"`
+}
+
+// GitLab CI/CD variable types
+const (
+	GitLabVariableTypeEnv  = "env_var" // The value is exposed as an environment variable (default)
+	GitLabVariableTypeFile = "file"    // The value is written to a file whose path is the variable
+)
+
+// GitLabCredentials represents a GitLab access token with the api scope
+type GitLabCredentials struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// GitLabConfig represents GitLab-specific configuration. Either a project or a group is set.
+type GitLabConfig struct {
+	BaseURL          string      `json:"base_url,omitempty"`          // GitLab instance URL; defaults to https://gitlab.com
+	ProjectID        string      `json:"project_id,omitempty"`        // Numeric ID or full path, such as acme/payments
+	GroupID          string      `json:"group_id,omitempty"`          // Numeric ID or full path of a group, instead of a project
+	EnvironmentScope string      `json:"environment_scope,omitempty"` // Environments the variables apply to; defaults to *
+	Prefix           string      `json:"prefix,omitempty"`            // Prepended to variable keys
+	Masked           bool        `json:"masked,omitempty"`            // If true, values are masked in job logs
+	Protected        bool        `json:"protected,omitempty"`         // If true, variables are only exposed to protected branches and tags
+	VariableType     string      `json:"variable_type,omitempty"`     // env_var or file
+	FileKeys         []string    `json:"file_keys,omitempty"`         // Keys stored as file variables whatever the variable type
+	RetryConfig      RetryConfig `json:"retry_config,omitempty"`
+}
+
+// Webhook payload modes
+const (
+	WebhookPayloadFull   = "full"    // Every secret of the sync in one request (default)
+	WebhookPayloadPerKey = "per_key" // One request per secret
+)
+
+// WebhookEncryptionSealedBox encrypts each value to the receiver's X25519 public key with a libsodium sealed box
+const WebhookEncryptionSealedBox = "x25519_sealed_box"
+
+// WebhookCredentials represents the signing secret of a webhook, with an optional client certificate for mTLS
+type WebhookCredentials struct {
+	SigningSecret     string            `json:"signing_secret" binding:"required"` // HMAC-SHA256 key the receiver verifies requests with
+	ClientCertificate string            `json:"client_certificate,omitempty"`      // PEM-encoded client certificate for mTLS
+	ClientKey         string            `json:"client_key,omitempty"`              // PEM-encoded private key of the client certificate
+	Headers           map[string]string `json:"headers,omitempty"`                 // Sensitive headers, such as Authorization
+}
+
+// WebhookConfig represents webhook-specific configuration
+type WebhookConfig struct {
+	URL                string            `json:"url" binding:"required"`         // HTTPS endpoint the secrets are posted to
+	PayloadMode        string            `json:"payload_mode,omitempty"`         // full or per_key
+	Encryption         string            `json:"encryption,omitempty"`           // x25519_sealed_box; values are sent in plain text otherwise
+	RecipientPublicKey string            `json:"recipient_public_key,omitempty"` // Base64 X25519 public key values are sealed to
+	CACertificate      string            `json:"ca_certificate,omitempty"`       // PEM-encoded CA trusted for the endpoint, in addition to the system roots
+	Headers            map[string]string `json:"headers,omitempty"`              // Added to every request
+	RetryConfig        RetryConfig       `json:"retry_config,omitempty"`
+}
+
 // SyncRequest represents the request to sync secrets to a provider
 type SyncRequest struct {
 	Provider  ProviderType `json:"provider" binding:"required"`
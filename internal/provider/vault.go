@@ -0,0 +1,737 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Custom metadata marking the secrets created by Kavach
+const (
+	vaultManagedByKey   = "managed-by"
+	vaultManagedByValue = "kavach-backend"
+)
+
+// vaultKavachVersionKey is the custom metadata entry recording the version Kavach last wrote, which
+// check-and-set writes expect to find
+const vaultKavachVersionKey = "kavach-version"
+
+// vaultDefaultMount is the KV v2 mount used when none is configured
+const vaultDefaultMount = "secret"
+
+// vaultValueField is the field holding a key's value in per_key mode
+const vaultValueField = "value"
+
+// vaultServiceAccountTokenPath is where Kubernetes mounts the pod's service account token
+const vaultServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// vaultTokenRenewMargin is how long before it expires a login token is replaced
+const vaultTokenRenewMargin = 30 * time.Second
+
+// VaultProvider implements ProviderSync for a HashiCorp Vault KV v2 mount. Keys are stored as
+// fields of one secret or at one path each, depending on the storage mode.
+type VaultProvider struct {
+	credentials VaultCredentials
+	config      VaultConfig
+	logger      *logrus.Logger
+	httpClient  *http.Client
+	executor    *SyncExecutor
+	owner       SecretOwner // Environment and target recorded on created secrets
+
+	// Token used for requests; AppRole and Kubernetes auth log in again shortly before it expires
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// vaultResponseError is a failed Vault API response
+type vaultResponseError struct {
+	StatusCode int
+	Header     http.Header
+	Errors     []string
+}
+
+// Error returns the status and the errors Vault reported
+func (e *vaultResponseError) Error() string {
+	return fmt.Sprintf("vault returned status %d: %s", e.StatusCode, strings.Join(e.Errors, "; "))
+}
+
+// checkAndSetMismatch reports whether a write was rejected because the secret changed since it was read
+func (e *vaultResponseError) checkAndSetMismatch() bool {
+	for _, message := range e.Errors {
+		if strings.Contains(message, "check-and-set") {
+			return true
+		}
+	}
+	return false
+}
+
+// vaultSecret is the data and version metadata of a KV v2 secret
+type vaultSecret struct {
+	Data     map[string]interface{} `json:"data"`
+	Metadata struct {
+		Version     int        `json:"version"`
+		CreatedTime *time.Time `json:"created_time"`
+	} `json:"metadata"`
+}
+
+// vaultSecretMetadata is the metadata shared by all versions of a KV v2 secret
+type vaultSecretMetadata struct {
+	CurrentVersion int               `json:"current_version"`
+	CustomMetadata map[string]string `json:"custom_metadata"`
+}
+
+// NewVaultProvider creates a new Vault KV v2 provider instance
+func NewVaultProvider(credentials map[string]interface{}, config map[string]interface{}, logger *logrus.Logger) (*VaultProvider, error) {
+	// Parse credentials
+	credBytes, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	var vaultCreds VaultCredentials
+	if err := json.Unmarshal(credBytes, &vaultCreds); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Vault credentials: %w", err)
+	}
+	switch vaultCreds.AuthMethod {
+	case "":
+		vaultCreds.AuthMethod = VaultAuthToken
+	case VaultAuthToken, VaultAuthAppRole, VaultAuthKubernetes:
+	default:
+		return nil, fmt.Errorf("unsupported Vault auth method: %s", vaultCreds.AuthMethod)
+	}
+
+	// Parse config
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var vaultConfig VaultConfig
+	if err := json.Unmarshal(configBytes, &vaultConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Vault config: %w", err)
+	}
+	vaultConfig.Address = strings.TrimSuffix(vaultConfig.Address, "/")
+	vaultConfig.Mount = strings.Trim(vaultConfig.Mount, "/")
+	if vaultConfig.Mount == "" {
+		vaultConfig.Mount = vaultDefaultMount
+	}
+	vaultConfig.Path = strings.Trim(vaultConfig.Path, "/")
+	if vaultConfig.StorageMode == "" {
+		vaultConfig.StorageMode = VaultStorageModeSingle
+	}
+
+	provider := &VaultProvider{
+		credentials: vaultCreds,
+		config:      vaultConfig,
+		logger:      logger,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		executor:    NewSyncExecutor(DefaultSyncExecutorConfig(), logger),
+	}
+	if vaultCreds.AuthMethod == VaultAuthToken {
+		provider.token = vaultCreds.Token
+	}
+
+	return provider, nil
+}
+
+// Sync syncs secrets to Vault
+func (v *VaultProvider) Sync(ctx context.Context, secrets []Secret) ([]SyncResult, error) {
+	logEntry := v.logger.WithFields(logrus.Fields{
+		"provider":     "vault",
+		"mount":        v.config.Mount,
+		"path":         v.config.Path,
+		"storage_mode": v.config.StorageMode,
+		"secret_count": len(secrets),
+	})
+
+	logEntry.Info("Starting Vault sync")
+
+	var results []SyncResult
+	if v.config.StorageMode == VaultStorageModeSingle {
+		// All keys go into one secret, so they are written together and share the outcome
+		results = v.updateSecret(ctx, secrets, func(data map[string]interface{}) {
+			for _, secret := range secrets {
+				data[secret.Name] = secret.Value
+			}
+		})
+	} else {
+		results = v.executor.Run(ctx, ProviderVault, v.config.RetryConfig, secrets, func(ctx context.Context, secret Secret) error {
+			return v.putKey(ctx, v.RemoteName(secret.Name), secret.Value)
+		})
+	}
+
+	for _, result := range results {
+		if !result.Success {
+			logEntry.WithFields(logrus.Fields{
+				"secret_name": result.Name,
+				"error":       result.Error,
+			}).Error("Failed to sync secret to Vault after all retry attempts")
+		}
+	}
+
+	logEntry.WithFields(logrus.Fields{
+		"synced_count": len(results),
+		"total_count":  len(secrets),
+	}).Info("Completed Vault sync")
+
+	return results, nil
+}
+
+// Delete removes secrets from Vault
+func (v *VaultProvider) Delete(ctx context.Context, names []string) ([]SyncResult, error) {
+	remoteNames := make([]string, len(names))
+	for i, name := range names {
+		remoteNames[i] = v.RemoteName(name)
+	}
+
+	results := v.deleteSecrets(ctx, remoteNames)
+	for i := range results {
+		results[i].Name = names[i]
+	}
+	return results, nil
+}
+
+// RemoteName returns the path a secret is stored at within the mount. In single mode this is
+// the field of the secret holding the key.
+func (v *VaultProvider) RemoteName(name string) string {
+	if v.config.StorageMode == VaultStorageModeSingle {
+		return name
+	}
+	return v.config.Path + "/" + sanitizeVaultKey(name)
+}
+
+// List returns the keys stored under the configured path, or the fields of the secret in single mode
+func (v *VaultProvider) List(ctx context.Context) ([]RemoteSecret, error) {
+	if v.config.StorageMode == VaultStorageModeSingle {
+		secret, _, err := v.readSecret(ctx, v.config.Path)
+		if err != nil {
+			return nil, err
+		}
+		secrets := make([]RemoteSecret, 0, len(secret.Data))
+		for name := range secret.Data {
+			secrets = append(secrets, RemoteSecret{Name: name, UpdatedAt: secret.Metadata.CreatedTime})
+		}
+		sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+		return secrets, nil
+	}
+
+	paths, err := v.listKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]RemoteSecret, len(paths))
+	for i, path := range paths {
+		secrets[i] = RemoteSecret{Name: path}
+	}
+	return secrets, nil
+}
+
+// Read returns the current value of each named secret
+func (v *VaultProvider) Read(ctx context.Context, remoteNames []string) ([]RemoteSecret, error) {
+	if v.config.StorageMode == VaultStorageModeSingle {
+		secret, _, err := v.readSecret(ctx, v.config.Path)
+		if err != nil {
+			return nil, err
+		}
+		var secrets []RemoteSecret
+		for _, name := range remoteNames {
+			if value, ok := secret.Data[name]; ok {
+				secrets = append(secrets, RemoteSecret{Name: name, Value: vaultValueString(value), HasValue: true, UpdatedAt: secret.Metadata.CreatedTime})
+			}
+		}
+		return secrets, nil
+	}
+
+	var secrets []RemoteSecret
+	for _, name := range remoteNames {
+		secret, exists, err := v.readSecret(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		value, ok := secret.Data[vaultValueField]
+		if !exists || !ok {
+			continue
+		}
+
+		secrets = append(secrets, RemoteSecret{
+			Name:      name,
+			Value:     vaultValueString(value),
+			HasValue:  true,
+			UpdatedAt: secret.Metadata.CreatedTime,
+		})
+	}
+
+	return secrets, nil
+}
+
+// SetOwner sets the environment and target recorded on created secrets and required for pruning
+func (v *VaultProvider) SetOwner(owner SecretOwner) {
+	v.owner = owner
+}
+
+// PruneCandidates returns the secrets under the configured path marked as created by Kavach for the
+// owning environment and target that are not among the given secret names. In single mode the whole
+// secret belongs to the target that created it, so every other field in it is a candidate.
+func (v *VaultProvider) PruneCandidates(ctx context.Context, keep []string) ([]string, error) {
+	if !v.owner.IsSet() {
+		return nil, errSecretOwnerUnset
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		kept[v.RemoteName(name)] = true
+	}
+
+	var candidates []string
+	if v.config.StorageMode == VaultStorageModeSingle {
+		metadata, exists, err := v.readMetadata(ctx, v.config.Path)
+		if err != nil || !exists || !v.ownsSecret(metadata) {
+			return nil, err
+		}
+		secret, _, err := v.readSecret(ctx, v.config.Path)
+		if err != nil {
+			return nil, err
+		}
+		for name := range secret.Data {
+			if !kept[name] {
+				candidates = append(candidates, name)
+			}
+		}
+		sort.Strings(candidates)
+		return candidates, nil
+	}
+
+	paths, err := v.listKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if kept[path] {
+			continue
+		}
+		metadata, exists, err := v.readMetadata(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		if exists && v.ownsSecret(metadata) {
+			candidates = append(candidates, path)
+		}
+	}
+	return candidates, nil
+}
+
+// Prune deletes the given secrets, or the given fields of the secret in single mode
+func (v *VaultProvider) Prune(ctx context.Context, remoteNames []string) ([]SyncResult, error) {
+	results := v.deleteSecrets(ctx, remoteNames)
+
+	v.logger.WithFields(logrus.Fields{
+		"provider":     "vault",
+		"mount":        v.config.Mount,
+		"path":         v.config.Path,
+		"pruned_count": len(results),
+	}).Info("Completed Vault prune")
+	return results, nil
+}
+
+// ValidateCredentials validates Vault credentials by logging in and looking up the token
+func (v *VaultProvider) ValidateCredentials(ctx context.Context) error {
+	logEntry := v.logger.WithFields(logrus.Fields{
+		"provider":    "vault",
+		"address":     v.config.Address,
+		"auth_method": v.credentials.AuthMethod,
+	})
+
+	logEntry.Info("Validating Vault credentials")
+
+	if err := v.do(ctx, http.MethodGet, "auth/token/lookup-self", nil, nil); err != nil {
+		return fmt.Errorf("failed to validate Vault credentials: %w", err)
+	}
+
+	logEntry.Info("Vault credentials validated successfully")
+	return nil
+}
+
+// GetProviderName returns the provider name
+func (v *VaultProvider) GetProviderName() string {
+	return "vault"
+}
+
+// putKey writes a key's value to its own path in per_key mode
+func (v *VaultProvider) putKey(ctx context.Context, path, value string) error {
+	metadata, exists, err := v.readMetadata(ctx, path)
+	if err != nil {
+		return err
+	}
+	version, err := v.checkAndSetVersion(path, metadata, exists)
+	if err != nil {
+		return err
+	}
+
+	return v.writeSecret(ctx, path, map[string]interface{}{vaultValueField: value}, version, metadata)
+}
+
+// updateSecret applies a change to the data of the secret in single mode and writes it back once.
+// Every given secret shares the outcome of that write.
+func (v *VaultProvider) updateSecret(ctx context.Context, secrets []Secret, change func(data map[string]interface{})) []SyncResult {
+	document := []Secret{{Name: v.config.Path}}
+	outcome := v.executor.Run(ctx, ProviderVault, v.config.RetryConfig, document, func(ctx context.Context, _ Secret) error {
+		// Read on every attempt so a retry after a transient failure keeps fields written in between
+		current, _, err := v.readSecret(ctx, v.config.Path)
+		if err != nil {
+			return err
+		}
+		metadata, exists, err := v.readMetadata(ctx, v.config.Path)
+		if err != nil {
+			return err
+		}
+		version, err := v.checkAndSetVersion(v.config.Path, metadata, exists)
+		if err != nil {
+			return err
+		}
+		change(current.Data)
+		return v.writeSecret(ctx, v.config.Path, current.Data, version, metadata)
+	})
+
+	results := make([]SyncResult, len(secrets))
+	for i, secret := range secrets {
+		results[i] = SyncResult{Name: secret.Name, Success: outcome[0].Success, Error: outcome[0].Error}
+	}
+	return results
+}
+
+// checkAndSetVersion returns the version a check-and-set write to the secret at path must find: the
+// version Kavach last wrote, or 0 when the secret does not exist yet. A secret changed outside Kavach
+// since then is a conflict, which is left for the user to resolve rather than overwritten. Secrets
+// marked as managed before Kavach recorded the versions it wrote are taken at their current version.
+func (v *VaultProvider) checkAndSetVersion(path string, metadata vaultSecretMetadata, exists bool) (int, error) {
+	if !v.config.CheckAndSet || !exists {
+		return 0, nil
+	}
+
+	written, recorded := metadata.CustomMetadata[vaultKavachVersionKey]
+	if !recorded {
+		if metadata.CustomMetadata[vaultManagedByKey] == vaultManagedByValue {
+			return metadata.CurrentVersion, nil
+		}
+		return 0, fmt.Errorf("the Vault secret '%s' was not written by Kavach: %w", path, appErrors.ErrVaultSecretChanged)
+	}
+	if written != strconv.Itoa(metadata.CurrentVersion) {
+		return 0, fmt.Errorf("the Vault secret '%s' is at version %d but Kavach last wrote version %s: %w",
+			path, metadata.CurrentVersion, written, appErrors.ErrVaultSecretChanged)
+	}
+	return metadata.CurrentVersion, nil
+}
+
+// writeSecret writes data as a new version of the secret at path, whose metadata was read before. With
+// check-and-set enabled the write only applies while the secret is still at the given version, where 0
+// means it must not exist yet. The written version is recorded in the custom metadata, and new secrets
+// are marked as managed by Kavach and with the owner.
+func (v *VaultProvider) writeSecret(ctx context.Context, path string, data map[string]interface{}, version int, metadata vaultSecretMetadata) error {
+	body := map[string]interface{}{"data": data}
+	if v.config.CheckAndSet {
+		body["options"] = map[string]interface{}{"cas": version}
+	}
+
+	var resp struct {
+		Data struct {
+			Version int `json:"version"`
+		} `json:"data"`
+	}
+	if err := v.do(ctx, http.MethodPost, v.config.Mount+"/data/"+path, body, &resp); err != nil {
+		var vaultErr *vaultResponseError
+		if errors.As(err, &vaultErr) && vaultErr.checkAndSetMismatch() {
+			return fmt.Errorf("the Vault secret '%s' changed while it was written: %w", path, appErrors.ErrVaultSecretChanged)
+		}
+		return fmt.Errorf("failed to write Vault secret '%s': %w", path, err)
+	}
+
+	// Vault replaces the custom metadata as a whole, so entries set by others are carried over
+	customMetadata := maps.Clone(metadata.CustomMetadata)
+	if customMetadata == nil {
+		customMetadata = map[string]string{}
+	}
+	if resp.Data.Version == 1 {
+		customMetadata[vaultManagedByKey] = vaultManagedByValue
+		maps.Copy(customMetadata, v.owner.Markers())
+	}
+	customMetadata[vaultKavachVersionKey] = strconv.Itoa(resp.Data.Version)
+	body = map[string]interface{}{"custom_metadata": customMetadata}
+	if err := v.do(ctx, http.MethodPost, v.config.Mount+"/metadata/"+path, body, nil); err != nil {
+		return fmt.Errorf("failed to record the written version of Vault secret '%s': %w", path, err)
+	}
+
+	v.logger.WithField("secret_path", path).Info("Successfully wrote Vault secret")
+	return nil
+}
+
+// ownsSecret reports whether the custom metadata marks a secret as created by Kavach for the owning
+// environment and target
+func (v *VaultProvider) ownsSecret(metadata vaultSecretMetadata) bool {
+	return metadata.CustomMetadata[vaultManagedByKey] == vaultManagedByValue && v.owner.Owns(metadata.CustomMetadata)
+}
+
+// readSecret returns the latest version of the secret at path, and whether it exists. A secret
+// whose latest version was deleted has no data but still reports that version.
+func (v *VaultProvider) readSecret(ctx context.Context, path string) (vaultSecret, bool, error) {
+	var resp struct {
+		Data vaultSecret `json:"data"`
+	}
+	err := v.do(ctx, http.MethodGet, v.config.Mount+"/data/"+path, nil, &resp)
+	if err == nil {
+		if resp.Data.Data == nil {
+			resp.Data.Data = map[string]interface{}{}
+		}
+		return resp.Data, true, nil
+	}
+	if !vaultNotFound(err) {
+		return vaultSecret{}, false, fmt.Errorf("failed to read Vault secret '%s': %w", path, err)
+	}
+
+	secret := vaultSecret{Data: map[string]interface{}{}}
+	metadata, _, err := v.readMetadata(ctx, path)
+	if err != nil {
+		return vaultSecret{}, false, err
+	}
+	secret.Metadata.Version = metadata.CurrentVersion
+	return secret, false, nil
+}
+
+// readMetadata returns the metadata of the secret at path, and whether it exists
+func (v *VaultProvider) readMetadata(ctx context.Context, path string) (vaultSecretMetadata, bool, error) {
+	var resp struct {
+		Data vaultSecretMetadata `json:"data"`
+	}
+	if err := v.do(ctx, http.MethodGet, v.config.Mount+"/metadata/"+path, nil, &resp); err != nil {
+		if vaultNotFound(err) {
+			return vaultSecretMetadata{}, false, nil
+		}
+		return vaultSecretMetadata{}, false, fmt.Errorf("failed to read Vault metadata of '%s': %w", path, err)
+	}
+	return resp.Data, true, nil
+}
+
+// listKeys returns the paths of the secrets directly under the configured path
+func (v *VaultProvider) listKeys(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+	if err := v.do(ctx, "LIST", v.config.Mount+"/metadata/"+v.config.Path, nil, &resp); err != nil {
+		if vaultNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list Vault secrets: %w", err)
+	}
+
+	var paths []string
+	for _, key := range resp.Data.Keys {
+		// Keys ending in a slash are folders, not secrets
+		if !strings.HasSuffix(key, "/") {
+			paths = append(paths, v.config.Path+"/"+key)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// deleteSecrets permanently deletes secrets with all their versions, or fields of the secret in
+// single mode. Secrets that no longer exist are reported as successfully removed.
+func (v *VaultProvider) deleteSecrets(ctx context.Context, remoteNames []string) []SyncResult {
+	logEntry := v.logger.WithFields(logrus.Fields{
+		"provider":     "vault",
+		"mount":        v.config.Mount,
+		"path":         v.config.Path,
+		"secret_count": len(remoteNames),
+	})
+
+	if v.config.StorageMode == VaultStorageModeSingle {
+		removed := make([]Secret, len(remoteNames))
+		for i, name := range remoteNames {
+			removed[i] = Secret{Name: name}
+		}
+		return v.updateSecret(ctx, removed, func(data map[string]interface{}) {
+			for _, name := range remoteNames {
+				delete(data, name)
+			}
+		})
+	}
+
+	results := make([]SyncResult, 0, len(remoteNames))
+	for _, name := range remoteNames {
+		result := SyncResult{Name: name, Success: true}
+
+		err := v.do(ctx, http.MethodDelete, v.config.Mount+"/metadata/"+name, nil, nil)
+		if err != nil && !vaultNotFound(err) {
+			result.Success = false
+			result.Error = fmt.Sprintf("failed to delete secret: %v", err)
+			logEntry.WithFields(logrus.Fields{
+				"secret_path": name,
+				"error":       err.Error(),
+			}).Error("Failed to delete secret from Vault")
+		}
+
+		results = append(results, result)
+	}
+
+	logEntry.Info("Completed Vault secrets removal")
+	return results
+}
+
+// do sends an authenticated request to the Vault API
+func (v *VaultProvider) do(ctx context.Context, method, path string, body, out interface{}) error {
+	token, err := v.clientToken(ctx)
+	if err != nil {
+		return err
+	}
+	return v.send(ctx, method, path, token, body, out)
+}
+
+// clientToken returns the token for requests, logging in first when there is none or it is about
+// to expire
+func (v *VaultProvider) clientToken(ctx context.Context) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.token != "" && (v.tokenExpiry.IsZero() || time.Now().Before(v.tokenExpiry)) {
+		return v.token, nil
+	}
+
+	var body map[string]interface{}
+	switch v.credentials.AuthMethod {
+	case VaultAuthAppRole:
+		body = map[string]interface{}{"role_id": v.credentials.RoleID, "secret_id": v.credentials.SecretID}
+	case VaultAuthKubernetes:
+		jwt := v.credentials.JWT
+		if jwt == "" {
+			token, err := os.ReadFile(vaultServiceAccountTokenPath)
+			if err != nil {
+				return "", fmt.Errorf("failed to read Kubernetes service account token: %w", err)
+			}
+			jwt = strings.TrimSpace(string(token))
+		}
+		body = map[string]interface{}{"role": v.credentials.Role, "jwt": jwt}
+	default:
+		return "", fmt.Errorf("vault token is empty")
+	}
+
+	authMount := strings.Trim(v.credentials.AuthMount, "/")
+	if authMount == "" {
+		authMount = v.credentials.AuthMethod
+	}
+
+	var resp struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := v.send(ctx, http.MethodPost, "auth/"+authMount+"/login", "", body, &resp); err != nil {
+		return "", fmt.Errorf("failed to log in to Vault with %s auth: %w", v.credentials.AuthMethod, err)
+	}
+
+	v.token = resp.Auth.ClientToken
+	v.tokenExpiry = time.Time{}
+	if resp.Auth.LeaseDuration > 0 {
+		v.tokenExpiry = time.Now().Add(time.Duration(resp.Auth.LeaseDuration)*time.Second - vaultTokenRenewMargin)
+	}
+
+	v.logger.WithFields(logrus.Fields{
+		"provider":    "vault",
+		"auth_method": v.credentials.AuthMethod,
+	}).Info("Logged in to Vault")
+	return v.token, nil
+}
+
+// send sends a request to the Vault API and decodes the JSON response into out
+func (v *VaultProvider) send(ctx context.Context, method, path, token string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal Vault request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, v.config.Address+"/v1/"+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create Vault request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if v.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.config.Namespace)
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var payload struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&payload)
+		return &vaultResponseError{StatusCode: resp.StatusCode, Header: resp.Header, Errors: payload.Errors}
+	}
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode Vault response: %w", err)
+		}
+	}
+	return nil
+}
+
+// vaultNotFound reports whether a request failed because the path does not exist
+func vaultNotFound(err error) bool {
+	var vaultErr *vaultResponseError
+	return errors.As(err, &vaultErr) && vaultErr.StatusCode == http.StatusNotFound
+}
+
+// vaultValueString returns a field value as a string, encoding values other writers stored as JSON
+func vaultValueString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+// sanitizeVaultKey sanitizes a key for use as the last segment of a secret path, which keeps
+// letters, digits and _.-
+func sanitizeVaultKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune("_.-", r):
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,433 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeVaultSecret is a KV v2 secret held by the Vault stand-in
+type fakeVaultSecret struct {
+	versions       []map[string]interface{}
+	customMetadata map[string]string
+}
+
+// fakeVault is a local HTTP stand-in of the Vault API covering KV v2 and the login endpoints the
+// provider uses
+type fakeVault struct {
+	mu         sync.Mutex
+	mount      string
+	secrets    map[string]*fakeVaultSecret
+	tokens     map[string]bool
+	logins     map[string]int
+	namespaces map[string]bool
+	casErrors  int
+
+	// beforeWrite runs once before the next data write, such as to simulate a concurrent change
+	beforeWrite func()
+}
+
+// ServeHTTP routes a Vault API request
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var body map[string]interface{}
+	if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+		if err := json.Unmarshal(data, &body); err != nil {
+			f.fail(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	f.namespaces[r.Header.Get("X-Vault-Namespace")] = true
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	if strings.HasPrefix(path, "auth/") && strings.HasSuffix(path, "/login") {
+		f.login(w, strings.TrimSuffix(strings.TrimPrefix(path, "auth/"), "/login"), body)
+		return
+	}
+	if !f.tokens[r.Header.Get("X-Vault-Token")] {
+		f.fail(w, http.StatusForbidden, "permission denied")
+		return
+	}
+	if path == "auth/token/lookup-self" {
+		respondJSON(w, "application/json", http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"ttl": 3600}})
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(path, f.mount+"/data/"):
+		f.serveData(w, r.Method, strings.TrimPrefix(path, f.mount+"/data/"), body)
+	case strings.HasPrefix(path, f.mount+"/metadata/"):
+		f.serveMetadata(w, r.Method, strings.TrimPrefix(path, f.mount+"/metadata/"), body)
+	default:
+		f.fail(w, http.StatusNotFound, "no handler for route "+path)
+	}
+}
+
+// login issues a token for AppRole and Kubernetes logins with the expected credentials
+func (f *fakeVault) login(w http.ResponseWriter, authMount string, body map[string]interface{}) {
+	valid := (body["role_id"] == "kavach-sync" && body["secret_id"] == "secret-id") ||
+		(body["role"] == "kavach" && body["jwt"] == "service-account-jwt")
+	if !valid {
+		f.fail(w, http.StatusBadRequest, "invalid credentials")
+		return
+	}
+
+	f.logins[authMount]++
+	token := fmt.Sprintf("hvs.login-%d", len(f.tokens))
+	f.tokens[token] = true
+	respondJSON(w, "application/json", http.StatusOK, map[string]interface{}{"auth": map[string]interface{}{"client_token": token, "lease_duration": 3600}})
+}
+
+// serveData reads or writes versions of a secret
+func (f *fakeVault) serveData(w http.ResponseWriter, method, path string, body map[string]interface{}) {
+	secret := f.secrets[path]
+	switch method {
+	case http.MethodGet:
+		if secret == nil {
+			f.fail(w, http.StatusNotFound, "")
+			return
+		}
+		respondJSON(w, "application/json", http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+			"data":     secret.versions[len(secret.versions)-1],
+			"metadata": map[string]interface{}{"version": len(secret.versions), "created_time": time.Now().UTC()},
+		}})
+	case http.MethodPost, http.MethodPut:
+		if f.beforeWrite != nil {
+			beforeWrite := f.beforeWrite
+			f.beforeWrite = nil
+			beforeWrite()
+			secret = f.secrets[path]
+		}
+
+		current := 0
+		if secret != nil {
+			current = len(secret.versions)
+		}
+		if options, ok := body["options"].(map[string]interface{}); ok {
+			if cas, ok := options["cas"].(float64); ok && int(cas) != current {
+				f.casErrors++
+				f.fail(w, http.StatusBadRequest, fmt.Sprintf("check-and-set parameter did not match the current version: %d", current))
+				return
+			}
+		}
+
+		if secret == nil {
+			secret = &fakeVaultSecret{}
+			f.secrets[path] = secret
+		}
+		data, _ := body["data"].(map[string]interface{})
+		secret.versions = append(secret.versions, data)
+		respondJSON(w, "application/json", http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"version": len(secret.versions)}})
+	default:
+		f.fail(w, http.StatusMethodNotAllowed, "")
+	}
+}
+
+// serveMetadata reads, updates, lists or deletes the metadata of secrets
+func (f *fakeVault) serveMetadata(w http.ResponseWriter, method, path string, body map[string]interface{}) {
+	secret := f.secrets[path]
+	switch method {
+	case http.MethodGet:
+		if secret == nil {
+			f.fail(w, http.StatusNotFound, "")
+			return
+		}
+		respondJSON(w, "application/json", http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+			"current_version": len(secret.versions),
+			"custom_metadata": secret.customMetadata,
+		}})
+	case http.MethodPost:
+		if secret == nil {
+			secret = &fakeVaultSecret{}
+			f.secrets[path] = secret
+		}
+		secret.customMetadata = map[string]string{}
+		custom, _ := body["custom_metadata"].(map[string]interface{})
+		for key, value := range custom {
+			secret.customMetadata[key] = fmt.Sprint(value)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(f.secrets, path)
+		w.WriteHeader(http.StatusNoContent)
+	case "LIST":
+		seen := map[string]bool{}
+		for name := range f.secrets {
+			if rest, ok := strings.CutPrefix(name, path+"/"); ok {
+				if folder, _, nested := strings.Cut(rest, "/"); nested {
+					rest = folder + "/"
+				}
+				seen[rest] = true
+			}
+		}
+		if len(seen) == 0 {
+			f.fail(w, http.StatusNotFound, "")
+			return
+		}
+		keys := make([]string, 0, len(seen))
+		for key := range seen {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		respondJSON(w, "application/json", http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"keys": keys}})
+	default:
+		f.fail(w, http.StatusMethodNotAllowed, "")
+	}
+}
+
+// fail writes a Vault error response
+func (f *fakeVault) fail(w http.ResponseWriter, status int, message string) {
+	errs := []string{}
+	if message != "" {
+		errs = append(errs, message)
+	}
+	respondJSON(w, "application/json", status, map[string][]string{"errors": errs})
+}
+
+// VaultProviderTestSuite tests the Vault KV v2 provider against a local stand-in
+type VaultProviderTestSuite struct {
+	standInSuite
+	fake *fakeVault
+}
+
+// SetupTest starts an empty stand-in for each test
+func (suite *VaultProviderTestSuite) SetupTest() {
+	suite.fake = &fakeVault{
+		mount:      "kv",
+		secrets:    make(map[string]*fakeVaultSecret),
+		tokens:     map[string]bool{"hvs.static": true},
+		logins:     make(map[string]int),
+		namespaces: make(map[string]bool),
+	}
+	suite.serve(suite.fake)
+}
+
+// newProvider creates a provider pointed at the stand-in with the given credentials and config
+func (suite *VaultProviderTestSuite) newProvider(credentials, config map[string]interface{}) *VaultProvider {
+	config["address"] = suite.server.URL
+	config["mount"] = "kv"
+	provider, err := NewVaultProvider(credentials, config, suite.logger)
+	suite.prepareProvider(provider, err)
+	return provider
+}
+
+// TestSyncSingleSecret tests that keys are merged into one secret with check-and-set, which reports changes
+// made outside Kavach as conflicts instead of overwriting them
+func (suite *VaultProviderTestSuite) TestSyncSingleSecret() {
+	provider := suite.newProvider(map[string]interface{}{"token": "hvs.static"}, map[string]interface{}{
+		"namespace":     "payments",
+		"path":          "/acme/payments/prod/",
+		"check_and_set": true,
+		"retry_config":  map[string]interface{}{"max_retries": 3, "retry_delay": int64(time.Millisecond)},
+	})
+
+	results, err := provider.Sync(suite.ctx, []Secret{{Name: "DATABASE_URL", Value: "postgresql://localhost/db"}, {Name: "API_KEY", Value: "sk-1"}})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), results, 2)
+	for _, result := range results {
+		require.True(suite.T(), result.Success, result.Error)
+	}
+
+	secret := suite.fake.secrets["acme/payments/prod"]
+	require.NotNil(suite.T(), secret, "Expected the secret at the configured path")
+	assert.Equal(suite.T(), map[string]interface{}{"DATABASE_URL": "postgresql://localhost/db", "API_KEY": "sk-1"}, secret.versions[0])
+	assert.Equal(suite.T(), map[string]string{
+		"managed-by":         "kavach-backend",
+		"kavach-environment": testOwner.EnvironmentID,
+		"kavach-target":      testOwner.Target,
+		"kavach-version":     "1",
+	}, secret.customMetadata)
+	assert.True(suite.T(), suite.fake.namespaces["payments"], "Expected requests in the configured namespace")
+
+	// A change made between the read and the write is a conflict, which is not retried
+	suite.fake.beforeWrite = func() {
+		current := secret.versions[len(secret.versions)-1]
+		next := map[string]interface{}{"ADDED_ELSEWHERE": "1"}
+		for key, value := range current {
+			next[key] = value
+		}
+		secret.versions = append(secret.versions, next)
+	}
+	results, err = provider.Sync(suite.ctx, []Secret{{Name: "API_KEY", Value: "sk-2"}})
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), results[0].Success)
+	assert.Contains(suite.T(), results[0].Error, appErrors.ErrVaultSecretChanged.Message)
+	assert.Equal(suite.T(), 1, suite.fake.casErrors)
+	assert.Len(suite.T(), secret.versions, 2, "Expected the change made outside Kavach to be kept")
+
+	// Later syncs keep reporting the conflict without writing
+	results, err = provider.Sync(suite.ctx, []Secret{{Name: "API_KEY", Value: "sk-2"}})
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), results[0].Success)
+	assert.Len(suite.T(), secret.versions, 2)
+
+	// Syncing once without check-and-set resolves the conflict
+	overwrite := suite.newProvider(map[string]interface{}{"token": "hvs.static"}, map[string]interface{}{
+		"namespace": "payments",
+		"path":      "acme/payments/prod",
+	})
+	results, err = overwrite.Sync(suite.ctx, []Secret{{Name: "API_KEY", Value: "sk-2"}})
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), results[0].Success, results[0].Error)
+	assert.Equal(suite.T(), "3", secret.customMetadata["kavach-version"])
+
+	results, err = provider.Sync(suite.ctx, []Secret{{Name: "API_KEY", Value: "sk-2"}})
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), results[0].Success, results[0].Error)
+
+	read, err := provider.Read(suite.ctx, []string{"API_KEY", "ADDED_ELSEWHERE", "MISSING"})
+	require.NoError(suite.T(), err)
+	values := map[string]string{}
+	for _, remote := range read {
+		values[remote.Name] = remote.Value
+	}
+	assert.Equal(suite.T(), map[string]string{"API_KEY": "sk-2", "ADDED_ELSEWHERE": "1"}, values)
+
+	// Every other field of the secret is a prune candidate
+	candidates, err := provider.PruneCandidates(suite.ctx, []string{"API_KEY", "DATABASE_URL"})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"ADDED_ELSEWHERE"}, candidates)
+
+	// Another target writing to the same secret prunes nothing from it
+	provider.SetOwner(SecretOwner{EnvironmentID: testOwner.EnvironmentID, Target: "vault-dr"})
+	candidates, err = provider.PruneCandidates(suite.ctx, []string{"API_KEY"})
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), candidates)
+}
+
+// TestSyncPerKeyWithAppRole tests that each key gets its own path after a single AppRole login
+func (suite *VaultProviderTestSuite) TestSyncPerKeyWithAppRole() {
+	provider := suite.newProvider(map[string]interface{}{
+		"auth_method": "approle",
+		"role_id":     "kavach-sync",
+		"secret_id":   "secret-id",
+	}, map[string]interface{}{
+		"path":          "acme/prod",
+		"storage_mode":  "per_key",
+		"check_and_set": true,
+	})
+
+	secrets := []Secret{{Name: "API_KEY", Value: "sk-1"}, {Name: "db/url", Value: "postgresql://localhost/db"}}
+	results, err := provider.Sync(suite.ctx, secrets)
+	require.NoError(suite.T(), err)
+	for _, result := range results {
+		require.True(suite.T(), result.Success, result.Error)
+	}
+	assert.Equal(suite.T(), 1, suite.fake.logins["approle"], "Expected concurrent writes to share one login")
+
+	assert.Equal(suite.T(), "sk-1", suite.fake.secrets["acme/prod/API_KEY"].versions[0]["value"])
+	assert.Equal(suite.T(), "acme/prod/db_url", provider.RemoteName("db/url"))
+	assert.Contains(suite.T(), suite.fake.secrets, "acme/prod/db_url")
+
+	// A second sync writes the next version of each path
+	results, err = provider.Sync(suite.ctx, []Secret{{Name: "API_KEY", Value: "sk-2"}})
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), results[0].Success, results[0].Error)
+	assert.Len(suite.T(), suite.fake.secrets["acme/prod/API_KEY"].versions, 2)
+
+	// A path written outside Kavach since its last sync is a conflict, while the other keys sync
+	apiKey := suite.fake.secrets["acme/prod/API_KEY"]
+	apiKey.versions = append(apiKey.versions, map[string]interface{}{"value": "rotated-elsewhere"})
+	results, err = provider.Sync(suite.ctx, []Secret{{Name: "API_KEY", Value: "sk-3"}, {Name: "db/url", Value: "postgresql://replica/db"}})
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), results[0].Success)
+	assert.Contains(suite.T(), results[0].Error, appErrors.ErrVaultSecretChanged.Message)
+	assert.True(suite.T(), results[1].Success, results[1].Error)
+	assert.Len(suite.T(), apiKey.versions, 3)
+	apiKey.versions = apiKey.versions[:2]
+
+	read, err := provider.Read(suite.ctx, []string{"acme/prod/API_KEY", "acme/prod/MISSING"})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), read, 1, "Missing secrets are left out")
+	assert.Equal(suite.T(), "sk-2", read[0].Value)
+}
+
+// TestPruneCandidatesPerKey tests that only secrets directly under the path created for the same environment
+// and target are pruned
+func (suite *VaultProviderTestSuite) TestPruneCandidatesPerKey() {
+	provider := suite.newProvider(map[string]interface{}{"token": "hvs.static"}, map[string]interface{}{
+		"path":         "acme/prod",
+		"storage_mode": "per_key",
+	})
+	suite.fake.secrets["acme/prod/UNMANAGED"] = &fakeVaultSecret{versions: []map[string]interface{}{{"value": "x"}}}
+	suite.fake.secrets["acme/prod/nested/OLD"] = &fakeVaultSecret{
+		versions:       []map[string]interface{}{{"value": "x"}},
+		customMetadata: map[string]string{"managed-by": "kavach-backend"},
+	}
+	suite.fake.secrets["acme/prod/BEFORE_OWNERS"] = &fakeVaultSecret{
+		versions:       []map[string]interface{}{{"value": "x"}},
+		customMetadata: map[string]string{"managed-by": "kavach-backend"},
+	}
+	suite.fake.secrets["acme/prod/OTHER_ENVIRONMENT"] = &fakeVaultSecret{
+		versions: []map[string]interface{}{{"value": "x"}},
+		customMetadata: map[string]string{
+			"managed-by": "kavach-backend", "kavach-environment": "550e8400-e29b-41d4-a716-446655440099", "kavach-target": testOwner.Target,
+		},
+	}
+
+	suite.fake.secrets["acme/prod/OTHER_TARGET"] = &fakeVaultSecret{
+		versions: []map[string]interface{}{{"value": "x"}},
+		customMetadata: map[string]string{
+			"managed-by": "kavach-backend", "kavach-environment": testOwner.EnvironmentID, "kavach-target": "vault-dr",
+		},
+	}
+	suite.fake.secrets["acme/prod/REMOVED"] = &fakeVaultSecret{
+		versions: []map[string]interface{}{{"value": "x"}},
+		customMetadata: map[string]string{
+			"managed-by": "kavach-backend", "kavach-environment": testOwner.EnvironmentID, "kavach-target": testOwner.Target,
+		},
+	}
+
+	_, err := provider.Sync(suite.ctx, []Secret{{Name: "KEEP", Value: "1"}, {Name: "OLD", Value: "2"}})
+	require.NoError(suite.T(), err)
+
+	candidates, err := provider.PruneCandidates(suite.ctx, []string{"KEEP"})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"acme/prod/OLD", "acme/prod/REMOVED"}, candidates)
+
+	pruned, err := provider.Prune(suite.ctx, append(candidates, "acme/prod/GONE"))
+	require.NoError(suite.T(), err)
+	for _, result := range pruned {
+		assert.True(suite.T(), result.Success, "Prune of %s failed: %s", result.Name, result.Error)
+	}
+	assert.NotContains(suite.T(), suite.fake.secrets, "acme/prod/OLD")
+	assert.Contains(suite.T(), suite.fake.secrets, "acme/prod/UNMANAGED")
+	assert.Contains(suite.T(), suite.fake.secrets, "acme/prod/KEEP")
+}
+
+// TestKubernetesAuth tests logging in through a custom Kubernetes auth mount
+func (suite *VaultProviderTestSuite) TestKubernetesAuth() {
+	provider := suite.newProvider(map[string]interface{}{
+		"auth_method": "kubernetes",
+		"role":        "kavach",
+		"jwt":         "service-account-jwt",
+		"auth_mount":  "k8s-prod",
+	}, map[string]interface{}{"path": "acme/prod"})
+
+	require.NoError(suite.T(), provider.ValidateCredentials(suite.ctx))
+	assert.Equal(suite.T(), 1, suite.fake.logins["k8s-prod"])
+
+	rejected := suite.newProvider(map[string]interface{}{"token": "hvs.revoked"}, map[string]interface{}{"path": "acme/prod"})
+	err := rejected.ValidateCredentials(suite.ctx)
+	require.Error(suite.T(), err)
+	kind, _ := classifySyncError(err)
+	assert.Equal(suite.T(), failurePermanent, kind)
+}
+
+// TestVaultProviderTestSuite runs the Vault provider test suite
+func TestVaultProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(VaultProviderTestSuite))
+}
@@ -0,0 +1,392 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// Headers set on every webhook request. The signature is the hex HMAC-SHA256 of the timestamp, a dot and
+// the body, so a receiver can reject requests whose timestamp is too old and stop replays.
+const (
+	webhookTimestampHeader = "X-Kavach-Timestamp"
+	webhookSignatureHeader = "X-Kavach-Signature"
+	webhookDeliveryHeader  = "X-Kavach-Delivery"
+	webhookEventHeader     = "X-Kavach-Event"
+)
+
+// Webhook events
+const (
+	webhookEventSync = "sync" // Secrets of a version
+	webhookEventPing = "ping" // Sent when validating credentials; carries no secrets
+)
+
+// webhookMaxResponseSize caps how much of a receiver's response is read
+const webhookMaxResponseSize = 1 << 20
+
+// WebhookProvider implements ProviderSync by posting secrets to an HTTPS endpoint
+type WebhookProvider struct {
+	credentials WebhookCredentials
+	config      WebhookConfig
+	recipient   *[32]byte // Public key values are sealed to when encryption is configured
+	logger      *logrus.Logger
+	httpClient  *http.Client
+	executor    *SyncExecutor
+	versionID   string
+}
+
+// webhookPayload is the body of a webhook request
+type webhookPayload struct {
+	Event      string          `json:"event"`
+	DeliveryID string          `json:"delivery_id"`
+	VersionID  string          `json:"version_id,omitempty"`
+	Encryption string          `json:"encryption,omitempty"`
+	Secrets    []webhookSecret `json:"secrets,omitempty"`
+}
+
+// webhookSecret is one secret in a webhook payload, with its value in plain text or sealed
+type webhookSecret struct {
+	Name           string `json:"name"`
+	Value          string `json:"value,omitempty"`
+	EncryptedValue string `json:"encrypted_value,omitempty"` // Base64 sealed box
+}
+
+// webhookDelivery is a signed-on-send request to the webhook; retries reuse it
+type webhookDelivery struct {
+	id    string
+	event string
+	body  []byte
+}
+
+// webhookResponse is the optional body of a successful webhook response. Secrets it reports as failed
+// fail on their own; every other secret of the request succeeds.
+type webhookResponse struct {
+	Results []SyncResult `json:"results"`
+}
+
+// webhookResponseError is a failed webhook response
+type webhookResponseError struct {
+	StatusCode int
+	Header     http.Header
+	Message    string
+}
+
+// Error returns the status and the body the receiver responded with
+func (e *webhookResponseError) Error() string {
+	return fmt.Sprintf("webhook returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// NewWebhookProvider creates a new webhook provider instance
+func NewWebhookProvider(credentials map[string]interface{}, config map[string]interface{}, logger *logrus.Logger) (*WebhookProvider, error) {
+	// Parse credentials
+	credBytes, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	var webhookCreds WebhookCredentials
+	if err := json.Unmarshal(credBytes, &webhookCreds); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook credentials: %w", err)
+	}
+
+	// Parse config
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var webhookConfig WebhookConfig
+	if err := json.Unmarshal(configBytes, &webhookConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook config: %w", err)
+	}
+	if webhookConfig.PayloadMode == "" {
+		webhookConfig.PayloadMode = WebhookPayloadFull
+	}
+
+	var recipient *[32]byte
+	if webhookConfig.Encryption == WebhookEncryptionSealedBox {
+		recipient, err = parseWebhookPublicKey(webhookConfig.RecipientPublicKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tlsConfig, err := webhookTLSConfig(webhookCreds, webhookConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebhookProvider{
+		credentials: webhookCreds,
+		config:      webhookConfig,
+		recipient:   recipient,
+		logger:      logger,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+		},
+		executor: NewSyncExecutor(DefaultSyncExecutorConfig(), logger),
+	}, nil
+}
+
+// SetSyncVersion sets the version ID sent with the following syncs
+func (w *WebhookProvider) SetSyncVersion(versionID string) {
+	w.versionID = versionID
+}
+
+// Sync posts secrets to the webhook, all in one request or one request per secret. Secrets the receiver
+// reports as failed in its response fail on their own without affecting the other secrets.
+func (w *WebhookProvider) Sync(ctx context.Context, secrets []Secret) ([]SyncResult, error) {
+	logEntry := w.logger.WithFields(logrus.Fields{
+		"provider":     "webhook",
+		"url":          w.config.URL,
+		"payload_mode": w.config.PayloadMode,
+		"secret_count": len(secrets),
+	})
+
+	logEntry.Info("Starting webhook sync")
+
+	// Payloads are built once, so retries of a delivery keep its ID and receivers can drop duplicates
+	var results []SyncResult
+	if w.config.PayloadMode == WebhookPayloadPerKey {
+		deliveries := make(map[string]webhookDelivery, len(secrets))
+		for _, secret := range secrets {
+			delivery, err := w.newDelivery(webhookEventSync, []Secret{secret})
+			if err != nil {
+				return nil, err
+			}
+			deliveries[secret.Name] = delivery
+		}
+		results = w.executor.Run(ctx, ProviderWebhook, w.config.RetryConfig, secrets, func(ctx context.Context, secret Secret) error {
+			rejected, err := w.deliver(ctx, deliveries[secret.Name])
+			if err != nil {
+				return err
+			}
+			if message, ok := rejected[secret.Name]; ok {
+				return fmt.Errorf("%s: %w", message, appErrors.ErrWebhookSecretRejected)
+			}
+			return nil
+		})
+	} else {
+		delivery, err := w.newDelivery(webhookEventSync, secrets)
+		if err != nil {
+			return nil, err
+		}
+		results = w.syncFull(ctx, secrets, delivery)
+	}
+
+	for _, result := range results {
+		if !result.Success {
+			logEntry.WithFields(logrus.Fields{
+				"secret_name": result.Name,
+				"error":       result.Error,
+			}).Error("Failed to sync secret to webhook after all retry attempts")
+		}
+	}
+
+	logEntry.WithFields(logrus.Fields{
+		"synced_count": len(results),
+		"total_count":  len(secrets),
+	}).Info("Completed webhook sync")
+
+	return results, nil
+}
+
+// ValidateCredentials sends a signed ping to the webhook, which must respond with a success status
+func (w *WebhookProvider) ValidateCredentials(ctx context.Context) error {
+	logEntry := w.logger.WithFields(logrus.Fields{
+		"provider": "webhook",
+		"url":      w.config.URL,
+	})
+
+	logEntry.Info("Validating webhook credentials")
+
+	delivery, err := w.newDelivery(webhookEventPing, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := w.post(ctx, delivery); err != nil {
+		return fmt.Errorf("failed to validate webhook credentials: %w", err)
+	}
+
+	logEntry.Info("Webhook credentials validated successfully")
+	return nil
+}
+
+// GetProviderName returns the provider name
+func (w *WebhookProvider) GetProviderName() string {
+	return "webhook"
+}
+
+// syncFull posts every secret in one request, retried as a whole. The secrets share the outcome of the
+// request unless the receiver reports them as failed.
+func (w *WebhookProvider) syncFull(ctx context.Context, secrets []Secret, delivery webhookDelivery) []SyncResult {
+	var rejected map[string]string
+	document := []Secret{{Name: delivery.id}}
+	outcome := w.executor.Run(ctx, ProviderWebhook, w.config.RetryConfig, document, func(ctx context.Context, _ Secret) error {
+		var err error
+		rejected, err = w.deliver(ctx, delivery)
+		return err
+	})
+
+	results := make([]SyncResult, len(secrets))
+	for i, secret := range secrets {
+		results[i] = SyncResult{Name: secret.Name, Success: outcome[0].Success, Error: outcome[0].Error}
+		if message, ok := rejected[secret.Name]; ok && outcome[0].Success {
+			results[i].Success = false
+			results[i].Error = fmt.Errorf("%s: %w", message, appErrors.ErrWebhookSecretRejected).Error()
+		}
+	}
+	return results
+}
+
+// newDelivery builds the payload of a request, sealing the values when encryption is configured
+func (w *WebhookProvider) newDelivery(event string, secrets []Secret) (webhookDelivery, error) {
+	payload := webhookPayload{
+		Event:      event,
+		DeliveryID: uuid.NewString(),
+		VersionID:  w.versionID,
+	}
+	if len(secrets) > 0 {
+		payload.Encryption = w.config.Encryption
+		payload.Secrets = make([]webhookSecret, len(secrets))
+	}
+	for i, secret := range secrets {
+		payload.Secrets[i] = webhookSecret{Name: secret.Name, Value: secret.Value}
+		if w.recipient != nil {
+			sealed, err := box.SealAnonymous(nil, []byte(secret.Value), w.recipient, rand.Reader)
+			if err != nil {
+				return webhookDelivery{}, fmt.Errorf("failed to encrypt secret '%s': %w", secret.Name, err)
+			}
+			payload.Secrets[i] = webhookSecret{Name: secret.Name, EncryptedValue: base64.StdEncoding.EncodeToString(sealed)}
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return webhookDelivery{}, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+	return webhookDelivery{id: payload.DeliveryID, event: event, body: body}, nil
+}
+
+// deliver posts a delivery and returns the error message of each secret the receiver rejected
+func (w *WebhookProvider) deliver(ctx context.Context, delivery webhookDelivery) (map[string]string, error) {
+	responseBody, err := w.post(ctx, delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	// Receivers that do not report per-secret results accept every secret
+	var response webhookResponse
+	if len(bytes.TrimSpace(responseBody)) == 0 || json.Unmarshal(responseBody, &response) != nil {
+		return nil, nil
+	}
+	rejected := make(map[string]string)
+	for _, result := range response.Results {
+		if !result.Success {
+			rejected[result.Name] = result.Error
+		}
+	}
+	return rejected, nil
+}
+
+// post signs a delivery with the current time and posts it to the webhook, returning the response body
+func (w *WebhookProvider) post(ctx context.Context, delivery webhookDelivery) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, bytes.NewReader(delivery.body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	// Configured headers go first so they cannot replace the signature headers
+	for name, value := range w.config.Headers {
+		req.Header.Set(name, value)
+	}
+	for name, value := range w.credentials.Headers {
+		req.Header.Set(name, value)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, delivery.event)
+	req.Header.Set(webhookDeliveryHeader, delivery.id)
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, "sha256="+signWebhookPayload(w.credentials.SigningSecret, timestamp, delivery.body))
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook response: %w", err)
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, &webhookResponseError{StatusCode: resp.StatusCode, Header: resp.Header, Message: string(bytes.TrimSpace(responseBody))}
+	}
+	return responseBody, nil
+}
+
+// signWebhookPayload returns the hex HMAC-SHA256 of the timestamp and body
+func signWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseWebhookPublicKey decodes a base64 X25519 public key
+func parseWebhookPublicKey(encoded string) (*[32]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(decoded) != 32 {
+		return nil, appErrors.ErrInvalidProviderData
+	}
+	var key [32]byte
+	copy(key[:], decoded)
+	return &key, nil
+}
+
+// webhookTLSConfig returns the TLS settings for the endpoint, with the client certificate for mTLS and
+// the configured CA when they are set
+func webhookTLSConfig(creds WebhookCredentials, config WebhookConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if creds.ClientCertificate != "" || creds.ClientKey != "" {
+		certificate, err := tls.X509KeyPair([]byte(creds.ClientCertificate), []byte(creds.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load webhook client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	if config.CACertificate != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(config.CACertificate)) {
+			return nil, fmt.Errorf("failed to parse webhook CA certificate: %w", appErrors.ErrInvalidProviderData)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,327 @@
+package secret
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	apiErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/Gkemhcs/kavach-backend/internal/provider"
+	secretdb "github.com/Gkemhcs/kavach-backend/internal/secret/gen"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// CheckDrift compares the secrets held by the provider targets of an environment with its latest version
+// and records the outcome. Every target is checked unless the request names targets or a provider type.
+func (s *SecretService) CheckDrift(ctx context.Context, environmentID, userID string, req DriftCheckRequest) ([]DriftCheckResponse, error) {
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := s.resolveSyncTargets(ctx, environmentID, req.Targets, req.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 && (len(req.Targets) > 0 || req.Provider != "") {
+		return nil, apiErrors.ErrNoSyncTargets
+	}
+
+	checkedBy := uuid.NullUUID{}
+	if userUUID, err := uuid.Parse(userID); err == nil {
+		checkedBy = uuid.NullUUID{UUID: userUUID, Valid: true}
+	}
+
+	return s.runDriftChecks(ctx, environmentUUID, targets, DriftTriggerManual, checkedBy)
+}
+
+// ListDriftChecks returns the latest drift check of every provider target of an environment
+func (s *SecretService) ListDriftChecks(ctx context.Context, environmentID string) ([]DriftCheckResponse, error) {
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	checks, err := s.repo.ListLatestDriftChecks(ctx, environmentUUID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to list drift checks")
+		return nil, fmt.Errorf("failed to list drift checks: %w", err)
+	}
+
+	responses := make([]DriftCheckResponse, len(checks))
+	for i, check := range checks {
+		response, err := toDriftCheckResponse(check)
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = *response
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"environment_id": environmentID,
+		"check_count":    len(responses),
+	}).Info("Successfully listed drift checks")
+
+	return responses, nil
+}
+
+// StartDriftCheckScheduler checks every configured provider for drift on a fixed interval
+// until ctx is cancelled. A non-positive interval disables scheduled checks.
+func (s *SecretService) StartDriftCheckScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		s.logger.Info("Scheduled drift checks are disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.checkAllEnvironmentsForDrift(ctx)
+			}
+		}
+	}()
+
+	s.logger.WithField("interval", interval.String()).Info("Started scheduled drift checks")
+}
+
+// checkAllEnvironmentsForDrift runs a drift check for every environment with a configured provider,
+// logging failures per environment
+func (s *SecretService) checkAllEnvironmentsForDrift(ctx context.Context) {
+	targets, err := s.repo.ListDriftCheckTargets(ctx)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to list environments for drift checks")
+		return
+	}
+
+	var environmentIDs []uuid.UUID
+	targetsByEnvironment := make(map[uuid.UUID][]syncTarget)
+	for _, target := range targets {
+		if _, ok := targetsByEnvironment[target.EnvironmentID]; !ok {
+			environmentIDs = append(environmentIDs, target.EnvironmentID)
+		}
+		targetsByEnvironment[target.EnvironmentID] = append(targetsByEnvironment[target.EnvironmentID], syncTarget{Name: target.Name, Provider: target.Provider})
+	}
+
+	for _, environmentID := range environmentIDs {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := s.runDriftChecks(ctx, environmentID, targetsByEnvironment[environmentID], DriftTriggerScheduled, uuid.NullUUID{}); err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"error":          err.Error(),
+				"environment_id": environmentID,
+			}).Error("Scheduled drift check failed")
+		}
+	}
+}
+
+// runDriftChecks compares each provider target with the latest version of an environment and records every
+// outcome. A target that cannot be checked is recorded as failed without stopping the others.
+func (s *SecretService) runDriftChecks(ctx context.Context, environmentID uuid.UUID, targets []syncTarget, trigger string, checkedBy uuid.NullUUID) ([]DriftCheckResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":         "runDriftChecks",
+		"environment_id": environmentID,
+		"trigger":        trigger,
+	})
+
+	versions, err := s.repo.ListSecretVersions(ctx, environmentID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list secret versions")
+		return nil, fmt.Errorf("failed to list secret versions: %w", err)
+	}
+
+	// Without a version there is nothing in Kavach, so every remote secret is reported as extra
+	var latestVersionID sql.NullString
+	var secrets []SecretWithValue
+	if len(versions) > 0 {
+		details, err := s.getCurrentVersionDetails(ctx, versions[0].ID)
+		if err != nil {
+			return nil, err
+		}
+		latestVersionID = sql.NullString{String: versions[0].ID, Valid: true}
+		secrets = details.Secrets
+	}
+
+	syncedVersions, err := s.repo.ListCurrentSyncedVersions(ctx, environmentID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list synced versions")
+		return nil, fmt.Errorf("failed to list synced versions: %w", err)
+	}
+	lastSyncByTarget := make(map[string]secretdb.ListCurrentSyncedVersionsRow, len(syncedVersions))
+	for _, synced := range syncedVersions {
+		lastSyncByTarget[synced.Target] = synced
+	}
+
+	responses := make([]DriftCheckResponse, 0, len(targets))
+	for _, target := range targets {
+		lastSync, synced := lastSyncByTarget[target.Name]
+
+		status := DriftStatusInSync
+		var checkError sql.NullString
+		findings, err := s.detectProviderDrift(ctx, environmentID.String(), target.Name, secrets, lastSync.FinishedAt)
+		if err != nil {
+			logEntry.WithFields(logrus.Fields{
+				"target": target.Name,
+				"error":  err.Error(),
+			}).Warn("Failed to check provider target for drift")
+			status = DriftStatusFailed
+			checkError = sql.NullString{String: err.Error(), Valid: true}
+		} else if len(findings.Missing)+len(findings.Extra)+len(findings.Changed) > 0 {
+			status = DriftStatusDrifted
+		}
+
+		findingsJSON, err := json.Marshal(findings)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal drift findings: %w", err)
+		}
+
+		syncedVersionID := sql.NullString{}
+		if synced {
+			syncedVersionID = lastSync.VersionID
+		}
+
+		check, err := s.repo.InsertDriftCheck(ctx, secretdb.InsertDriftCheckParams{
+			EnvironmentID:   environmentID,
+			Target:          target.Name,
+			Provider:        target.Provider,
+			VersionID:       latestVersionID,
+			SyncedVersionID: syncedVersionID,
+			Status:          status,
+			Findings:        findingsJSON,
+			Error:           checkError,
+			Trigger:         trigger,
+			CheckedBy:       checkedBy,
+		})
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Error("Failed to save drift check")
+			return nil, fmt.Errorf("failed to save drift check: %w", err)
+		}
+
+		response, err := toDriftCheckResponse(check)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *response)
+
+		logEntry.WithFields(logrus.Fields{
+			"target":        target.Name,
+			"status":        status,
+			"missing_count": len(findings.Missing),
+			"extra_count":   len(findings.Extra),
+			"changed_count": len(findings.Changed),
+		}).Info("Completed provider drift check")
+	}
+
+	return responses, nil
+}
+
+// detectProviderDrift reads the secrets held by a provider target and compares them with the given secrets.
+// Values are compared when the provider returns them; otherwise a secret counts as changed when it
+// was modified after the last successful sync finished.
+func (s *SecretService) detectProviderDrift(ctx context.Context, environmentID, target string, secrets []SecretWithValue, lastSyncedAt sql.NullTime) (DriftFindings, error) {
+	findings := DriftFindings{Missing: []DriftKey{}, Extra: []DriftKey{}, Changed: []DriftKey{}}
+
+	syncer, syncOptions, err := s.providerService.GetProviderSyncerWithOptions(ctx, environmentID, target)
+	if err != nil {
+		return findings, err
+	}
+	reader, ok := syncer.(provider.ProviderReader)
+	if !ok {
+		return findings, apiErrors.ErrDriftCheckNotSupported
+	}
+
+	// Compare against what the target receives after its key mapping
+	secrets, err = applyKeyMapping(syncOptions.KeyMapping, secrets)
+	if err != nil {
+		return findings, err
+	}
+
+	listed, err := reader.List(ctx)
+	if err != nil {
+		return findings, err
+	}
+
+	// Read the expected secrets directly so ones outside the listed scope are still found
+	localByRemoteName := make(map[string]SecretWithValue, len(secrets))
+	remoteNames := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		remoteName := reader.RemoteName(secret.Name)
+		localByRemoteName[remoteName] = secret
+		remoteNames = append(remoteNames, remoteName)
+	}
+	sort.Strings(remoteNames)
+
+	read, err := reader.Read(ctx, remoteNames)
+	if err != nil {
+		return findings, err
+	}
+	remoteByName := make(map[string]provider.RemoteSecret, len(read))
+	for _, remote := range read {
+		remoteByName[remote.Name] = remote
+	}
+
+	for _, remoteName := range remoteNames {
+		local := localByRemoteName[remoteName]
+		remote, found := remoteByName[remoteName]
+		if !found {
+			findings.Missing = append(findings.Missing, DriftKey{Name: local.Name, RemoteName: remoteName})
+			continue
+		}
+
+		key := DriftKey{Name: local.Name, RemoteName: remoteName, RemoteUpdatedAt: remote.UpdatedAt}
+		if remote.HasValue {
+			if remote.Value != local.Value {
+				key.Reason = DriftReasonValueChanged
+				findings.Changed = append(findings.Changed, key)
+			}
+		} else if lastSyncedAt.Valid && remote.UpdatedAt != nil && remote.UpdatedAt.After(lastSyncedAt.Time) {
+			key.Reason = DriftReasonUpdatedSinceSync
+			findings.Changed = append(findings.Changed, key)
+		}
+	}
+
+	for _, remote := range listed {
+		if _, expected := localByRemoteName[remote.Name]; !expected {
+			findings.Extra = append(findings.Extra, DriftKey{RemoteName: remote.Name, RemoteUpdatedAt: remote.UpdatedAt})
+		}
+	}
+	sort.Slice(findings.Extra, func(i, j int) bool { return findings.Extra[i].RemoteName < findings.Extra[j].RemoteName })
+
+	return findings, nil
+}
+
+// toDriftCheckResponse converts a stored drift check into its API representation
+func toDriftCheckResponse(check secretdb.DriftCheck) (*DriftCheckResponse, error) {
+	var findings DriftFindings
+	if err := json.Unmarshal(check.Findings, &findings); err != nil {
+		return nil, fmt.Errorf("failed to parse drift findings: %w", err)
+	}
+
+	response := &DriftCheckResponse{
+		ID:              check.ID,
+		EnvironmentID:   check.EnvironmentID,
+		Target:          check.Target,
+		Provider:        check.Provider,
+		Trigger:         check.Trigger,
+		VersionID:       check.VersionID.String,
+		SyncedVersionID: check.SyncedVersionID.String,
+		Status:          check.Status,
+		DriftFindings:   findings,
+		Error:           check.Error.String,
+		CheckedAt:       check.CheckedAt,
+	}
+	if check.CheckedBy.Valid {
+		response.CheckedBy = &check.CheckedBy.UUID
+	}
+	return response, nil
+}
@@ -0,0 +1,13 @@
+-- name: InsertDriftCheck :one
+INSERT INTO drift_checks (environment_id, target, provider, version_id, synced_version_id, status, findings, error, trigger, checked_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: ListDriftCheckTargets :many
+SELECT environment_id, name, provider FROM provider_credentials
+ORDER BY environment_id, name;
+
+-- name: ListLatestDriftChecks :many
+SELECT DISTINCT ON (target) * FROM drift_checks
+WHERE environment_id = $1
+ORDER BY target, checked_at DESC;
@@ -3,8 +3,11 @@ package secret
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 
@@ -14,9 +17,10 @@ import (
 
 // EncryptionService handles encryption and decryption of secret values
 type EncryptionService struct {
-	key  []byte
-	aead cipher.AEAD
-	log  *logrus.Logger
+	key            []byte
+	fingerprintKey []byte
+	aead           cipher.AEAD
+	log            *logrus.Logger
 }
 
 // NewEncryptionService creates a new encryption service with the provided key
@@ -44,13 +48,26 @@ func NewEncryptionService(key string, logger *logrus.Logger) (*EncryptionService
 		return nil, fmt.Errorf("failed to create GCM mode: %w", err)
 	}
 
+	// Derive a separate key for fingerprints so they never reuse the encryption key directly
+	fingerprintMAC := hmac.New(sha256.New, decodedKey)
+	fingerprintMAC.Write([]byte("kavach-secret-fingerprint"))
+
 	return &EncryptionService{
-		key:  decodedKey,
-		aead: aead,
-		log:  logger,
+		key:            decodedKey,
+		fingerprintKey: fingerprintMAC.Sum(nil),
+		aead:           aead,
+		log:            logger,
 	}, nil
 }
 
+// Fingerprint returns a keyed, non-reversible fingerprint of a plaintext value.
+// Equal values produce equal fingerprints, which allows comparing values without exposing them.
+func (e *EncryptionService) Fingerprint(plaintext string) string {
+	mac := hmac.New(sha256.New, e.fingerprintKey)
+	mac.Write([]byte(plaintext))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 // Encrypt encrypts a plaintext value and returns the encrypted bytes
 func (e *EncryptionService) Encrypt(plaintext string) ([]byte, error) {
 	e.log.Debug("Encrypting secret value")
@@ -157,6 +157,21 @@ type CasbinRule struct {
 	V5    sql.NullString `json:"v5"`
 }
 
+type DriftCheck struct {
+	ID              uuid.UUID       `json:"id"`
+	EnvironmentID   uuid.UUID       `json:"environment_id"`
+	Provider        string          `json:"provider"`
+	VersionID       sql.NullString  `json:"version_id"`
+	SyncedVersionID sql.NullString  `json:"synced_version_id"`
+	Status          string          `json:"status"`
+	Findings        json.RawMessage `json:"findings"`
+	Error           sql.NullString  `json:"error"`
+	Trigger         string          `json:"trigger"`
+	CheckedBy       uuid.NullUUID   `json:"checked_by"`
+	CheckedAt       time.Time       `json:"checked_at"`
+	Target          string          `json:"target"`
+}
+
 type Environment struct {
 	ID            uuid.UUID      `json:"id"`
 	Name          string         `json:"name"`
@@ -187,15 +202,41 @@ type Organization struct {
 	UpdatedAt   time.Time      `json:"updated_at"`
 }
 
+type ProviderConnection struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Name           string    `json:"name"`
+	Provider       string    `json:"provider"`
+	Credentials    []byte    `json:"credentials"`
+	CreatedBy      uuid.UUID `json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type ProviderConnectionGrant struct {
+	ID            uuid.UUID     `json:"id"`
+	ConnectionID  uuid.UUID     `json:"connection_id"`
+	SecretGroupID uuid.NullUUID `json:"secret_group_id"`
+	EnvironmentID uuid.NullUUID `json:"environment_id"`
+	GrantedBy     uuid.NullUUID `json:"granted_by"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
 type ProviderCredential struct {
-	ID            uuid.UUID       `json:"id"`
-	EnvironmentID uuid.UUID       `json:"environment_id"`
-	Provider      string          `json:"provider"`
-	Credentials   []byte          `json:"credentials"`
-	Config        json.RawMessage `json:"config"`
-	CreatedBy     uuid.UUID       `json:"created_by"`
-	CreatedAt     time.Time       `json:"created_at"`
-	UpdatedAt     time.Time       `json:"updated_at"`
+	ID                      uuid.UUID       `json:"id"`
+	EnvironmentID           uuid.UUID       `json:"environment_id"`
+	Provider                string          `json:"provider"`
+	Credentials             []byte          `json:"credentials"`
+	Config                  json.RawMessage `json:"config"`
+	CreatedBy               uuid.UUID       `json:"created_by"`
+	CreatedAt               time.Time       `json:"created_at"`
+	UpdatedAt               time.Time       `json:"updated_at"`
+	AutoSync                bool            `json:"auto_sync"`
+	AutoSyncDebounceSeconds int32           `json:"auto_sync_debounce_seconds"`
+	MirrorMode              bool            `json:"mirror_mode"`
+	Name                    string          `json:"name"`
+	ConnectionID            uuid.NullUUID   `json:"connection_id"`
+	KeyMapping              json.RawMessage `json:"key_mapping"`
 }
 
 type RoleBinding struct {
@@ -234,13 +275,163 @@ type SecretGroupMember struct {
 	Role          RoleType  `json:"role"`
 }
 
-type SecretVersion struct {
+type SecretGroupPolicy struct {
+	SecretGroupID uuid.UUID       `json:"secret_group_id"`
+	Schema        json.RawMessage `json:"schema"`
+	UpdatedBy     uuid.UUID       `json:"updated_by"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+type SecretGroupSecret struct {
+	ID             uuid.UUID `json:"id"`
+	VersionID      string    `json:"version_id"`
+	Name           string    `json:"name"`
+	ValueEncrypted []byte    `json:"value_encrypted"`
+}
+
+type SecretGroupVersion struct {
 	ID            string    `json:"id"`
-	EnvironmentID uuid.UUID `json:"environment_id"`
+	SecretGroupID uuid.UUID `json:"secret_group_id"`
 	CommitMessage string    `json:"commit_message"`
 	CreatedAt     time.Time `json:"created_at"`
 }
 
+type SecretKeyChange struct {
+	ID                   uuid.UUID      `json:"id"`
+	VersionID            string         `json:"version_id"`
+	EnvironmentID        uuid.UUID      `json:"environment_id"`
+	ChangeType           string         `json:"change_type"`
+	OldName              string         `json:"old_name"`
+	NewName              string         `json:"new_name"`
+	RelatedEnvironmentID uuid.NullUUID  `json:"related_environment_id"`
+	RelatedVersionID     sql.NullString `json:"related_version_id"`
+	CreatedAt            time.Time      `json:"created_at"`
+}
+
+type SecretKeyChangeCleanup struct {
+	KeyChangeID uuid.UUID `json:"key_change_id"`
+	Target      string    `json:"target"`
+	CleanedAt   time.Time `json:"cleaned_at"`
+}
+
+type SecretReuseFinding struct {
+	ID               uuid.UUID       `json:"id"`
+	OrganizationID   uuid.UUID       `json:"organization_id"`
+	Fingerprint      string          `json:"fingerprint"`
+	Scope            string          `json:"scope"`
+	Occurrences      json.RawMessage `json:"occurrences"`
+	Suppressed       bool            `json:"suppressed"`
+	SuppressedBy     uuid.NullUUID   `json:"suppressed_by"`
+	SuppressedReason sql.NullString  `json:"suppressed_reason"`
+	SuppressedAt     sql.NullTime    `json:"suppressed_at"`
+	FirstSeenAt      time.Time       `json:"first_seen_at"`
+	LastSeenAt       time.Time       `json:"last_seen_at"`
+	ResolvedAt       sql.NullTime    `json:"resolved_at"`
+}
+
+type SecretShare struct {
+	ID             uuid.UUID      `json:"id"`
+	EnvironmentID  uuid.UUID      `json:"environment_id"`
+	VersionID      sql.NullString `json:"version_id"`
+	SecretName     sql.NullString `json:"secret_name"`
+	TokenHash      string         `json:"token_hash"`
+	ValueEncrypted []byte         `json:"value_encrypted"`
+	PassphraseHash sql.NullString `json:"passphrase_hash"`
+	MaxViews       int32          `json:"max_views"`
+	ViewCount      int32          `json:"view_count"`
+	FailedAttempts int32          `json:"failed_attempts"`
+	ExpiresAt      time.Time      `json:"expires_at"`
+	RevokedAt      sql.NullTime   `json:"revoked_at"`
+	CreatedBy      uuid.UUID      `json:"created_by"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+type SecretShareEvent struct {
+	ID        uuid.UUID      `json:"id"`
+	ShareID   uuid.UUID      `json:"share_id"`
+	EventType string         `json:"event_type"`
+	ActorID   uuid.NullUUID  `json:"actor_id"`
+	IpAddress sql.NullString `json:"ip_address"`
+	UserAgent sql.NullString `json:"user_agent"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type SecretVersion struct {
+	ID             string         `json:"id"`
+	EnvironmentID  uuid.UUID      `json:"environment_id"`
+	CommitMessage  string         `json:"commit_message"`
+	CreatedAt      time.Time      `json:"created_at"`
+	GroupVersionID sql.NullString `json:"group_version_id"`
+}
+
+type SyncJob struct {
+	ID             uuid.UUID      `json:"id"`
+	EnvironmentID  uuid.UUID      `json:"environment_id"`
+	Provider       string         `json:"provider"`
+	VersionID      sql.NullString `json:"version_id"`
+	InitiatedBy    uuid.NullUUID  `json:"initiated_by"`
+	Status         string         `json:"status"`
+	Attempts       int32          `json:"attempts"`
+	MaxAttempts    int32          `json:"max_attempts"`
+	RunID          uuid.NullUUID  `json:"run_id"`
+	LastError      sql.NullString `json:"last_error"`
+	RunAt          time.Time      `json:"run_at"`
+	LockedBy       sql.NullString `json:"locked_by"`
+	LeaseExpiresAt sql.NullTime   `json:"lease_expires_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	FinishedAt     sql.NullTime   `json:"finished_at"`
+	Trigger        string         `json:"trigger"`
+	PlanID         uuid.NullUUID  `json:"plan_id"`
+	Target         string         `json:"target"`
+}
+
+type SyncPlan struct {
+	ID                  uuid.UUID       `json:"id"`
+	EnvironmentID       uuid.UUID       `json:"environment_id"`
+	Provider            string          `json:"provider"`
+	VersionID           string          `json:"version_id"`
+	MirrorMode          bool            `json:"mirror_mode"`
+	Changes             json.RawMessage `json:"changes"`
+	Collisions          json.RawMessage `json:"collisions"`
+	CredentialUpdatedAt time.Time       `json:"credential_updated_at"`
+	CreatedBy           uuid.NullUUID   `json:"created_by"`
+	CreatedAt           time.Time       `json:"created_at"`
+	ExpiresAt           time.Time       `json:"expires_at"`
+	AppliedBy           uuid.NullUUID   `json:"applied_by"`
+	AppliedAt           sql.NullTime    `json:"applied_at"`
+	JobID               uuid.NullUUID   `json:"job_id"`
+	Target              string          `json:"target"`
+}
+
+type SyncRun struct {
+	ID                   uuid.UUID      `json:"id"`
+	EnvironmentID        uuid.UUID      `json:"environment_id"`
+	Provider             string         `json:"provider"`
+	ProviderCredentialID uuid.NullUUID  `json:"provider_credential_id"`
+	VersionID            sql.NullString `json:"version_id"`
+	InitiatedBy          uuid.NullUUID  `json:"initiated_by"`
+	Status               string         `json:"status"`
+	Message              sql.NullString `json:"message"`
+	Error                sql.NullString `json:"error"`
+	SyncedCount          int32          `json:"synced_count"`
+	FailedCount          int32          `json:"failed_count"`
+	TotalCount           int32          `json:"total_count"`
+	StartedAt            time.Time      `json:"started_at"`
+	FinishedAt           sql.NullTime   `json:"finished_at"`
+	Trigger              string         `json:"trigger"`
+	Target               string         `json:"target"`
+}
+
+type SyncRunResult struct {
+	RunID     uuid.UUID      `json:"run_id"`
+	Operation string         `json:"operation"`
+	Name      string         `json:"name"`
+	Success   bool           `json:"success"`
+	Error     sql.NullString `json:"error"`
+}
+
 type User struct {
 	ID         uuid.UUID      `json:"id"`
 	Provider   string         `json:"provider"`
@@ -11,13 +11,76 @@ import (
 )
 
 type Querier interface {
+	AbandonSyncRun(ctx context.Context, arg AbandonSyncRunParams) error
+	ClaimSyncJob(ctx context.Context, arg ClaimSyncJobParams) (SyncJob, error)
+	ClearSecretShareValue(ctx context.Context, id uuid.UUID) error
+	CompleteSyncJob(ctx context.Context, arg CompleteSyncJobParams) (int64, error)
+	ConsumeSecretShare(ctx context.Context, arg ConsumeSecretShareParams) (SecretShare, error)
+	CreateSecretGroupVersion(ctx context.Context, arg CreateSecretGroupVersionParams) (SecretGroupVersion, error)
+	CreateSecretShare(ctx context.Context, arg CreateSecretShareParams) (SecretShare, error)
 	CreateSecretVersion(ctx context.Context, arg CreateSecretVersionParams) (SecretVersion, error)
+	CreateSyncPlan(ctx context.Context, arg CreateSyncPlanParams) (SyncPlan, error)
+	CreateSyncRun(ctx context.Context, arg CreateSyncRunParams) (SyncRun, error)
+	DeleteSecretGroupPolicy(ctx context.Context, secretGroupID uuid.UUID) error
 	DiffSecretVersions(ctx context.Context, arg DiffSecretVersionsParams) ([]DiffSecretVersionsRow, error)
+	EnqueueAutoSyncJobs(ctx context.Context, arg EnqueueAutoSyncJobsParams) ([]SyncJob, error)
+	EnqueueSyncJob(ctx context.Context, arg EnqueueSyncJobParams) (SyncJob, error)
+	FailExpiredSyncJobs(ctx context.Context) ([]SyncJob, error)
+	FailSyncJob(ctx context.Context, arg FailSyncJobParams) (int64, error)
+	FinishSyncRun(ctx context.Context, arg FinishSyncRunParams) (SyncRun, error)
+	GetEnvironmentSecretGroupID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	GetLatestSecretGroupSecretsForEnvironment(ctx context.Context, id uuid.UUID) ([]GetLatestSecretGroupSecretsForEnvironmentRow, error)
+	GetSecretGroupPolicy(ctx context.Context, secretGroupID uuid.UUID) (SecretGroupPolicy, error)
+	GetSecretGroupSecretsForSecretVersion(ctx context.Context, id string) ([]GetSecretGroupSecretsForSecretVersionRow, error)
+	GetSecretGroupVersion(ctx context.Context, id string) (SecretGroupVersion, error)
+	GetSecretPolicyForEnvironment(ctx context.Context, id uuid.UUID) (GetSecretPolicyForEnvironmentRow, error)
+	GetSecretShare(ctx context.Context, arg GetSecretShareParams) (SecretShare, error)
+	GetSecretShareByTokenHash(ctx context.Context, tokenHash string) (SecretShare, error)
 	GetSecretVersion(ctx context.Context, id string) (SecretVersion, error)
+	GetSecretsForSecretGroupVersion(ctx context.Context, versionID string) ([]GetSecretsForSecretGroupVersionRow, error)
 	GetSecretsForVersion(ctx context.Context, versionID string) ([]GetSecretsForVersionRow, error)
+	GetSyncJob(ctx context.Context, arg GetSyncJobParams) (SyncJob, error)
+	GetSyncPlan(ctx context.Context, arg GetSyncPlanParams) (SyncPlan, error)
+	GetSyncRun(ctx context.Context, arg GetSyncRunParams) (SyncRun, error)
+	HeartbeatSyncJob(ctx context.Context, arg HeartbeatSyncJobParams) (int64, error)
+	InsertDriftCheck(ctx context.Context, arg InsertDriftCheckParams) (DriftCheck, error)
 	InsertSecret(ctx context.Context, arg InsertSecretParams) error
+	InsertSecretGroupSecret(ctx context.Context, arg InsertSecretGroupSecretParams) error
+	InsertSecretKeyChange(ctx context.Context, arg InsertSecretKeyChangeParams) (SecretKeyChange, error)
+	InsertSecretShareEvent(ctx context.Context, arg InsertSecretShareEventParams) error
+	InsertSyncRunResult(ctx context.Context, arg InsertSyncRunResultParams) error
+	ListCurrentSyncedVersions(ctx context.Context, environmentID uuid.UUID) ([]ListCurrentSyncedVersionsRow, error)
+	ListDriftCheckTargets(ctx context.Context) ([]ListDriftCheckTargetsRow, error)
+	ListLatestDriftChecks(ctx context.Context, environmentID uuid.UUID) ([]DriftCheck, error)
+	ListLatestSecretsForOrganization(ctx context.Context, organizationID uuid.UUID) ([]ListLatestSecretsForOrganizationRow, error)
+	ListOrganizationIDs(ctx context.Context) ([]uuid.UUID, error)
+	ListPendingSecretKeyRemovals(ctx context.Context, arg ListPendingSecretKeyRemovalsParams) ([]SecretKeyChange, error)
+	ListSecretGroupEnvironmentIDs(ctx context.Context, secretGroupID uuid.UUID) ([]uuid.UUID, error)
+	ListSecretGroupVersions(ctx context.Context, secretGroupID uuid.UUID) ([]SecretGroupVersion, error)
+	ListSecretKeyChangesForEnvironment(ctx context.Context, environmentID uuid.UUID) ([]SecretKeyChange, error)
+	ListSecretKeyChangesForVersion(ctx context.Context, versionID string) ([]SecretKeyChange, error)
+	ListSecretReuseFindings(ctx context.Context, organizationID uuid.UUID) ([]SecretReuseFinding, error)
+	ListSecretShareEvents(ctx context.Context, shareID uuid.UUID) ([]SecretShareEvent, error)
+	ListSecretSharesForEnvironment(ctx context.Context, environmentID uuid.UUID) ([]SecretShare, error)
 	ListSecretVersions(ctx context.Context, environmentID uuid.UUID) ([]SecretVersion, error)
+	ListSyncJobsForEnvironment(ctx context.Context, arg ListSyncJobsForEnvironmentParams) ([]SyncJob, error)
+	ListSyncRunResults(ctx context.Context, runID uuid.UUID) ([]SyncRunResult, error)
+	ListSyncRunsForEnvironment(ctx context.Context, arg ListSyncRunsForEnvironmentParams) ([]SyncRun, error)
+	ListSyncRunsForTarget(ctx context.Context, arg ListSyncRunsForTargetParams) ([]SyncRun, error)
+	MarkSecretKeyChangeCleanedUp(ctx context.Context, arg MarkSecretKeyChangeCleanedUpParams) error
+	MarkSyncPlanApplied(ctx context.Context, arg MarkSyncPlanAppliedParams) (SyncPlan, error)
+	PurgeExpiredSecretShareValues(ctx context.Context) (int64, error)
+	RecordSecretShareFailedAttempt(ctx context.Context, arg RecordSecretShareFailedAttemptParams) (int32, error)
+	ResolveStaleSecretReuseFindings(ctx context.Context, arg ResolveStaleSecretReuseFindingsParams) (int64, error)
+	RetrySyncJob(ctx context.Context, arg RetrySyncJobParams) (int64, error)
+	RevokeSecretShare(ctx context.Context, arg RevokeSecretShareParams) (SecretShare, error)
 	RollbackSecretsToVersion(ctx context.Context, arg RollbackSecretsToVersionParams) error
+	SetSyncJobRun(ctx context.Context, arg SetSyncJobRunParams) error
+	SetSyncPlanJob(ctx context.Context, arg SetSyncPlanJobParams) error
+	SuppressSecretReuseFinding(ctx context.Context, arg SuppressSecretReuseFindingParams) (SecretReuseFinding, error)
+	UnsuppressSecretReuseFinding(ctx context.Context, arg UnsuppressSecretReuseFindingParams) (SecretReuseFinding, error)
+	UpsertSecretGroupPolicy(ctx context.Context, arg UpsertSecretGroupPolicyParams) (SecretGroupPolicy, error)
+	UpsertSecretReuseFinding(ctx context.Context, arg UpsertSecretReuseFindingParams) (SecretReuseFinding, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -12,9 +12,16 @@ import (
 )
 
 const createSecretVersion = `-- name: CreateSecretVersion :one
-INSERT INTO secret_versions (environment_id, commit_message)
-VALUES ($1, $2)
-RETURNING id, environment_id, commit_message, created_at
+INSERT INTO secret_versions (environment_id, commit_message, group_version_id)
+VALUES ($1, $2, (
+    SELECT sgv.id
+    FROM secret_group_versions sgv
+    JOIN environments e ON e.secret_group_id = sgv.secret_group_id
+    WHERE e.id = $1
+    ORDER BY sgv.created_at DESC
+    LIMIT 1
+))
+RETURNING id, environment_id, commit_message, created_at, group_version_id
 `
 
 type CreateSecretVersionParams struct {
@@ -30,6 +37,7 @@ func (q *Queries) CreateSecretVersion(ctx context.Context, arg CreateSecretVersi
 		&i.EnvironmentID,
 		&i.CommitMessage,
 		&i.CreatedAt,
+		&i.GroupVersionID,
 	)
 	return i, err
 }
@@ -86,7 +94,7 @@ func (q *Queries) DiffSecretVersions(ctx context.Context, arg DiffSecretVersions
 }
 
 const getSecretVersion = `-- name: GetSecretVersion :one
-SELECT id, environment_id, commit_message, created_at FROM secret_versions WHERE id = $1
+SELECT id, environment_id, commit_message, created_at, group_version_id FROM secret_versions WHERE id = $1
 `
 
 func (q *Queries) GetSecretVersion(ctx context.Context, id string) (SecretVersion, error) {
@@ -97,6 +105,7 @@ func (q *Queries) GetSecretVersion(ctx context.Context, id string) (SecretVersio
 		&i.EnvironmentID,
 		&i.CommitMessage,
 		&i.CreatedAt,
+		&i.GroupVersionID,
 	)
 	return i, err
 }
@@ -152,7 +161,7 @@ func (q *Queries) InsertSecret(ctx context.Context, arg InsertSecretParams) erro
 }
 
 const listSecretVersions = `-- name: ListSecretVersions :many
-SELECT id, environment_id, commit_message, created_at FROM secret_versions WHERE environment_id = $1 ORDER BY created_at DESC
+SELECT id, environment_id, commit_message, created_at, group_version_id FROM secret_versions WHERE environment_id = $1 ORDER BY created_at DESC
 `
 
 func (q *Queries) ListSecretVersions(ctx context.Context, environmentID uuid.UUID) ([]SecretVersion, error) {
@@ -169,6 +178,7 @@ func (q *Queries) ListSecretVersions(ctx context.Context, environmentID uuid.UUI
 			&i.EnvironmentID,
 			&i.CommitMessage,
 			&i.CreatedAt,
+			&i.GroupVersionID,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,39 @@
+-- name: CreateSecretGroupVersion :one
+INSERT INTO secret_group_versions (secret_group_id, commit_message)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: InsertSecretGroupSecret :exec
+INSERT INTO secret_group_secrets (version_id, name, value_encrypted)
+VALUES ($1, $2, $3)
+ON CONFLICT (version_id, name) DO UPDATE SET value_encrypted = EXCLUDED.value_encrypted;
+
+-- name: ListSecretGroupVersions :many
+SELECT * FROM secret_group_versions WHERE secret_group_id = $1 ORDER BY created_at DESC;
+
+-- name: GetSecretGroupVersion :one
+SELECT * FROM secret_group_versions WHERE id = $1;
+
+-- name: GetSecretsForSecretGroupVersion :many
+SELECT id, name, value_encrypted FROM secret_group_secrets WHERE version_id = $1;
+
+-- name: GetLatestSecretGroupSecretsForEnvironment :many
+SELECT sgs.name, sgs.value_encrypted
+FROM secret_group_secrets sgs
+WHERE sgs.version_id = (
+    SELECT sgv.id
+    FROM secret_group_versions sgv
+    JOIN environments e ON e.secret_group_id = sgv.secret_group_id
+    WHERE e.id = $1
+    ORDER BY sgv.created_at DESC
+    LIMIT 1
+);
+
+-- name: GetSecretGroupSecretsForSecretVersion :many
+SELECT sgs.name, sgs.value_encrypted
+FROM secret_group_secrets sgs
+JOIN secret_versions sv ON sv.group_version_id = sgs.version_id
+WHERE sv.id = $1;
+
+-- name: ListSecretGroupEnvironmentIDs :many
+SELECT id FROM environments WHERE secret_group_id = $1 ORDER BY created_at;
@@ -1,7 +1,12 @@
 package secret
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	appErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
 	"github.com/Gkemhcs/kavach-backend/internal/utils"
@@ -30,12 +35,61 @@ func RegisterSecretRoutes(handler *SecretHandler, envGroup *gin.RouterGroup) {
 		secretsGroup.POST("/", handler.CreateVersion)
 		secretsGroup.GET("/versions", handler.ListVersions)
 		secretsGroup.GET("/versions/:versionID", handler.GetVersionDetails)
+		secretsGroup.GET("/versions/:versionID/lint", handler.LintVersion)
 		secretsGroup.POST("/rollback", handler.RollbackToVersion)
 		secretsGroup.GET("/diff", handler.GetVersionDiff)
 		secretsGroup.POST("/sync", handler.SyncSecrets)
+		secretsGroup.GET("/sync/jobs", handler.ListSyncJobs)
+		secretsGroup.GET("/sync/jobs/:jobID", handler.GetSyncJob)
+		secretsGroup.GET("/sync/runs", handler.ListSyncRuns)
+		secretsGroup.GET("/sync/runs/:runID", handler.GetSyncRun)
+		secretsGroup.POST("/sync/plans", handler.CreateSyncPlan)
+		secretsGroup.GET("/sync/plans/:planID", handler.GetSyncPlan)
+		secretsGroup.POST("/sync/plans/:planID/apply", handler.ApplySyncPlan)
+		secretsGroup.POST("/sync/key-mapping/preview", handler.PreviewKeyMapping)
+		secretsGroup.POST("/drift", handler.CheckDrift)
+		secretsGroup.GET("/drift", handler.ListDriftChecks)
+		secretsGroup.POST("/rename", handler.RenameKeys)
+		secretsGroup.POST("/move", handler.MoveKeys)
+		secretsGroup.POST("/import", handler.ImportFromProvider)
+		secretsGroup.GET("/keys/:name/history", handler.GetKeyHistory)
+		secretsGroup.POST("/shares", handler.CreateShare)
+		secretsGroup.GET("/shares", handler.ListShares)
+		secretsGroup.GET("/shares/:shareID", handler.GetShare)
+		secretsGroup.DELETE("/shares/:shareID", handler.RevokeShare)
 	}
 }
 
+// RegisterSecretGroupSecretRoutes registers the base secret routes under a secret group
+func RegisterSecretGroupSecretRoutes(handler *SecretHandler, secretGroup *gin.RouterGroup) {
+	groupSecretsGroup := secretGroup.Group("/:groupID/secrets")
+	{
+		groupSecretsGroup.POST("/", handler.CreateGroupVersion)
+		groupSecretsGroup.GET("/versions", handler.ListGroupVersions)
+		groupSecretsGroup.GET("/versions/:versionID", handler.GetGroupVersionDetails)
+		groupSecretsGroup.PUT("/policy", handler.SetGroupPolicy)
+		groupSecretsGroup.GET("/policy", handler.GetGroupPolicy)
+		groupSecretsGroup.DELETE("/policy", handler.DeleteGroupPolicy)
+	}
+}
+
+// RegisterSecretReuseRoutes registers the secret reuse scan and findings routes under an organization
+func RegisterSecretReuseRoutes(handler *SecretHandler, orgGroup *gin.RouterGroup) {
+	reuseGroup := orgGroup.Group("/:orgID/secret-reuse")
+	{
+		reuseGroup.POST("/scan", handler.ScanSecretReuse)
+		reuseGroup.GET("/findings", handler.ListReuseFindings)
+		reuseGroup.POST("/findings/:findingID/suppress", handler.SuppressReuseFinding)
+		reuseGroup.DELETE("/findings/:findingID/suppress", handler.UnsuppressReuseFinding)
+	}
+}
+
+// RegisterPublicShareRoutes registers the public share retrieval route. It must be registered
+// outside the JWT-protected group since share recipients have no Kavach account.
+func RegisterPublicShareRoutes(handler *SecretHandler, v1 *gin.RouterGroup) {
+	v1.POST("/shares/:token", handler.RetrieveShare)
+}
+
 // CreateVersion handles POST /orgs/:orgID/secret-groups/:groupID/environments/:envID/secrets
 func (h *SecretHandler) CreateVersion(c *gin.Context) {
 	environmentID := c.Param("envID")
@@ -63,6 +117,9 @@ func (h *SecretHandler) CreateVersion(c *gin.Context) {
 
 	result, err := h.service.CreateVersion(c.Request.Context(), environmentID, req)
 	if err != nil {
+		if respondPolicyViolation(c, err) {
+			return
+		}
 
 		switch err {
 		case appErrors.ErrEmptySecrets:
@@ -207,6 +264,9 @@ func (h *SecretHandler) RollbackToVersion(c *gin.Context) {
 
 	result, err := h.service.RollbackToVersion(c.Request.Context(), environmentID, req)
 	if err != nil {
+		if respondPolicyViolation(c, err) {
+			return
+		}
 		switch err {
 		case appErrors.ErrEnvironmentNotFound:
 			apiErr := err.(*appErrors.APIError)
@@ -302,6 +362,8 @@ func (h *SecretHandler) GetVersionDiff(c *gin.Context) {
 }
 
 // SyncSecrets handles POST /orgs/:orgID/secret-groups/:groupID/environments/:envID/secrets/sync
+// A sync job is queued per addressed provider target and processed by a background worker; poll the
+// returned jobs for their outcomes.
 func (h *SecretHandler) SyncSecrets(c *gin.Context) {
 	environmentID := c.Param("envID")
 
@@ -322,51 +384,1077 @@ func (h *SecretHandler) SyncSecrets(c *gin.Context) {
 	}
 
 	logEntry.WithFields(logrus.Fields{
+		"targets":    req.Targets,
 		"provider":   req.Provider,
 		"version_id": req.VersionID,
 	}).Info("Request validated successfully")
 
-	result, err := h.service.SyncSecrets(c.Request.Context(), environmentID, req)
+	result, err := h.service.EnqueueSync(c.Request.Context(), environmentID, c.GetString("user_id"), req)
 	if err != nil {
-		logEntry.WithField("error", err.Error()).Error("Failed to sync secrets")
+		logEntry.WithField("error", err.Error()).Error("Failed to queue secret sync")
 		switch err {
-		case appErrors.ErrNoSecretsToSync:
-			utils.RespondError(c, appErrors.ErrNoSecretsToSync.Status, appErrors.ErrNoSecretsToSync.Code, appErrors.ErrNoSecretsToSync.Message)
+		case appErrors.ErrInvalidProviderType, appErrors.ErrSecretVersionNotFound, appErrors.ErrProviderCredentialNotFound,
+			appErrors.ErrNoSyncTargets, appErrors.ErrProviderCredentialListFailed:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			utils.RespondError(c, http.StatusInternalServerError, "sync_secrets_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.WithField("job_count", len(result.Jobs)).Info("Successfully queued secret sync")
+
+	utils.RespondSuccess(c, http.StatusAccepted, result)
+}
+
+// CreateGroupVersion handles POST /orgs/:orgID/secret-groups/:groupID/secrets
+func (h *SecretHandler) CreateGroupVersion(c *gin.Context) {
+	secretGroupID := c.Param("groupID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "CreateGroupVersion",
+		"secret_group_id": secretGroupID,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing create secret group version request")
+
+	var req CreateSecretVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	result, err := h.service.CreateGroupVersion(c.Request.Context(), secretGroupID, req)
+	if err != nil {
+		if respondPolicyViolation(c, err) {
 			return
-		case appErrors.ErrProviderSyncFailed:
-			utils.RespondError(c, appErrors.ErrProviderSyncFailed.Status, appErrors.ErrProviderSyncFailed.Code, appErrors.ErrProviderSyncFailed.Message)
+		}
+
+		switch err {
+		case appErrors.ErrEmptySecrets, appErrors.ErrTooManySecrets, appErrors.ErrInvalidSecretName,
+			appErrors.ErrSecretValueTooLong, appErrors.ErrEncryptionFailed:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
 			return
-		case appErrors.ErrProviderCredentialNotFound:
-			utils.RespondError(c, appErrors.ErrProviderCredentialNotFound.Status, appErrors.ErrProviderCredentialNotFound.Code, appErrors.ErrProviderCredentialNotFound.Message)
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to create secret group version")
+			utils.RespondError(c, http.StatusInternalServerError, "create_group_version_failed", err.Error())
 			return
-		case appErrors.ErrProviderCredentialValidationFailed:
-			utils.RespondError(c, appErrors.ErrProviderCredentialValidationFailed.Status, appErrors.ErrProviderCredentialValidationFailed.Code, appErrors.ErrProviderCredentialValidationFailed.Message)
+		}
+	}
+
+	logEntry.WithFields(logrus.Fields{
+		"version_id":   result.ID,
+		"secret_count": result.SecretCount,
+	}).Info("Successfully created secret group version")
+
+	utils.RespondSuccess(c, http.StatusCreated, result)
+}
+
+// ListGroupVersions handles GET /orgs/:orgID/secret-groups/:groupID/secrets/versions
+func (h *SecretHandler) ListGroupVersions(c *gin.Context) {
+	secretGroupID := c.Param("groupID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "ListGroupVersions",
+		"secret_group_id": secretGroupID,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing list secret group versions request")
+
+	versions, err := h.service.ListGroupVersions(c.Request.Context(), secretGroupID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list secret group versions")
+		utils.RespondError(c, http.StatusInternalServerError, "list_group_versions_failed", err.Error())
+		return
+	}
+
+	logEntry.WithField("version_count", len(versions)).Info("Successfully listed secret group versions")
+
+	utils.RespondSuccess(c, http.StatusOK, versions)
+}
+
+// GetGroupVersionDetails handles GET /orgs/:orgID/secret-groups/:groupID/secrets/versions/:versionID
+func (h *SecretHandler) GetGroupVersionDetails(c *gin.Context) {
+	secretGroupID := c.Param("groupID")
+	versionID := c.Param("versionID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "GetGroupVersionDetails",
+		"secret_group_id": secretGroupID,
+		"version_id":      versionID,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing get secret group version details request")
+
+	version, err := h.service.GetGroupVersionDetails(c.Request.Context(), secretGroupID, versionID)
+	if err != nil {
+		switch err {
+		case appErrors.ErrSecretGroupVersionNotFound, appErrors.ErrSecretNotFound, appErrors.ErrDecryptionFailed:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
 			return
-		case appErrors.ErrGitHubEnvironmentNotFound:
-			utils.RespondError(c, appErrors.ErrGitHubEnvironmentNotFound.Status, appErrors.ErrGitHubEnvironmentNotFound.Code, appErrors.ErrGitHubEnvironmentNotFound.Message)
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to get secret group version details")
+			utils.RespondError(c, http.StatusInternalServerError, "get_group_version_details_failed", err.Error())
 			return
-		case appErrors.ErrGitHubEncryptionFailed:
-			utils.RespondError(c, appErrors.ErrGitHubEncryptionFailed.Status, appErrors.ErrGitHubEncryptionFailed.Code, appErrors.ErrGitHubEncryptionFailed.Message)
+		}
+	}
+
+	logEntry.WithFields(logrus.Fields{
+		"version_id":   versionID,
+		"secret_count": len(version.Secrets),
+	}).Info("Successfully retrieved secret group version details")
+
+	utils.RespondSuccess(c, http.StatusOK, version)
+}
+
+// SetGroupPolicy handles PUT /orgs/:orgID/secret-groups/:groupID/secrets/policy
+func (h *SecretHandler) SetGroupPolicy(c *gin.Context) {
+	secretGroupID := c.Param("groupID")
+	userID := c.GetString("user_id")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "SetGroupPolicy",
+		"secret_group_id": secretGroupID,
+		"user_id":         userID,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing set secret policy request")
+
+	var req SecretPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	result, err := h.service.SetGroupPolicy(c.Request.Context(), secretGroupID, userID, req)
+	if err != nil {
+		switch err {
+		case appErrors.ErrInvalidSecretPolicy:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
 			return
-		case appErrors.ErrInvalidProviderType:
-			utils.RespondError(c, appErrors.ErrInvalidProviderType.Status, appErrors.ErrInvalidProviderType.Code, appErrors.ErrInvalidProviderType.Message)
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to set secret policy")
+			utils.RespondError(c, http.StatusInternalServerError, "set_secret_policy_failed", err.Error())
 			return
-		case appErrors.ErrInternalServer:
-			utils.RespondError(c, appErrors.ErrInternalServer.Status, appErrors.ErrInternalServer.Code, appErrors.ErrInternalServer.Message)
+		}
+	}
+
+	logEntry.Info("Successfully set secret policy")
+
+	utils.RespondSuccess(c, http.StatusOK, result)
+}
+
+// GetGroupPolicy handles GET /orgs/:orgID/secret-groups/:groupID/secrets/policy
+func (h *SecretHandler) GetGroupPolicy(c *gin.Context) {
+	secretGroupID := c.Param("groupID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "GetGroupPolicy",
+		"secret_group_id": secretGroupID,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing get secret policy request")
+
+	result, err := h.service.GetGroupPolicy(c.Request.Context(), secretGroupID)
+	if err != nil {
+		switch err {
+		case appErrors.ErrSecretPolicyNotFound:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
 			return
 		default:
-			utils.RespondError(c, http.StatusInternalServerError, "sync_secrets_failed", err.Error())
+			logEntry.WithField("error", err.Error()).Error("Failed to get secret policy")
+			utils.RespondError(c, http.StatusInternalServerError, "get_secret_policy_failed", err.Error())
+			return
+		}
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, result)
+}
+
+// DeleteGroupPolicy handles DELETE /orgs/:orgID/secret-groups/:groupID/secrets/policy
+func (h *SecretHandler) DeleteGroupPolicy(c *gin.Context) {
+	secretGroupID := c.Param("groupID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "DeleteGroupPolicy",
+		"secret_group_id": secretGroupID,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing delete secret policy request")
+
+	if err := h.service.DeleteGroupPolicy(c.Request.Context(), secretGroupID); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to delete secret policy")
+		utils.RespondError(c, http.StatusInternalServerError, "delete_secret_policy_failed", err.Error())
+		return
+	}
+
+	logEntry.Info("Successfully deleted secret policy")
+
+	utils.RespondSuccess(c, http.StatusOK, gin.H{"message": "secret policy deleted"})
+}
+
+// LintVersion handles GET /orgs/:orgID/secret-groups/:groupID/environments/:envID/secrets/versions/:versionID/lint
+func (h *SecretHandler) LintVersion(c *gin.Context) {
+	versionID := c.Param("versionID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":    "LintVersion",
+		"version_id": versionID,
+		"method":     c.Request.Method,
+		"path":       c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing lint secret version request")
+
+	result, err := h.service.LintVersion(c.Request.Context(), versionID)
+	if err != nil {
+		switch err {
+		case appErrors.ErrSecretVersionNotFound, appErrors.ErrSecretNotFound, appErrors.ErrDecryptionFailed:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to lint secret version")
+			utils.RespondError(c, http.StatusInternalServerError, "lint_version_failed", err.Error())
 			return
 		}
 	}
 
 	logEntry.WithFields(logrus.Fields{
-		"provider":     result.Provider,
-		"status":       result.Status,
-		"synced_count": result.SyncedCount,
-		"failed_count": result.FailedCount,
-		"total_count":  result.TotalCount,
-	}).Info("Successfully synced secrets to provider")
+		"valid":           result.Valid,
+		"violation_count": len(result.Violations),
+	}).Info("Successfully linted secret version")
+
+	utils.RespondSuccess(c, http.StatusOK, result)
+}
+
+// respondPolicyViolation writes a policy violation response and reports whether err was one
+func respondPolicyViolation(c *gin.Context, err error) bool {
+	var policyErr *SecretPolicyViolationError
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+	utils.RespondError(c, appErrors.ErrSecretPolicyViolation.Status, appErrors.ErrSecretPolicyViolation.Code, policyErr.Error())
+	return true
+}
+
+// ScanSecretReuse handles POST /orgs/:orgID/secret-reuse/scan
+func (h *SecretHandler) ScanSecretReuse(c *gin.Context) {
+	organizationID := c.Param("orgID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "ScanSecretReuse",
+		"organization_id": organizationID,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing secret reuse scan request")
+
+	result, err := h.service.ScanSecretReuse(c.Request.Context(), organizationID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to scan for reused secrets")
+		utils.RespondError(c, http.StatusInternalServerError, "secret_reuse_scan_failed", err.Error())
+		return
+	}
+
+	logEntry.WithField("findings_count", result.FindingsCount).Info("Successfully scanned for reused secrets")
+
+	utils.RespondSuccess(c, http.StatusOK, result)
+}
+
+// ListReuseFindings handles GET /orgs/:orgID/secret-reuse/findings
+func (h *SecretHandler) ListReuseFindings(c *gin.Context) {
+	organizationID := c.Param("orgID")
+	includeSuppressed, _ := strconv.ParseBool(c.Query("include_suppressed"))
+	includeResolved, _ := strconv.ParseBool(c.Query("include_resolved"))
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":            "ListReuseFindings",
+		"organization_id":    organizationID,
+		"include_suppressed": includeSuppressed,
+		"include_resolved":   includeResolved,
+		"method":             c.Request.Method,
+		"path":               c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing list secret reuse findings request")
+
+	findings, err := h.service.ListReuseFindings(c.Request.Context(), organizationID, includeSuppressed, includeResolved)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list secret reuse findings")
+		utils.RespondError(c, http.StatusInternalServerError, "list_secret_reuse_findings_failed", err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, findings)
+}
+
+// SuppressReuseFinding handles POST /orgs/:orgID/secret-reuse/findings/:findingID/suppress
+func (h *SecretHandler) SuppressReuseFinding(c *gin.Context) {
+	organizationID := c.Param("orgID")
+	findingID := c.Param("findingID")
+	userID := c.GetString("user_id")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "SuppressReuseFinding",
+		"organization_id": organizationID,
+		"finding_id":      findingID,
+		"user_id":         userID,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing suppress secret reuse finding request")
+
+	var req SuppressReuseFindingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	result, err := h.service.SuppressReuseFinding(c.Request.Context(), organizationID, findingID, userID, req)
+	if err != nil {
+		switch err {
+		case appErrors.ErrSecretReuseFindingNotFound:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to suppress secret reuse finding")
+			utils.RespondError(c, http.StatusInternalServerError, "suppress_secret_reuse_finding_failed", err.Error())
+			return
+		}
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, result)
+}
+
+// UnsuppressReuseFinding handles DELETE /orgs/:orgID/secret-reuse/findings/:findingID/suppress
+func (h *SecretHandler) UnsuppressReuseFinding(c *gin.Context) {
+	organizationID := c.Param("orgID")
+	findingID := c.Param("findingID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":         "UnsuppressReuseFinding",
+		"organization_id": organizationID,
+		"finding_id":      findingID,
+		"method":          c.Request.Method,
+		"path":            c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing unsuppress secret reuse finding request")
+
+	result, err := h.service.UnsuppressReuseFinding(c.Request.Context(), organizationID, findingID)
+	if err != nil {
+		switch err {
+		case appErrors.ErrSecretReuseFindingNotFound:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to unsuppress secret reuse finding")
+			utils.RespondError(c, http.StatusInternalServerError, "unsuppress_secret_reuse_finding_failed", err.Error())
+			return
+		}
+	}
 
 	utils.RespondSuccess(c, http.StatusOK, result)
 }
+
+// RenameKeys handles POST /environments/:envID/secrets/rename
+func (h *SecretHandler) RenameKeys(c *gin.Context) {
+	environmentID := c.Param("envID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "RenameKeys",
+		"environment_id": environmentID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing rename secret keys request")
+
+	var req RenameSecretKeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind rename request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	result, err := h.service.RenameKeys(c.Request.Context(), environmentID, req)
+	if err != nil {
+		if respondPolicyViolation(c, err) {
+			return
+		}
+		switch err {
+		case appErrors.ErrSecretKeyNotFound, appErrors.ErrSecretKeyConflict, appErrors.ErrEmptySecrets,
+			appErrors.ErrInvalidSecretName, appErrors.ErrEncryptionFailed:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to rename secret keys")
+			utils.RespondError(c, http.StatusInternalServerError, "rename_secret_keys_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.WithField("version_id", result.ID).Info("Successfully renamed secret keys")
+
+	utils.RespondSuccess(c, http.StatusCreated, result)
+}
+
+// MoveKeys handles POST /environments/:envID/secrets/move
+func (h *SecretHandler) MoveKeys(c *gin.Context) {
+	environmentID := c.Param("envID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "MoveKeys",
+		"environment_id": environmentID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing move secret keys request")
+
+	var req MoveSecretKeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind move request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	result, err := h.service.MoveKeys(c.Request.Context(), environmentID, req)
+	if err != nil {
+		if respondPolicyViolation(c, err) {
+			return
+		}
+		switch err {
+		case appErrors.ErrSecretKeyNotFound, appErrors.ErrSecretKeyConflict, appErrors.ErrInvalidMoveTarget,
+			appErrors.ErrEnvironmentNotFound, appErrors.ErrEmptySecrets, appErrors.ErrEncryptionFailed:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to move secret keys")
+			utils.RespondError(c, http.StatusInternalServerError, "move_secret_keys_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.WithFields(logrus.Fields{
+		"source_version_id": result.Source.ID,
+		"target_version_id": result.Target.ID,
+	}).Info("Successfully moved secret keys")
+
+	utils.RespondSuccess(c, http.StatusCreated, result)
+}
+
+// GetKeyHistory handles GET /environments/:envID/secrets/keys/:name/history
+func (h *SecretHandler) GetKeyHistory(c *gin.Context) {
+	environmentID := c.Param("envID")
+	name := c.Param("name")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "GetKeyHistory",
+		"environment_id": environmentID,
+		"name":           name,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing key history request")
+
+	history, err := h.service.GetKeyHistory(c.Request.Context(), environmentID, name)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to get key history")
+		utils.RespondError(c, http.StatusInternalServerError, "get_key_history_failed", err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, history)
+}
+
+// CreateShare handles POST /environments/:envID/secrets/shares
+func (h *SecretHandler) CreateShare(c *gin.Context) {
+	environmentID := c.Param("envID")
+	userID := c.GetString("user_id")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "CreateShare",
+		"environment_id": environmentID,
+		"user_id":        userID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing create secret share request")
+
+	var req CreateSecretShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind create share request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	result, err := h.service.CreateShare(c.Request.Context(), environmentID, userID, req, shareClientInfo(c))
+	if err != nil {
+		switch err {
+		case appErrors.ErrInvalidSecretShare, appErrors.ErrSecretVersionNotFound, appErrors.ErrSecretKeyNotFound,
+			appErrors.ErrSecretValueTooLong, appErrors.ErrEncryptionFailed:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to create secret share")
+			utils.RespondError(c, http.StatusInternalServerError, "create_secret_share_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.WithField("share_id", result.ID).Info("Successfully created secret share")
+
+	utils.RespondSuccess(c, http.StatusCreated, result)
+}
+
+// ListShares handles GET /environments/:envID/secrets/shares
+func (h *SecretHandler) ListShares(c *gin.Context) {
+	environmentID := c.Param("envID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "ListShares",
+		"environment_id": environmentID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing list secret shares request")
+
+	shares, err := h.service.ListShares(c.Request.Context(), environmentID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list secret shares")
+		utils.RespondError(c, http.StatusInternalServerError, "list_secret_shares_failed", err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, shares)
+}
+
+// GetShare handles GET /environments/:envID/secrets/shares/:shareID
+func (h *SecretHandler) GetShare(c *gin.Context) {
+	environmentID := c.Param("envID")
+	shareID := c.Param("shareID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "GetShare",
+		"environment_id": environmentID,
+		"share_id":       shareID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing get secret share request")
+
+	share, err := h.service.GetShare(c.Request.Context(), environmentID, shareID)
+	if err != nil {
+		switch err {
+		case appErrors.ErrSecretShareNotFound:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to get secret share")
+			utils.RespondError(c, http.StatusInternalServerError, "get_secret_share_failed", err.Error())
+			return
+		}
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, share)
+}
+
+// RevokeShare handles DELETE /environments/:envID/secrets/shares/:shareID
+func (h *SecretHandler) RevokeShare(c *gin.Context) {
+	environmentID := c.Param("envID")
+	shareID := c.Param("shareID")
+	userID := c.GetString("user_id")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "RevokeShare",
+		"environment_id": environmentID,
+		"share_id":       shareID,
+		"user_id":        userID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing revoke secret share request")
+
+	share, err := h.service.RevokeShare(c.Request.Context(), environmentID, shareID, userID, shareClientInfo(c))
+	if err != nil {
+		switch err {
+		case appErrors.ErrSecretShareNotFound:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to revoke secret share")
+			utils.RespondError(c, http.StatusInternalServerError, "revoke_secret_share_failed", err.Error())
+			return
+		}
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, share)
+}
+
+// RetrieveShare handles POST /shares/:token. This route is public and authenticated by the token only.
+func (h *SecretHandler) RetrieveShare(c *gin.Context) {
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler": "RetrieveShare",
+		"method":  c.Request.Method,
+	})
+
+	logEntry.Info("Processing retrieve secret share request")
+
+	// The passphrase body is optional
+	var req RetrieveSecretShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind retrieve share request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	result, err := h.service.RetrieveShare(c.Request.Context(), c.Param("token"), req, shareClientInfo(c))
+	if err != nil {
+		switch err {
+		case appErrors.ErrSecretShareNotFound, appErrors.ErrInvalidSharePassphrase, appErrors.ErrDecryptionFailed:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to retrieve secret share")
+			utils.RespondError(c, http.StatusInternalServerError, "retrieve_secret_share_failed", "failed to retrieve the shared secret")
+			return
+		}
+	}
+
+	// Shared values must never be cached by browsers or proxies
+	c.Header("Cache-Control", "no-store")
+	utils.RespondSuccess(c, http.StatusOK, result)
+}
+
+// shareClientInfo returns the client details recorded in the audit trail of a share
+func shareClientInfo(c *gin.Context) ShareClientInfo {
+	return ShareClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// ListSyncJobs handles GET /environments/:envID/secrets/sync/jobs
+func (h *SecretHandler) ListSyncJobs(c *gin.Context) {
+	environmentID := c.Param("envID")
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "ListSyncJobs",
+		"environment_id": environmentID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing list sync jobs request")
+
+	jobs, err := h.service.ListSyncJobs(c.Request.Context(), environmentID, limit)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list sync jobs")
+		utils.RespondError(c, http.StatusInternalServerError, "list_sync_jobs_failed", err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, jobs)
+}
+
+// GetSyncJob handles GET /environments/:envID/secrets/sync/jobs/:jobID
+func (h *SecretHandler) GetSyncJob(c *gin.Context) {
+	environmentID := c.Param("envID")
+	jobID := c.Param("jobID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "GetSyncJob",
+		"environment_id": environmentID,
+		"job_id":         jobID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing get sync job request")
+
+	job, err := h.service.GetSyncJob(c.Request.Context(), environmentID, jobID)
+	if err != nil {
+		switch err {
+		case appErrors.ErrSyncJobNotFound:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to get sync job")
+			utils.RespondError(c, http.StatusInternalServerError, "get_sync_job_failed", err.Error())
+			return
+		}
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, job)
+}
+
+// ListSyncRuns handles GET /environments/:envID/secrets/sync/runs
+func (h *SecretHandler) ListSyncRuns(c *gin.Context) {
+	environmentID := c.Param("envID")
+	target := c.Query("target")
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "ListSyncRuns",
+		"environment_id": environmentID,
+		"target":         target,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing list sync runs request")
+
+	runs, err := h.service.ListSyncRuns(c.Request.Context(), environmentID, target, limit)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list sync runs")
+		utils.RespondError(c, http.StatusInternalServerError, "list_sync_runs_failed", err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, runs)
+}
+
+// GetSyncRun handles GET /environments/:envID/secrets/sync/runs/:runID
+func (h *SecretHandler) GetSyncRun(c *gin.Context) {
+	environmentID := c.Param("envID")
+	runID := c.Param("runID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "GetSyncRun",
+		"environment_id": environmentID,
+		"run_id":         runID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing get sync run request")
+
+	run, err := h.service.GetSyncRun(c.Request.Context(), environmentID, runID)
+	if err != nil {
+		switch err {
+		case appErrors.ErrSyncRunNotFound:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to get sync run")
+			utils.RespondError(c, http.StatusInternalServerError, "get_sync_run_failed", err.Error())
+			return
+		}
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, run)
+}
+
+// CreateSyncPlan handles POST /environments/:envID/secrets/sync/plans
+// The plan lists what a sync would change without touching the provider.
+func (h *SecretHandler) CreateSyncPlan(c *gin.Context) {
+	environmentID := c.Param("envID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "CreateSyncPlan",
+		"environment_id": environmentID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing create sync plan request")
+
+	var req SyncPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	plan, err := h.service.CreateSyncPlan(c.Request.Context(), environmentID, c.GetString("user_id"), req)
+	if err != nil {
+		switch err {
+		case appErrors.ErrSecretVersionNotFound, appErrors.ErrNoSecretsToSync, appErrors.ErrProviderCredentialNotFound,
+			appErrors.ErrSyncPlanNotSupported, appErrors.ErrMirrorModeRequiresPrefix, appErrors.ErrProviderConnectionNotGranted,
+			appErrors.ErrKeyMappingCollision:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to create sync plan")
+			utils.RespondError(c, http.StatusInternalServerError, "create_sync_plan_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.WithField("plan_id", plan.ID).Info("Successfully created sync plan")
+
+	utils.RespondSuccess(c, http.StatusCreated, plan)
+}
+
+// GetSyncPlan handles GET /environments/:envID/secrets/sync/plans/:planID
+func (h *SecretHandler) GetSyncPlan(c *gin.Context) {
+	environmentID := c.Param("envID")
+	planID := c.Param("planID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "GetSyncPlan",
+		"environment_id": environmentID,
+		"plan_id":        planID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing get sync plan request")
+
+	plan, err := h.service.GetSyncPlan(c.Request.Context(), environmentID, planID)
+	if err != nil {
+		switch err {
+		case appErrors.ErrSyncPlanNotFound:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to get sync plan")
+			utils.RespondError(c, http.StatusInternalServerError, "get_sync_plan_failed", err.Error())
+			return
+		}
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, plan)
+}
+
+// ApplySyncPlan handles POST /environments/:envID/secrets/sync/plans/:planID/apply
+// The plan is queued as a sync job; poll the returned job for its outcome.
+func (h *SecretHandler) ApplySyncPlan(c *gin.Context) {
+	environmentID := c.Param("envID")
+	planID := c.Param("planID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "ApplySyncPlan",
+		"environment_id": environmentID,
+		"plan_id":        planID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing apply sync plan request")
+
+	job, err := h.service.ApplySyncPlan(c.Request.Context(), environmentID, c.GetString("user_id"), planID)
+	if err != nil {
+		switch err {
+		case appErrors.ErrSyncPlanNotFound, appErrors.ErrSyncPlanAlreadyApplied, appErrors.ErrSyncPlanExpired, appErrors.ErrSyncPlanHasCollisions:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to apply sync plan")
+			utils.RespondError(c, http.StatusInternalServerError, "apply_sync_plan_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.WithField("job_id", job.ID).Info("Successfully queued sync plan")
+
+	utils.RespondSuccess(c, http.StatusAccepted, job)
+}
+
+// PreviewKeyMapping handles POST /environments/:envID/secrets/sync/key-mapping/preview
+func (h *SecretHandler) PreviewKeyMapping(c *gin.Context) {
+	environmentID := c.Param("envID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "PreviewKeyMapping",
+		"environment_id": environmentID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing preview key mapping request")
+
+	var req KeyMappingPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	preview, err := h.service.PreviewKeyMapping(c.Request.Context(), environmentID, req)
+	if err != nil {
+		switch err {
+		case appErrors.ErrKeyMappingCollision:
+			// Name the colliding keys so the rules can be fixed without another round trip
+			collisions := make([]string, len(preview.Collisions))
+			for i, collision := range preview.Collisions {
+				collisions[i] = fmt.Sprintf("%s <- %s", collision.RemoteName, strings.Join(collision.Names, ", "))
+			}
+			utils.RespondError(c, http.StatusConflict, appErrors.ErrKeyMappingCollision.Code,
+				fmt.Sprintf("%s: %s", appErrors.ErrKeyMappingCollision.Message, strings.Join(collisions, "; ")))
+			return
+		case appErrors.ErrNoSecretsToSync, appErrors.ErrProviderCredentialNotFound, appErrors.ErrInvalidKeyMapping,
+			appErrors.ErrProviderConnectionNotGranted:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to preview key mapping")
+			utils.RespondError(c, http.StatusInternalServerError, "preview_key_mapping_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.WithField("key_count", len(preview.Keys)).Info("Successfully previewed key mapping")
+
+	utils.RespondSuccess(c, http.StatusOK, preview)
+}
+
+// ImportFromProvider handles POST /environments/:envID/secrets/import
+func (h *SecretHandler) ImportFromProvider(c *gin.Context) {
+	environmentID := c.Param("envID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "ImportFromProvider",
+		"environment_id": environmentID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing import from provider request")
+
+	var req ImportFromProviderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	result, err := h.service.ImportFromProvider(c.Request.Context(), environmentID, req)
+	if err != nil {
+		if respondPolicyViolation(c, err) {
+			return
+		}
+
+		switch err {
+		case appErrors.ErrImportNotSupported, appErrors.ErrImportHasCollisions, appErrors.ErrProviderCredentialNotFound,
+			appErrors.ErrProviderConnectionNotGranted, appErrors.ErrTooManySecrets:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to import from provider")
+			utils.RespondError(c, http.StatusInternalServerError, "import_from_provider_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.WithFields(logrus.Fields{
+		"version_id":   result.VersionID,
+		"change_count": len(result.Changes),
+	}).Info("Successfully imported from provider")
+
+	status := http.StatusOK
+	if result.VersionID != "" {
+		status = http.StatusCreated
+	}
+	utils.RespondSuccess(c, status, result)
+}
+
+// CheckDrift handles POST /environments/:envID/secrets/drift
+// The body is optional; without targets or a provider type every provider target is checked.
+func (h *SecretHandler) CheckDrift(c *gin.Context) {
+	environmentID := c.Param("envID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "CheckDrift",
+		"environment_id": environmentID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing drift check request")
+
+	var req DriftCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		logEntry.WithField("error", err.Error()).Error("Failed to bind request body")
+		utils.RespondError(c, appErrors.ErrInvalidBody.Status, appErrors.ErrInvalidBody.Code, appErrors.ErrInvalidBody.Message)
+		return
+	}
+
+	checks, err := h.service.CheckDrift(c.Request.Context(), environmentID, c.GetString("user_id"), req)
+	if err != nil {
+		switch err {
+		case appErrors.ErrInvalidProviderType, appErrors.ErrProviderCredentialListFailed, appErrors.ErrSecretVersionNotFound,
+			appErrors.ErrProviderCredentialNotFound, appErrors.ErrNoSyncTargets:
+			apiErr := err.(*appErrors.APIError)
+			utils.RespondError(c, apiErr.Status, apiErr.Code, apiErr.Message)
+			return
+		default:
+			logEntry.WithField("error", err.Error()).Error("Failed to check drift")
+			utils.RespondError(c, http.StatusInternalServerError, "check_drift_failed", err.Error())
+			return
+		}
+	}
+
+	logEntry.WithField("check_count", len(checks)).Info("Successfully checked providers for drift")
+
+	utils.RespondSuccess(c, http.StatusOK, checks)
+}
+
+// ListDriftChecks handles GET /environments/:envID/secrets/drift
+func (h *SecretHandler) ListDriftChecks(c *gin.Context) {
+	environmentID := c.Param("envID")
+
+	logEntry := h.logger.WithFields(logrus.Fields{
+		"handler":        "ListDriftChecks",
+		"environment_id": environmentID,
+		"method":         c.Request.Method,
+		"path":           c.Request.URL.Path,
+	})
+
+	logEntry.Info("Processing list drift checks request")
+
+	checks, err := h.service.ListDriftChecks(c.Request.Context(), environmentID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list drift checks")
+		utils.RespondError(c, http.StatusInternalServerError, "list_drift_checks_failed", err.Error())
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, checks)
+}
@@ -0,0 +1,236 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	apiErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/Gkemhcs/kavach-backend/internal/provider"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ImportFromProvider reads the secrets a provider target holds and creates a new version of the environment
+// with them, on top of the latest version. Remote names are mapped back to keys through the names the latest
+// version syncs under, then by reversing the provider prefix and the target's key mapping. A dry run only
+// reports the changes.
+func (s *SecretService) ImportFromProvider(ctx context.Context, environmentID string, req ImportFromProviderRequest) (*ImportFromProviderResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":         "ImportFromProvider",
+		"environment_id": environmentID,
+		"target":         req.Target,
+		"dry_run":        req.DryRun,
+	})
+
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	syncer, syncOptions, err := s.providerService.GetProviderSyncerWithOptions(ctx, environmentID, req.Target)
+	if err != nil {
+		return nil, err
+	}
+	importer, ok := syncer.(provider.ProviderImporter)
+	if !ok {
+		return nil, apiErrors.ErrImportNotSupported
+	}
+
+	// The import builds on the latest version, or on the secret group base of an environment without versions
+	response := &ImportFromProviderResponse{
+		Target:   req.Target,
+		Provider: string(syncOptions.Provider),
+		DryRun:   req.DryRun,
+	}
+	var current []SecretWithValue
+	versions, err := s.repo.ListSecretVersions(ctx, environmentUUID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list secret versions")
+		return nil, fmt.Errorf("failed to list secret versions: %w", err)
+	}
+	if len(versions) > 0 {
+		details, err := s.getCurrentVersionDetails(ctx, versions[0].ID)
+		if err != nil {
+			return nil, err
+		}
+		response.BaseVersionID = details.ID
+		current = details.Secrets
+	} else {
+		current, err = s.getGroupBaseSecrets(ctx, environmentUUID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	remote, err := importer.Import(ctx)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to read provider secrets")
+		return nil, err
+	}
+	sort.Slice(remote, func(i, j int) bool { return remote[i].Name < remote[j].Name })
+
+	keys, imported := s.importKeys(importer, syncer, syncOptions.KeyMapping, current, remote)
+
+	// Keys several remote secrets map to are left out, since either value could be the right one
+	sourcesByName := make(map[string][]string)
+	for _, key := range keys {
+		if key.Skipped == "" {
+			sourcesByName[key.Name] = append(sourcesByName[key.Name], key.RemoteName)
+		}
+	}
+	response.Collisions = []ImportCollision{}
+	for i, key := range keys {
+		if key.Skipped == "" && len(sourcesByName[key.Name]) > 1 {
+			keys[i].Skipped = ImportSkipCollision
+		}
+	}
+	for name, remoteNames := range sourcesByName {
+		if len(remoteNames) > 1 {
+			response.Collisions = append(response.Collisions, ImportCollision{Name: name, RemoteNames: remoteNames})
+			delete(imported, name)
+		}
+	}
+	sort.Slice(response.Collisions, func(i, j int) bool { return response.Collisions[i].Name < response.Collisions[j].Name })
+	response.Keys = keys
+
+	// Compare with the latest version; only added and modified keys go into the new version
+	currentByName := make(map[string]SecretWithValue, len(current))
+	for _, secret := range current {
+		currentByName[secret.Name] = secret
+	}
+	names := make([]string, 0, len(imported))
+	for name := range imported {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	response.Changes = []SecretDiffChange{}
+	for _, name := range names {
+		existing, found := currentByName[name]
+		switch {
+		case !found:
+			response.Changes = append(response.Changes, SecretDiffChange{Name: name, Type: "added"})
+		case existing.Value != imported[name]:
+			response.Changes = append(response.Changes, SecretDiffChange{Name: name, Type: "modified", Source: existing.Source})
+		default:
+			response.Unchanged++
+		}
+	}
+
+	logEntry = logEntry.WithFields(logrus.Fields{
+		"remote_count":    len(remote),
+		"change_count":    len(response.Changes),
+		"collision_count": len(response.Collisions),
+	})
+
+	if req.DryRun {
+		logEntry.Info("Completed import dry run")
+		return response, nil
+	}
+	if len(response.Collisions) > 0 {
+		logEntry.Error("Import has remote secrets mapping to the same key")
+		return nil, apiErrors.ErrImportHasCollisions
+	}
+	if len(response.Changes) == 0 {
+		logEntry.Info("Provider holds no changes to import")
+		return response, nil
+	}
+
+	// The new version keeps the environment's own keys and overrides them and the group base with the import
+	changed := make(map[string]bool, len(response.Changes))
+	for _, change := range response.Changes {
+		changed[change.Name] = true
+	}
+	var inputs []SecretInput
+	for _, secret := range current {
+		if secret.Source != SecretSourceGroup && !changed[secret.Name] {
+			inputs = append(inputs, SecretInput{Name: secret.Name, Value: secret.Value})
+		}
+	}
+	for _, change := range response.Changes {
+		inputs = append(inputs, SecretInput{Name: change.Name, Value: imported[change.Name]})
+	}
+
+	commitMessage := req.CommitMessage
+	if commitMessage == "" {
+		commitMessage = fmt.Sprintf("Import from %s", req.Target)
+	}
+	version, err := s.CreateVersion(ctx, environmentID, CreateSecretVersionRequest{Secrets: inputs, CommitMessage: commitMessage})
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to create imported version")
+		return nil, err
+	}
+	response.VersionID = version.ID
+
+	logEntry.WithField("version_id", version.ID).Info("Successfully imported secrets from provider")
+	return response, nil
+}
+
+// importKeys maps remote secrets back to keys and returns where each one ends up, with the imported values by key
+func (s *SecretService) importKeys(importer provider.ProviderImporter, syncer provider.ProviderSyncer, rules provider.KeyMappingRules, current []SecretWithValue, remote []provider.RemoteSecret) ([]ImportedKey, map[string]string) {
+	// Remote names the latest version syncs under map back exactly, whatever sanitizing did to them
+	knownKeys := make(map[string]string)
+	knownGroups := make(map[string]string)
+	if reader, ok := syncer.(provider.ProviderReader); ok {
+		for _, secret := range current {
+			if mapped, ok := rules.MapName(secret.Name); ok {
+				knownKeys[reader.RemoteName(mapped)] = secret.Name
+			}
+		}
+		for _, group := range rules.Groups {
+			knownGroups[reader.RemoteName(group.Name)] = group.Name
+		}
+	}
+
+	keys := []ImportedKey{}
+	imported := make(map[string]string)
+	add := func(key ImportedKey, value string) {
+		switch {
+		case s.encrypt.ValidateSecretName(key.Name) != nil:
+			key.Skipped = ImportSkipInvalidName
+		case value == "":
+			key.Skipped = ImportSkipEmptyValue
+		default:
+			imported[key.Name] = value
+		}
+		keys = append(keys, key)
+	}
+
+	for _, secret := range remote {
+		name, known := knownKeys[secret.Name]
+		group, isGroup := knownGroups[secret.Name]
+		if !known && !isGroup {
+			mapped, ok := importer.ImportName(secret.Name)
+			if !ok {
+				keys = append(keys, ImportedKey{RemoteName: secret.Name, Skipped: ImportSkipOutsidePrefix})
+				continue
+			}
+			name, isGroup = rules.UnmapName(mapped)
+			group = mapped
+		}
+
+		if !isGroup {
+			add(ImportedKey{RemoteName: secret.Name, Name: name}, secret.Value)
+			continue
+		}
+
+		// A group secret holds its keys as a JSON object of their original names and values
+		var values map[string]string
+		if err := json.Unmarshal([]byte(secret.Value), &values); err != nil || len(values) == 0 {
+			keys = append(keys, ImportedKey{RemoteName: secret.Name, Group: group, Skipped: ImportSkipInvalidGroup})
+			continue
+		}
+		groupKeys := make([]string, 0, len(values))
+		for key := range values {
+			groupKeys = append(groupKeys, key)
+		}
+		sort.Strings(groupKeys)
+		for _, key := range groupKeys {
+			add(ImportedKey{RemoteName: secret.Name, Name: key, Group: group}, values[key])
+		}
+	}
+
+	return keys, imported
+}
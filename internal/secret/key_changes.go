@@ -0,0 +1,598 @@
+package secret
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	apiErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/Gkemhcs/kavach-backend/internal/provider"
+	secretdb "github.com/Gkemhcs/kavach-backend/internal/secret/gen"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// maxKeyHistoryMoves bounds how many moves between environments a key history follows
+const maxKeyHistoryMoves = 10
+
+// RenameKeys creates a new version of an environment with keys renamed and records each rename
+// in the version metadata so history follows the key and the next sync removes the old remote name
+func (s *SecretService) RenameKeys(ctx context.Context, environmentID string, req RenameSecretKeysRequest) (*SecretVersionResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":         "RenameKeys",
+		"environment_id": environmentID,
+		"rename_count":   len(req.Renames),
+	})
+
+	logEntry.Info("Renaming secret keys")
+
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Renames) == 0 {
+		return nil, apiErrors.ErrEmptySecrets
+	}
+
+	_, secrets, err := s.loadLatestSecrets(ctx, environmentUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	present := make(map[string]bool, len(secrets))
+	for _, secret := range secrets {
+		present[secret.Name] = true
+	}
+
+	// Resolve the new name of every renamed key, rejecting unknown sources and taken targets
+	newNames := make(map[string]string, len(req.Renames))
+	targets := make(map[string]bool, len(req.Renames))
+	for _, rename := range req.Renames {
+		if !present[rename.From] {
+			return nil, apiErrors.ErrSecretKeyNotFound
+		}
+		if _, ok := newNames[rename.From]; ok {
+			return nil, fmt.Errorf("duplicate rename of secret key: %s", rename.From)
+		}
+		if targets[rename.To] {
+			return nil, apiErrors.ErrSecretKeyConflict
+		}
+		newNames[rename.From] = rename.To
+		targets[rename.To] = true
+	}
+
+	// A new name may only be taken by a key that is itself renamed away
+	for _, rename := range req.Renames {
+		if _, renamedAway := newNames[rename.To]; present[rename.To] && !renamedAway {
+			return nil, apiErrors.ErrSecretKeyConflict
+		}
+	}
+
+	versionReq := CreateSecretVersionRequest{CommitMessage: req.CommitMessage}
+	for _, secret := range secrets {
+		if newName, ok := newNames[secret.Name]; ok {
+			secret.Name = newName
+		}
+		versionReq.Secrets = append(versionReq.Secrets, secret)
+	}
+
+	if err := s.validateCreateVersionRequest(ctx, environmentUUID, versionReq); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Renamed secrets do not satisfy validation")
+		return nil, err
+	}
+
+	// The version and its renames are stored together, so a version never lacks the renames the next sync relies on
+	var version secretdb.SecretVersion
+	err = s.repo.WithTx(ctx, func(repo secretdb.Querier) error {
+		var err error
+		version, err = s.storeSecretVersion(ctx, repo, environmentUUID, versionReq)
+		if err != nil {
+			return err
+		}
+
+		for _, rename := range req.Renames {
+			if rename.From == rename.To {
+				continue
+			}
+			_, err := repo.InsertSecretKeyChange(ctx, secretdb.InsertSecretKeyChangeParams{
+				VersionID:     version.ID,
+				EnvironmentID: environmentUUID,
+				ChangeType:    KeyChangeRename,
+				OldName:       rename.From,
+				NewName:       rename.To,
+			})
+			if err != nil {
+				logEntry.WithField("error", err.Error()).Error("Failed to record key rename")
+				return fmt.Errorf("failed to record rename of %s: %w", rename.From, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logEntry.WithField("version_id", version.ID).Info("Successfully renamed secret keys")
+
+	s.scheduleAutoSync(ctx, environmentUUID)
+
+	return &SecretVersionResponse{
+		ID:            version.ID,
+		EnvironmentID: version.EnvironmentID,
+		CommitMessage: version.CommitMessage,
+		CreatedAt:     version.CreatedAt,
+		SecretCount:   len(versionReq.Secrets),
+	}, nil
+}
+
+// MoveKeys moves keys to another environment of the same secret group. A new version is created
+// in both environments and the move is recorded on each side so history follows the key.
+func (s *SecretService) MoveKeys(ctx context.Context, environmentID string, req MoveSecretKeysRequest) (*MoveSecretKeysResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":                "MoveKeys",
+		"environment_id":        environmentID,
+		"target_environment_id": req.TargetEnvironmentID,
+		"key_count":             len(req.Keys),
+	})
+
+	logEntry.Info("Moving secret keys")
+
+	sourceUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+	targetUUID, err := uuid.Parse(req.TargetEnvironmentID)
+	if err != nil || targetUUID == sourceUUID {
+		return nil, apiErrors.ErrInvalidMoveTarget
+	}
+
+	if len(req.Keys) == 0 {
+		return nil, apiErrors.ErrEmptySecrets
+	}
+
+	// Both environments must belong to the same secret group
+	sourceGroupID, err := s.repo.GetEnvironmentSecretGroupID(ctx, sourceUUID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to get source environment")
+		return nil, apiErrors.ErrEnvironmentNotFound
+	}
+	targetGroupID, err := s.repo.GetEnvironmentSecretGroupID(ctx, targetUUID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to get target environment")
+		return nil, apiErrors.ErrInvalidMoveTarget
+	}
+	if sourceGroupID != targetGroupID {
+		return nil, apiErrors.ErrInvalidMoveTarget
+	}
+
+	_, sourceSecrets, err := s.loadLatestSecrets(ctx, sourceUUID)
+	if err != nil {
+		return nil, err
+	}
+	_, targetSecrets, err := s.loadLatestSecrets(ctx, targetUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	moving := make(map[string]bool, len(req.Keys))
+	for _, key := range req.Keys {
+		if moving[key] {
+			return nil, fmt.Errorf("duplicate secret key: %s", key)
+		}
+		moving[key] = true
+	}
+	for _, secret := range targetSecrets {
+		if moving[secret.Name] {
+			return nil, apiErrors.ErrSecretKeyConflict
+		}
+	}
+
+	sourceReq := CreateSecretVersionRequest{CommitMessage: req.CommitMessage}
+	targetReq := CreateSecretVersionRequest{CommitMessage: req.CommitMessage, Secrets: targetSecrets}
+	found := 0
+	for _, secret := range sourceSecrets {
+		if moving[secret.Name] {
+			targetReq.Secrets = append(targetReq.Secrets, secret)
+			found++
+			continue
+		}
+		sourceReq.Secrets = append(sourceReq.Secrets, secret)
+	}
+	if found != len(moving) {
+		return nil, apiErrors.ErrSecretKeyNotFound
+	}
+
+	if err := s.validateCreateVersionRequest(ctx, sourceUUID, sourceReq); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Source secrets after move do not satisfy validation")
+		return nil, err
+	}
+	if err := s.validateCreateVersionRequest(ctx, targetUUID, targetReq); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Target secrets after move do not satisfy validation")
+		return nil, err
+	}
+
+	// Both versions and the moves are stored together, so a failure never leaves the keys in neither environment
+	var sourceVersion, targetVersion secretdb.SecretVersion
+	err = s.repo.WithTx(ctx, func(repo secretdb.Querier) error {
+		var err error
+		targetVersion, err = s.storeSecretVersion(ctx, repo, targetUUID, targetReq)
+		if err != nil {
+			return err
+		}
+		sourceVersion, err = s.storeSecretVersion(ctx, repo, sourceUUID, sourceReq)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range req.Keys {
+			changes := []secretdb.InsertSecretKeyChangeParams{
+				{
+					VersionID:            sourceVersion.ID,
+					EnvironmentID:        sourceUUID,
+					ChangeType:           KeyChangeMoveOut,
+					OldName:              key,
+					NewName:              key,
+					RelatedEnvironmentID: uuid.NullUUID{UUID: targetUUID, Valid: true},
+					RelatedVersionID:     sql.NullString{String: targetVersion.ID, Valid: true},
+				},
+				{
+					VersionID:            targetVersion.ID,
+					EnvironmentID:        targetUUID,
+					ChangeType:           KeyChangeMoveIn,
+					OldName:              key,
+					NewName:              key,
+					RelatedEnvironmentID: uuid.NullUUID{UUID: sourceUUID, Valid: true},
+					RelatedVersionID:     sql.NullString{String: sourceVersion.ID, Valid: true},
+				},
+			}
+			for _, change := range changes {
+				if _, err := repo.InsertSecretKeyChange(ctx, change); err != nil {
+					logEntry.WithField("error", err.Error()).Error("Failed to record key move")
+					return fmt.Errorf("failed to record move of %s: %w", key, err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logEntry.WithFields(logrus.Fields{
+		"source_version_id": sourceVersion.ID,
+		"target_version_id": targetVersion.ID,
+	}).Info("Successfully moved secret keys")
+
+	s.scheduleAutoSync(ctx, sourceUUID)
+	s.scheduleAutoSync(ctx, targetUUID)
+
+	return &MoveSecretKeysResponse{
+		Source: SecretVersionResponse{
+			ID:            sourceVersion.ID,
+			EnvironmentID: sourceVersion.EnvironmentID,
+			CommitMessage: sourceVersion.CommitMessage,
+			CreatedAt:     sourceVersion.CreatedAt,
+			SecretCount:   len(sourceReq.Secrets),
+		},
+		Target: SecretVersionResponse{
+			ID:            targetVersion.ID,
+			EnvironmentID: targetVersion.EnvironmentID,
+			CommitMessage: targetVersion.CommitMessage,
+			CreatedAt:     targetVersion.CreatedAt,
+			SecretCount:   len(targetReq.Secrets),
+		},
+	}, nil
+}
+
+// keySnapshot is the state of a tracked key in a single version
+type keySnapshot struct {
+	version secretdb.SecretVersion
+	name    string
+	value   string
+	present bool
+	change  *secretdb.SecretKeyChange // Rename or move that produced the name in this version
+}
+
+// GetKeyHistory returns the versions in which a key was added, modified, removed, renamed or moved,
+// newest first. Renames and moves are followed back to the key's previous name and environment.
+func (s *SecretService) GetKeyHistory(ctx context.Context, environmentID, name string) ([]SecretKeyHistoryEntry, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":         "GetKeyHistory",
+		"environment_id": environmentID,
+		"name":           name,
+	})
+
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var chain []keySnapshot
+	trackedName := name
+	var cutoff *secretdb.SecretVersion
+	for moves := 0; moves <= maxKeyHistoryMoves; moves++ {
+		versions, err := s.repo.ListSecretVersions(ctx, environmentUUID)
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Error("Failed to list secret versions")
+			return nil, fmt.Errorf("failed to list secret versions: %w", err)
+		}
+		changes, err := s.repo.ListSecretKeyChangesForEnvironment(ctx, environmentUUID)
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Error("Failed to list key changes")
+			return nil, fmt.Errorf("failed to list key changes: %w", err)
+		}
+		changesByVersion := make(map[string][]secretdb.SecretKeyChange)
+		for _, change := range changes {
+			changesByVersion[change.VersionID] = append(changesByVersion[change.VersionID], change)
+		}
+
+		var moveIn *secretdb.SecretKeyChange
+		for _, version := range versions {
+			// After following a move, only versions older than the move matter
+			if cutoff != nil && !version.CreatedAt.Before(cutoff.CreatedAt) {
+				continue
+			}
+
+			snapshot, err := s.snapshotKey(ctx, version, trackedName)
+			if err != nil {
+				return nil, err
+			}
+
+			for i := range changesByVersion[version.ID] {
+				change := changesByVersion[version.ID][i]
+				if change.NewName == trackedName && (change.ChangeType == KeyChangeRename || change.ChangeType == KeyChangeMoveIn) {
+					snapshot.change = &change
+					break
+				}
+			}
+			chain = append(chain, snapshot)
+
+			if snapshot.change == nil {
+				continue
+			}
+			trackedName = snapshot.change.OldName
+			if snapshot.change.ChangeType == KeyChangeMoveIn {
+				moveIn = snapshot.change
+				break
+			}
+		}
+
+		if moveIn == nil || !moveIn.RelatedEnvironmentID.Valid || !moveIn.RelatedVersionID.Valid {
+			break
+		}
+
+		// Continue in the source environment from before the key was moved out
+		moveOutVersion, err := s.repo.GetSecretVersion(ctx, moveIn.RelatedVersionID.String)
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Warn("Move source version no longer exists, stopping history")
+			break
+		}
+		environmentUUID = moveIn.RelatedEnvironmentID.UUID
+		cutoff = &moveOutVersion
+	}
+
+	history := []SecretKeyHistoryEntry{}
+	for i, newer := range chain {
+		var older *keySnapshot
+		if i+1 < len(chain) {
+			older = &chain[i+1]
+		}
+
+		entry := SecretKeyHistoryEntry{
+			VersionID:     newer.version.ID,
+			EnvironmentID: newer.version.EnvironmentID,
+			Name:          newer.name,
+			CommitMessage: newer.version.CommitMessage,
+			CreatedAt:     newer.version.CreatedAt,
+		}
+
+		switch {
+		case newer.present && (older == nil || !older.present):
+			entry.Change = KeyHistoryAdded
+		case !newer.present && older != nil && older.present:
+			entry.Name = older.name
+			entry.Change = KeyHistoryRemoved
+		case newer.present && newer.change != nil:
+			entry.Change = KeyHistoryRenamed
+			entry.PreviousName = older.name
+			if newer.change.ChangeType == KeyChangeMoveIn {
+				entry.Change = KeyHistoryMovedIn
+				entry.PreviousEnvironmentID = &older.version.EnvironmentID
+			}
+		case newer.present && newer.value != older.value:
+			entry.Change = KeyHistoryModified
+		default:
+			continue
+		}
+		history = append(history, entry)
+	}
+
+	logEntry.WithField("entry_count", len(history)).Info("Successfully retrieved key history")
+	return history, nil
+}
+
+// snapshotKey decrypts the value of a key in a version, if present
+func (s *SecretService) snapshotKey(ctx context.Context, version secretdb.SecretVersion, name string) (keySnapshot, error) {
+	snapshot := keySnapshot{version: version, name: name}
+
+	secrets, err := s.repo.GetSecretsForVersion(ctx, version.ID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to get secrets for version")
+		return snapshot, fmt.Errorf("failed to get secrets for version %s: %w", version.ID, err)
+	}
+
+	for _, secret := range secrets {
+		if secret.Name != name {
+			continue
+		}
+		value, err := s.encrypt.Decrypt(secret.ValueEncrypted)
+		if err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"error": err.Error(),
+				"name":  secret.Name,
+			}).Error("Failed to decrypt secret value")
+			return snapshot, err
+		}
+		snapshot.value = value
+		snapshot.present = true
+		break
+	}
+
+	return snapshot, nil
+}
+
+// loadLatestSecrets returns the latest version of an environment and its decrypted secrets.
+// The version is nil when the environment has no versions yet.
+func (s *SecretService) loadLatestSecrets(ctx context.Context, environmentID uuid.UUID) (*secretdb.SecretVersion, []SecretInput, error) {
+	versions, err := s.repo.ListSecretVersions(ctx, environmentID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to list secret versions")
+		return nil, nil, fmt.Errorf("failed to list secret versions: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, nil, nil
+	}
+
+	secrets, err := s.repo.GetSecretsForVersion(ctx, versions[0].ID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to get secrets for version")
+		return nil, nil, apiErrors.ErrSecretNotFound
+	}
+
+	inputs := make([]SecretInput, len(secrets))
+	for i, secret := range secrets {
+		value, err := s.encrypt.Decrypt(secret.ValueEncrypted)
+		if err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"error": err.Error(),
+				"name":  secret.Name,
+			}).Error("Failed to decrypt secret value")
+			return nil, nil, err
+		}
+		inputs[i] = SecretInput{Name: secret.Name, Value: value}
+	}
+
+	return &versions[0], inputs, nil
+}
+
+// removeRenamedKeys removes the old remote names of keys renamed or moved out of an environment
+// from a provider target that supports deletion. Old names go through the target's key mapping; names still
+// in use are never removed, and when allowed is set only the old names in it are. Failures are logged and
+// retried on the next sync.
+func (s *SecretService) removeRenamedKeys(ctx context.Context, environmentID, target string, syncer provider.ProviderSyncer, keyMapping provider.KeyMappingRules, synced []provider.Secret, allowed map[string]bool) []SyncResult {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":         "removeRenamedKeys",
+		"environment_id": environmentID,
+		"target":         target,
+	})
+
+	deleter, ok := syncer.(provider.ProviderDeleter)
+	if !ok {
+		return nil
+	}
+
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil
+	}
+
+	pending, err := s.repo.ListPendingSecretKeyRemovals(ctx, secretdb.ListPendingSecretKeyRemovalsParams{
+		EnvironmentID: environmentUUID,
+		Target:        target,
+	})
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Warn("Failed to list pending key removals")
+		return nil
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	inUse := make(map[string]bool, len(synced))
+	for _, secret := range synced {
+		inUse[secret.Name] = true
+	}
+
+	// Several changes can point at the same old name; delete each name once
+	changesByName := make(map[string][]secretdb.SecretKeyChange)
+	var names []string
+	for _, change := range pending {
+		// Old keys the mapping excludes or groups were never synced under a name of their own
+		oldName, mapped := keyMapping.MapName(change.OldName)
+		if !mapped {
+			continue
+		}
+		if inUse[oldName] {
+			s.markKeyChangeCleanedUp(ctx, change.ID, target)
+			continue
+		}
+		if allowed != nil && !allowed[oldName] {
+			continue
+		}
+		if _, ok := changesByName[oldName]; !ok {
+			names = append(names, oldName)
+		}
+		changesByName[oldName] = append(changesByName[oldName], change)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	results, err := deleter.Delete(ctx, names)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Warn("Failed to remove old key names from provider")
+		return nil
+	}
+
+	removals := make([]SyncResult, len(results))
+	for i, result := range results {
+		removals[i] = SyncResult{Name: result.Name, Success: result.Success, Error: result.Error}
+		if !result.Success {
+			continue
+		}
+		for _, change := range changesByName[result.Name] {
+			s.markKeyChangeCleanedUp(ctx, change.ID, target)
+		}
+	}
+
+	logEntry.WithField("removal_count", len(removals)).Info("Removed old key names from provider")
+	return removals
+}
+
+// markKeyChangeCleanedUp records that a provider target no longer holds the old name of a key change
+func (s *SecretService) markKeyChangeCleanedUp(ctx context.Context, keyChangeID uuid.UUID, target string) {
+	err := s.repo.MarkSecretKeyChangeCleanedUp(ctx, secretdb.MarkSecretKeyChangeCleanedUpParams{
+		KeyChangeID: keyChangeID,
+		Target:      target,
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"error":         err.Error(),
+			"key_change_id": keyChangeID,
+			"target":        target,
+		}).Warn("Failed to mark key change as cleaned up")
+	}
+}
+
+// toSecretKeyChangeResponses converts stored key changes into their API representation
+func toSecretKeyChangeResponses(changes []secretdb.SecretKeyChange) []SecretKeyChangeResponse {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	responses := make([]SecretKeyChangeResponse, len(changes))
+	for i, change := range changes {
+		responses[i] = SecretKeyChangeResponse{
+			ChangeType:       change.ChangeType,
+			OldName:          change.OldName,
+			NewName:          change.NewName,
+			RelatedVersionID: change.RelatedVersionID.String,
+		}
+		if change.RelatedEnvironmentID.Valid {
+			relatedEnvironmentID := change.RelatedEnvironmentID.UUID
+			responses[i].RelatedEnvironmentID = &relatedEnvironmentID
+		}
+	}
+	return responses
+}
@@ -0,0 +1,33 @@
+-- name: InsertSecretKeyChange :one
+INSERT INTO secret_key_changes (version_id, environment_id, change_type, old_name, new_name, related_environment_id, related_version_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListSecretKeyChangesForVersion :many
+SELECT * FROM secret_key_changes
+WHERE version_id = $1
+ORDER BY created_at, old_name;
+
+-- name: ListSecretKeyChangesForEnvironment :many
+SELECT * FROM secret_key_changes
+WHERE environment_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListPendingSecretKeyRemovals :many
+SELECT kc.* FROM secret_key_changes kc
+WHERE kc.environment_id = $1
+  AND kc.change_type IN ('rename', 'move_out')
+  AND NOT EXISTS (
+    SELECT 1 FROM secret_key_change_cleanups c
+    WHERE c.key_change_id = kc.id AND c.target = $2
+  )
+ORDER BY kc.created_at;
+
+-- name: MarkSecretKeyChangeCleanedUp :exec
+INSERT INTO secret_key_change_cleanups (key_change_id, target)
+VALUES ($1, $2)
+ON CONFLICT (key_change_id, target) DO NOTHING;
+
+-- name: GetEnvironmentSecretGroupID :one
+SELECT secret_group_id FROM environments
+WHERE id = $1;
@@ -0,0 +1,120 @@
+package secret
+
+import (
+	"context"
+	"sort"
+
+	apiErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/Gkemhcs/kavach-backend/internal/provider"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// PreviewKeyMapping shows the remote names the latest version of an environment would sync under on a
+// provider target, using the given rules or the target's stored ones. Rules that land several keys on
+// the same remote name are rejected with ErrKeyMappingCollision; the preview is still returned so the
+// collisions can be shown.
+func (s *SecretService) PreviewKeyMapping(ctx context.Context, environmentID string, req KeyMappingPreviewRequest) (*KeyMappingPreviewResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":         "PreviewKeyMapping",
+		"environment_id": environmentID,
+		"target":         req.Target,
+	})
+
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	syncer, syncOptions, err := s.providerService.GetProviderSyncerWithOptions(ctx, environmentID, req.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := syncOptions.KeyMapping
+	if req.Rules != nil {
+		if err := req.Rules.Validate(); err != nil {
+			return nil, err
+		}
+		rules = *req.Rules
+	}
+
+	versionID, err := s.resolveSyncVersion(ctx, environmentUUID, "")
+	if err != nil {
+		return nil, err
+	}
+	details, err := s.getCurrentVersionDetails(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(details.Secrets))
+	for i, secret := range details.Secrets {
+		names[i] = secret.Name
+	}
+	sort.Strings(names)
+
+	// Providers without a reader sync under the mapped name as it is
+	reader, _ := syncer.(provider.ProviderReader)
+	mapped := rules.MapKeys(names)
+	keys := make([]KeyMappingPreviewKey, len(mapped))
+	remote := make([]provider.MappedKey, len(mapped))
+	for i, key := range mapped {
+		keys[i] = KeyMappingPreviewKey{Name: key.Name, MappedName: key.MappedName, Group: key.Group, Excluded: key.Excluded}
+		if !key.Excluded {
+			keys[i].RemoteName = key.MappedName
+			if reader != nil {
+				keys[i].RemoteName = reader.RemoteName(key.MappedName)
+			}
+		}
+		remote[i] = provider.MappedKey{Name: key.Name, MappedName: keys[i].RemoteName, Group: key.Group, Excluded: key.Excluded}
+	}
+
+	// Collisions are checked on remote names, so names the provider sanitizes alike are caught too
+	collisions := []SyncPlanCollision{}
+	for remoteName, collided := range provider.KeyMappingCollisions(remote) {
+		collisions = append(collisions, SyncPlanCollision{RemoteName: remoteName, Names: collided})
+	}
+	sort.Slice(collisions, func(i, j int) bool { return collisions[i].RemoteName < collisions[j].RemoteName })
+
+	response := &KeyMappingPreviewResponse{
+		Target:     req.Target,
+		Provider:   string(syncOptions.Provider),
+		VersionID:  versionID,
+		Rules:      rules,
+		Keys:       keys,
+		Collisions: collisions,
+	}
+
+	logEntry.WithFields(logrus.Fields{
+		"key_count":       len(keys),
+		"collision_count": len(collisions),
+	}).Info("Previewed key mapping")
+
+	if len(collisions) > 0 {
+		return response, apiErrors.ErrKeyMappingCollision
+	}
+	return response, nil
+}
+
+// applyKeyMapping maps the secrets of a version to the secrets a provider target receives
+func applyKeyMapping(rules provider.KeyMappingRules, secrets []SecretWithValue) ([]SecretWithValue, error) {
+	if rules.IsZero() {
+		return secrets, nil
+	}
+
+	providerSecrets := make([]provider.Secret, len(secrets))
+	for i, secret := range secrets {
+		providerSecrets[i] = provider.Secret{Name: secret.Name, Value: secret.Value}
+	}
+	mapped, err := rules.Apply(providerSecrets)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SecretWithValue, len(mapped))
+	for i, secret := range mapped {
+		result[i] = SecretWithValue{Name: secret.Name, Value: secret.Value}
+	}
+	return result, nil
+}
@@ -8,11 +8,16 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-// MockSecretRepository is a mock implementation of the secretdb.Querier interface
+// MockSecretRepository is a mock implementation of the SecretRepository interface
 type MockSecretRepository struct {
 	mock.Mock
 }
 
+// WithTx runs fn against the mock itself, since mocked queries have no transaction to roll back
+func (m *MockSecretRepository) WithTx(ctx context.Context, fn func(repo secretdb.Querier) error) error {
+	return fn(m)
+}
+
 // CreateSecretVersion mocks the CreateSecretVersion method
 func (m *MockSecretRepository) CreateSecretVersion(ctx context.Context, arg secretdb.CreateSecretVersionParams) (secretdb.SecretVersion, error) {
 	args := m.Called(ctx, arg)
@@ -69,3 +74,519 @@ func (m *MockSecretRepository) RollbackSecretsToVersion(ctx context.Context, arg
 	args := m.Called(ctx, arg)
 	return args.Error(0)
 }
+
+// CreateSecretGroupVersion mocks the CreateSecretGroupVersion method
+func (m *MockSecretRepository) CreateSecretGroupVersion(ctx context.Context, arg secretdb.CreateSecretGroupVersionParams) (secretdb.SecretGroupVersion, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SecretGroupVersion{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SecretGroupVersion), args.Error(1)
+}
+
+// InsertSecretGroupSecret mocks the InsertSecretGroupSecret method
+func (m *MockSecretRepository) InsertSecretGroupSecret(ctx context.Context, arg secretdb.InsertSecretGroupSecretParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+// ListSecretGroupVersions mocks the ListSecretGroupVersions method
+func (m *MockSecretRepository) ListSecretGroupVersions(ctx context.Context, secretGroupID uuid.UUID) ([]secretdb.SecretGroupVersion, error) {
+	args := m.Called(ctx, secretGroupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.SecretGroupVersion), args.Error(1)
+}
+
+// GetSecretGroupSecretsForSecretVersion mocks the GetSecretGroupSecretsForSecretVersion method
+func (m *MockSecretRepository) GetSecretGroupSecretsForSecretVersion(ctx context.Context, id string) ([]secretdb.GetSecretGroupSecretsForSecretVersionRow, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.GetSecretGroupSecretsForSecretVersionRow), args.Error(1)
+}
+
+// GetSecretGroupVersion mocks the GetSecretGroupVersion method
+func (m *MockSecretRepository) GetSecretGroupVersion(ctx context.Context, id string) (secretdb.SecretGroupVersion, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return secretdb.SecretGroupVersion{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SecretGroupVersion), args.Error(1)
+}
+
+// GetSecretsForSecretGroupVersion mocks the GetSecretsForSecretGroupVersion method
+func (m *MockSecretRepository) GetSecretsForSecretGroupVersion(ctx context.Context, versionID string) ([]secretdb.GetSecretsForSecretGroupVersionRow, error) {
+	args := m.Called(ctx, versionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.GetSecretsForSecretGroupVersionRow), args.Error(1)
+}
+
+// ListSecretGroupEnvironmentIDs mocks the ListSecretGroupEnvironmentIDs method
+func (m *MockSecretRepository) ListSecretGroupEnvironmentIDs(ctx context.Context, secretGroupID uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, secretGroupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+// GetLatestSecretGroupSecretsForEnvironment mocks the GetLatestSecretGroupSecretsForEnvironment method
+func (m *MockSecretRepository) GetLatestSecretGroupSecretsForEnvironment(ctx context.Context, id uuid.UUID) ([]secretdb.GetLatestSecretGroupSecretsForEnvironmentRow, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.GetLatestSecretGroupSecretsForEnvironmentRow), args.Error(1)
+}
+
+// UpsertSecretGroupPolicy mocks the UpsertSecretGroupPolicy method
+func (m *MockSecretRepository) UpsertSecretGroupPolicy(ctx context.Context, arg secretdb.UpsertSecretGroupPolicyParams) (secretdb.SecretGroupPolicy, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SecretGroupPolicy{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SecretGroupPolicy), args.Error(1)
+}
+
+// GetSecretGroupPolicy mocks the GetSecretGroupPolicy method
+func (m *MockSecretRepository) GetSecretGroupPolicy(ctx context.Context, secretGroupID uuid.UUID) (secretdb.SecretGroupPolicy, error) {
+	args := m.Called(ctx, secretGroupID)
+	if args.Get(0) == nil {
+		return secretdb.SecretGroupPolicy{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SecretGroupPolicy), args.Error(1)
+}
+
+// DeleteSecretGroupPolicy mocks the DeleteSecretGroupPolicy method
+func (m *MockSecretRepository) DeleteSecretGroupPolicy(ctx context.Context, secretGroupID uuid.UUID) error {
+	args := m.Called(ctx, secretGroupID)
+	return args.Error(0)
+}
+
+// GetSecretPolicyForEnvironment mocks the GetSecretPolicyForEnvironment method
+func (m *MockSecretRepository) GetSecretPolicyForEnvironment(ctx context.Context, id uuid.UUID) (secretdb.GetSecretPolicyForEnvironmentRow, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return secretdb.GetSecretPolicyForEnvironmentRow{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.GetSecretPolicyForEnvironmentRow), args.Error(1)
+}
+
+// ListLatestSecretsForOrganization mocks the ListLatestSecretsForOrganization method
+func (m *MockSecretRepository) ListLatestSecretsForOrganization(ctx context.Context, organizationID uuid.UUID) ([]secretdb.ListLatestSecretsForOrganizationRow, error) {
+	args := m.Called(ctx, organizationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.ListLatestSecretsForOrganizationRow), args.Error(1)
+}
+
+// ListOrganizationIDs mocks the ListOrganizationIDs method
+func (m *MockSecretRepository) ListOrganizationIDs(ctx context.Context) ([]uuid.UUID, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+// UpsertSecretReuseFinding mocks the UpsertSecretReuseFinding method
+func (m *MockSecretRepository) UpsertSecretReuseFinding(ctx context.Context, arg secretdb.UpsertSecretReuseFindingParams) (secretdb.SecretReuseFinding, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SecretReuseFinding{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SecretReuseFinding), args.Error(1)
+}
+
+// ResolveStaleSecretReuseFindings mocks the ResolveStaleSecretReuseFindings method
+func (m *MockSecretRepository) ResolveStaleSecretReuseFindings(ctx context.Context, arg secretdb.ResolveStaleSecretReuseFindingsParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// ListSecretReuseFindings mocks the ListSecretReuseFindings method
+func (m *MockSecretRepository) ListSecretReuseFindings(ctx context.Context, organizationID uuid.UUID) ([]secretdb.SecretReuseFinding, error) {
+	args := m.Called(ctx, organizationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.SecretReuseFinding), args.Error(1)
+}
+
+// SuppressSecretReuseFinding mocks the SuppressSecretReuseFinding method
+func (m *MockSecretRepository) SuppressSecretReuseFinding(ctx context.Context, arg secretdb.SuppressSecretReuseFindingParams) (secretdb.SecretReuseFinding, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SecretReuseFinding{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SecretReuseFinding), args.Error(1)
+}
+
+// UnsuppressSecretReuseFinding mocks the UnsuppressSecretReuseFinding method
+func (m *MockSecretRepository) UnsuppressSecretReuseFinding(ctx context.Context, arg secretdb.UnsuppressSecretReuseFindingParams) (secretdb.SecretReuseFinding, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SecretReuseFinding{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SecretReuseFinding), args.Error(1)
+}
+
+// GetEnvironmentSecretGroupID mocks the GetEnvironmentSecretGroupID method
+func (m *MockSecretRepository) GetEnvironmentSecretGroupID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+// InsertSecretKeyChange mocks the InsertSecretKeyChange method
+func (m *MockSecretRepository) InsertSecretKeyChange(ctx context.Context, arg secretdb.InsertSecretKeyChangeParams) (secretdb.SecretKeyChange, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SecretKeyChange{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SecretKeyChange), args.Error(1)
+}
+
+// ListSecretKeyChangesForVersion mocks the ListSecretKeyChangesForVersion method
+func (m *MockSecretRepository) ListSecretKeyChangesForVersion(ctx context.Context, versionID string) ([]secretdb.SecretKeyChange, error) {
+	args := m.Called(ctx, versionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.SecretKeyChange), args.Error(1)
+}
+
+// ListSecretKeyChangesForEnvironment mocks the ListSecretKeyChangesForEnvironment method
+func (m *MockSecretRepository) ListSecretKeyChangesForEnvironment(ctx context.Context, environmentID uuid.UUID) ([]secretdb.SecretKeyChange, error) {
+	args := m.Called(ctx, environmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.SecretKeyChange), args.Error(1)
+}
+
+// ListPendingSecretKeyRemovals mocks the ListPendingSecretKeyRemovals method
+func (m *MockSecretRepository) ListPendingSecretKeyRemovals(ctx context.Context, arg secretdb.ListPendingSecretKeyRemovalsParams) ([]secretdb.SecretKeyChange, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.SecretKeyChange), args.Error(1)
+}
+
+// MarkSecretKeyChangeCleanedUp mocks the MarkSecretKeyChangeCleanedUp method
+func (m *MockSecretRepository) MarkSecretKeyChangeCleanedUp(ctx context.Context, arg secretdb.MarkSecretKeyChangeCleanedUpParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+// ClearSecretShareValue mocks the ClearSecretShareValue method
+func (m *MockSecretRepository) ClearSecretShareValue(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// ConsumeSecretShare mocks the ConsumeSecretShare method
+func (m *MockSecretRepository) ConsumeSecretShare(ctx context.Context, arg secretdb.ConsumeSecretShareParams) (secretdb.SecretShare, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SecretShare{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SecretShare), args.Error(1)
+}
+
+// CreateSecretShare mocks the CreateSecretShare method
+func (m *MockSecretRepository) CreateSecretShare(ctx context.Context, arg secretdb.CreateSecretShareParams) (secretdb.SecretShare, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SecretShare{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SecretShare), args.Error(1)
+}
+
+// GetSecretShare mocks the GetSecretShare method
+func (m *MockSecretRepository) GetSecretShare(ctx context.Context, arg secretdb.GetSecretShareParams) (secretdb.SecretShare, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SecretShare{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SecretShare), args.Error(1)
+}
+
+// GetSecretShareByTokenHash mocks the GetSecretShareByTokenHash method
+func (m *MockSecretRepository) GetSecretShareByTokenHash(ctx context.Context, tokenHash string) (secretdb.SecretShare, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return secretdb.SecretShare{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SecretShare), args.Error(1)
+}
+
+// InsertSecretShareEvent mocks the InsertSecretShareEvent method
+func (m *MockSecretRepository) InsertSecretShareEvent(ctx context.Context, arg secretdb.InsertSecretShareEventParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+// ListSecretShareEvents mocks the ListSecretShareEvents method
+func (m *MockSecretRepository) ListSecretShareEvents(ctx context.Context, shareID uuid.UUID) ([]secretdb.SecretShareEvent, error) {
+	args := m.Called(ctx, shareID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.SecretShareEvent), args.Error(1)
+}
+
+// ListSecretSharesForEnvironment mocks the ListSecretSharesForEnvironment method
+func (m *MockSecretRepository) ListSecretSharesForEnvironment(ctx context.Context, environmentID uuid.UUID) ([]secretdb.SecretShare, error) {
+	args := m.Called(ctx, environmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.SecretShare), args.Error(1)
+}
+
+// PurgeExpiredSecretShareValues mocks the PurgeExpiredSecretShareValues method
+func (m *MockSecretRepository) PurgeExpiredSecretShareValues(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// RecordSecretShareFailedAttempt mocks the RecordSecretShareFailedAttempt method
+func (m *MockSecretRepository) RecordSecretShareFailedAttempt(ctx context.Context, arg secretdb.RecordSecretShareFailedAttemptParams) (int32, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int32), args.Error(1)
+}
+
+// RevokeSecretShare mocks the RevokeSecretShare method
+func (m *MockSecretRepository) RevokeSecretShare(ctx context.Context, arg secretdb.RevokeSecretShareParams) (secretdb.SecretShare, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SecretShare{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SecretShare), args.Error(1)
+}
+
+// CreateSyncRun mocks the CreateSyncRun method
+func (m *MockSecretRepository) CreateSyncRun(ctx context.Context, arg secretdb.CreateSyncRunParams) (secretdb.SyncRun, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SyncRun{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SyncRun), args.Error(1)
+}
+
+// FinishSyncRun mocks the FinishSyncRun method
+func (m *MockSecretRepository) FinishSyncRun(ctx context.Context, arg secretdb.FinishSyncRunParams) (secretdb.SyncRun, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SyncRun{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SyncRun), args.Error(1)
+}
+
+// GetSyncRun mocks the GetSyncRun method
+func (m *MockSecretRepository) GetSyncRun(ctx context.Context, arg secretdb.GetSyncRunParams) (secretdb.SyncRun, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SyncRun{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SyncRun), args.Error(1)
+}
+
+// InsertSyncRunResult mocks the InsertSyncRunResult method
+func (m *MockSecretRepository) InsertSyncRunResult(ctx context.Context, arg secretdb.InsertSyncRunResultParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+// ListCurrentSyncedVersions mocks the ListCurrentSyncedVersions method
+func (m *MockSecretRepository) ListCurrentSyncedVersions(ctx context.Context, environmentID uuid.UUID) ([]secretdb.ListCurrentSyncedVersionsRow, error) {
+	args := m.Called(ctx, environmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.ListCurrentSyncedVersionsRow), args.Error(1)
+}
+
+// ListSyncRunResults mocks the ListSyncRunResults method
+func (m *MockSecretRepository) ListSyncRunResults(ctx context.Context, runID uuid.UUID) ([]secretdb.SyncRunResult, error) {
+	args := m.Called(ctx, runID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.SyncRunResult), args.Error(1)
+}
+
+// ListSyncRunsForEnvironment mocks the ListSyncRunsForEnvironment method
+func (m *MockSecretRepository) ListSyncRunsForEnvironment(ctx context.Context, arg secretdb.ListSyncRunsForEnvironmentParams) ([]secretdb.SyncRun, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.SyncRun), args.Error(1)
+}
+
+// ListSyncRunsForTarget mocks the ListSyncRunsForTarget method
+func (m *MockSecretRepository) ListSyncRunsForTarget(ctx context.Context, arg secretdb.ListSyncRunsForTargetParams) ([]secretdb.SyncRun, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.SyncRun), args.Error(1)
+}
+
+// AbandonSyncRun mocks the AbandonSyncRun method
+func (m *MockSecretRepository) AbandonSyncRun(ctx context.Context, arg secretdb.AbandonSyncRunParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+// ClaimSyncJob mocks the ClaimSyncJob method
+func (m *MockSecretRepository) ClaimSyncJob(ctx context.Context, arg secretdb.ClaimSyncJobParams) (secretdb.SyncJob, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SyncJob{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SyncJob), args.Error(1)
+}
+
+// CompleteSyncJob mocks the CompleteSyncJob method
+func (m *MockSecretRepository) CompleteSyncJob(ctx context.Context, arg secretdb.CompleteSyncJobParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// EnqueueSyncJob mocks the EnqueueSyncJob method
+func (m *MockSecretRepository) EnqueueSyncJob(ctx context.Context, arg secretdb.EnqueueSyncJobParams) (secretdb.SyncJob, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SyncJob{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SyncJob), args.Error(1)
+}
+
+// FailExpiredSyncJobs mocks the FailExpiredSyncJobs method
+func (m *MockSecretRepository) FailExpiredSyncJobs(ctx context.Context) ([]secretdb.SyncJob, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.SyncJob), args.Error(1)
+}
+
+// FailSyncJob mocks the FailSyncJob method
+func (m *MockSecretRepository) FailSyncJob(ctx context.Context, arg secretdb.FailSyncJobParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// GetSyncJob mocks the GetSyncJob method
+func (m *MockSecretRepository) GetSyncJob(ctx context.Context, arg secretdb.GetSyncJobParams) (secretdb.SyncJob, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SyncJob{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SyncJob), args.Error(1)
+}
+
+// HeartbeatSyncJob mocks the HeartbeatSyncJob method
+func (m *MockSecretRepository) HeartbeatSyncJob(ctx context.Context, arg secretdb.HeartbeatSyncJobParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// ListSyncJobsForEnvironment mocks the ListSyncJobsForEnvironment method
+func (m *MockSecretRepository) ListSyncJobsForEnvironment(ctx context.Context, arg secretdb.ListSyncJobsForEnvironmentParams) ([]secretdb.SyncJob, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.SyncJob), args.Error(1)
+}
+
+// RetrySyncJob mocks the RetrySyncJob method
+func (m *MockSecretRepository) RetrySyncJob(ctx context.Context, arg secretdb.RetrySyncJobParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// SetSyncJobRun mocks the SetSyncJobRun method
+func (m *MockSecretRepository) SetSyncJobRun(ctx context.Context, arg secretdb.SetSyncJobRunParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+// EnqueueAutoSyncJobs mocks the EnqueueAutoSyncJobs method
+func (m *MockSecretRepository) EnqueueAutoSyncJobs(ctx context.Context, arg secretdb.EnqueueAutoSyncJobsParams) ([]secretdb.SyncJob, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.SyncJob), args.Error(1)
+}
+
+// InsertDriftCheck mocks the InsertDriftCheck method
+func (m *MockSecretRepository) InsertDriftCheck(ctx context.Context, arg secretdb.InsertDriftCheckParams) (secretdb.DriftCheck, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.DriftCheck{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.DriftCheck), args.Error(1)
+}
+
+// ListDriftCheckTargets mocks the ListDriftCheckTargets method
+func (m *MockSecretRepository) ListDriftCheckTargets(ctx context.Context) ([]secretdb.ListDriftCheckTargetsRow, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.ListDriftCheckTargetsRow), args.Error(1)
+}
+
+// ListLatestDriftChecks mocks the ListLatestDriftChecks method
+func (m *MockSecretRepository) ListLatestDriftChecks(ctx context.Context, environmentID uuid.UUID) ([]secretdb.DriftCheck, error) {
+	args := m.Called(ctx, environmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]secretdb.DriftCheck), args.Error(1)
+}
+
+// CreateSyncPlan mocks the CreateSyncPlan method
+func (m *MockSecretRepository) CreateSyncPlan(ctx context.Context, arg secretdb.CreateSyncPlanParams) (secretdb.SyncPlan, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SyncPlan{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SyncPlan), args.Error(1)
+}
+
+// GetSyncPlan mocks the GetSyncPlan method
+func (m *MockSecretRepository) GetSyncPlan(ctx context.Context, arg secretdb.GetSyncPlanParams) (secretdb.SyncPlan, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SyncPlan{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SyncPlan), args.Error(1)
+}
+
+// MarkSyncPlanApplied mocks the MarkSyncPlanApplied method
+func (m *MockSecretRepository) MarkSyncPlanApplied(ctx context.Context, arg secretdb.MarkSyncPlanAppliedParams) (secretdb.SyncPlan, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return secretdb.SyncPlan{}, args.Error(1)
+	}
+	return args.Get(0).(secretdb.SyncPlan), args.Error(1)
+}
+
+// SetSyncPlanJob mocks the SetSyncPlanJob method
+func (m *MockSecretRepository) SetSyncPlanJob(ctx context.Context, arg secretdb.SetSyncPlanJobParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
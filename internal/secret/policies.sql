@@ -0,0 +1,20 @@
+-- name: UpsertSecretGroupPolicy :one
+INSERT INTO secret_group_policies (secret_group_id, schema, updated_by)
+VALUES ($1, $2, $3)
+ON CONFLICT (secret_group_id) DO UPDATE
+SET schema = EXCLUDED.schema, updated_by = EXCLUDED.updated_by, updated_at = now()
+RETURNING *;
+
+-- name: GetSecretGroupPolicy :one
+SELECT * FROM secret_group_policies
+WHERE secret_group_id = $1;
+
+-- name: DeleteSecretGroupPolicy :exec
+DELETE FROM secret_group_policies
+WHERE secret_group_id = $1;
+
+-- name: GetSecretPolicyForEnvironment :one
+SELECT p.schema, e.name AS environment_name
+FROM secret_group_policies p
+JOIN environments e ON e.secret_group_id = p.secret_group_id
+WHERE e.id = $1;
@@ -0,0 +1,250 @@
+package secret
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported value formats for secret policy key rules
+const (
+	PolicyFormatURL     = "url"
+	PolicyFormatInteger = "integer"
+	PolicyFormatJSON    = "json"
+	PolicyFormatPEM     = "pem"
+)
+
+// Policy rule identifiers reported in violations
+const (
+	PolicyRuleRequired   = "required"
+	PolicyRuleForbidden  = "forbidden"
+	PolicyRulePattern    = "pattern"
+	PolicyRuleFormat     = "format"
+	PolicyRuleMinEntropy = "min_entropy"
+)
+
+// SecretPolicySchema declares the expected shape of an environment's secrets.
+// Rules and forbidden keys accept glob patterns (e.g. "*_PASSWORD"); required keys are exact names.
+type SecretPolicySchema struct {
+	RequiredKeys  []string                        `json:"required_keys,omitempty"`
+	ForbiddenKeys []string                        `json:"forbidden_keys,omitempty"`
+	Rules         map[string]SecretKeyRule        `json:"rules,omitempty"`
+	Environments  map[string]SecretPolicyOverride `json:"environments,omitempty"`
+}
+
+// SecretPolicyOverride adds to or replaces parts of the schema for a single environment
+type SecretPolicyOverride struct {
+	RequiredKeys  []string                 `json:"required_keys,omitempty"`
+	ForbiddenKeys []string                 `json:"forbidden_keys,omitempty"`
+	Rules         map[string]SecretKeyRule `json:"rules,omitempty"`
+}
+
+// SecretKeyRule constrains the value of every key matching the rule's pattern
+type SecretKeyRule struct {
+	Pattern    string  `json:"pattern,omitempty"`     // Regular expression the whole value must match
+	Format     string  `json:"format,omitempty"`      // One of url, integer, json, pem
+	MinEntropy float64 `json:"min_entropy,omitempty"` // Minimum estimated entropy of the value in bits
+}
+
+// PolicyViolation describes a single policy failure. It never carries the secret value.
+type PolicyViolation struct {
+	Key     string `json:"key"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// SecretPolicyViolationError is returned when a set of secrets does not satisfy the policy
+type SecretPolicyViolationError struct {
+	Violations []PolicyViolation
+	// EnvironmentID names the violating environment when a change spans several, such as a group base version
+	EnvironmentID string
+}
+
+// Error implements the error interface for SecretPolicyViolationError.
+func (e *SecretPolicyViolationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = fmt.Sprintf("%s: %s", violation.Key, violation.Message)
+	}
+	if e.EnvironmentID != "" {
+		return fmt.Sprintf("secret policy violated in environment %s: %s", e.EnvironmentID, strings.Join(messages, "; "))
+	}
+	return fmt.Sprintf("secret policy violated: %s", strings.Join(messages, "; "))
+}
+
+// Validate checks that every pattern, format and glob in the schema is usable
+func (p *SecretPolicySchema) Validate() error {
+	if err := validatePolicyRules(p.ForbiddenKeys, p.Rules); err != nil {
+		return err
+	}
+	for environment, override := range p.Environments {
+		if err := validatePolicyRules(override.ForbiddenKeys, override.Rules); err != nil {
+			return fmt.Errorf("environment %s: %w", environment, err)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the effective schema for an environment by applying its override.
+// Override keys are added to the base lists and override rules replace base rules with the same pattern.
+func (p *SecretPolicySchema) Resolve(environmentName string) SecretPolicySchema {
+	resolved := SecretPolicySchema{
+		RequiredKeys:  append([]string{}, p.RequiredKeys...),
+		ForbiddenKeys: append([]string{}, p.ForbiddenKeys...),
+		Rules:         make(map[string]SecretKeyRule, len(p.Rules)),
+	}
+	for pattern, rule := range p.Rules {
+		resolved.Rules[pattern] = rule
+	}
+
+	override, ok := p.Environments[environmentName]
+	if !ok {
+		return resolved
+	}
+	resolved.RequiredKeys = append(resolved.RequiredKeys, override.RequiredKeys...)
+	resolved.ForbiddenKeys = append(resolved.ForbiddenKeys, override.ForbiddenKeys...)
+	for pattern, rule := range override.Rules {
+		resolved.Rules[pattern] = rule
+	}
+	return resolved
+}
+
+// Evaluate checks the given secrets against the schema and returns all violations sorted by key
+func (p *SecretPolicySchema) Evaluate(secrets []SecretWithValue) []PolicyViolation {
+	violations := []PolicyViolation{}
+
+	present := make(map[string]bool, len(secrets))
+	for _, secret := range secrets {
+		present[secret.Name] = true
+	}
+
+	for _, key := range p.RequiredKeys {
+		if !present[key] {
+			violations = append(violations, PolicyViolation{Key: key, Rule: PolicyRuleRequired, Message: "required key is missing"})
+		}
+	}
+
+	patterns := make([]string, 0, len(p.Rules))
+	for pattern := range p.Rules {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, secret := range secrets {
+		for _, forbidden := range p.ForbiddenKeys {
+			if matched, _ := path.Match(forbidden, secret.Name); matched {
+				violations = append(violations, PolicyViolation{Key: secret.Name, Rule: PolicyRuleForbidden, Message: fmt.Sprintf("key is forbidden by %q", forbidden)})
+				break
+			}
+		}
+
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, secret.Name); matched {
+				violations = append(violations, evaluateKeyRule(secret, p.Rules[pattern])...)
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Key < violations[j].Key })
+	return violations
+}
+
+// validatePolicyRules checks glob patterns, regular expressions and formats of a rule set
+func validatePolicyRules(forbiddenKeys []string, rules map[string]SecretKeyRule) error {
+	for _, forbidden := range forbiddenKeys {
+		if _, err := path.Match(forbidden, ""); err != nil {
+			return fmt.Errorf("invalid forbidden key pattern %q: %w", forbidden, err)
+		}
+	}
+	for pattern, rule := range rules {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid rule key pattern %q: %w", pattern, err)
+		}
+		if rule.Pattern != "" {
+			if _, err := regexp.Compile(rule.Pattern); err != nil {
+				return fmt.Errorf("invalid regular expression for %q: %w", pattern, err)
+			}
+		}
+		switch rule.Format {
+		case "", PolicyFormatURL, PolicyFormatInteger, PolicyFormatJSON, PolicyFormatPEM:
+		default:
+			return fmt.Errorf("unsupported format %q for %q", rule.Format, pattern)
+		}
+		if rule.MinEntropy < 0 {
+			return fmt.Errorf("min_entropy for %q cannot be negative", pattern)
+		}
+	}
+	return nil
+}
+
+// evaluateKeyRule checks a single secret value against a key rule
+func evaluateKeyRule(secret SecretWithValue, rule SecretKeyRule) []PolicyViolation {
+	var violations []PolicyViolation
+
+	if rule.Pattern != "" {
+		// Patterns are validated on save, so a compile error here means the value cannot match
+		re, err := regexp.Compile("^(?:" + rule.Pattern + ")$")
+		if err != nil || !re.MatchString(secret.Value) {
+			violations = append(violations, PolicyViolation{Key: secret.Name, Rule: PolicyRulePattern, Message: fmt.Sprintf("value does not match pattern %q", rule.Pattern)})
+		}
+	}
+
+	if rule.Format != "" && !matchesFormat(secret.Value, rule.Format) {
+		violations = append(violations, PolicyViolation{Key: secret.Name, Rule: PolicyRuleFormat, Message: fmt.Sprintf("value is not a valid %s", rule.Format)})
+	}
+
+	if rule.MinEntropy > 0 {
+		if entropy := estimateEntropyBits(secret.Value); entropy < rule.MinEntropy {
+			violations = append(violations, PolicyViolation{Key: secret.Name, Rule: PolicyRuleMinEntropy, Message: fmt.Sprintf("value entropy %.1f bits is below the minimum of %.1f bits", entropy, rule.MinEntropy)})
+		}
+	}
+
+	return violations
+}
+
+// matchesFormat reports whether the value is well-formed for the given format
+func matchesFormat(value, format string) bool {
+	switch format {
+	case PolicyFormatURL:
+		parsed, err := url.ParseRequestURI(value)
+		return err == nil && parsed.Scheme != "" && parsed.Host != ""
+	case PolicyFormatInteger:
+		_, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		return err == nil
+	case PolicyFormatJSON:
+		return json.Valid([]byte(value))
+	case PolicyFormatPEM:
+		block, _ := pem.Decode([]byte(value))
+		return block != nil
+	default:
+		return false
+	}
+}
+
+// estimateEntropyBits estimates the entropy of a value as its Shannon entropy per character times its length
+func estimateEntropyBits(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	total := 0
+	for _, char := range value {
+		counts[char]++
+		total++
+	}
+
+	var perChar float64
+	for _, count := range counts {
+		probability := float64(count) / float64(total)
+		perChar -= probability * math.Log2(probability)
+	}
+	return perChar * float64(total)
+}
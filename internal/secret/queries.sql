@@ -1,6 +1,13 @@
 -- name: CreateSecretVersion :one
-INSERT INTO secret_versions (environment_id, commit_message)
-VALUES ($1, $2)
+INSERT INTO secret_versions (environment_id, commit_message, group_version_id)
+VALUES ($1, $2, (
+    SELECT sgv.id
+    FROM secret_group_versions sgv
+    JOIN environments e ON e.secret_group_id = sgv.secret_group_id
+    WHERE e.id = $1
+    ORDER BY sgv.created_at DESC
+    LIMIT 1
+))
 RETURNING *;
 
 -- name: InsertSecret :exec
@@ -0,0 +1,48 @@
+package secret
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	secretdb "github.com/Gkemhcs/kavach-backend/internal/secret/gen"
+)
+
+// SecretRepository is the store of the secret service: the generated queries, and a way to run
+// several of them in one transaction
+type SecretRepository interface {
+	secretdb.Querier
+
+	// WithTx runs fn with queries bound to one transaction, which is committed when fn returns nil
+	// and rolled back otherwise
+	WithTx(ctx context.Context, fn func(repo secretdb.Querier) error) error
+}
+
+// sqlSecretRepository runs the generated queries against a database
+type sqlSecretRepository struct {
+	*secretdb.Queries
+	db *sql.DB
+}
+
+// NewSecretRepository creates the store of the secret service backed by the given database
+func NewSecretRepository(db *sql.DB) SecretRepository {
+	return &sqlSecretRepository{Queries: secretdb.New(db), db: db}
+}
+
+// WithTx runs fn with queries bound to one transaction
+func (r *sqlSecretRepository) WithTx(ctx context.Context, fn func(repo secretdb.Querier) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(r.Queries.WithTx(tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
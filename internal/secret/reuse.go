@@ -0,0 +1,319 @@
+package secret
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	apiErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	secretdb "github.com/Gkemhcs/kavach-backend/internal/secret/gen"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ScanSecretReuse finds identical values reused across environments of an organization.
+// Values are compared by keyed fingerprint; findings no longer present are marked resolved.
+func (s *SecretService) ScanSecretReuse(ctx context.Context, organizationID string) (*SecretReuseScanResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":          "ScanSecretReuse",
+		"organization_id": organizationID,
+	})
+
+	orgUUID, err := uuid.Parse(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	scannedAt := time.Now()
+	rows, err := s.repo.ListLatestSecretsForOrganization(ctx, orgUUID)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to list secrets for organization")
+		return nil, fmt.Errorf("failed to list secrets for organization: %w", err)
+	}
+
+	response := &SecretReuseScanResponse{
+		OrganizationID: orgUUID,
+		ScannedAt:      scannedAt,
+	}
+
+	// Group occurrences by fingerprint, dropping each plaintext value as soon as it is fingerprinted
+	occurrencesByFingerprint := make(map[string][]SecretReuseOccurrence)
+	for _, row := range rows {
+		value, err := s.encrypt.Decrypt(row.ValueEncrypted)
+		if err != nil {
+			logEntry.WithFields(logrus.Fields{
+				"error":          err.Error(),
+				"environment_id": row.EnvironmentID,
+				"name":           row.Name,
+			}).Warn("Skipping secret that could not be decrypted")
+			response.SkippedSecrets++
+			continue
+		}
+		response.ScannedSecrets++
+
+		fingerprint := s.encrypt.Fingerprint(value)
+		occurrencesByFingerprint[fingerprint] = append(occurrencesByFingerprint[fingerprint], SecretReuseOccurrence{
+			SecretGroupID:   row.SecretGroupID,
+			SecretGroupName: row.SecretGroupName,
+			EnvironmentID:   row.EnvironmentID,
+			EnvironmentName: row.EnvironmentName,
+			VersionID:       row.VersionID,
+			Key:             row.Name,
+		})
+	}
+
+	fingerprints := make([]string, 0, len(occurrencesByFingerprint))
+	for fingerprint := range occurrencesByFingerprint {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Strings(fingerprints)
+
+	for _, fingerprint := range fingerprints {
+		occurrences := occurrencesByFingerprint[fingerprint]
+		scope, reused := classifyReuse(occurrences)
+		if !reused {
+			continue
+		}
+
+		occurrencesJSON, err := json.Marshal(occurrences)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal reuse occurrences: %w", err)
+		}
+
+		_, err = s.repo.UpsertSecretReuseFinding(ctx, secretdb.UpsertSecretReuseFindingParams{
+			OrganizationID: orgUUID,
+			Fingerprint:    fingerprint,
+			Scope:          scope,
+			Occurrences:    occurrencesJSON,
+			LastSeenAt:     scannedAt,
+		})
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Error("Failed to save secret reuse finding")
+			return nil, fmt.Errorf("failed to save secret reuse finding: %w", err)
+		}
+		response.FindingsCount++
+	}
+
+	resolved, err := s.repo.ResolveStaleSecretReuseFindings(ctx, secretdb.ResolveStaleSecretReuseFindingsParams{
+		OrganizationID: orgUUID,
+		LastSeenAt:     scannedAt,
+	})
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to resolve stale secret reuse findings")
+		return nil, fmt.Errorf("failed to resolve stale secret reuse findings: %w", err)
+	}
+	response.ResolvedCount = resolved
+
+	logEntry.WithFields(logrus.Fields{
+		"scanned_secrets": response.ScannedSecrets,
+		"skipped_secrets": response.SkippedSecrets,
+		"findings_count":  response.FindingsCount,
+		"resolved_count":  response.ResolvedCount,
+	}).Info("Successfully scanned organization for reused secrets")
+
+	return response, nil
+}
+
+// ListReuseFindings lists the secret reuse findings of an organization.
+// Suppressed and resolved findings are only included when requested.
+func (s *SecretService) ListReuseFindings(ctx context.Context, organizationID string, includeSuppressed, includeResolved bool) ([]SecretReuseFindingResponse, error) {
+	orgUUID, err := uuid.Parse(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := s.repo.ListSecretReuseFindings(ctx, orgUUID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to list secret reuse findings")
+		return nil, fmt.Errorf("failed to list secret reuse findings: %w", err)
+	}
+
+	responses := []SecretReuseFindingResponse{}
+	for _, finding := range findings {
+		if finding.Suppressed && !includeSuppressed {
+			continue
+		}
+		if finding.ResolvedAt.Valid && !includeResolved {
+			continue
+		}
+
+		response, err := toSecretReuseFindingResponse(finding)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *response)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"organization_id": organizationID,
+		"finding_count":   len(responses),
+	}).Info("Successfully listed secret reuse findings")
+
+	return responses, nil
+}
+
+// SuppressReuseFinding suppresses a single finding so it is hidden from the default report
+func (s *SecretService) SuppressReuseFinding(ctx context.Context, organizationID, findingID, userID string, req SuppressReuseFindingRequest) (*SecretReuseFindingResponse, error) {
+	orgUUID, err := uuid.Parse(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	findingUUID, err := uuid.Parse(findingID)
+	if err != nil {
+		return nil, apiErrors.ErrSecretReuseFindingNotFound
+	}
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	finding, err := s.repo.SuppressSecretReuseFinding(ctx, secretdb.SuppressSecretReuseFindingParams{
+		ID:               findingUUID,
+		OrganizationID:   orgUUID,
+		SuppressedBy:     uuid.NullUUID{UUID: userUUID, Valid: true},
+		SuppressedReason: sql.NullString{String: req.Reason, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apiErrors.ErrSecretReuseFindingNotFound
+		}
+		s.logger.WithField("error", err.Error()).Error("Failed to suppress secret reuse finding")
+		return nil, fmt.Errorf("failed to suppress secret reuse finding: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"organization_id": organizationID,
+		"finding_id":      findingID,
+		"user_id":         userID,
+	}).Info("Successfully suppressed secret reuse finding")
+
+	return toSecretReuseFindingResponse(finding)
+}
+
+// UnsuppressReuseFinding restores a suppressed finding to the default report
+func (s *SecretService) UnsuppressReuseFinding(ctx context.Context, organizationID, findingID string) (*SecretReuseFindingResponse, error) {
+	orgUUID, err := uuid.Parse(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	findingUUID, err := uuid.Parse(findingID)
+	if err != nil {
+		return nil, apiErrors.ErrSecretReuseFindingNotFound
+	}
+
+	finding, err := s.repo.UnsuppressSecretReuseFinding(ctx, secretdb.UnsuppressSecretReuseFindingParams{
+		ID:             findingUUID,
+		OrganizationID: orgUUID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apiErrors.ErrSecretReuseFindingNotFound
+		}
+		s.logger.WithField("error", err.Error()).Error("Failed to unsuppress secret reuse finding")
+		return nil, fmt.Errorf("failed to unsuppress secret reuse finding: %w", err)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"organization_id": organizationID,
+		"finding_id":      findingID,
+	}).Info("Successfully unsuppressed secret reuse finding")
+
+	return toSecretReuseFindingResponse(finding)
+}
+
+// StartReuseScanScheduler scans every organization for reused secrets on a fixed interval
+// until ctx is cancelled. A non-positive interval disables scheduled scans.
+func (s *SecretService) StartReuseScanScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		s.logger.Info("Scheduled secret reuse scans are disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.scanAllOrganizations(ctx)
+			}
+		}
+	}()
+
+	s.logger.WithField("interval", interval.String()).Info("Started scheduled secret reuse scans")
+}
+
+// scanAllOrganizations runs a reuse scan for every organization, logging failures per organization
+func (s *SecretService) scanAllOrganizations(ctx context.Context) {
+	organizationIDs, err := s.repo.ListOrganizationIDs(ctx)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to list organizations for secret reuse scan")
+		return
+	}
+
+	for _, organizationID := range organizationIDs {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := s.ScanSecretReuse(ctx, organizationID.String()); err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"error":           err.Error(),
+				"organization_id": organizationID,
+			}).Error("Scheduled secret reuse scan failed")
+		}
+	}
+}
+
+// classifyReuse reports whether the occurrences span more than one environment and the scope of the reuse
+func classifyReuse(occurrences []SecretReuseOccurrence) (string, bool) {
+	environments := make(map[uuid.UUID]bool)
+	secretGroups := make(map[uuid.UUID]bool)
+	for _, occurrence := range occurrences {
+		environments[occurrence.EnvironmentID] = true
+		secretGroups[occurrence.SecretGroupID] = true
+	}
+
+	if len(environments) < 2 {
+		return "", false
+	}
+	if len(secretGroups) > 1 {
+		return ReuseScopeCrossSecretGroup, true
+	}
+	return ReuseScopeCrossEnvironment, true
+}
+
+// toSecretReuseFindingResponse converts a stored finding into its API representation.
+// The fingerprint is intentionally left out.
+func toSecretReuseFindingResponse(finding secretdb.SecretReuseFinding) (*SecretReuseFindingResponse, error) {
+	var occurrences []SecretReuseOccurrence
+	if err := json.Unmarshal(finding.Occurrences, &occurrences); err != nil {
+		return nil, fmt.Errorf("failed to parse reuse occurrences: %w", err)
+	}
+
+	response := &SecretReuseFindingResponse{
+		ID:               finding.ID,
+		Scope:            finding.Scope,
+		Occurrences:      occurrences,
+		Suppressed:       finding.Suppressed,
+		SuppressedReason: finding.SuppressedReason.String,
+		FirstSeenAt:      finding.FirstSeenAt,
+		LastSeenAt:       finding.LastSeenAt,
+	}
+	if finding.SuppressedBy.Valid {
+		response.SuppressedBy = &finding.SuppressedBy.UUID
+	}
+	if finding.SuppressedAt.Valid {
+		response.SuppressedAt = &finding.SuppressedAt.Time
+	}
+	if finding.ResolvedAt.Valid {
+		response.ResolvedAt = &finding.ResolvedAt.Time
+	}
+	return response, nil
+}
@@ -0,0 +1,48 @@
+-- name: ListLatestSecretsForOrganization :many
+SELECT sg.id AS secret_group_id, sg.name AS secret_group_name,
+       e.id AS environment_id, e.name AS environment_name,
+       sv.id AS version_id, s.name, s.value_encrypted
+FROM environments e
+JOIN secret_groups sg ON sg.id = e.secret_group_id
+JOIN secret_versions sv ON sv.id = (
+    SELECT v.id FROM secret_versions v
+    WHERE v.environment_id = e.id
+    ORDER BY v.created_at DESC
+    LIMIT 1
+)
+JOIN secrets s ON s.version_id = sv.id
+WHERE sg.organization_id = $1
+ORDER BY sg.name, e.name, s.name;
+
+-- name: ListOrganizationIDs :many
+SELECT id FROM organizations ORDER BY created_at;
+
+-- name: UpsertSecretReuseFinding :one
+INSERT INTO secret_reuse_findings (organization_id, fingerprint, scope, occurrences, last_seen_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (organization_id, fingerprint) DO UPDATE
+SET scope = EXCLUDED.scope, occurrences = EXCLUDED.occurrences,
+    last_seen_at = EXCLUDED.last_seen_at, resolved_at = NULL
+RETURNING *;
+
+-- name: ResolveStaleSecretReuseFindings :execrows
+UPDATE secret_reuse_findings
+SET resolved_at = now()
+WHERE organization_id = $1 AND last_seen_at < $2 AND resolved_at IS NULL;
+
+-- name: ListSecretReuseFindings :many
+SELECT * FROM secret_reuse_findings
+WHERE organization_id = $1
+ORDER BY last_seen_at DESC;
+
+-- name: SuppressSecretReuseFinding :one
+UPDATE secret_reuse_findings
+SET suppressed = true, suppressed_by = $3, suppressed_reason = $4, suppressed_at = now()
+WHERE id = $1 AND organization_id = $2
+RETURNING *;
+
+-- name: UnsuppressSecretReuseFinding :one
+UPDATE secret_reuse_findings
+SET suppressed = false, suppressed_by = NULL, suppressed_reason = NULL, suppressed_at = NULL
+WHERE id = $1 AND organization_id = $2
+RETURNING *;
@@ -2,7 +2,11 @@ package secret
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	apiErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
@@ -14,14 +18,14 @@ import (
 
 // SecretService handles business logic for secret management
 type SecretService struct {
-	repo            secretdb.Querier
+	repo            SecretRepository
 	encrypt         *EncryptionService
 	providerService *provider.ProviderService
 	logger          *logrus.Logger
 }
 
 // NewSecretService creates a new secret service
-func NewSecretService(repo secretdb.Querier, encrypt *EncryptionService, providerService *provider.ProviderService, logger *logrus.Logger) *SecretService {
+func NewSecretService(repo SecretRepository, encrypt *EncryptionService, providerService *provider.ProviderService, logger *logrus.Logger) *SecretService {
 	return &SecretService{
 		repo:            repo,
 		encrypt:         encrypt,
@@ -38,25 +42,49 @@ func (s *SecretService) CreateVersion(ctx context.Context, environmentID string,
 		"commit_message": req.CommitMessage,
 	}).Info("Creating new secret version")
 
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Validate input
-	if err := s.validateCreateVersionRequest(req); err != nil {
+	if err := s.validateCreateVersionRequest(ctx, environmentUUID, req); err != nil {
 		s.logger.WithField("error", err.Error()).Error("Invalid create version request")
 		return nil, err
 	}
 
-	environmentUUID, err := uuid.Parse(environmentID)
+	version, err := s.storeSecretVersion(ctx, s.repo, environmentUUID, req)
 	if err != nil {
 		return nil, err
 	}
 
+	s.logger.WithFields(logrus.Fields{
+		"version_id":   version.ID,
+		"secret_count": len(req.Secrets),
+	}).Info("Successfully created secret version")
+
+	s.scheduleAutoSync(ctx, environmentUUID)
+
+	return &SecretVersionResponse{
+		ID:            version.ID,
+		EnvironmentID: version.EnvironmentID,
+		CommitMessage: version.CommitMessage,
+		CreatedAt:     version.CreatedAt,
+		SecretCount:   len(req.Secrets),
+	}, nil
+}
+
+// storeSecretVersion creates a version for an environment and stores the encrypted secrets of the request
+// through the given queries, which may be bound to a transaction
+func (s *SecretService) storeSecretVersion(ctx context.Context, repo secretdb.Querier, environmentID uuid.UUID, req CreateSecretVersionRequest) (secretdb.SecretVersion, error) {
 	// Create the version
-	version, err := s.repo.CreateSecretVersion(ctx, secretdb.CreateSecretVersionParams{
-		EnvironmentID: environmentUUID,
+	version, err := repo.CreateSecretVersion(ctx, secretdb.CreateSecretVersionParams{
+		EnvironmentID: environmentID,
 		CommitMessage: req.CommitMessage,
 	})
 	if err != nil {
 		s.logger.WithField("error", err.Error()).Error("Failed to create secret version")
-		return nil, fmt.Errorf("failed to create secret version: %w", err)
+		return secretdb.SecretVersion{}, fmt.Errorf("failed to create secret version: %w", err)
 	}
 
 	// Encrypt and store secrets
@@ -67,10 +95,10 @@ func (s *SecretService) CreateVersion(ctx context.Context, environmentID string,
 				"error": err.Error(),
 				"name":  secret.Name,
 			}).Error("Failed to encrypt secret value")
-			return nil, apiErrors.ErrEncryptionFailed
+			return secretdb.SecretVersion{}, apiErrors.ErrEncryptionFailed
 		}
 
-		err = s.repo.InsertSecret(ctx, secretdb.InsertSecretParams{
+		err = repo.InsertSecret(ctx, secretdb.InsertSecretParams{
 			VersionID:      version.ID,
 			Name:           secret.Name,
 			ValueEncrypted: encryptedValue,
@@ -80,22 +108,11 @@ func (s *SecretService) CreateVersion(ctx context.Context, environmentID string,
 				"error": err.Error(),
 				"name":  secret.Name,
 			}).Error("Failed to insert secret")
-			return nil, fmt.Errorf("failed to insert secret %s: %w", secret.Name, err)
+			return secretdb.SecretVersion{}, fmt.Errorf("failed to insert secret %s: %w", secret.Name, err)
 		}
 	}
 
-	s.logger.WithFields(logrus.Fields{
-		"version_id":   version.ID,
-		"secret_count": len(req.Secrets),
-	}).Info("Successfully created secret version")
-
-	return &SecretVersionResponse{
-		ID:            version.ID,
-		EnvironmentID: version.EnvironmentID,
-		CommitMessage: version.CommitMessage,
-		CreatedAt:     version.CreatedAt,
-		SecretCount:   len(req.Secrets),
-	}, nil
+	return version, nil
 }
 
 // ListVersions lists all versions for an environment
@@ -113,6 +130,27 @@ func (s *SecretService) ListVersions(ctx context.Context, environmentID string)
 		return nil, fmt.Errorf("failed to list secret versions: %w", err)
 	}
 
+	syncedVersions, err := s.repo.ListCurrentSyncedVersions(ctx, environmentUUID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to list synced versions")
+		return nil, fmt.Errorf("failed to list synced versions: %w", err)
+	}
+
+	// Each provider target currently holds the version of its latest successful or partial sync
+	syncedTo := make(map[string][]VersionSyncStatus)
+	for _, synced := range syncedVersions {
+		status := VersionSyncStatus{
+			Target:   synced.Target,
+			Provider: synced.Provider,
+			Status:   synced.Status,
+			RunID:    synced.ID,
+		}
+		if synced.FinishedAt.Valid {
+			status.SyncedAt = &synced.FinishedAt.Time
+		}
+		syncedTo[synced.VersionID.String] = append(syncedTo[synced.VersionID.String], status)
+	}
+
 	// Convert to response format
 	responses := make([]SecretVersionResponse, len(versions))
 	for i, version := range versions {
@@ -121,6 +159,7 @@ func (s *SecretService) ListVersions(ctx context.Context, environmentID string)
 			EnvironmentID: version.EnvironmentID,
 			CommitMessage: version.CommitMessage,
 			CreatedAt:     version.CreatedAt,
+			SyncedTo:      syncedTo[version.ID],
 			// Note: SecretCount would need a separate query to get actual count
 		}
 	}
@@ -129,8 +168,19 @@ func (s *SecretService) ListVersions(ctx context.Context, environmentID string)
 	return responses, nil
 }
 
-// GetVersionDetails gets detailed information about a specific version including secrets
+// GetVersionDetails gets detailed information about a specific version including secrets.
+// Inherited keys come from the secret group base the version was created on top of.
 func (s *SecretService) GetVersionDetails(ctx context.Context, versionID string) (*SecretVersionDetailResponse, error) {
+	return s.getVersionDetails(ctx, versionID, false)
+}
+
+// getCurrentVersionDetails gets a version with the current secret group base of its environment,
+// which is the effective set that gets synced and compared against providers
+func (s *SecretService) getCurrentVersionDetails(ctx context.Context, versionID string) (*SecretVersionDetailResponse, error) {
+	return s.getVersionDetails(ctx, versionID, true)
+}
+
+func (s *SecretService) getVersionDetails(ctx context.Context, versionID string, currentBase bool) (*SecretVersionDetailResponse, error) {
 	s.logger.WithField("version_id", versionID).Info("Getting version details")
 
 	// Get version info
@@ -160,11 +210,31 @@ func (s *SecretService) GetVersionDetails(ctx context.Context, versionID string)
 		}
 
 		decryptedSecrets[i] = SecretWithValue{
-			Name:  secret.Name,
-			Value: decryptedValue,
+			Name:   secret.Name,
+			Value:  decryptedValue,
+			Source: SecretSourceEnvironment,
 		}
 	}
 
+	// Overlay the environment's own secrets on top of the secret group base
+	var groupSecrets []SecretWithValue
+	if currentBase {
+		groupSecrets, err = s.getGroupBaseSecrets(ctx, version.EnvironmentID)
+	} else {
+		groupSecrets, err = s.getVersionGroupBaseSecrets(ctx, versionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	decryptedSecrets = mergeEffectiveSecrets(groupSecrets, decryptedSecrets)
+
+	// Load renames and moves recorded by this version
+	keyChanges, err := s.repo.ListSecretKeyChangesForVersion(ctx, versionID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to get key changes for version")
+		return nil, fmt.Errorf("failed to get key changes for version: %w", err)
+	}
+
 	s.logger.WithFields(logrus.Fields{
 		"version_id":   versionID,
 		"secret_count": len(decryptedSecrets),
@@ -176,6 +246,7 @@ func (s *SecretService) GetVersionDetails(ctx context.Context, versionID string)
 		CommitMessage: version.CommitMessage,
 		CreatedAt:     version.CreatedAt,
 		Secrets:       decryptedSecrets,
+		KeyChanges:    toSecretKeyChangeResponses(keyChanges),
 	}, nil
 }
 
@@ -205,6 +276,29 @@ func (s *SecretService) RollbackToVersion(ctx context.Context, environmentID str
 		return nil, apiErrors.ErrEnvironmentsMisMatch
 	}
 
+	// Validate the secrets being restored against the current policy
+	targetSecrets, err := s.repo.GetSecretsForVersion(ctx, req.VersionID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to get secrets for rollback target")
+		return nil, apiErrors.ErrSecretNotFound
+	}
+	rollbackReq := CreateSecretVersionRequest{CommitMessage: req.CommitMessage}
+	for _, secret := range targetSecrets {
+		decryptedValue, err := s.encrypt.Decrypt(secret.ValueEncrypted)
+		if err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"error": err.Error(),
+				"name":  secret.Name,
+			}).Error("Failed to decrypt secret value")
+			return nil, err
+		}
+		rollbackReq.Secrets = append(rollbackReq.Secrets, SecretInput{Name: secret.Name, Value: decryptedValue})
+	}
+	if err := s.validateCreateVersionRequest(ctx, environmentUUID, rollbackReq); err != nil {
+		s.logger.WithField("error", err.Error()).Error("Rollback target version does not satisfy validation")
+		return nil, err
+	}
+
 	// Create new version
 	newVersion, err := s.repo.CreateSecretVersion(ctx, secretdb.CreateSecretVersionParams{
 		EnvironmentID: environmentUUID,
@@ -238,6 +332,8 @@ func (s *SecretService) RollbackToVersion(ctx context.Context, environmentID str
 		"secret_count":   len(secrets),
 	}).Info("Successfully rolled back to previous version")
 
+	s.scheduleAutoSync(ctx, environmentUUID)
+
 	return &SecretVersionResponse{
 		ID:            newVersion.ID,
 		EnvironmentID: newVersion.EnvironmentID,
@@ -283,47 +379,84 @@ func (s *SecretService) GetVersionDiff(ctx context.Context, fromVersionID, toVer
 		return nil, fmt.Errorf("failed to get version diff: %w", err)
 	}
 
+	// Keys missing from an environment version fall back to the secret group base
+	// that version was created on top of, which can differ between the two sides
+	fromGroupValues, err := s.getVersionGroupBaseValues(ctx, fromVersionID)
+	if err != nil {
+		return nil, err
+	}
+	toGroupValues, err := s.getVersionGroupBaseValues(ctx, toVersionID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Process diff data
-	changes := make([]SecretDiffChange, 0, len(diffData))
+	changes := make([]SecretDiffChange, 0, len(diffData)+len(toGroupValues))
+	seenNames := make(map[string]bool, len(diffData))
 	for _, diff := range diffData {
-		change := SecretDiffChange{Name: diff.Name}
+		seenNames[diff.Name] = true
+		change := SecretDiffChange{Name: diff.Name, Source: SecretSourceEnvironment}
+		fromGroupValue, inFromGroup := fromGroupValues[diff.Name]
+		toGroupValue, inToGroup := toGroupValues[diff.Name]
 
 		// Decrypt values if they exist
-		if diff.ValueV1 != nil {
+		hasV1 := diff.ValueV1 != nil
+		if hasV1 {
 			decryptedV1, err := s.encrypt.Decrypt(diff.ValueV1)
 			if err != nil {
 				s.logger.WithField("error", err.Error()).Error("Failed to decrypt v1 value")
 				return nil, fmt.Errorf("failed to decrypt v1 value for %s: %w", diff.Name, err)
 			}
 			change.OldValue = decryptedV1
+		} else if inFromGroup {
+			change.OldValue = fromGroupValue
+			hasV1 = true
 		}
 
-		if diff.ValueV2 != nil {
+		hasV2 := diff.ValueV2 != nil
+		if hasV2 {
 			decryptedV2, err := s.encrypt.Decrypt(diff.ValueV2)
 			if err != nil {
 				s.logger.WithField("error", err.Error()).Error("Failed to decrypt v2 value")
 				return nil, err
 			}
 			change.NewValue = decryptedV2
+		} else if inToGroup {
+			change.NewValue = toGroupValue
+			change.Source = SecretSourceGroup
+			hasV2 = true
 		}
 
-		// Determine change type
-		if diff.ValueV1 == nil && diff.ValueV2 != nil {
-			change.Type = "added"
-		} else if diff.ValueV1 != nil && diff.ValueV2 == nil {
-			change.Type = "removed"
-		} else if diff.ValueV1 != nil && diff.ValueV2 != nil {
-			// Both values exist, check if they're actually different
-			if change.OldValue == change.NewValue {
-				change.Type = "no_change"
-			} else {
-				change.Type = "modified"
-			}
-		}
-
+		change.Type = diffChangeType(hasV1, hasV2, change.OldValue, change.NewValue)
 		changes = append(changes, change)
 	}
 
+	// Keys that only exist in the group bases
+	groupOnlyNames := make([]string, 0, len(fromGroupValues)+len(toGroupValues))
+	for name := range fromGroupValues {
+		if !seenNames[name] {
+			groupOnlyNames = append(groupOnlyNames, name)
+			seenNames[name] = true
+		}
+	}
+	for name := range toGroupValues {
+		if !seenNames[name] {
+			groupOnlyNames = append(groupOnlyNames, name)
+		}
+	}
+	sort.Strings(groupOnlyNames)
+	for _, name := range groupOnlyNames {
+		oldValue, hasV1 := fromGroupValues[name]
+		newValue, hasV2 := toGroupValues[name]
+		changes = append(changes, SecretDiffChange{
+			Name:     name,
+			Type:     diffChangeType(hasV1, hasV2, oldValue, newValue),
+			OldValue: oldValue,
+			NewValue: newValue,
+			Source:   SecretSourceGroup,
+		})
+	}
+
 	s.logger.WithField("change_count", len(changes)).Info("Successfully generated version diff")
 
 	return &SecretDiffResponse{
@@ -333,8 +466,497 @@ func (s *SecretService) GetVersionDiff(ctx context.Context, fromVersionID, toVer
 	}, nil
 }
 
-// validateCreateVersionRequest validates the create version request
-func (s *SecretService) validateCreateVersionRequest(req CreateSecretVersionRequest) error {
+// CreateGroupVersion creates a new base version of secrets shared by every environment of a secret group
+func (s *SecretService) CreateGroupVersion(ctx context.Context, secretGroupID string, req CreateSecretVersionRequest) (*SecretGroupVersionResponse, error) {
+	s.logger.WithFields(logrus.Fields{
+		"secret_group_id": secretGroupID,
+		"secret_count":    len(req.Secrets),
+		"commit_message":  req.CommitMessage,
+	}).Info("Creating new secret group version")
+
+	// Validate input
+	if err := s.validateSecretInputs(req); err != nil {
+		s.logger.WithField("error", err.Error()).Error("Invalid create secret group version request")
+		return nil, err
+	}
+
+	secretGroupUUID, err := uuid.Parse(secretGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every environment of the group builds on the new base, so each must still satisfy its policy
+	environmentIDs, err := s.repo.ListSecretGroupEnvironmentIDs(ctx, secretGroupUUID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to list secret group environments")
+		return nil, fmt.Errorf("failed to list secret group environments: %w", err)
+	}
+	if err := s.enforceGroupBasePolicy(ctx, environmentIDs, req); err != nil {
+		s.logger.WithField("error", err.Error()).Error("Secret group version rejected by environment policy")
+		return nil, err
+	}
+
+	// Create the version
+	version, err := s.repo.CreateSecretGroupVersion(ctx, secretdb.CreateSecretGroupVersionParams{
+		SecretGroupID: secretGroupUUID,
+		CommitMessage: req.CommitMessage,
+	})
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to create secret group version")
+		return nil, fmt.Errorf("failed to create secret group version: %w", err)
+	}
+
+	// Encrypt and store secrets
+	for _, secret := range req.Secrets {
+		encryptedValue, err := s.encrypt.Encrypt(secret.Value)
+		if err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"error": err.Error(),
+				"name":  secret.Name,
+			}).Error("Failed to encrypt secret value")
+			return nil, apiErrors.ErrEncryptionFailed
+		}
+
+		err = s.repo.InsertSecretGroupSecret(ctx, secretdb.InsertSecretGroupSecretParams{
+			VersionID:      version.ID,
+			Name:           secret.Name,
+			ValueEncrypted: encryptedValue,
+		})
+		if err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"error": err.Error(),
+				"name":  secret.Name,
+			}).Error("Failed to insert secret group secret")
+			return nil, fmt.Errorf("failed to insert secret %s: %w", secret.Name, err)
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"version_id":   version.ID,
+		"secret_count": len(req.Secrets),
+	}).Info("Successfully created secret group version")
+
+	for _, environmentID := range environmentIDs {
+		s.scheduleAutoSync(ctx, environmentID)
+	}
+
+	return &SecretGroupVersionResponse{
+		ID:            version.ID,
+		SecretGroupID: version.SecretGroupID,
+		CommitMessage: version.CommitMessage,
+		CreatedAt:     version.CreatedAt,
+		SecretCount:   len(req.Secrets),
+	}, nil
+}
+
+// ListGroupVersions lists all base versions for a secret group
+func (s *SecretService) ListGroupVersions(ctx context.Context, secretGroupID string) ([]SecretGroupVersionResponse, error) {
+	s.logger.WithField("secret_group_id", secretGroupID).Info("Listing secret group versions")
+
+	secretGroupUUID, err := uuid.Parse(secretGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.repo.ListSecretGroupVersions(ctx, secretGroupUUID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to list secret group versions")
+		return nil, fmt.Errorf("failed to list secret group versions: %w", err)
+	}
+
+	responses := make([]SecretGroupVersionResponse, len(versions))
+	for i, version := range versions {
+		responses[i] = SecretGroupVersionResponse{
+			ID:            version.ID,
+			SecretGroupID: version.SecretGroupID,
+			CommitMessage: version.CommitMessage,
+			CreatedAt:     version.CreatedAt,
+		}
+	}
+
+	s.logger.WithField("version_count", len(versions)).Info("Successfully listed secret group versions")
+	return responses, nil
+}
+
+// GetGroupVersionDetails gets detailed information about a secret group base version including secrets
+func (s *SecretService) GetGroupVersionDetails(ctx context.Context, secretGroupID, versionID string) (*SecretGroupVersionDetailResponse, error) {
+	s.logger.WithFields(logrus.Fields{
+		"secret_group_id": secretGroupID,
+		"version_id":      versionID,
+	}).Info("Getting secret group version details")
+
+	version, err := s.repo.GetSecretGroupVersion(ctx, versionID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to get secret group version")
+		return nil, apiErrors.ErrSecretGroupVersionNotFound
+	}
+
+	// The version must belong to the secret group in the request path
+	if version.SecretGroupID.String() != secretGroupID {
+		s.logger.Error("Secret group version does not belong to the specified secret group")
+		return nil, apiErrors.ErrSecretGroupVersionNotFound
+	}
+
+	secrets, err := s.repo.GetSecretsForSecretGroupVersion(ctx, versionID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to get secrets for secret group version")
+		return nil, apiErrors.ErrSecretNotFound
+	}
+
+	decryptedSecrets := make([]SecretWithValue, len(secrets))
+	for i, secret := range secrets {
+		decryptedValue, err := s.encrypt.Decrypt(secret.ValueEncrypted)
+		if err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"error": err.Error(),
+				"name":  secret.Name,
+			}).Error("Failed to decrypt secret value")
+			return nil, err
+		}
+
+		decryptedSecrets[i] = SecretWithValue{
+			Name:   secret.Name,
+			Value:  decryptedValue,
+			Source: SecretSourceGroup,
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"version_id":   versionID,
+		"secret_count": len(decryptedSecrets),
+	}).Info("Successfully retrieved secret group version details")
+
+	return &SecretGroupVersionDetailResponse{
+		ID:            version.ID,
+		SecretGroupID: version.SecretGroupID,
+		CommitMessage: version.CommitMessage,
+		CreatedAt:     version.CreatedAt,
+		Secrets:       decryptedSecrets,
+	}, nil
+}
+
+// getGroupBaseSecrets returns the decrypted secrets of the latest base version of the
+// secret group the environment belongs to. It returns no secrets if the group has no base version.
+func (s *SecretService) getGroupBaseSecrets(ctx context.Context, environmentID uuid.UUID) ([]SecretWithValue, error) {
+	rows, err := s.repo.GetLatestSecretGroupSecretsForEnvironment(ctx, environmentID)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"error":          err.Error(),
+			"environment_id": environmentID,
+		}).Error("Failed to get secret group base secrets")
+		return nil, fmt.Errorf("failed to get secret group base secrets: %w", err)
+	}
+
+	return s.decryptGroupBaseSecrets(rows)
+}
+
+// decryptGroupBaseSecrets decrypts secret group base rows, sorted by name
+func (s *SecretService) decryptGroupBaseSecrets(rows []secretdb.GetLatestSecretGroupSecretsForEnvironmentRow) ([]SecretWithValue, error) {
+	secrets := make([]SecretWithValue, len(rows))
+	for i, row := range rows {
+		decryptedValue, err := s.encrypt.Decrypt(row.ValueEncrypted)
+		if err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"error": err.Error(),
+				"name":  row.Name,
+			}).Error("Failed to decrypt secret group value")
+			return nil, err
+		}
+		secrets[i] = SecretWithValue{
+			Name:   row.Name,
+			Value:  decryptedValue,
+			Source: SecretSourceGroup,
+		}
+	}
+
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+	return secrets, nil
+}
+
+// getVersionGroupBaseSecrets returns the decrypted secrets of the secret group base version that was
+// current when the environment version was created. It returns no secrets if there was none.
+func (s *SecretService) getVersionGroupBaseSecrets(ctx context.Context, versionID string) ([]SecretWithValue, error) {
+	rows, err := s.repo.GetSecretGroupSecretsForSecretVersion(ctx, versionID)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"error":      err.Error(),
+			"version_id": versionID,
+		}).Error("Failed to get secret group base secrets for version")
+		return nil, fmt.Errorf("failed to get secret group base secrets for version: %w", err)
+	}
+
+	baseRows := make([]secretdb.GetLatestSecretGroupSecretsForEnvironmentRow, len(rows))
+	for i, row := range rows {
+		baseRows[i] = secretdb.GetLatestSecretGroupSecretsForEnvironmentRow(row)
+	}
+	return s.decryptGroupBaseSecrets(baseRows)
+}
+
+// getVersionGroupBaseValues returns the group base values of an environment version keyed by name
+func (s *SecretService) getVersionGroupBaseValues(ctx context.Context, versionID string) (map[string]string, error) {
+	secrets, err := s.getVersionGroupBaseSecrets(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(secrets))
+	for _, secret := range secrets {
+		values[secret.Name] = secret.Value
+	}
+	return values, nil
+}
+
+// diffChangeType classifies a key by whether it exists on each side of a diff and its values
+func diffChangeType(hasOld, hasNew bool, oldValue, newValue string) string {
+	switch {
+	case !hasOld && hasNew:
+		return "added"
+	case hasOld && !hasNew:
+		return "removed"
+	case oldValue == newValue:
+		return "no_change"
+	default:
+		return "modified"
+	}
+}
+
+// mergeEffectiveSecrets overlays the environment secrets on top of the group base.
+// Environment keys win; group keys that the environment does not override are appended.
+func mergeEffectiveSecrets(groupSecrets, environmentSecrets []SecretWithValue) []SecretWithValue {
+	if len(groupSecrets) == 0 {
+		return environmentSecrets
+	}
+
+	overridden := make(map[string]bool, len(environmentSecrets))
+	for _, secret := range environmentSecrets {
+		overridden[secret.Name] = true
+	}
+
+	effective := make([]SecretWithValue, 0, len(environmentSecrets)+len(groupSecrets))
+	effective = append(effective, environmentSecrets...)
+	for _, secret := range groupSecrets {
+		if !overridden[secret.Name] {
+			effective = append(effective, secret)
+		}
+	}
+	return effective
+}
+
+// SetGroupPolicy creates or replaces the value policy schema of a secret group
+func (s *SecretService) SetGroupPolicy(ctx context.Context, secretGroupID, userID string, req SecretPolicyRequest) (*SecretPolicyResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":          "SetGroupPolicy",
+		"secret_group_id": secretGroupID,
+		"user_id":         userID,
+	})
+
+	if err := req.Schema.Validate(); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Invalid secret policy schema")
+		return nil, apiErrors.ErrInvalidSecretPolicy
+	}
+
+	secretGroupUUID, err := uuid.Parse(secretGroupID)
+	if err != nil {
+		return nil, err
+	}
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaJSON, err := json.Marshal(req.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal secret policy: %w", err)
+	}
+
+	policy, err := s.repo.UpsertSecretGroupPolicy(ctx, secretdb.UpsertSecretGroupPolicyParams{
+		SecretGroupID: secretGroupUUID,
+		Schema:        schemaJSON,
+		UpdatedBy:     userUUID,
+	})
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to save secret policy")
+		return nil, fmt.Errorf("failed to save secret policy: %w", err)
+	}
+
+	logEntry.Info("Successfully saved secret policy")
+	return toSecretPolicyResponse(policy)
+}
+
+// GetGroupPolicy returns the value policy schema of a secret group
+func (s *SecretService) GetGroupPolicy(ctx context.Context, secretGroupID string) (*SecretPolicyResponse, error) {
+	secretGroupUUID, err := uuid.Parse(secretGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.repo.GetSecretGroupPolicy(ctx, secretGroupUUID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apiErrors.ErrSecretPolicyNotFound
+		}
+		s.logger.WithField("error", err.Error()).Error("Failed to get secret policy")
+		return nil, fmt.Errorf("failed to get secret policy: %w", err)
+	}
+
+	return toSecretPolicyResponse(policy)
+}
+
+// DeleteGroupPolicy removes the value policy schema of a secret group
+func (s *SecretService) DeleteGroupPolicy(ctx context.Context, secretGroupID string) error {
+	secretGroupUUID, err := uuid.Parse(secretGroupID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteSecretGroupPolicy(ctx, secretGroupUUID); err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to delete secret policy")
+		return fmt.Errorf("failed to delete secret policy: %w", err)
+	}
+
+	s.logger.WithField("secret_group_id", secretGroupID).Info("Successfully deleted secret policy")
+	return nil
+}
+
+// LintVersion reports the policy violations of an existing version without blocking anything
+func (s *SecretService) LintVersion(ctx context.Context, versionID string) (*SecretPolicyLintResponse, error) {
+	details, err := s.getCurrentVersionDetails(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SecretPolicyLintResponse{
+		VersionID:     details.ID,
+		EnvironmentID: details.EnvironmentID,
+		Valid:         true,
+		Violations:    []PolicyViolation{},
+	}
+
+	policy, err := s.loadSecretPolicy(ctx, details.EnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return response, nil
+	}
+
+	response.PolicyFound = true
+	response.Violations = policy.Evaluate(details.Secrets)
+	response.Valid = len(response.Violations) == 0
+
+	s.logger.WithFields(logrus.Fields{
+		"version_id":      versionID,
+		"violation_count": len(response.Violations),
+	}).Info("Successfully linted secret version")
+
+	return response, nil
+}
+
+// loadSecretPolicy returns the policy resolved for an environment, or nil when none is configured
+func (s *SecretService) loadSecretPolicy(ctx context.Context, environmentID uuid.UUID) (*SecretPolicySchema, error) {
+	row, err := s.repo.GetSecretPolicyForEnvironment(ctx, environmentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		s.logger.WithFields(logrus.Fields{
+			"error":          err.Error(),
+			"environment_id": environmentID,
+		}).Error("Failed to get secret policy for environment")
+		return nil, fmt.Errorf("failed to get secret policy: %w", err)
+	}
+
+	var schema SecretPolicySchema
+	if err := json.Unmarshal(row.Schema, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse secret policy: %w", err)
+	}
+
+	resolved := schema.Resolve(row.EnvironmentName)
+	return &resolved, nil
+}
+
+// enforceSecretPolicy checks the effective secret set of an environment against its policy
+func (s *SecretService) enforceSecretPolicy(ctx context.Context, environmentID uuid.UUID, secrets []SecretWithValue) error {
+	policy, err := s.loadSecretPolicy(ctx, environmentID)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	groupSecrets, err := s.getGroupBaseSecrets(ctx, environmentID)
+	if err != nil {
+		return err
+	}
+
+	if violations := policy.Evaluate(mergeEffectiveSecrets(groupSecrets, secrets)); len(violations) > 0 {
+		return &SecretPolicyViolationError{Violations: violations}
+	}
+	return nil
+}
+
+// enforceGroupBasePolicy checks the effective secret set every given environment would have on top of a new
+// group base version against the environment's policy
+func (s *SecretService) enforceGroupBasePolicy(ctx context.Context, environmentIDs []uuid.UUID, req CreateSecretVersionRequest) error {
+	base := make([]SecretWithValue, len(req.Secrets))
+	for i, secret := range req.Secrets {
+		base[i] = SecretWithValue{Name: secret.Name, Value: secret.Value, Source: SecretSourceGroup}
+	}
+
+	for _, environmentID := range environmentIDs {
+		policy, err := s.loadSecretPolicy(ctx, environmentID)
+		if err != nil {
+			return err
+		}
+		if policy == nil {
+			continue
+		}
+
+		_, inputs, err := s.loadLatestSecrets(ctx, environmentID)
+		if err != nil {
+			return err
+		}
+		secrets := make([]SecretWithValue, len(inputs))
+		for i, input := range inputs {
+			secrets[i] = SecretWithValue{Name: input.Name, Value: input.Value, Source: SecretSourceEnvironment}
+		}
+
+		if violations := policy.Evaluate(mergeEffectiveSecrets(base, secrets)); len(violations) > 0 {
+			return &SecretPolicyViolationError{Violations: violations, EnvironmentID: environmentID.String()}
+		}
+	}
+	return nil
+}
+
+// toSecretPolicyResponse converts a stored policy into its API representation
+func toSecretPolicyResponse(policy secretdb.SecretGroupPolicy) (*SecretPolicyResponse, error) {
+	var schema SecretPolicySchema
+	if err := json.Unmarshal(policy.Schema, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse secret policy: %w", err)
+	}
+
+	return &SecretPolicyResponse{
+		SecretGroupID: policy.SecretGroupID,
+		Schema:        schema,
+		UpdatedBy:     policy.UpdatedBy,
+		CreatedAt:     policy.CreatedAt,
+		UpdatedAt:     policy.UpdatedAt,
+	}, nil
+}
+
+// validateCreateVersionRequest validates the create version request and enforces the
+// secret group policy of the environment on the resulting effective secret set
+func (s *SecretService) validateCreateVersionRequest(ctx context.Context, environmentID uuid.UUID, req CreateSecretVersionRequest) error {
+	if err := s.validateSecretInputs(req); err != nil {
+		return err
+	}
+
+	secrets := make([]SecretWithValue, len(req.Secrets))
+	for i, secret := range req.Secrets {
+		secrets[i] = SecretWithValue{Name: secret.Name, Value: secret.Value, Source: SecretSourceEnvironment}
+	}
+	return s.enforceSecretPolicy(ctx, environmentID, secrets)
+}
+
+// validateSecretInputs validates the commit message, secret names and values of a request
+func (s *SecretService) validateSecretInputs(req CreateSecretVersionRequest) error {
 	// Validate commit message
 	if req.CommitMessage == "" {
 		return fmt.Errorf("commit message cannot be empty")
@@ -373,35 +995,58 @@ func (s *SecretService) validateCreateVersionRequest(req CreateSecretVersionRequ
 	return nil
 }
 
-// SyncSecrets syncs secrets to an external provider
-func (s *SecretService) SyncSecrets(ctx context.Context, environmentID string, req SyncSecretsRequest) (*SyncSecretsResponse, error) {
+// SyncSecrets syncs secrets to a provider target and records the run with its per-secret outcomes.
+// The user ID may be empty for runs not started by a user.
+func (s *SecretService) SyncSecrets(ctx context.Context, environmentID, userID string, req SyncTargetRequest) (*SyncSecretsResponse, error) {
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.startSyncRun(ctx, environmentUUID, userID, req.Target, SyncTriggerManual)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.executeSyncRun(ctx, environmentUUID, run.ID, req)
+}
+
+// executeSyncRun performs a recorded sync run and records its outcome
+func (s *SecretService) executeSyncRun(ctx context.Context, environmentID, runID uuid.UUID, req SyncTargetRequest) (*SyncSecretsResponse, error) {
+	// Get the latest version if no specific version is provided
+	versionID, err := s.resolveSyncVersion(ctx, environmentID, req.VersionID)
+	if err != nil {
+		s.finishSyncRun(ctx, runID, "", nil, err)
+		return nil, err
+	}
+	req.VersionID = versionID
+
+	response, err := s.syncVersion(ctx, environmentID.String(), req, nil)
+	s.finishSyncRun(ctx, runID, versionID, response, err)
+	if err != nil {
+		return nil, err
+	}
+
+	response.RunID = runID
+	return response, nil
+}
+
+// syncVersion pushes the secrets of a version to a provider target. A plan, when given,
+// limits the sync to the writes and deletions it lists.
+func (s *SecretService) syncVersion(ctx context.Context, environmentID string, req SyncTargetRequest, plan *plannedSync) (*SyncSecretsResponse, error) {
 	logEntry := s.logger.WithFields(logrus.Fields{
 		"method":         "SyncSecrets",
 		"environment_id": environmentID,
-		"provider":       req.Provider,
+		"target":         req.Target,
 		"version_id":     req.VersionID,
 	})
 
 	logEntry.Info("Starting secret sync to provider")
 
-	// Get the latest version if no specific version is provided
 	versionID := req.VersionID
-	if versionID == "" {
-		versions, err := s.ListVersions(ctx, environmentID)
-		if err != nil {
-			logEntry.WithField("error", err.Error()).Error("Failed to get latest version")
-			return nil, fmt.Errorf("failed to get latest version: %w", err)
-		}
-		if len(versions) == 0 {
-			logEntry.Error("No versions found for environment")
-			return nil, apiErrors.ErrNoSecretsToSync
-		}
-		versionID = versions[0].ID
-		logEntry.WithField("version_id", versionID).Info("Using latest version for sync")
-	}
 
 	// Get version details to get the secrets
-	versionDetails, err := s.GetVersionDetails(ctx, versionID)
+	versionDetails, err := s.getCurrentVersionDetails(ctx, versionID)
 	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to get version details")
 		return nil, fmt.Errorf("failed to get version details: %w", err)
@@ -413,13 +1058,19 @@ func (s *SecretService) SyncSecrets(ctx context.Context, environmentID string, r
 	}
 
 	// Get provider syncer
-	providerSyncer, err := s.providerService.GetProviderSyncer(ctx, environmentID, req.Provider)
+	providerSyncer, syncOptions, err := s.providerService.GetProviderSyncerWithOptions(ctx, environmentID, req.Target)
 	if err != nil {
 		logEntry.WithField("error", err.Error()).Error("Failed to get provider syncer")
 		// Return the specific error from provider service
 		return nil, err
 	}
 
+	// A plan is only valid against the credential settings it was computed with
+	if plan != nil && !syncOptions.UpdatedAt.Equal(plan.credentialUpdatedAt) {
+		logEntry.Error("Provider credential changed since the sync plan was created")
+		return nil, apiErrors.ErrSyncPlanStale
+	}
+
 	// Convert secrets to provider format
 	providerSecrets := make([]provider.Secret, len(versionDetails.Secrets))
 	for i, secret := range versionDetails.Secrets {
@@ -429,11 +1080,39 @@ func (s *SecretService) SyncSecrets(ctx context.Context, environmentID string, r
 		}
 	}
 
-	// Sync secrets to provider
-	syncResults, err := providerSyncer.Sync(ctx, providerSecrets)
+	// The target's key mapping decides which keys it receives and under which names
+	providerSecrets, err = syncOptions.KeyMapping.Apply(providerSecrets)
 	if err != nil {
-		logEntry.WithField("error", err.Error()).Error("Failed to sync secrets to provider")
-		return nil, apiErrors.ErrProviderSyncFailed
+		logEntry.WithField("error", err.Error()).Error("Failed to apply key mapping")
+		return nil, err
+	}
+	if len(providerSecrets) == 0 {
+		logEntry.Error("Key mapping excludes every secret in version")
+		return nil, apiErrors.ErrNoSecretsToSync
+	}
+
+	// A plan skips the secrets it found unchanged
+	writes := providerSecrets
+	if plan != nil {
+		writes = nil
+		for _, secret := range providerSecrets {
+			if plan.writes[secret.Name] {
+				writes = append(writes, secret)
+			}
+		}
+	}
+
+	// Sync secrets to provider
+	if recorder, ok := providerSyncer.(provider.ProviderVersionRecorder); ok {
+		recorder.SetSyncVersion(versionID)
+	}
+	var syncResults []provider.SyncResult
+	if len(writes) > 0 {
+		syncResults, err = providerSyncer.Sync(ctx, writes)
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Error("Failed to sync secrets to provider")
+			return nil, apiErrors.ErrProviderSyncFailed
+		}
 	}
 
 	// Process results
@@ -468,27 +1147,100 @@ func (s *SecretService) SyncSecrets(ctx context.Context, environmentID string, r
 		}
 	}
 
-	message := fmt.Sprintf("Synced %d secrets to %s provider", successCount, req.Provider)
+	// Remove the old remote names of keys renamed or moved away since the last sync, and in mirror mode the
+	// managed remote secrets that are no longer in the version. When every write failed, nothing is removed:
+	// the target keeps its old secrets rather than losing them without gaining the new ones, and the removals
+	// are retried on the next sync.
+	var removals, pruned []SyncResult
+	if status == "failed" {
+		logEntry.Warn("Every write failed, skipping removals and pruning")
+	} else {
+		var allowedRemovals, allowedPrunes map[string]bool
+		if plan != nil {
+			allowedRemovals, allowedPrunes = plan.removals, plan.prunes
+		}
+		removals = s.removeRenamedKeys(ctx, environmentID, req.Target, providerSyncer, syncOptions.KeyMapping, providerSecrets, allowedRemovals)
+		if syncOptions.MirrorMode {
+			pruned = s.pruneRemovedKeys(ctx, req.Target, providerSyncer, providerSecrets, allowedPrunes)
+		}
+	}
+
+	message := fmt.Sprintf("Synced %d secrets to %s %s target", successCount, req.Target, syncOptions.Provider)
 	if failedCount > 0 {
-		message = fmt.Sprintf("Synced %d secrets, %d failed to %s provider", successCount, failedCount, req.Provider)
+		message = fmt.Sprintf("Synced %d secrets, %d failed to %s %s target", successCount, failedCount, req.Target, syncOptions.Provider)
 	}
 
 	logEntry.WithFields(logrus.Fields{
 		"synced_count": successCount,
 		"failed_count": failedCount,
-		"total_count":  len(providerSecrets),
+		"total_count":  len(writes),
 		"status":       status,
 	}).Info("Secret sync completed")
 
 	return &SyncSecretsResponse{
-		Provider:    req.Provider,
+		Target:      req.Target,
+		Provider:    string(syncOptions.Provider),
 		Status:      status,
 		Message:     message,
 		SyncedCount: successCount,
 		FailedCount: failedCount,
-		TotalCount:  len(providerSecrets),
+		TotalCount:  len(writes),
 		Results:     results,
 		Errors:      errors,
+		Removals:    removals,
+		Pruned:      pruned,
 		SyncedAt:    time.Now().Format(time.RFC3339),
+		VersionID:   versionID,
 	}, nil
 }
+
+// pruneRemovedKeys deletes the remote secrets managed by Kavach that are not among the synced
+// secrets, for providers that can tell managed secrets apart. When allowed is set, only the remote
+// names in it are deleted. Failures are logged and retried on the next sync.
+func (s *SecretService) pruneRemovedKeys(ctx context.Context, target string, syncer provider.ProviderSyncer, synced []provider.Secret, allowed map[string]bool) []SyncResult {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method": "pruneRemovedKeys",
+		"target": target,
+	})
+
+	pruner, ok := syncer.(provider.ProviderPruner)
+	if !ok {
+		logEntry.Warn("Provider does not support mirror mode pruning")
+		return nil
+	}
+
+	keep := make([]string, len(synced))
+	for i, secret := range synced {
+		keep[i] = secret.Name
+	}
+
+	candidates, err := pruner.PruneCandidates(ctx, keep)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Warn("Failed to list managed secrets to prune")
+		return nil
+	}
+
+	var remoteNames []string
+	for _, remoteName := range candidates {
+		if allowed == nil || allowed[remoteName] {
+			remoteNames = append(remoteNames, remoteName)
+		}
+	}
+	if len(remoteNames) == 0 {
+		return nil
+	}
+
+	results, err := pruner.Prune(ctx, remoteNames)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Warn("Failed to prune managed secrets from provider")
+		return nil
+	}
+
+	pruned := make([]SyncResult, len(results))
+	for i, result := range results {
+		pruned[i] = SyncResult{Name: result.Name, Success: result.Success, Error: result.Error}
+	}
+
+	logEntry.WithField("pruned_count", len(pruned)).Info("Pruned managed secrets from provider")
+	return pruned
+}
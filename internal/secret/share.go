@@ -0,0 +1,458 @@
+package secret
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	apiErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	secretdb "github.com/Gkemhcs/kavach-backend/internal/secret/gen"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Share limits
+const (
+	defaultShareTTL            = time.Hour
+	maxShareTTL                = 7 * 24 * time.Hour
+	maxShareViews              = 10
+	maxSharePassphraseAttempts = 5
+	shareTokenBytes            = 32
+)
+
+// CreateShare creates a one-time, time-limited share of a key of an environment version or of an
+// ad-hoc value. Only a hash of the returned token is stored.
+func (s *SecretService) CreateShare(ctx context.Context, environmentID, userID string, req CreateSecretShareRequest, client ShareClientInfo) (*CreateSecretShareResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":         "CreateShare",
+		"environment_id": environmentID,
+		"user_id":        userID,
+		"secret_name":    req.SecretName,
+	})
+
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if (req.SecretName == "") == (req.Value == "") {
+		return nil, apiErrors.ErrInvalidSecretShare
+	}
+	maxViews := req.MaxViews
+	if maxViews == 0 {
+		maxViews = 1
+	}
+	if maxViews < 0 || maxViews > maxShareViews {
+		return nil, apiErrors.ErrInvalidSecretShare
+	}
+	ttl := defaultShareTTL
+	if req.ExpiresInMinutes != 0 {
+		ttl = time.Duration(req.ExpiresInMinutes) * time.Minute
+	}
+	if ttl <= 0 || ttl > maxShareTTL {
+		return nil, apiErrors.ErrInvalidSecretShare
+	}
+
+	params := secretdb.CreateSecretShareParams{
+		EnvironmentID: environmentUUID,
+		MaxViews:      int32(maxViews),
+		ExpiresAt:     time.Now().Add(ttl),
+		CreatedBy:     userUUID,
+	}
+
+	if req.SecretName != "" {
+		versionID, valueEncrypted, err := s.findSharedKey(ctx, environmentUUID, req.VersionID, req.SecretName)
+		if err != nil {
+			return nil, err
+		}
+		params.VersionID = sql.NullString{String: versionID, Valid: true}
+		params.SecretName = sql.NullString{String: req.SecretName, Valid: true}
+		params.ValueEncrypted = valueEncrypted
+	} else {
+		if err := s.encrypt.ValidateSecretValue(req.Value); err != nil {
+			return nil, err
+		}
+		params.ValueEncrypted, err = s.encrypt.Encrypt(req.Value)
+		if err != nil {
+			logEntry.WithField("error", err.Error()).Error("Failed to encrypt shared value")
+			return nil, apiErrors.ErrEncryptionFailed
+		}
+	}
+
+	if req.Passphrase != "" {
+		passphraseHash, err := bcrypt.GenerateFromPassword([]byte(req.Passphrase), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash share passphrase: %w", err)
+		}
+		params.PassphraseHash = sql.NullString{String: string(passphraseHash), Valid: true}
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return nil, err
+	}
+	params.TokenHash = hashShareToken(token)
+
+	share, err := s.repo.CreateSecretShare(ctx, params)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to create secret share")
+		return nil, fmt.Errorf("failed to create secret share: %w", err)
+	}
+
+	if err := s.recordShareEvent(ctx, share.ID, ShareEventCreated, &userUUID, client); err != nil {
+		return nil, err
+	}
+
+	// Expired shares are purged lazily so their values do not outlive the link
+	if purged, err := s.repo.PurgeExpiredSecretShareValues(ctx); err != nil {
+		logEntry.WithField("error", err.Error()).Warn("Failed to purge expired secret shares")
+	} else if purged > 0 {
+		logEntry.WithField("purged_count", purged).Info("Purged expired secret shares")
+	}
+
+	logEntry.WithField("share_id", share.ID).Info("Successfully created secret share")
+
+	return &CreateSecretShareResponse{
+		SecretShareResponse: toSecretShareResponse(share),
+		Token:               token,
+	}, nil
+}
+
+// RetrieveShare returns a shared value and counts the view. Once the view limit is reached the
+// stored value is cleared. Unknown, expired, revoked and used-up shares are indistinguishable.
+func (s *SecretService) RetrieveShare(ctx context.Context, token string, req RetrieveSecretShareRequest, client ShareClientInfo) (*RetrieveSecretShareResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":     "RetrieveShare",
+		"ip_address": client.IPAddress,
+	})
+
+	share, err := s.repo.GetSecretShareByTokenHash(ctx, hashShareToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apiErrors.ErrSecretShareNotFound
+		}
+		logEntry.WithField("error", err.Error()).Error("Failed to get secret share")
+		return nil, fmt.Errorf("failed to get secret share: %w", err)
+	}
+	logEntry = logEntry.WithField("share_id", share.ID)
+
+	if shareStatus(share) != ShareStatusActive {
+		if share.ValueEncrypted != nil {
+			s.clearShareValue(ctx, share.ID)
+		}
+		return nil, apiErrors.ErrSecretShareNotFound
+	}
+
+	if share.PassphraseHash.Valid {
+		if bcrypt.CompareHashAndPassword([]byte(share.PassphraseHash.String), []byte(req.Passphrase)) != nil {
+			if err := s.recordShareEvent(ctx, share.ID, ShareEventPassphraseFailed, nil, client); err != nil {
+				logEntry.WithField("error", err.Error()).Warn("Failed to record failed share passphrase")
+			}
+			// The value is cleared by the same update that reaches the limit, so a locked share never keeps it
+			attempts, err := s.repo.RecordSecretShareFailedAttempt(ctx, secretdb.RecordSecretShareFailedAttemptParams{
+				ID:                share.ID,
+				MaxFailedAttempts: maxSharePassphraseAttempts,
+			})
+			if err != nil {
+				logEntry.WithField("error", err.Error()).Warn("Failed to count failed share passphrase")
+			} else if attempts >= maxSharePassphraseAttempts {
+				logEntry.Warn("Too many failed passphrase attempts, locked secret share")
+			}
+			return nil, apiErrors.ErrInvalidSharePassphrase
+		}
+	}
+
+	// The view is counted atomically so concurrent retrievals cannot exceed the view limit, and a share
+	// locked by failed passphrases in the meantime is not consumed
+	consumed, err := s.repo.ConsumeSecretShare(ctx, secretdb.ConsumeSecretShareParams{
+		ID:                share.ID,
+		MaxFailedAttempts: maxSharePassphraseAttempts,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apiErrors.ErrSecretShareNotFound
+		}
+		logEntry.WithField("error", err.Error()).Error("Failed to consume secret share")
+		return nil, fmt.Errorf("failed to consume secret share: %w", err)
+	}
+
+	value, err := s.encrypt.Decrypt(consumed.ValueEncrypted)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to decrypt shared value")
+		return nil, apiErrors.ErrDecryptionFailed
+	}
+
+	if consumed.ViewCount >= consumed.MaxViews {
+		s.clearShareValue(ctx, consumed.ID)
+	}
+
+	if err := s.recordShareEvent(ctx, consumed.ID, ShareEventRetrieved, nil, client); err != nil {
+		// The view is already counted on the share itself, so the value is still returned
+		logEntry.WithField("error", err.Error()).Error("Failed to record secret share retrieval")
+	}
+
+	logEntry.WithField("view_count", consumed.ViewCount).Info("Successfully retrieved secret share")
+
+	return &RetrieveSecretShareResponse{
+		SecretName:     consumed.SecretName.String,
+		Value:          value,
+		RemainingViews: int(consumed.MaxViews - consumed.ViewCount),
+		ExpiresAt:      consumed.ExpiresAt,
+	}, nil
+}
+
+// ListShares lists the shares created from an environment, newest first
+func (s *SecretService) ListShares(ctx context.Context, environmentID string) ([]SecretShareResponse, error) {
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := s.repo.ListSecretSharesForEnvironment(ctx, environmentUUID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to list secret shares")
+		return nil, fmt.Errorf("failed to list secret shares: %w", err)
+	}
+
+	responses := make([]SecretShareResponse, len(shares))
+	for i, share := range shares {
+		responses[i] = toSecretShareResponse(share)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"environment_id": environmentID,
+		"share_count":    len(responses),
+	}).Info("Successfully listed secret shares")
+
+	return responses, nil
+}
+
+// GetShare returns a share of an environment with its recorded events
+func (s *SecretService) GetShare(ctx context.Context, environmentID, shareID string) (*SecretShareDetailResponse, error) {
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+	shareUUID, err := uuid.Parse(shareID)
+	if err != nil {
+		return nil, apiErrors.ErrSecretShareNotFound
+	}
+
+	share, err := s.repo.GetSecretShare(ctx, secretdb.GetSecretShareParams{
+		ID:            shareUUID,
+		EnvironmentID: environmentUUID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apiErrors.ErrSecretShareNotFound
+		}
+		s.logger.WithField("error", err.Error()).Error("Failed to get secret share")
+		return nil, fmt.Errorf("failed to get secret share: %w", err)
+	}
+
+	events, err := s.repo.ListSecretShareEvents(ctx, share.ID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to list secret share events")
+		return nil, fmt.Errorf("failed to list secret share events: %w", err)
+	}
+
+	response := &SecretShareDetailResponse{
+		SecretShareResponse: toSecretShareResponse(share),
+		Events:              make([]SecretShareEventResponse, len(events)),
+	}
+	for i, event := range events {
+		response.Events[i] = SecretShareEventResponse{
+			EventType: event.EventType,
+			IPAddress: event.IpAddress.String,
+			UserAgent: event.UserAgent.String,
+			CreatedAt: event.CreatedAt,
+		}
+		if event.ActorID.Valid {
+			actorID := event.ActorID.UUID
+			response.Events[i].ActorID = &actorID
+		}
+	}
+	return response, nil
+}
+
+// RevokeShare revokes an active share and clears its value
+func (s *SecretService) RevokeShare(ctx context.Context, environmentID, shareID, userID string, client ShareClientInfo) (*SecretShareResponse, error) {
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+	shareUUID, err := uuid.Parse(shareID)
+	if err != nil {
+		return nil, apiErrors.ErrSecretShareNotFound
+	}
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	share, err := s.repo.RevokeSecretShare(ctx, secretdb.RevokeSecretShareParams{
+		ID:            shareUUID,
+		EnvironmentID: environmentUUID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apiErrors.ErrSecretShareNotFound
+		}
+		s.logger.WithField("error", err.Error()).Error("Failed to revoke secret share")
+		return nil, fmt.Errorf("failed to revoke secret share: %w", err)
+	}
+
+	if err := s.recordShareEvent(ctx, share.ID, ShareEventRevoked, &userUUID, client); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"environment_id": environmentID,
+		"share_id":       shareID,
+		"user_id":        userID,
+	}).Info("Successfully revoked secret share")
+
+	response := toSecretShareResponse(share)
+	return &response, nil
+}
+
+// findSharedKey returns the version and encrypted value of a key in the effective secrets of a version,
+// defaulting to the latest version
+func (s *SecretService) findSharedKey(ctx context.Context, environmentID uuid.UUID, versionID, name string) (string, []byte, error) {
+	if versionID == "" {
+		versions, err := s.repo.ListSecretVersions(ctx, environmentID)
+		if err != nil {
+			s.logger.WithField("error", err.Error()).Error("Failed to list secret versions")
+			return "", nil, fmt.Errorf("failed to list secret versions: %w", err)
+		}
+		if len(versions) == 0 {
+			return "", nil, apiErrors.ErrSecretVersionNotFound
+		}
+		versionID = versions[0].ID
+	} else {
+		version, err := s.repo.GetSecretVersion(ctx, versionID)
+		if err != nil || version.EnvironmentID != environmentID {
+			return "", nil, apiErrors.ErrSecretVersionNotFound
+		}
+	}
+
+	secrets, err := s.repo.GetSecretsForVersion(ctx, versionID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to get secrets for version")
+		return "", nil, fmt.Errorf("failed to get secrets for version %s: %w", versionID, err)
+	}
+	for _, secret := range secrets {
+		if secret.Name == name {
+			return versionID, secret.ValueEncrypted, nil
+		}
+	}
+
+	// Keys the version does not override are inherited from the group base it was created on top of
+	groupSecrets, err := s.repo.GetSecretGroupSecretsForSecretVersion(ctx, versionID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to get secret group base secrets for version")
+		return "", nil, fmt.Errorf("failed to get secret group base secrets for version %s: %w", versionID, err)
+	}
+	for _, secret := range groupSecrets {
+		if secret.Name == name {
+			return versionID, secret.ValueEncrypted, nil
+		}
+	}
+	return "", nil, apiErrors.ErrSecretKeyNotFound
+}
+
+// recordShareEvent appends an event to the audit trail of a share
+func (s *SecretService) recordShareEvent(ctx context.Context, shareID uuid.UUID, eventType string, actorID *uuid.UUID, client ShareClientInfo) error {
+	params := secretdb.InsertSecretShareEventParams{
+		ShareID:   shareID,
+		EventType: eventType,
+		IpAddress: sql.NullString{String: client.IPAddress, Valid: client.IPAddress != ""},
+		UserAgent: sql.NullString{String: client.UserAgent, Valid: client.UserAgent != ""},
+	}
+	if actorID != nil {
+		params.ActorID = uuid.NullUUID{UUID: *actorID, Valid: true}
+	}
+
+	if err := s.repo.InsertSecretShareEvent(ctx, params); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"error":      err.Error(),
+			"share_id":   shareID,
+			"event_type": eventType,
+		}).Error("Failed to record secret share event")
+		return fmt.Errorf("failed to record secret share event: %w", err)
+	}
+	return nil
+}
+
+// clearShareValue removes the stored value of a share that can no longer be retrieved
+func (s *SecretService) clearShareValue(ctx context.Context, shareID uuid.UUID) {
+	if err := s.repo.ClearSecretShareValue(ctx, shareID); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"error":    err.Error(),
+			"share_id": shareID,
+		}).Warn("Failed to clear secret share value")
+	}
+}
+
+// generateShareToken returns an unguessable URL-safe share token
+func generateShareToken() (string, error) {
+	token := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// hashShareToken returns the stored representation of a share token
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// shareStatus reports whether a share can still be retrieved and why not
+func shareStatus(share secretdb.SecretShare) string {
+	switch {
+	case share.RevokedAt.Valid:
+		return ShareStatusRevoked
+	case share.ViewCount >= share.MaxViews:
+		return ShareStatusUsed
+	case !share.ExpiresAt.After(time.Now()):
+		return ShareStatusExpired
+	case share.FailedAttempts >= maxSharePassphraseAttempts || share.ValueEncrypted == nil:
+		return ShareStatusLocked
+	default:
+		return ShareStatusActive
+	}
+}
+
+// toSecretShareResponse converts a stored share into its API representation without value or token
+func toSecretShareResponse(share secretdb.SecretShare) SecretShareResponse {
+	response := SecretShareResponse{
+		ID:                  share.ID,
+		EnvironmentID:       share.EnvironmentID,
+		VersionID:           share.VersionID.String,
+		SecretName:          share.SecretName.String,
+		PassphraseProtected: share.PassphraseHash.Valid,
+		MaxViews:            int(share.MaxViews),
+		ViewCount:           int(share.ViewCount),
+		Status:              shareStatus(share),
+		ExpiresAt:           share.ExpiresAt,
+		CreatedBy:           share.CreatedBy,
+		CreatedAt:           share.CreatedAt,
+	}
+	if share.RevokedAt.Valid {
+		response.RevokedAt = &share.RevokedAt.Time
+	}
+	return response
+}
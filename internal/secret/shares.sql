@@ -0,0 +1,57 @@
+-- name: ClearSecretShareValue :exec
+UPDATE secret_shares
+SET value_encrypted = NULL
+WHERE id = $1;
+
+-- name: ConsumeSecretShare :one
+UPDATE secret_shares
+SET view_count = view_count + 1
+WHERE id = sqlc.arg(id) AND revoked_at IS NULL AND expires_at > now()
+  AND view_count < max_views AND failed_attempts < sqlc.arg(max_failed_attempts)::int
+  AND value_encrypted IS NOT NULL
+RETURNING *;
+
+-- name: CreateSecretShare :one
+INSERT INTO secret_shares (environment_id, version_id, secret_name, token_hash, value_encrypted, passphrase_hash, max_views, expires_at, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetSecretShare :one
+SELECT * FROM secret_shares
+WHERE id = $1 AND environment_id = $2;
+
+-- name: GetSecretShareByTokenHash :one
+SELECT * FROM secret_shares
+WHERE token_hash = $1;
+
+-- name: InsertSecretShareEvent :exec
+INSERT INTO secret_share_events (share_id, event_type, actor_id, ip_address, user_agent)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListSecretShareEvents :many
+SELECT * FROM secret_share_events
+WHERE share_id = $1
+ORDER BY created_at;
+
+-- name: ListSecretSharesForEnvironment :many
+SELECT * FROM secret_shares
+WHERE environment_id = $1
+ORDER BY created_at DESC;
+
+-- name: PurgeExpiredSecretShareValues :execrows
+UPDATE secret_shares
+SET value_encrypted = NULL
+WHERE value_encrypted IS NOT NULL AND expires_at <= now();
+
+-- name: RecordSecretShareFailedAttempt :one
+UPDATE secret_shares
+SET failed_attempts = failed_attempts + 1,
+    value_encrypted = CASE WHEN failed_attempts + 1 >= sqlc.arg(max_failed_attempts)::int THEN NULL ELSE value_encrypted END
+WHERE id = sqlc.arg(id)
+RETURNING failed_attempts;
+
+-- name: RevokeSecretShare :one
+UPDATE secret_shares
+SET revoked_at = now(), value_encrypted = NULL
+WHERE id = $1 AND environment_id = $2 AND revoked_at IS NULL
+RETURNING *;
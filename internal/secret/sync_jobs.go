@@ -0,0 +1,520 @@
+package secret
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	apiErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/Gkemhcs/kavach-backend/internal/provider"
+	secretdb "github.com/Gkemhcs/kavach-backend/internal/secret/gen"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Sync job queue tuning. A worker extends the lease of its job on every heartbeat; a job whose lease
+// expires, because its worker crashed or lost the database, is claimed again by another worker.
+// Provider writes are idempotent upserts, so repeating an interrupted sync is safe.
+const (
+	defaultSyncJobMaxAttempts = 5
+	syncJobLease              = 2 * time.Minute
+	syncJobHeartbeatInterval  = 30 * time.Second
+	syncJobPollInterval       = 2 * time.Second
+	syncJobBaseRetryDelay     = 30 * time.Second
+	syncJobMaxRetryDelay      = 15 * time.Minute
+	defaultSyncJobLimit       = 50
+	maxSyncJobLimit           = 200
+	// autoSyncMaxDelay bounds how long a steady stream of new versions can postpone a debounced auto-sync
+	autoSyncMaxDelay = 10 * time.Minute
+)
+
+// syncTarget is a provider target of an environment
+type syncTarget struct {
+	Name     string
+	Provider string
+}
+
+// EnqueueSync queues a sync of an environment to each provider target the request addresses and
+// returns the jobs to poll, one per target
+func (s *SecretService) EnqueueSync(ctx context.Context, environmentID, userID string, req SyncSecretsRequest) (*EnqueueSyncResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":         "EnqueueSync",
+		"environment_id": environmentID,
+		"targets":        req.Targets,
+		"provider":       req.Provider,
+		"version_id":     req.VersionID,
+	})
+
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := s.resolveSyncTargets(ctx, environmentID, req.Targets, req.Provider)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to resolve sync targets")
+		return nil, err
+	}
+	if len(targets) == 0 {
+		logEntry.Error("No provider targets match the sync request")
+		return nil, apiErrors.ErrNoSyncTargets
+	}
+
+	params := secretdb.EnqueueSyncJobParams{
+		EnvironmentID: environmentUUID,
+		MaxAttempts:   defaultSyncJobMaxAttempts,
+	}
+	if req.VersionID != "" {
+		version, err := s.repo.GetSecretVersion(ctx, req.VersionID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, apiErrors.ErrSecretVersionNotFound
+			}
+			logEntry.WithField("error", err.Error()).Error("Failed to get secret version")
+			return nil, fmt.Errorf("failed to get secret version: %w", err)
+		}
+		if version.EnvironmentID != environmentUUID {
+			return nil, apiErrors.ErrSecretVersionNotFound
+		}
+		params.VersionID = sql.NullString{String: req.VersionID, Valid: true}
+	}
+	if userUUID, err := uuid.Parse(userID); err == nil {
+		params.InitiatedBy = uuid.NullUUID{UUID: userUUID, Valid: true}
+	}
+
+	response := &EnqueueSyncResponse{Jobs: make([]SyncJobResponse, 0, len(targets))}
+	for _, target := range targets {
+		params.Target = target.Name
+		params.Provider = target.Provider
+
+		job, err := s.repo.EnqueueSyncJob(ctx, params)
+		if err != nil {
+			logEntry.WithFields(logrus.Fields{
+				"error":  err.Error(),
+				"target": target.Name,
+			}).Error("Failed to enqueue sync job")
+			return nil, fmt.Errorf("failed to enqueue sync job for target %s: %w", target.Name, err)
+		}
+
+		logEntry.WithFields(logrus.Fields{
+			"job_id": job.ID,
+			"target": target.Name,
+		}).Info("Successfully queued sync job")
+		response.Jobs = append(response.Jobs, toSyncJobResponse(job))
+	}
+
+	return response, nil
+}
+
+// resolveSyncTargets returns the provider targets of an environment that a request addresses: the
+// named targets, or every target when none are named, narrowed to one provider type when one is given
+func (s *SecretService) resolveSyncTargets(ctx context.Context, environmentID string, names []string, providerType string) ([]syncTarget, error) {
+	if providerType != "" && !s.providerService.IsSupportedProvider(provider.ProviderType(providerType)) {
+		return nil, apiErrors.ErrInvalidProviderType
+	}
+
+	credentials, err := s.providerService.ListProviderCredentials(ctx, environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]syncTarget, len(credentials))
+	byName := make(map[string]syncTarget, len(credentials))
+	for i, credential := range credentials {
+		candidates[i] = syncTarget{Name: credential.Name, Provider: string(credential.Provider)}
+		byName[credential.Name] = candidates[i]
+	}
+
+	if len(names) > 0 {
+		candidates = candidates[:0:0]
+		seen := make(map[string]bool, len(names))
+		for _, name := range names {
+			target, ok := byName[name]
+			if !ok {
+				return nil, apiErrors.ErrProviderCredentialNotFound
+			}
+			if !seen[name] {
+				seen[name] = true
+				candidates = append(candidates, target)
+			}
+		}
+	}
+
+	var targets []syncTarget
+	for _, target := range candidates {
+		if providerType == "" || target.Provider == providerType {
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+// ListSyncJobs lists the sync jobs of an environment newest first
+func (s *SecretService) ListSyncJobs(ctx context.Context, environmentID string, limit int) ([]SyncJobResponse, error) {
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultSyncJobLimit
+	}
+	if limit > maxSyncJobLimit {
+		limit = maxSyncJobLimit
+	}
+
+	jobs, err := s.repo.ListSyncJobsForEnvironment(ctx, secretdb.ListSyncJobsForEnvironmentParams{
+		EnvironmentID: environmentUUID,
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to list sync jobs")
+		return nil, fmt.Errorf("failed to list sync jobs: %w", err)
+	}
+
+	responses := make([]SyncJobResponse, len(jobs))
+	for i, job := range jobs {
+		responses[i] = toSyncJobResponse(job)
+	}
+	return responses, nil
+}
+
+// GetSyncJob returns a sync job of an environment
+func (s *SecretService) GetSyncJob(ctx context.Context, environmentID, jobID string) (*SyncJobResponse, error) {
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+	jobUUID, err := uuid.Parse(jobID)
+	if err != nil {
+		return nil, apiErrors.ErrSyncJobNotFound
+	}
+
+	job, err := s.repo.GetSyncJob(ctx, secretdb.GetSyncJobParams{
+		ID:            jobUUID,
+		EnvironmentID: environmentUUID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apiErrors.ErrSyncJobNotFound
+		}
+		s.logger.WithField("error", err.Error()).Error("Failed to get sync job")
+		return nil, fmt.Errorf("failed to get sync job: %w", err)
+	}
+
+	response := toSyncJobResponse(job)
+	return &response, nil
+}
+
+// scheduleAutoSync queues a sync of the latest version to every provider target of an environment with
+// auto-sync enabled. A pending auto-sync is postponed by the target's debounce instead of queueing
+// another, so bursts of versions coalesce into one sync. The version already exists, so failures
+// are logged rather than returned.
+func (s *SecretService) scheduleAutoSync(ctx context.Context, environmentID uuid.UUID) {
+	jobs, err := s.repo.EnqueueAutoSyncJobs(ctx, secretdb.EnqueueAutoSyncJobsParams{
+		MaxAttempts:     defaultSyncJobMaxAttempts,
+		EnvironmentID:   environmentID,
+		MaxDelaySeconds: int32(autoSyncMaxDelay.Seconds()),
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"error":          err.Error(),
+			"environment_id": environmentID,
+		}).Error("Failed to schedule auto-sync")
+		return
+	}
+
+	for _, job := range jobs {
+		s.logger.WithFields(logrus.Fields{
+			"environment_id": environmentID,
+			"target":         job.Target,
+			"job_id":         job.ID,
+			"run_at":         job.RunAt,
+		}).Info("Scheduled auto-sync")
+	}
+}
+
+// StartSyncWorkers starts workers that process queued sync jobs until ctx is cancelled.
+// A non-positive worker count disables sync job processing on this replica.
+func (s *SecretService) StartSyncWorkers(ctx context.Context, workers int) {
+	if workers <= 0 {
+		s.logger.Info("Sync job workers are disabled")
+		return
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	for i := 0; i < workers; i++ {
+		workerID := fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), i)
+		go s.runSyncWorker(ctx, workerID)
+	}
+
+	// Jobs whose worker died during the final attempt are never claimed again, so fail them explicitly
+	go func() {
+		ticker := time.NewTicker(syncJobLease)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.failExpiredSyncJobs(ctx)
+			}
+		}
+	}()
+
+	s.logger.WithField("workers", workers).Info("Started sync job workers")
+}
+
+// runSyncWorker claims and processes sync jobs one at a time until ctx is cancelled
+func (s *SecretService) runSyncWorker(ctx context.Context, workerID string) {
+	for ctx.Err() == nil {
+		job, err := s.repo.ClaimSyncJob(ctx, secretdb.ClaimSyncJobParams{
+			WorkerID:     workerID,
+			LeaseSeconds: int32(syncJobLease.Seconds()),
+		})
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) && ctx.Err() == nil {
+				s.logger.WithFields(logrus.Fields{
+					"error":     err.Error(),
+					"worker_id": workerID,
+				}).Error("Failed to claim sync job")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(syncJobPollInterval):
+			}
+			continue
+		}
+
+		s.processSyncJob(ctx, workerID, job)
+	}
+}
+
+// processSyncJob runs one attempt of a claimed sync job and records whether it completed,
+// failed for good or should be retried. If the lease is lost mid-sync the attempt is abandoned
+// and left to the worker that claims the job next.
+func (s *SecretService) processSyncJob(ctx context.Context, workerID string, job secretdb.SyncJob) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":    "processSyncJob",
+		"job_id":    job.ID,
+		"worker_id": workerID,
+		"target":    job.Target,
+		"attempt":   job.Attempts,
+	})
+
+	logEntry.Info("Processing sync job")
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go s.heartbeatSyncJob(jobCtx, cancel, workerID, job.ID)
+
+	// A previous attempt whose worker stopped responding left its run open
+	if job.RunID.Valid {
+		s.abandonSyncRun(ctx, job.RunID.UUID, "the worker stopped responding and the sync was retried")
+	}
+
+	initiatedBy := ""
+	if job.InitiatedBy.Valid {
+		initiatedBy = job.InitiatedBy.UUID.String()
+	}
+	req := SyncTargetRequest{Target: job.Target, VersionID: job.VersionID.String}
+
+	var runID uuid.NullUUID
+	var response *SyncSecretsResponse
+	run, err := s.startSyncRun(jobCtx, job.EnvironmentID, initiatedBy, job.Target, job.Trigger)
+	if err == nil {
+		runID = uuid.NullUUID{UUID: run.ID, Valid: true}
+		if err = s.repo.SetSyncJobRun(jobCtx, secretdb.SetSyncJobRunParams{
+			RunID:    runID,
+			ID:       job.ID,
+			WorkerID: workerID,
+		}); err != nil {
+			logEntry.WithField("error", err.Error()).Warn("Failed to attach sync run to job")
+		}
+		if job.PlanID.Valid {
+			response, err = s.executePlannedSyncRun(jobCtx, job.EnvironmentID, run.ID, job.PlanID.UUID)
+		} else {
+			response, err = s.executeSyncRun(jobCtx, job.EnvironmentID, run.ID, req)
+		}
+	}
+
+	if ctx.Err() != nil {
+		// Shutting down; the lease expires and another replica retries the job
+		return
+	}
+	if jobCtx.Err() != nil {
+		logEntry.Warn("Lost the sync job lease, abandoning the attempt")
+		if runID.Valid {
+			s.abandonSyncRun(ctx, runID.UUID, "the sync job lease was lost and the sync was retried")
+		}
+		return
+	}
+
+	if err == nil && response.Status == SyncStatusFailed {
+		err = fmt.Errorf("%s", response.Message)
+	}
+
+	var rows int64
+	switch {
+	case err == nil:
+		rows, err = s.repo.CompleteSyncJob(ctx, secretdb.CompleteSyncJobParams{
+			RunID:    runID,
+			ID:       job.ID,
+			WorkerID: workerID,
+		})
+		logEntry = logEntry.WithField("outcome", SyncJobStatusCompleted)
+	case isPermanentSyncError(err) || job.Attempts >= job.MaxAttempts:
+		logEntry = logEntry.WithFields(logrus.Fields{"outcome": SyncJobStatusFailed, "sync_error": err.Error()})
+		rows, err = s.repo.FailSyncJob(ctx, secretdb.FailSyncJobParams{
+			RunID:     runID,
+			LastError: sql.NullString{String: err.Error(), Valid: true},
+			ID:        job.ID,
+			WorkerID:  workerID,
+		})
+	default:
+		delay := syncJobRetryDelay(int(job.Attempts))
+		logEntry = logEntry.WithFields(logrus.Fields{"outcome": "retry", "sync_error": err.Error(), "delay": delay.String()})
+		rows, err = s.repo.RetrySyncJob(ctx, secretdb.RetrySyncJobParams{
+			RunID:        runID,
+			LastError:    sql.NullString{String: err.Error(), Valid: true},
+			DelaySeconds: int32(delay.Seconds()),
+			ID:           job.ID,
+			WorkerID:     workerID,
+		})
+	}
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to record sync job outcome")
+		return
+	}
+	if rows == 0 {
+		logEntry.Warn("Sync job was claimed by another worker before its outcome was recorded")
+		return
+	}
+
+	logEntry.Info("Finished sync job attempt")
+}
+
+// heartbeatSyncJob extends the lease of a job while it is processed and cancels the attempt
+// once another worker has taken the job over
+func (s *SecretService) heartbeatSyncJob(ctx context.Context, cancel context.CancelFunc, workerID string, jobID uuid.UUID) {
+	ticker := time.NewTicker(syncJobHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rows, err := s.repo.HeartbeatSyncJob(ctx, secretdb.HeartbeatSyncJobParams{
+				LeaseSeconds: int32(syncJobLease.Seconds()),
+				ID:           jobID,
+				WorkerID:     workerID,
+			})
+			if err != nil {
+				// The lease is still valid for a while; try again on the next tick
+				s.logger.WithFields(logrus.Fields{
+					"error":  err.Error(),
+					"job_id": jobID,
+				}).Warn("Failed to extend sync job lease")
+				continue
+			}
+			if rows == 0 {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// failExpiredSyncJobs fails jobs whose worker stopped responding during their final attempt
+func (s *SecretService) failExpiredSyncJobs(ctx context.Context) {
+	jobs, err := s.repo.FailExpiredSyncJobs(ctx)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to fail expired sync jobs")
+		return
+	}
+
+	for _, job := range jobs {
+		if job.RunID.Valid {
+			s.abandonSyncRun(ctx, job.RunID.UUID, "the worker stopped responding during the final attempt")
+		}
+		s.logger.WithField("job_id", job.ID).Warn("Failed sync job whose worker stopped responding")
+	}
+}
+
+// abandonSyncRun marks a run that was interrupted before it could record its outcome as failed
+func (s *SecretService) abandonSyncRun(ctx context.Context, runID uuid.UUID, reason string) {
+	err := s.repo.AbandonSyncRun(ctx, secretdb.AbandonSyncRunParams{
+		ID:    runID,
+		Error: sql.NullString{String: reason, Valid: true},
+	})
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"error":  err.Error(),
+			"run_id": runID,
+		}).Error("Failed to mark interrupted sync run as failed")
+	}
+}
+
+// isPermanentSyncError reports whether retrying a sync cannot succeed without user action,
+// such as missing credentials, an unknown GitHub environment or an empty version
+func isPermanentSyncError(err error) bool {
+	var apiErr *apiErrors.APIError
+	return errors.As(err, &apiErr) && apiErr.Status < 500
+}
+
+// syncJobRetryDelay returns the exponential backoff before the next attempt of a job
+func syncJobRetryDelay(attempts int) time.Duration {
+	delay := syncJobBaseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= syncJobMaxRetryDelay {
+			return syncJobMaxRetryDelay
+		}
+	}
+	return delay
+}
+
+// toSyncJobResponse converts a stored sync job into its API representation
+func toSyncJobResponse(job secretdb.SyncJob) SyncJobResponse {
+	response := SyncJobResponse{
+		ID:            job.ID,
+		EnvironmentID: job.EnvironmentID,
+		Target:        job.Target,
+		Provider:      job.Provider,
+		Trigger:       job.Trigger,
+		VersionID:     job.VersionID.String,
+		Status:        job.Status,
+		Attempts:      int(job.Attempts),
+		MaxAttempts:   int(job.MaxAttempts),
+		LastError:     job.LastError.String,
+		CreatedAt:     job.CreatedAt,
+		UpdatedAt:     job.UpdatedAt,
+	}
+	if job.InitiatedBy.Valid {
+		response.InitiatedBy = &job.InitiatedBy.UUID
+	}
+	if job.RunID.Valid {
+		response.RunID = &job.RunID.UUID
+	}
+	if job.PlanID.Valid {
+		response.PlanID = &job.PlanID.UUID
+	}
+	if job.Status == SyncJobStatusQueued {
+		response.NextAttemptAt = &job.RunAt
+	}
+	if job.FinishedAt.Valid {
+		response.FinishedAt = &job.FinishedAt.Time
+	}
+	return response
+}
@@ -0,0 +1,74 @@
+-- name: ClaimSyncJob :one
+UPDATE sync_jobs
+SET status = 'running', attempts = attempts + 1, locked_by = sqlc.arg(worker_id)::text,
+    lease_expires_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::int), updated_at = now()
+WHERE id = (
+    SELECT j.id FROM sync_jobs j
+    WHERE (j.status = 'queued' AND j.run_at <= now())
+       OR (j.status = 'running' AND j.lease_expires_at < now() AND j.attempts < j.max_attempts)
+    ORDER BY j.run_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteSyncJob :execrows
+UPDATE sync_jobs
+SET status = 'completed', run_id = sqlc.arg(run_id), last_error = NULL,
+    locked_by = NULL, lease_expires_at = NULL, updated_at = now(), finished_at = now()
+WHERE id = sqlc.arg(id) AND locked_by = sqlc.arg(worker_id)::text AND status = 'running';
+
+-- name: EnqueueAutoSyncJobs :many
+INSERT INTO sync_jobs (environment_id, target, provider, trigger, max_attempts, run_at)
+SELECT pc.environment_id, pc.name, pc.provider, 'auto', sqlc.arg(max_attempts)::int, now() + make_interval(secs => pc.auto_sync_debounce_seconds)
+FROM provider_credentials pc
+WHERE pc.environment_id = sqlc.arg(environment_id) AND pc.auto_sync
+ON CONFLICT (environment_id, target) WHERE status = 'queued' AND trigger = 'auto' AND attempts = 0
+DO UPDATE SET run_at = LEAST(EXCLUDED.run_at, sync_jobs.created_at + make_interval(secs => sqlc.arg(max_delay_seconds)::int)),
+    updated_at = now()
+RETURNING *;
+
+-- name: EnqueueSyncJob :one
+INSERT INTO sync_jobs (environment_id, target, provider, version_id, initiated_by, max_attempts, plan_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: FailExpiredSyncJobs :many
+UPDATE sync_jobs
+SET status = 'failed', last_error = 'the worker stopped responding during the final attempt',
+    locked_by = NULL, lease_expires_at = NULL, updated_at = now(), finished_at = now()
+WHERE status = 'running' AND lease_expires_at < now() AND attempts >= max_attempts
+RETURNING *;
+
+-- name: FailSyncJob :execrows
+UPDATE sync_jobs
+SET status = 'failed', run_id = sqlc.arg(run_id), last_error = sqlc.arg(last_error),
+    locked_by = NULL, lease_expires_at = NULL, updated_at = now(), finished_at = now()
+WHERE id = sqlc.arg(id) AND locked_by = sqlc.arg(worker_id)::text AND status = 'running';
+
+-- name: GetSyncJob :one
+SELECT * FROM sync_jobs
+WHERE id = $1 AND environment_id = $2;
+
+-- name: HeartbeatSyncJob :execrows
+UPDATE sync_jobs
+SET lease_expires_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::int), updated_at = now()
+WHERE id = sqlc.arg(id) AND locked_by = sqlc.arg(worker_id)::text AND status = 'running';
+
+-- name: ListSyncJobsForEnvironment :many
+SELECT * FROM sync_jobs
+WHERE environment_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: RetrySyncJob :execrows
+UPDATE sync_jobs
+SET status = 'queued', run_id = sqlc.arg(run_id), last_error = sqlc.arg(last_error),
+    run_at = now() + make_interval(secs => sqlc.arg(delay_seconds)::int),
+    locked_by = NULL, lease_expires_at = NULL, updated_at = now()
+WHERE id = sqlc.arg(id) AND locked_by = sqlc.arg(worker_id)::text AND status = 'running';
+
+-- name: SetSyncJobRun :exec
+UPDATE sync_jobs
+SET run_id = sqlc.arg(run_id), updated_at = now()
+WHERE id = sqlc.arg(id) AND locked_by = sqlc.arg(worker_id)::text;
@@ -0,0 +1,441 @@
+package secret
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	apiErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	"github.com/Gkemhcs/kavach-backend/internal/provider"
+	secretdb "github.com/Gkemhcs/kavach-backend/internal/secret/gen"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// syncPlanTTL is how long a sync plan can be applied after it was created
+const syncPlanTTL = time.Hour
+
+// plannedSync limits a sync to the changes of a reviewed plan
+type plannedSync struct {
+	credentialUpdatedAt time.Time
+	writes              map[string]bool // Keys to create or update
+	removals            map[string]bool // Old names of renamed or moved keys to remove
+	prunes              map[string]bool // Managed remote names to delete in mirror mode
+}
+
+// CreateSyncPlan computes what syncing a version to a provider target would create, update, leave unchanged
+// or delete, without changing anything, and stores the plan so it can be reviewed and applied
+func (s *SecretService) CreateSyncPlan(ctx context.Context, environmentID, userID string, req SyncPlanRequest) (*SyncPlanResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":         "CreateSyncPlan",
+		"environment_id": environmentID,
+		"target":         req.Target,
+		"version_id":     req.VersionID,
+	})
+
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	syncer, syncOptions, err := s.providerService.GetProviderSyncerWithOptions(ctx, environmentID, req.Target)
+	if err != nil {
+		return nil, err
+	}
+	reader, ok := syncer.(provider.ProviderReader)
+	if !ok {
+		return nil, apiErrors.ErrSyncPlanNotSupported
+	}
+
+	if req.VersionID != "" {
+		version, err := s.repo.GetSecretVersion(ctx, req.VersionID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, apiErrors.ErrSecretVersionNotFound
+			}
+			logEntry.WithField("error", err.Error()).Error("Failed to get secret version")
+			return nil, fmt.Errorf("failed to get secret version: %w", err)
+		}
+		if version.EnvironmentID != environmentUUID {
+			return nil, apiErrors.ErrSecretVersionNotFound
+		}
+	}
+	versionID, err := s.resolveSyncVersion(ctx, environmentUUID, req.VersionID)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := s.getCurrentVersionDetails(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(details.Secrets) == 0 {
+		return nil, apiErrors.ErrNoSecretsToSync
+	}
+
+	secrets, err := applyKeyMapping(syncOptions.KeyMapping, details.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	if len(secrets) == 0 {
+		return nil, apiErrors.ErrNoSecretsToSync
+	}
+
+	changes, collisions, err := s.planProviderChanges(ctx, environmentUUID, req.Target, syncer, reader, syncOptions, secrets)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to plan provider changes")
+		return nil, err
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sync plan changes: %w", err)
+	}
+	collisionsJSON, err := json.Marshal(collisions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sync plan collisions: %w", err)
+	}
+
+	params := secretdb.CreateSyncPlanParams{
+		EnvironmentID:       environmentUUID,
+		Target:              req.Target,
+		Provider:            string(syncOptions.Provider),
+		VersionID:           versionID,
+		MirrorMode:          syncOptions.MirrorMode,
+		Changes:             changesJSON,
+		Collisions:          collisionsJSON,
+		CredentialUpdatedAt: syncOptions.UpdatedAt,
+		ExpiresAt:           time.Now().Add(syncPlanTTL),
+	}
+	if userUUID, err := uuid.Parse(userID); err == nil {
+		params.CreatedBy = uuid.NullUUID{UUID: userUUID, Valid: true}
+	}
+
+	plan, err := s.repo.CreateSyncPlan(ctx, params)
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to save sync plan")
+		return nil, fmt.Errorf("failed to save sync plan: %w", err)
+	}
+
+	logEntry.WithFields(logrus.Fields{
+		"plan_id":         plan.ID,
+		"change_count":    len(changes),
+		"collision_count": len(collisions),
+	}).Info("Successfully created sync plan")
+
+	return toSyncPlanResponse(plan)
+}
+
+// GetSyncPlan returns a sync plan of an environment
+func (s *SecretService) GetSyncPlan(ctx context.Context, environmentID, planID string) (*SyncPlanResponse, error) {
+	plan, err := s.getSyncPlan(ctx, environmentID, planID)
+	if err != nil {
+		return nil, err
+	}
+	return toSyncPlanResponse(plan)
+}
+
+// ApplySyncPlan queues a sync job that applies exactly the changes of a plan and returns the job to poll.
+// A plan can be applied once, before it expires, and only when no keys collide.
+func (s *SecretService) ApplySyncPlan(ctx context.Context, environmentID, userID, planID string) (*SyncJobResponse, error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method":         "ApplySyncPlan",
+		"environment_id": environmentID,
+		"plan_id":        planID,
+	})
+
+	plan, err := s.getSyncPlan(ctx, environmentID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	var collisions []SyncPlanCollision
+	if err := json.Unmarshal(plan.Collisions, &collisions); err != nil {
+		return nil, fmt.Errorf("failed to parse sync plan collisions: %w", err)
+	}
+	switch {
+	case plan.AppliedAt.Valid:
+		return nil, apiErrors.ErrSyncPlanAlreadyApplied
+	case time.Now().After(plan.ExpiresAt):
+		return nil, apiErrors.ErrSyncPlanExpired
+	case len(collisions) > 0:
+		return nil, apiErrors.ErrSyncPlanHasCollisions
+	}
+
+	appliedBy := uuid.NullUUID{}
+	if userUUID, err := uuid.Parse(userID); err == nil {
+		appliedBy = uuid.NullUUID{UUID: userUUID, Valid: true}
+	}
+
+	// Claim the plan first so concurrent applies queue a single job
+	plan, err = s.repo.MarkSyncPlanApplied(ctx, secretdb.MarkSyncPlanAppliedParams{
+		ID:            plan.ID,
+		EnvironmentID: plan.EnvironmentID,
+		AppliedBy:     appliedBy,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apiErrors.ErrSyncPlanAlreadyApplied
+		}
+		logEntry.WithField("error", err.Error()).Error("Failed to mark sync plan as applied")
+		return nil, fmt.Errorf("failed to mark sync plan as applied: %w", err)
+	}
+
+	job, err := s.repo.EnqueueSyncJob(ctx, secretdb.EnqueueSyncJobParams{
+		EnvironmentID: plan.EnvironmentID,
+		Target:        plan.Target,
+		Provider:      plan.Provider,
+		VersionID:     sql.NullString{String: plan.VersionID, Valid: true},
+		InitiatedBy:   appliedBy,
+		MaxAttempts:   defaultSyncJobMaxAttempts,
+		PlanID:        uuid.NullUUID{UUID: plan.ID, Valid: true},
+	})
+	if err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to enqueue sync job")
+		return nil, fmt.Errorf("failed to enqueue sync job: %w", err)
+	}
+
+	if err := s.repo.SetSyncPlanJob(ctx, secretdb.SetSyncPlanJobParams{
+		ID:    plan.ID,
+		JobID: uuid.NullUUID{UUID: job.ID, Valid: true},
+	}); err != nil {
+		logEntry.WithField("error", err.Error()).Warn("Failed to attach sync job to plan")
+	}
+
+	logEntry.WithField("job_id", job.ID).Info("Successfully queued sync plan")
+
+	response := toSyncJobResponse(job)
+	return &response, nil
+}
+
+// executePlannedSyncRun performs a recorded sync run limited to the changes of a plan and records its outcome
+func (s *SecretService) executePlannedSyncRun(ctx context.Context, environmentID, runID, planID uuid.UUID) (*SyncSecretsResponse, error) {
+	plan, err := s.getSyncPlan(ctx, environmentID.String(), planID.String())
+	if err != nil {
+		s.finishSyncRun(ctx, runID, "", nil, err)
+		return nil, err
+	}
+
+	planned, err := toPlannedSync(plan)
+	if err != nil {
+		s.finishSyncRun(ctx, runID, plan.VersionID, nil, err)
+		return nil, err
+	}
+
+	req := SyncTargetRequest{Target: plan.Target, VersionID: plan.VersionID}
+	response, err := s.syncVersion(ctx, environmentID.String(), req, planned)
+	s.finishSyncRun(ctx, runID, plan.VersionID, response, err)
+	if err != nil {
+		return nil, err
+	}
+
+	response.RunID = runID
+	return response, nil
+}
+
+// planProviderChanges compares the key-mapped secrets of a version with what a provider target holds under
+// the remote names they map to, and lists the deletions a sync would make
+func (s *SecretService) planProviderChanges(ctx context.Context, environmentID uuid.UUID, target string, syncer provider.ProviderSyncer, reader provider.ProviderReader, syncOptions provider.SyncOptions, secrets []SecretWithValue) ([]SyncPlanChange, []SyncPlanCollision, error) {
+	// Keys that sanitize to the same remote name overwrite each other
+	namesByRemoteName := make(map[string][]string)
+	valueByName := make(map[string]string, len(secrets))
+	var remoteNames []string
+	for _, secret := range secrets {
+		remoteName := reader.RemoteName(secret.Name)
+		if _, ok := namesByRemoteName[remoteName]; !ok {
+			remoteNames = append(remoteNames, remoteName)
+		}
+		namesByRemoteName[remoteName] = append(namesByRemoteName[remoteName], secret.Name)
+		valueByName[secret.Name] = secret.Value
+	}
+	sort.Strings(remoteNames)
+
+	read, err := reader.Read(ctx, remoteNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	remoteByName := make(map[string]provider.RemoteSecret, len(read))
+	for _, remote := range read {
+		remoteByName[remote.Name] = remote
+	}
+
+	changes := []SyncPlanChange{}
+	collisions := []SyncPlanCollision{}
+	for _, remoteName := range remoteNames {
+		names := namesByRemoteName[remoteName]
+		if len(names) > 1 {
+			collisions = append(collisions, SyncPlanCollision{RemoteName: remoteName, Names: names})
+		}
+
+		remote, found := remoteByName[remoteName]
+		for _, name := range names {
+			change := SyncPlanChange{Name: name, RemoteName: remoteName}
+			switch {
+			case !found:
+				change.Action = SyncPlanActionCreate
+			case !remote.HasValue:
+				change.Action = SyncPlanActionUpdate
+				change.Reason = SyncPlanReasonValueUnreadable
+			case remote.Value == valueByName[name]:
+				change.Action = SyncPlanActionUnchanged
+			default:
+				change.Action = SyncPlanActionUpdate
+				change.Reason = SyncPlanReasonValueChanged
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	// Old names of renamed or moved keys, as removeRenamedKeys would remove them
+	if _, ok := syncer.(provider.ProviderDeleter); ok {
+		pending, err := s.repo.ListPendingSecretKeyRemovals(ctx, secretdb.ListPendingSecretKeyRemovalsParams{
+			EnvironmentID: environmentID,
+			Target:        target,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list pending key removals: %w", err)
+		}
+
+		planned := make(map[string]bool)
+		for _, change := range pending {
+			oldName, mapped := syncOptions.KeyMapping.MapName(change.OldName)
+			if !mapped {
+				continue
+			}
+			if _, inUse := valueByName[oldName]; inUse || planned[oldName] {
+				continue
+			}
+			planned[oldName] = true
+			changes = append(changes, SyncPlanChange{
+				Action:     SyncPlanActionDelete,
+				Name:       oldName,
+				RemoteName: reader.RemoteName(oldName),
+				Reason:     SyncPlanReasonRenamed,
+			})
+		}
+	}
+
+	// Managed secrets no longer in the version, as mirror mode would prune them
+	if pruner, ok := syncer.(provider.ProviderPruner); ok && syncOptions.MirrorMode {
+		keep := make([]string, len(secrets))
+		for i, secret := range secrets {
+			keep[i] = secret.Name
+		}
+		candidates, err := pruner.PruneCandidates(ctx, keep)
+		if err != nil {
+			return nil, nil, err
+		}
+		sort.Strings(candidates)
+		for _, remoteName := range candidates {
+			changes = append(changes, SyncPlanChange{
+				Action:     SyncPlanActionDelete,
+				RemoteName: remoteName,
+				Reason:     SyncPlanReasonMirror,
+			})
+		}
+	}
+
+	return changes, collisions, nil
+}
+
+// getSyncPlan loads a sync plan of an environment
+func (s *SecretService) getSyncPlan(ctx context.Context, environmentID, planID string) (secretdb.SyncPlan, error) {
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return secretdb.SyncPlan{}, err
+	}
+	planUUID, err := uuid.Parse(planID)
+	if err != nil {
+		return secretdb.SyncPlan{}, apiErrors.ErrSyncPlanNotFound
+	}
+
+	plan, err := s.repo.GetSyncPlan(ctx, secretdb.GetSyncPlanParams{
+		ID:            planUUID,
+		EnvironmentID: environmentUUID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return plan, apiErrors.ErrSyncPlanNotFound
+		}
+		s.logger.WithField("error", err.Error()).Error("Failed to get sync plan")
+		return plan, fmt.Errorf("failed to get sync plan: %w", err)
+	}
+	return plan, nil
+}
+
+// toPlannedSync collects the writes and deletions of a stored plan
+func toPlannedSync(plan secretdb.SyncPlan) (*plannedSync, error) {
+	var changes []SyncPlanChange
+	if err := json.Unmarshal(plan.Changes, &changes); err != nil {
+		return nil, fmt.Errorf("failed to parse sync plan changes: %w", err)
+	}
+
+	planned := &plannedSync{
+		credentialUpdatedAt: plan.CredentialUpdatedAt,
+		writes:              make(map[string]bool),
+		removals:            make(map[string]bool),
+		prunes:              make(map[string]bool),
+	}
+	for _, change := range changes {
+		switch {
+		case change.Action == SyncPlanActionCreate || change.Action == SyncPlanActionUpdate:
+			planned.writes[change.Name] = true
+		case change.Action == SyncPlanActionDelete && change.Reason == SyncPlanReasonRenamed:
+			planned.removals[change.Name] = true
+		case change.Action == SyncPlanActionDelete && change.Reason == SyncPlanReasonMirror:
+			planned.prunes[change.RemoteName] = true
+		}
+	}
+	return planned, nil
+}
+
+// toSyncPlanResponse converts a stored sync plan into its API representation
+func toSyncPlanResponse(plan secretdb.SyncPlan) (*SyncPlanResponse, error) {
+	response := &SyncPlanResponse{
+		ID:            plan.ID,
+		EnvironmentID: plan.EnvironmentID,
+		Target:        plan.Target,
+		Provider:      plan.Provider,
+		VersionID:     plan.VersionID,
+		MirrorMode:    plan.MirrorMode,
+		CreatedAt:     plan.CreatedAt,
+		ExpiresAt:     plan.ExpiresAt,
+	}
+	if err := json.Unmarshal(plan.Changes, &response.Changes); err != nil {
+		return nil, fmt.Errorf("failed to parse sync plan changes: %w", err)
+	}
+	if err := json.Unmarshal(plan.Collisions, &response.Collisions); err != nil {
+		return nil, fmt.Errorf("failed to parse sync plan collisions: %w", err)
+	}
+
+	for _, change := range response.Changes {
+		switch change.Action {
+		case SyncPlanActionCreate:
+			response.Summary.Create++
+		case SyncPlanActionUpdate:
+			response.Summary.Update++
+		case SyncPlanActionUnchanged:
+			response.Summary.Unchanged++
+		case SyncPlanActionDelete:
+			response.Summary.Delete++
+		}
+	}
+
+	if plan.CreatedBy.Valid {
+		response.CreatedBy = &plan.CreatedBy.UUID
+	}
+	if plan.AppliedBy.Valid {
+		response.AppliedBy = &plan.AppliedBy.UUID
+	}
+	if plan.AppliedAt.Valid {
+		response.AppliedAt = &plan.AppliedAt.Time
+	}
+	if plan.JobID.Valid {
+		response.JobID = &plan.JobID.UUID
+	}
+	return response, nil
+}
@@ -0,0 +1,19 @@
+-- name: CreateSyncPlan :one
+INSERT INTO sync_plans (environment_id, target, provider, version_id, mirror_mode, changes, collisions, credential_updated_at, created_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetSyncPlan :one
+SELECT * FROM sync_plans
+WHERE id = $1 AND environment_id = $2;
+
+-- name: MarkSyncPlanApplied :one
+UPDATE sync_plans
+SET applied_by = $3, applied_at = now()
+WHERE id = $1 AND environment_id = $2 AND applied_at IS NULL AND expires_at > now()
+RETURNING *;
+
+-- name: SetSyncPlanJob :exec
+UPDATE sync_plans
+SET job_id = $2
+WHERE id = $1;
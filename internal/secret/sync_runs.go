@@ -0,0 +1,232 @@
+package secret
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	apiErrors "github.com/Gkemhcs/kavach-backend/internal/errors"
+	secretdb "github.com/Gkemhcs/kavach-backend/internal/secret/gen"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Sync history page sizes
+const (
+	defaultSyncRunLimit = 50
+	maxSyncRunLimit     = 200
+)
+
+// ListSyncRuns lists the sync runs of an environment newest first, optionally only those of one provider target
+func (s *SecretService) ListSyncRuns(ctx context.Context, environmentID, target string, limit int) ([]SyncRunResponse, error) {
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultSyncRunLimit
+	}
+	if limit > maxSyncRunLimit {
+		limit = maxSyncRunLimit
+	}
+
+	var runs []secretdb.SyncRun
+	if target == "" {
+		runs, err = s.repo.ListSyncRunsForEnvironment(ctx, secretdb.ListSyncRunsForEnvironmentParams{
+			EnvironmentID: environmentUUID,
+			Limit:         int32(limit),
+		})
+	} else {
+		runs, err = s.repo.ListSyncRunsForTarget(ctx, secretdb.ListSyncRunsForTargetParams{
+			EnvironmentID: environmentUUID,
+			Target:        target,
+			Limit:         int32(limit),
+		})
+	}
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to list sync runs")
+		return nil, fmt.Errorf("failed to list sync runs: %w", err)
+	}
+
+	responses := make([]SyncRunResponse, len(runs))
+	for i, run := range runs {
+		responses[i] = toSyncRunResponse(run)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"environment_id": environmentID,
+		"target":         target,
+		"run_count":      len(responses),
+	}).Info("Successfully listed sync runs")
+
+	return responses, nil
+}
+
+// GetSyncRun returns a sync run of an environment with the outcome of every secret
+func (s *SecretService) GetSyncRun(ctx context.Context, environmentID, runID string) (*SyncRunDetailResponse, error) {
+	environmentUUID, err := uuid.Parse(environmentID)
+	if err != nil {
+		return nil, err
+	}
+	runUUID, err := uuid.Parse(runID)
+	if err != nil {
+		return nil, apiErrors.ErrSyncRunNotFound
+	}
+
+	run, err := s.repo.GetSyncRun(ctx, secretdb.GetSyncRunParams{
+		ID:            runUUID,
+		EnvironmentID: environmentUUID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apiErrors.ErrSyncRunNotFound
+		}
+		s.logger.WithField("error", err.Error()).Error("Failed to get sync run")
+		return nil, fmt.Errorf("failed to get sync run: %w", err)
+	}
+
+	results, err := s.repo.ListSyncRunResults(ctx, run.ID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to list sync run results")
+		return nil, fmt.Errorf("failed to list sync run results: %w", err)
+	}
+
+	response := &SyncRunDetailResponse{SyncRunResponse: toSyncRunResponse(run)}
+	for _, result := range results {
+		syncResult := SyncResult{Name: result.Name, Success: result.Success, Error: result.Error.String}
+		switch result.Operation {
+		case SyncOperationDelete:
+			response.Removals = append(response.Removals, syncResult)
+		case SyncOperationPrune:
+			response.Pruned = append(response.Pruned, syncResult)
+		default:
+			response.Results = append(response.Results, syncResult)
+		}
+	}
+	return response, nil
+}
+
+// startSyncRun records the start of a sync run to a provider target
+func (s *SecretService) startSyncRun(ctx context.Context, environmentID uuid.UUID, userID, target, trigger string) (secretdb.SyncRun, error) {
+	params := secretdb.CreateSyncRunParams{
+		EnvironmentID: environmentID,
+		Target:        target,
+		Trigger:       trigger,
+	}
+	if userUUID, err := uuid.Parse(userID); err == nil {
+		params.InitiatedBy = uuid.NullUUID{UUID: userUUID, Valid: true}
+	}
+
+	run, err := s.repo.CreateSyncRun(ctx, params)
+	if err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"error":          err.Error(),
+			"environment_id": environmentID,
+			"target":         target,
+		}).Error("Failed to record sync run")
+		return run, fmt.Errorf("failed to record sync run: %w", err)
+	}
+	return run, nil
+}
+
+// resolveSyncVersion returns the requested version or the latest version of the environment
+func (s *SecretService) resolveSyncVersion(ctx context.Context, environmentID uuid.UUID, versionID string) (string, error) {
+	if versionID != "" {
+		return versionID, nil
+	}
+
+	versions, err := s.repo.ListSecretVersions(ctx, environmentID)
+	if err != nil {
+		s.logger.WithField("error", err.Error()).Error("Failed to get latest version")
+		return "", fmt.Errorf("failed to get latest version: %w", err)
+	}
+	if len(versions) == 0 {
+		s.logger.WithField("environment_id", environmentID).Error("No versions found for environment")
+		return "", apiErrors.ErrNoSecretsToSync
+	}
+	return versions[0].ID, nil
+}
+
+// finishSyncRun records the outcome of a sync run. The sync already happened, so failures to
+// record it are logged rather than returned.
+func (s *SecretService) finishSyncRun(ctx context.Context, runID uuid.UUID, versionID string, response *SyncSecretsResponse, syncErr error) {
+	logEntry := s.logger.WithFields(logrus.Fields{
+		"method": "finishSyncRun",
+		"run_id": runID,
+	})
+
+	params := secretdb.FinishSyncRunParams{
+		ID:        runID,
+		VersionID: sql.NullString{String: versionID, Valid: versionID != ""},
+		Status:    SyncStatusFailed,
+	}
+
+	if syncErr != nil {
+		params.Error = sql.NullString{String: syncErr.Error(), Valid: true}
+	} else {
+		params.Status = response.Status
+		params.Message = sql.NullString{String: response.Message, Valid: true}
+		params.SyncedCount = int32(response.SyncedCount)
+		params.FailedCount = int32(response.FailedCount)
+		params.TotalCount = int32(response.TotalCount)
+
+		s.recordSyncRunResults(ctx, runID, SyncOperationUpsert, response.Results)
+		s.recordSyncRunResults(ctx, runID, SyncOperationDelete, response.Removals)
+		s.recordSyncRunResults(ctx, runID, SyncOperationPrune, response.Pruned)
+	}
+
+	if _, err := s.repo.FinishSyncRun(ctx, params); err != nil {
+		logEntry.WithField("error", err.Error()).Error("Failed to record sync run outcome")
+	}
+}
+
+// recordSyncRunResults stores the outcome of each secret of a sync run
+func (s *SecretService) recordSyncRunResults(ctx context.Context, runID uuid.UUID, operation string, results []SyncResult) {
+	for _, result := range results {
+		err := s.repo.InsertSyncRunResult(ctx, secretdb.InsertSyncRunResultParams{
+			RunID:     runID,
+			Operation: operation,
+			Name:      result.Name,
+			Success:   result.Success,
+			Error:     sql.NullString{String: result.Error, Valid: result.Error != ""},
+		})
+		if err != nil {
+			s.logger.WithFields(logrus.Fields{
+				"error":  err.Error(),
+				"run_id": runID,
+				"name":   result.Name,
+			}).Error("Failed to record sync run result")
+		}
+	}
+}
+
+// toSyncRunResponse converts a stored sync run into its API representation
+func toSyncRunResponse(run secretdb.SyncRun) SyncRunResponse {
+	response := SyncRunResponse{
+		ID:            run.ID,
+		EnvironmentID: run.EnvironmentID,
+		Target:        run.Target,
+		Provider:      run.Provider,
+		Trigger:       run.Trigger,
+		VersionID:     run.VersionID.String,
+		Status:        run.Status,
+		Message:       run.Message.String,
+		Error:         run.Error.String,
+		SyncedCount:   int(run.SyncedCount),
+		FailedCount:   int(run.FailedCount),
+		TotalCount:    int(run.TotalCount),
+		StartedAt:     run.StartedAt,
+	}
+	if run.ProviderCredentialID.Valid {
+		response.ProviderCredentialID = &run.ProviderCredentialID.UUID
+	}
+	if run.InitiatedBy.Valid {
+		response.InitiatedBy = &run.InitiatedBy.UUID
+	}
+	if run.FinishedAt.Valid {
+		response.FinishedAt = &run.FinishedAt.Time
+	}
+	return response
+}
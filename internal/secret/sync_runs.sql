@@ -0,0 +1,49 @@
+-- name: AbandonSyncRun :exec
+UPDATE sync_runs
+SET status = 'failed', error = $2, finished_at = now()
+WHERE id = $1 AND status = 'running';
+
+-- name: CreateSyncRun :one
+INSERT INTO sync_runs (environment_id, target, provider, provider_credential_id, initiated_by, trigger)
+VALUES ($1, $2,
+    COALESCE((SELECT pc.provider FROM provider_credentials pc WHERE pc.environment_id = $1 AND pc.name = $2), ''),
+    (SELECT pc.id FROM provider_credentials pc WHERE pc.environment_id = $1 AND pc.name = $2), $3, $4)
+RETURNING *;
+
+-- name: FinishSyncRun :one
+UPDATE sync_runs
+SET version_id = $2, status = $3, message = $4, error = $5,
+    synced_count = $6, failed_count = $7, total_count = $8, finished_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetSyncRun :one
+SELECT * FROM sync_runs
+WHERE id = $1 AND environment_id = $2;
+
+-- name: InsertSyncRunResult :exec
+INSERT INTO sync_run_results (run_id, operation, name, success, error)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListCurrentSyncedVersions :many
+SELECT DISTINCT ON (target) id, target, provider, version_id, status, finished_at
+FROM sync_runs
+WHERE environment_id = $1 AND status IN ('success', 'partial') AND version_id IS NOT NULL
+ORDER BY target, started_at DESC;
+
+-- name: ListSyncRunResults :many
+SELECT * FROM sync_run_results
+WHERE run_id = $1
+ORDER BY operation DESC, name;
+
+-- name: ListSyncRunsForEnvironment :many
+SELECT * FROM sync_runs
+WHERE environment_id = $1
+ORDER BY started_at DESC
+LIMIT $2;
+
+-- name: ListSyncRunsForTarget :many
+SELECT * FROM sync_runs
+WHERE environment_id = $1 AND target = $2
+ORDER BY started_at DESC
+LIMIT $3;
@@ -3,6 +3,7 @@ package secret
 import (
 	"time"
 
+	"github.com/Gkemhcs/kavach-backend/internal/provider"
 	"github.com/google/uuid"
 )
 
@@ -22,10 +23,17 @@ type SecretVersion struct {
 	CreatedAt     time.Time `json:"created_at"`
 }
 
+// Secret sources describe where a key of an environment's effective secret set comes from
+const (
+	SecretSourceGroup       = "group"
+	SecretSourceEnvironment = "environment"
+)
+
 // SecretWithValue represents a secret with its decrypted value (for internal use only)
 type SecretWithValue struct {
-	Name  string `json:"name"`
-	Value string `json:"value"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source,omitempty"` // "group" or "environment"
 }
 
 // CreateSecretVersionRequest represents the request to create a new version of secrets
@@ -53,12 +61,42 @@ type SecretVersionResponse struct {
 	CommitMessage string    `json:"commit_message"`
 	CreatedAt     time.Time `json:"created_at"`
 	SecretCount   int       `json:"secret_count"`
+
+	SyncedTo []VersionSyncStatus `json:"synced_to,omitempty"` // Providers currently holding this version
+}
+
+// VersionSyncStatus represents a provider whose latest completed sync pushed a version
+type VersionSyncStatus struct {
+	Target   string     `json:"target"`
+	Provider string     `json:"provider"`
+	Status   string     `json:"status"` // success or partial
+	RunID    uuid.UUID  `json:"run_id"`
+	SyncedAt *time.Time `json:"synced_at,omitempty"`
 }
 
 // SecretVersionDetailResponse represents the detailed response for a secret version with secrets
 type SecretVersionDetailResponse struct {
+	ID            string                    `json:"id"`
+	EnvironmentID uuid.UUID                 `json:"environment_id"`
+	CommitMessage string                    `json:"commit_message"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	Secrets       []SecretWithValue         `json:"secrets"`
+	KeyChanges    []SecretKeyChangeResponse `json:"key_changes,omitempty"` // Renames and moves recorded by this version
+}
+
+// SecretGroupVersionResponse represents the response for a secret group base version
+type SecretGroupVersionResponse struct {
+	ID            string    `json:"id"`
+	SecretGroupID uuid.UUID `json:"secret_group_id"`
+	CommitMessage string    `json:"commit_message"`
+	CreatedAt     time.Time `json:"created_at"`
+	SecretCount   int       `json:"secret_count"`
+}
+
+// SecretGroupVersionDetailResponse represents the detailed response for a secret group base version with secrets
+type SecretGroupVersionDetailResponse struct {
 	ID            string            `json:"id"`
-	EnvironmentID uuid.UUID         `json:"environment_id"`
+	SecretGroupID uuid.UUID         `json:"secret_group_id"`
 	CommitMessage string            `json:"commit_message"`
 	CreatedAt     time.Time         `json:"created_at"`
 	Secrets       []SecretWithValue `json:"secrets"`
@@ -77,6 +115,7 @@ type SecretDiffChange struct {
 	Type     string `json:"type"` // "added", "removed", "modified"
 	OldValue string `json:"old_value,omitempty"`
 	NewValue string `json:"new_value,omitempty"`
+	Source   string `json:"source,omitempty"` // "group" or "environment"
 }
 
 // PushToProviderRequest represents the request to push secrets to an external provider
@@ -85,9 +124,18 @@ type PushToProviderRequest struct {
 	Config   map[string]string `json:"config" binding:"required"`   // Provider-specific configuration
 }
 
-// SyncSecretsRequest represents the request to sync secrets to a provider
+// SyncSecretsRequest represents the request to sync secrets to one, several or all provider targets
+// of an environment. Targets are addressed by name; without names every target is synced, optionally
+// only those of one provider type.
 type SyncSecretsRequest struct {
-	Provider  string `json:"provider" binding:"required"`
+	Targets   []string `json:"targets,omitempty"`
+	Provider  string   `json:"provider,omitempty"`   // Optional: only sync targets of this provider type
+	VersionID string   `json:"version_id,omitempty"` // Optional: sync specific version, otherwise latest
+}
+
+// SyncTargetRequest represents a sync of secrets to a single provider target
+type SyncTargetRequest struct {
+	Target    string `json:"target" binding:"required"`
 	VersionID string `json:"version_id,omitempty"` // Optional: sync specific version, otherwise latest
 }
 
@@ -100,6 +148,7 @@ type SyncResult struct {
 
 // SyncSecretsResponse represents the response for a sync operation
 type SyncSecretsResponse struct {
+	Target      string       `json:"target"`
 	Provider    string       `json:"provider"`
 	Status      string       `json:"status"` // success, failed, partial
 	Message     string       `json:"message"`
@@ -109,6 +158,494 @@ type SyncSecretsResponse struct {
 	Results     []SyncResult `json:"results"`
 	Errors      []string     `json:"errors,omitempty"`
 	Warnings    []string     `json:"warnings,omitempty"`
+	Removals    []SyncResult `json:"removals,omitempty"` // Old names of renamed or moved keys removed from the provider
+	Pruned      []SyncResult `json:"pruned,omitempty"`   // Managed remote secrets deleted in mirror mode
 	SyncedAt    string       `json:"synced_at"`
 	VersionID   string       `json:"version_id,omitempty"`
+	RunID       uuid.UUID    `json:"run_id"`
+}
+
+// Sync run states beyond the success, partial and failed outcomes of a sync
+const (
+	SyncStatusRunning = "running"
+	SyncStatusFailed  = "failed"
+)
+
+// Operations recorded for each secret of a sync run
+const (
+	SyncOperationUpsert = "upsert"
+	SyncOperationDelete = "delete"
+	SyncOperationPrune  = "prune"
+)
+
+// What started a sync job or run
+const (
+	SyncTriggerManual = "manual"
+	SyncTriggerAuto   = "auto"
+)
+
+// Sync job states
+const (
+	SyncJobStatusQueued    = "queued"
+	SyncJobStatusRunning   = "running"
+	SyncJobStatusCompleted = "completed"
+	SyncJobStatusFailed    = "failed"
+)
+
+// SyncJobResponse represents a queued provider sync and the run of its latest attempt
+type SyncJobResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	EnvironmentID uuid.UUID  `json:"environment_id"`
+	Target        string     `json:"target"`
+	Provider      string     `json:"provider"`
+	Trigger       string     `json:"trigger"`              // manual, auto
+	VersionID     string     `json:"version_id,omitempty"` // Empty syncs the latest version
+	InitiatedBy   *uuid.UUID `json:"initiated_by,omitempty"`
+	Status        string     `json:"status"` // queued, running, completed, failed
+	Attempts      int        `json:"attempts"`
+	MaxAttempts   int        `json:"max_attempts"`
+	RunID         *uuid.UUID `json:"run_id,omitempty"`
+	PlanID        *uuid.UUID `json:"plan_id,omitempty"` // Set when the job applies a sync plan
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// EnqueueSyncResponse lists the sync jobs queued for a request, one per target
+type EnqueueSyncResponse struct {
+	Jobs []SyncJobResponse `json:"jobs"`
+}
+
+// Planned actions for a remote secret
+const (
+	SyncPlanActionCreate    = "create"
+	SyncPlanActionUpdate    = "update"
+	SyncPlanActionUnchanged = "unchanged"
+	SyncPlanActionDelete    = "delete"
+)
+
+// Why a remote secret is planned to be updated or deleted
+const (
+	SyncPlanReasonValueChanged    = "value_changed"    // The remote value differs from the version
+	SyncPlanReasonValueUnreadable = "value_unreadable" // The provider does not return values, so existing secrets are always rewritten
+	SyncPlanReasonRenamed         = "renamed"          // Old name of a key renamed or moved out of the environment
+	SyncPlanReasonMirror          = "mirror"           // Managed secret no longer in the version, deleted in mirror mode
+)
+
+// SyncPlanRequest represents the request to plan a sync without applying it
+type SyncPlanRequest struct {
+	Target    string `json:"target" binding:"required"`
+	VersionID string `json:"version_id,omitempty"` // Optional: plan a specific version, otherwise latest
+}
+
+// SyncPlanChange is the planned action for one remote secret
+type SyncPlanChange struct {
+	Action     string `json:"action"`         // create, update, unchanged, delete
+	Name       string `json:"name,omitempty"` // Kavach key; empty for managed secrets deleted in mirror mode
+	RemoteName string `json:"remote_name"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// SyncPlanCollision lists Kavach keys that map to the same remote name, of which only one would survive a sync
+type SyncPlanCollision struct {
+	RemoteName string   `json:"remote_name"`
+	Names      []string `json:"names"`
+}
+
+// KeyMappingPreviewRequest represents the request to preview the remote names of a provider target.
+// Rules default to the target's stored key mapping, so unsaved rules can be tried before they are stored.
+type KeyMappingPreviewRequest struct {
+	Target string                    `json:"target" binding:"required"`
+	Rules  *provider.KeyMappingRules `json:"rules,omitempty"`
+}
+
+// KeyMappingPreviewKey shows where one key of the current version ends up on the provider target
+type KeyMappingPreviewKey struct {
+	Name       string `json:"name"`
+	MappedName string `json:"mapped_name,omitempty"` // Name after the key mapping rules
+	RemoteName string `json:"remote_name,omitempty"` // Name after the provider's prefix and sanitizing
+	Group      string `json:"group,omitempty"`       // JSON-valued secret the key is combined into
+	Excluded   bool   `json:"excluded,omitempty"`
+}
+
+// KeyMappingPreviewResponse represents the remote names the current version would sync under
+type KeyMappingPreviewResponse struct {
+	Target     string                   `json:"target"`
+	Provider   string                   `json:"provider"`
+	VersionID  string                   `json:"version_id"`
+	Rules      provider.KeyMappingRules `json:"rules"`
+	Keys       []KeyMappingPreviewKey   `json:"keys"`
+	Collisions []SyncPlanCollision      `json:"collisions"` // Keys landing on the same remote name; the rules are rejected when set
+}
+
+// SyncPlanSummary counts the planned changes by action
+type SyncPlanSummary struct {
+	Create    int `json:"create"`
+	Update    int `json:"update"`
+	Unchanged int `json:"unchanged"`
+	Delete    int `json:"delete"`
+}
+
+// SyncPlanResponse represents a stored sync plan
+type SyncPlanResponse struct {
+	ID            uuid.UUID           `json:"id"`
+	EnvironmentID uuid.UUID           `json:"environment_id"`
+	Target        string              `json:"target"`
+	Provider      string              `json:"provider"`
+	VersionID     string              `json:"version_id"`
+	MirrorMode    bool                `json:"mirror_mode"`
+	Summary       SyncPlanSummary     `json:"summary"`
+	Changes       []SyncPlanChange    `json:"changes"`
+	Collisions    []SyncPlanCollision `json:"collisions"`
+	CreatedBy     *uuid.UUID          `json:"created_by,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	ExpiresAt     time.Time           `json:"expires_at"`
+	AppliedBy     *uuid.UUID          `json:"applied_by,omitempty"`
+	AppliedAt     *time.Time          `json:"applied_at,omitempty"`
+	JobID         *uuid.UUID          `json:"job_id,omitempty"` // Sync job applying the plan
+}
+
+// SyncRunResponse represents a recorded sync run
+type SyncRunResponse struct {
+	ID                   uuid.UUID  `json:"id"`
+	EnvironmentID        uuid.UUID  `json:"environment_id"`
+	Target               string     `json:"target"`
+	Provider             string     `json:"provider"`
+	ProviderCredentialID *uuid.UUID `json:"provider_credential_id,omitempty"`
+	Trigger              string     `json:"trigger"` // manual, auto
+	VersionID            string     `json:"version_id,omitempty"`
+	InitiatedBy          *uuid.UUID `json:"initiated_by,omitempty"`
+	Status               string     `json:"status"` // running, success, partial, failed
+	Message              string     `json:"message,omitempty"`
+	Error                string     `json:"error,omitempty"`
+	SyncedCount          int        `json:"synced_count"`
+	FailedCount          int        `json:"failed_count"`
+	TotalCount           int        `json:"total_count"`
+	StartedAt            time.Time  `json:"started_at"`
+	FinishedAt           *time.Time `json:"finished_at,omitempty"`
+}
+
+// SyncRunDetailResponse represents a sync run with the outcome of every secret
+type SyncRunDetailResponse struct {
+	SyncRunResponse
+	Results  []SyncResult `json:"results"`
+	Removals []SyncResult `json:"removals,omitempty"`
+	Pruned   []SyncResult `json:"pruned,omitempty"`
+}
+
+// Drift check states
+const (
+	DriftStatusInSync  = "in_sync"
+	DriftStatusDrifted = "drifted"
+	DriftStatusFailed  = "failed"
+)
+
+// What started a drift check
+const (
+	DriftTriggerManual    = "manual"
+	DriftTriggerScheduled = "scheduled"
+)
+
+// Why a key present in Kavach and on the provider is reported as changed
+const (
+	DriftReasonValueChanged     = "value_changed"      // The remote value differs from the latest version
+	DriftReasonUpdatedSinceSync = "updated_since_sync" // The value cannot be read back and the secret was modified after the last successful sync
+)
+
+// DriftCheckRequest represents the request to check provider targets for drift. Without names every
+// target is checked, optionally only those of one provider type.
+type DriftCheckRequest struct {
+	Targets  []string `json:"targets,omitempty"`
+	Provider string   `json:"provider,omitempty"` // Optional: only check targets of this provider type
+}
+
+// DriftKey identifies a key that differs between Kavach and a provider. It never carries a value.
+type DriftKey struct {
+	Name            string     `json:"name,omitempty"` // Empty for keys that only exist on the provider
+	RemoteName      string     `json:"remote_name"`
+	Reason          string     `json:"reason,omitempty"`
+	RemoteUpdatedAt *time.Time `json:"remote_updated_at,omitempty"`
+}
+
+// DriftFindings groups the keys that differ between the latest version and a provider
+type DriftFindings struct {
+	Missing []DriftKey `json:"missing"` // In the latest version but not on the provider
+	Extra   []DriftKey `json:"extra"`   // On the provider but not in the latest version
+	Changed []DriftKey `json:"changed"` // In both with a different value
+}
+
+// DriftCheckResponse represents the outcome of comparing a provider with the latest version of an environment
+type DriftCheckResponse struct {
+	ID              uuid.UUID `json:"id"`
+	EnvironmentID   uuid.UUID `json:"environment_id"`
+	Target          string    `json:"target"`
+	Provider        string    `json:"provider"`
+	Trigger         string    `json:"trigger"`                     // manual, scheduled
+	VersionID       string    `json:"version_id,omitempty"`        // Latest version the provider was compared with
+	SyncedVersionID string    `json:"synced_version_id,omitempty"` // Version of the last successful sync
+	Status          string    `json:"status"`                      // in_sync, drifted, failed
+	DriftFindings
+	Error     string     `json:"error,omitempty"`
+	CheckedBy *uuid.UUID `json:"checked_by,omitempty"`
+	CheckedAt time.Time  `json:"checked_at"`
+}
+
+// Why a remote secret is left out of an import
+const (
+	ImportSkipOutsidePrefix = "outside_prefix" // The name is not under the target's prefix
+	ImportSkipInvalidName   = "invalid_name"   // The name is not a valid Kavach key
+	ImportSkipEmptyValue    = "empty_value"    // Kavach does not store empty values
+	ImportSkipInvalidGroup  = "invalid_group"  // A group secret whose value is not a JSON object of strings
+	ImportSkipCollision     = "collision"      // Another remote secret maps to the same key
+)
+
+// ImportFromProviderRequest represents the request to import the secrets of a provider target as a new version
+type ImportFromProviderRequest struct {
+	Target        string `json:"target" binding:"required"`
+	DryRun        bool   `json:"dry_run,omitempty"`        // Only report the changes the import would make
+	CommitMessage string `json:"commit_message,omitempty"` // Defaults to "Import from <target>"
+}
+
+// ImportedKey shows which key a remote secret is imported as
+type ImportedKey struct {
+	RemoteName string `json:"remote_name"`
+	Name       string `json:"name,omitempty"`    // Key in the environment; empty when skipped
+	Group      string `json:"group,omitempty"`   // Key mapping group the remote secret combines keys of
+	Skipped    string `json:"skipped,omitempty"` // Why the secret is left out of the import
+}
+
+// ImportCollision lists remote secrets that map to the same key, of which none is imported
+type ImportCollision struct {
+	Name        string   `json:"name"`
+	RemoteNames []string `json:"remote_names"`
+}
+
+// ImportFromProviderResponse represents the outcome of an import. Changes are relative to the latest
+// version; keys only in Kavach are kept. Values are never returned.
+type ImportFromProviderResponse struct {
+	Target        string             `json:"target"`
+	Provider      string             `json:"provider"`
+	DryRun        bool               `json:"dry_run"`
+	BaseVersionID string             `json:"base_version_id,omitempty"` // Latest version the import builds on
+	VersionID     string             `json:"version_id,omitempty"`      // Version created; empty for dry runs or when nothing changed
+	Keys          []ImportedKey      `json:"keys"`
+	Changes       []SecretDiffChange `json:"changes"` // added or modified keys
+	Unchanged     int                `json:"unchanged"`
+	Collisions    []ImportCollision  `json:"collisions"`
+}
+
+// SecretPolicyRequest represents the request to set the value policy of a secret group
+type SecretPolicyRequest struct {
+	Schema SecretPolicySchema `json:"schema" binding:"required"`
+}
+
+// SecretPolicyResponse represents the value policy configured for a secret group
+type SecretPolicyResponse struct {
+	SecretGroupID uuid.UUID          `json:"secret_group_id"`
+	Schema        SecretPolicySchema `json:"schema"`
+	UpdatedBy     uuid.UUID          `json:"updated_by"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// SecretPolicyLintResponse represents the policy violations found in an existing version
+type SecretPolicyLintResponse struct {
+	VersionID     string            `json:"version_id"`
+	EnvironmentID uuid.UUID         `json:"environment_id"`
+	PolicyFound   bool              `json:"policy_found"`
+	Valid         bool              `json:"valid"`
+	Violations    []PolicyViolation `json:"violations"`
+}
+
+// Secret reuse finding scopes
+const (
+	ReuseScopeCrossEnvironment = "cross_environment"  // Same value in several environments of one secret group
+	ReuseScopeCrossSecretGroup = "cross_secret_group" // Same value in environments of different secret groups
+)
+
+// SecretReuseOccurrence identifies one place where a reused value is stored. It never carries the value.
+type SecretReuseOccurrence struct {
+	SecretGroupID   uuid.UUID `json:"secret_group_id"`
+	SecretGroupName string    `json:"secret_group_name"`
+	EnvironmentID   uuid.UUID `json:"environment_id"`
+	EnvironmentName string    `json:"environment_name"`
+	VersionID       string    `json:"version_id"`
+	Key             string    `json:"key"`
+}
+
+// SecretReuseFindingResponse represents a value found in more than one environment
+type SecretReuseFindingResponse struct {
+	ID               uuid.UUID               `json:"id"`
+	Scope            string                  `json:"scope"`
+	Occurrences      []SecretReuseOccurrence `json:"occurrences"`
+	Suppressed       bool                    `json:"suppressed"`
+	SuppressedBy     *uuid.UUID              `json:"suppressed_by,omitempty"`
+	SuppressedReason string                  `json:"suppressed_reason,omitempty"`
+	SuppressedAt     *time.Time              `json:"suppressed_at,omitempty"`
+	FirstSeenAt      time.Time               `json:"first_seen_at"`
+	LastSeenAt       time.Time               `json:"last_seen_at"`
+	ResolvedAt       *time.Time              `json:"resolved_at,omitempty"`
+}
+
+// SecretReuseScanResponse summarizes a secret reuse scan of an organization
+type SecretReuseScanResponse struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	ScannedSecrets int       `json:"scanned_secrets"`
+	SkippedSecrets int       `json:"skipped_secrets"`
+	FindingsCount  int       `json:"findings_count"`
+	ResolvedCount  int64     `json:"resolved_count"`
+	ScannedAt      time.Time `json:"scanned_at"`
+}
+
+// SuppressReuseFindingRequest represents the request to suppress a secret reuse finding
+type SuppressReuseFindingRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// Secret key change types recorded in version metadata
+const (
+	KeyChangeRename  = "rename"
+	KeyChangeMoveOut = "move_out"
+	KeyChangeMoveIn  = "move_in"
+)
+
+// Key history change kinds reported by the key history endpoint
+const (
+	KeyHistoryAdded    = "added"
+	KeyHistoryModified = "modified"
+	KeyHistoryRemoved  = "removed"
+	KeyHistoryRenamed  = "renamed"
+	KeyHistoryMovedIn  = "moved_in"
+)
+
+// SecretKeyRename represents a single key rename
+type SecretKeyRename struct {
+	From string `json:"from" binding:"required"`
+	To   string `json:"to" binding:"required"`
+}
+
+// RenameSecretKeysRequest represents the request to rename keys of an environment
+type RenameSecretKeysRequest struct {
+	Renames       []SecretKeyRename `json:"renames" binding:"required"`
+	CommitMessage string            `json:"commit_message" binding:"required"`
+}
+
+// MoveSecretKeysRequest represents the request to move keys to another environment of the same secret group
+type MoveSecretKeysRequest struct {
+	Keys                []string `json:"keys" binding:"required"`
+	TargetEnvironmentID string   `json:"target_environment_id" binding:"required"`
+	CommitMessage       string   `json:"commit_message" binding:"required"`
+}
+
+// MoveSecretKeysResponse represents the versions created in both environments by a move
+type MoveSecretKeysResponse struct {
+	Source SecretVersionResponse `json:"source"`
+	Target SecretVersionResponse `json:"target"`
+}
+
+// SecretKeyChangeResponse represents a rename or move recorded on a version
+type SecretKeyChangeResponse struct {
+	ChangeType           string     `json:"change_type"` // "rename", "move_out" or "move_in"
+	OldName              string     `json:"old_name"`
+	NewName              string     `json:"new_name"`
+	RelatedEnvironmentID *uuid.UUID `json:"related_environment_id,omitempty"`
+	RelatedVersionID     string     `json:"related_version_id,omitempty"`
+}
+
+// SecretKeyHistoryEntry represents a version in which a key changed. Values are never included.
+type SecretKeyHistoryEntry struct {
+	VersionID             string     `json:"version_id"`
+	EnvironmentID         uuid.UUID  `json:"environment_id"`
+	Name                  string     `json:"name"`
+	Change                string     `json:"change"` // "added", "modified", "removed", "renamed" or "moved_in"
+	PreviousName          string     `json:"previous_name,omitempty"`
+	PreviousEnvironmentID *uuid.UUID `json:"previous_environment_id,omitempty"`
+	CommitMessage         string     `json:"commit_message"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+// Share lifecycle events recorded for every share
+const (
+	ShareEventCreated          = "created"
+	ShareEventRetrieved        = "retrieved"
+	ShareEventPassphraseFailed = "passphrase_failed"
+	ShareEventRevoked          = "revoked"
+)
+
+// Share states reported when listing shares
+const (
+	ShareStatusActive  = "active"
+	ShareStatusUsed    = "used"
+	ShareStatusExpired = "expired"
+	ShareStatusRevoked = "revoked"
+	ShareStatusLocked  = "locked"
+)
+
+// CreateSecretShareRequest represents the request to share a single key or an ad-hoc value.
+// Exactly one of SecretName and Value must be set.
+type CreateSecretShareRequest struct {
+	SecretName       string `json:"secret_name,omitempty"`
+	VersionID        string `json:"version_id,omitempty"` // Defaults to the latest version when sharing a key
+	Value            string `json:"value,omitempty"`
+	Passphrase       string `json:"passphrase,omitempty"`
+	MaxViews         int    `json:"max_views,omitempty"`          // Defaults to a single view
+	ExpiresInMinutes int    `json:"expires_in_minutes,omitempty"` // Defaults to one hour
+}
+
+// SecretShareResponse represents a share without its value or token
+type SecretShareResponse struct {
+	ID                  uuid.UUID  `json:"id"`
+	EnvironmentID       uuid.UUID  `json:"environment_id"`
+	VersionID           string     `json:"version_id,omitempty"`
+	SecretName          string     `json:"secret_name,omitempty"`
+	PassphraseProtected bool       `json:"passphrase_protected"`
+	MaxViews            int        `json:"max_views"`
+	ViewCount           int        `json:"view_count"`
+	Status              string     `json:"status"`
+	ExpiresAt           time.Time  `json:"expires_at"`
+	RevokedAt           *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy           uuid.UUID  `json:"created_by"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// CreateSecretShareResponse represents a newly created share. The token is only returned once.
+type CreateSecretShareResponse struct {
+	SecretShareResponse
+	Token string `json:"token"`
+}
+
+// SecretShareEventResponse represents a recorded share event
+type SecretShareEventResponse struct {
+	EventType string     `json:"event_type"`
+	ActorID   *uuid.UUID `json:"actor_id,omitempty"`
+	IPAddress string     `json:"ip_address,omitempty"`
+	UserAgent string     `json:"user_agent,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// SecretShareDetailResponse represents a share with its recorded events
+type SecretShareDetailResponse struct {
+	SecretShareResponse
+	Events []SecretShareEventResponse `json:"events"`
+}
+
+// RetrieveSecretShareRequest represents the request to retrieve a shared value
+type RetrieveSecretShareRequest struct {
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+// RetrieveSecretShareResponse represents a retrieved shared value
+type RetrieveSecretShareResponse struct {
+	SecretName     string    `json:"secret_name,omitempty"`
+	Value          string    `json:"value"`
+	RemainingViews int       `json:"remaining_views"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// ShareClientInfo identifies the client creating, retrieving or revoking a share for the audit trail
+type ShareClientInfo struct {
+	IPAddress string
+	UserAgent string
 }
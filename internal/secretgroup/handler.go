@@ -56,6 +56,7 @@ func RegisterSecretGroupRoutes(handler *SecretGroupHandler,
 		secretGroup.GET("/:groupID/role-bindings", handler.ListSecretGroupRoleBindings)
 	}
 
+	secret.RegisterSecretGroupSecretRoutes(secretHandler, secretGroup)
 	environment.RegisterEnvironmentRoutes(environmentHandler, secretHandler, providerHandler, secretGroup, jwtMiddleware)
 }
 
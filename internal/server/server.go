@@ -38,10 +38,16 @@ func (s *Server) SetupRoutes(authHandler *auth.AuthHandler,
 	userGroupHandler *groups.UserGroupHandler,
 	secretHandler *secret.SecretHandler,
 	providerHandler *provider.ProviderHandler,
+	oidcIssuer *provider.OIDCIssuer,
 	jwter *jwt.Manager,
 	cfg *config.Config,
 	logger *logrus.Logger,
 	authzMiddleware *middleware.AuthMiddleware) {
+	// The OIDC discovery document and JWKS are public and live under the issuer URL, outside /api/v1
+	if oidcIssuer != nil {
+		provider.RegisterOIDCRoutes(oidcIssuer, s.engine)
+	}
+
 	// Create API v1 router group
 	v1 := s.engine.Group("/api/v1")
 
@@ -50,6 +56,9 @@ func (s *Server) SetupRoutes(authHandler *auth.AuthHandler,
 	// Register auth routes FIRST (no middleware - these are public)
 	auth.RegisterAuthRoutes(authHandler, v1)
 
+	// One-time secret shares are retrieved by recipients without an account
+	secret.RegisterPublicShareRoutes(secretHandler, v1)
+
 	// Create a new group for protected routes that need JWT
 	protected := v1.Group("")
 	protected.Use(jwtMiddleware)